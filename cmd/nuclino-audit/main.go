@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+)
+
+func main() {
+	var (
+		path    = flag.String("file", "", "Audit log file (default: $NUCLINO_AUDIT_LOG or the user cache dir's nuclino-mcp/audit.jsonl)")
+		since   = flag.String("since", "", "Only records at or after this time: RFC 3339, YYYY-MM-DD or a duration such as 24h or 7d")
+		until   = flag.String("until", "", "Only records at or before this time, in the same formats as -since")
+		tool    = flag.String("tool", "", "Only records of this tool (e.g. nuclino_delete_item) or API operation (e.g. DeleteItem)")
+		itemID  = flag.String("item", "", "Only records that named or created this item, collection, workspace or file ID")
+		outcome = flag.String("outcome", "", "Only records with this outcome: success, error, denied, confirmation_required or cancelled")
+		kind    = flag.String("kind", "", "Only tool call records (tool) or API change records (api)")
+		limit   = flag.Int("limit", 100, "Maximum number of records, newest first (0 for all)")
+		format  = flag.String("format", "text", "Output format: text or json")
+	)
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Debug().Err(err).Msg("No .env file found")
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	if *path == "" {
+		*path = os.Getenv("NUCLINO_AUDIT_LOG")
+	}
+	if *path == "" {
+		*path = audit.DefaultPath()
+	}
+
+	now := time.Now()
+	filter := audit.Filter{Tool: *tool, ItemID: *itemID, Outcome: *outcome, Kind: *kind, Limit: *limit}
+	var err error
+	if filter.Since, err = audit.ParseTime(*since, now); err != nil {
+		log.Fatal().Err(err).Msg("Invalid -since")
+	}
+	if filter.Until, err = audit.ParseTime(*until, now); err != nil {
+		log.Fatal().Err(err).Msg("Invalid -until")
+	}
+
+	records, err := audit.Read(*path, filter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to read audit log")
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		for _, record := range records {
+			_ = encoder.Encode(record)
+		}
+	default:
+		printText(records)
+	}
+}
+
+func printText(records []audit.Record) {
+	for _, record := range records {
+		name := record.Tool
+		if name == "" {
+			name = record.Operation
+		}
+		who := record.Account
+		if record.Session != nil && record.Session.ClientName != "" {
+			who = strings.TrimPrefix(who+" "+record.Session.ClientName, " ")
+		}
+		fmt.Printf("%s  %-4s %-32s %-21s %6dms  %s",
+			record.Time.Local().Format("2006-01-02 15:04:05"), record.Kind, name, record.Outcome, record.LatencyMS,
+			strings.Join(record.Affected, ","))
+		if who != "" {
+			fmt.Printf("  [%s]", who)
+		}
+		fmt.Println()
+		if record.Error != "" {
+			fmt.Printf("    %s\n", strings.ReplaceAll(record.Error, "\n", " "))
+		}
+	}
+	fmt.Printf("\n%d records\n", len(records))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/lint"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func main() {
+	defaults := lint.DefaultConfig()
+	var (
+		workspaceID = flag.String("workspace", "", "ID of the workspace to lint (required)")
+		itemID      = flag.String("item", "", "Only report issues for this item")
+		staleDays   = flag.Int("stale-days", defaults.StaleDays, "Flag items not updated for this many days (0 disables)")
+		maxChars    = flag.Int("max-chars", defaults.MaxChars, "Flag items longer than this many characters (0 disables)")
+		disable     = flag.String("disable", "", "Comma-separated rule names to skip")
+		minSeverity = flag.String("min-severity", "info", "Lowest severity to report: info, warning or error")
+		failOn      = flag.String("fail-on", "error", "Exit with status 1 when an issue of this severity or worse is found")
+		format      = flag.String("format", "text", "Output format: text or json")
+		debug       = flag.Bool("debug", false, "Enable debug logging")
+	)
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Debug().Err(err).Msg("No .env file found")
+	}
+
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	apiKey := os.Getenv("NUCLINO_API_KEY")
+	if apiKey == "" {
+		log.Fatal().Msg("NUCLINO_API_KEY environment variable is required")
+	}
+	if *workspaceID == "" {
+		log.Fatal().Msg("-workspace is required")
+	}
+
+	config := lint.Config{StaleDays: *staleDays, MaxChars: *maxChars}
+	for _, name := range strings.Split(*disable, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			config.Disabled = append(config.Disabled, name)
+		}
+	}
+	var err error
+	if config.MinSeverity, err = lint.ParseSeverity(*minSeverity); err != nil {
+		log.Fatal().Err(err).Msg("Invalid -min-severity")
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid -fail-on")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	builder := linkgraph.NewBuilder(nuclino.NewClient(apiKey), linkgraph.DefaultConfig())
+	graph, err := builder.Build(ctx, *workspaceID, true)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load workspace")
+	}
+
+	var itemIDs []string
+	if *itemID != "" {
+		itemIDs = append(itemIDs, *itemID)
+	}
+	report := lint.NewLinter(config).LintGraph(graph, itemIDs...)
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(report)
+	default:
+		printText(report)
+	}
+
+	if report.HasIssuesAtLeast(threshold) {
+		os.Exit(1)
+	}
+}
+
+func printText(report *lint.Report) {
+	for _, item := range report.Items {
+		fmt.Printf("%s (%s)\n", item.Title, item.ItemID)
+		for _, issue := range item.Issues {
+			location := ""
+			if issue.Line > 0 {
+				location = fmt.Sprintf("line %d: ", issue.Line)
+			}
+			fmt.Printf("  %-7s %-15s %s%s\n", issue.Severity, issue.Rule, location, issue.Message)
+		}
+	}
+	fmt.Printf("\n%d items checked: %d errors, %d warnings, %d info\n",
+		report.ItemsChecked, report.Summary["error"], report.Summary["warning"], report.Summary["info"])
+}
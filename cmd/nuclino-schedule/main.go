@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/scheduler"
+	"github.com/lukasz/nuclino-mcp-server/internal/templates"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", "", "Path of the YAML schedule config (default: $NUCLINO_SCHEDULES_FILE)")
+		jobs       = flag.String("job", "", "Comma-separated job names to run (default: all)")
+		at         = flag.String("at", "", "Run as if the time were this RFC 3339 timestamp (default: now)")
+		daemon     = flag.Bool("daemon", false, "Keep running and create pages on schedule instead of running once")
+		dryRun     = flag.Bool("dry-run", false, "Report which pages would be created without writing anything")
+		debug      = flag.Bool("debug", false, "Enable debug logging")
+	)
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Debug().Err(err).Msg("No .env file found")
+	}
+
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	apiKey := os.Getenv("NUCLINO_API_KEY")
+	if apiKey == "" {
+		log.Fatal().Msg("NUCLINO_API_KEY environment variable is required")
+	}
+	if *configPath == "" {
+		*configPath = os.Getenv("NUCLINO_SCHEDULES_FILE")
+	}
+	if *configPath == "" {
+		log.Fatal().Msg("-config is required")
+	}
+
+	config, err := scheduler.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid schedule configuration")
+	}
+
+	now := time.Now()
+	if *at != "" {
+		if now, err = time.Parse(time.RFC3339, *at); err != nil {
+			log.Fatal().Err(err).Msg("Invalid -at")
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	client := nuclino.NewClient(apiKey)
+	runner := scheduler.New(client, templates.NewLibrary(client, templates.DefaultConfig()), config)
+	runner.DryRun = *dryRun
+
+	if *daemon {
+		if err := runner.Start(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start scheduler")
+		}
+		<-ctx.Done()
+		return
+	}
+
+	var names []string
+	for _, name := range strings.Split(*jobs, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	outcomes, err := runner.RunOnce(ctx, now, names...)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(outcomes)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Scheduled run failed")
+	}
+
+	for _, outcome := range outcomes {
+		if outcome.Action == scheduler.ActionFailed {
+			os.Exit(1)
+		}
+	}
+}
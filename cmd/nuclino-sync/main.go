@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/docsync"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func main() {
+	var (
+		workspaceID = flag.String("workspace", "", "ID of the workspace to sync (required)")
+		dir         = flag.String("dir", ".", "Local directory holding the Markdown mirror")
+		stateFile   = flag.String("state", "", "Path of the sync state file (default: <dir>/"+docsync.DefaultStateFile+")")
+		gitCommit   = flag.Bool("git", false, "Commit each sync to a git repository in <dir>")
+		dryRun      = flag.Bool("dry-run", false, "Report what would change without writing anything")
+		debug       = flag.Bool("debug", false, "Enable debug logging")
+	)
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Debug().Err(err).Msg("No .env file found")
+	}
+
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	apiKey := os.Getenv("NUCLINO_API_KEY")
+	if apiKey == "" {
+		log.Fatal().Msg("NUCLINO_API_KEY environment variable is required")
+	}
+	if *workspaceID == "" {
+		log.Fatal().Msg("-workspace is required")
+	}
+
+	engine, err := docsync.NewEngine(nuclino.NewClient(apiKey), docsync.Config{
+		WorkspaceID: *workspaceID,
+		Dir:         *dir,
+		StateFile:   *stateFile,
+		GitCommit:   *gitCommit,
+		DryRun:      *dryRun,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid sync configuration")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	report, err := engine.Sync(ctx)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(report)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Sync failed")
+	}
+
+	if report.Count(docsync.ActionConflict)+report.Count(docsync.ActionConflictPending) > 0 {
+		log.Warn().Msg("Sync finished with unresolved conflicts")
+		os.Exit(2)
+	}
+}
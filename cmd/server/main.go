@@ -7,24 +7,51 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+	"github.com/lukasz/nuclino-mcp-server/internal/config"
+	"github.com/lukasz/nuclino-mcp-server/internal/monitoring"
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino/nuclinotest"
+	"github.com/lukasz/nuclino-mcp-server/internal/scheduler"
 	"github.com/lukasz/nuclino-mcp-server/internal/server"
+	"github.com/lukasz/nuclino-mcp-server/internal/templates"
+	"github.com/lukasz/nuclino-mcp-server/internal/tools"
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
 )
 
+// serverVersion is reported by -version and in traces
+const serverVersion = "0.1.0"
+
 func main() {
 	var (
-		debug   = flag.Bool("debug", false, "Enable debug logging")
-		version = flag.Bool("version", false, "Show version information")
+		debug     = flag.Bool("debug", false, "Enable debug logging")
+		version   = flag.Bool("version", false, "Show version information")
+		schedules = flag.String("schedules", "", "YAML schedule config for recurring pages (default: $NUCLINO_SCHEDULES_FILE)")
+
+		configPath = flag.String("config", "", "YAML or TOML config file (default: $NUCLINO_CONFIG or the user config dir's nuclino-mcp/config.yaml)")
+		profile    = flag.String("profile", "", "Config profile to use (default: $NUCLINO_PROFILE or the file's default_profile)")
+		accounts   = flag.String("accounts", "", "Comma-separated profiles to serve as extra accounts, or * for all (default: $NUCLINO_ACCOUNTS or the file's accounts)")
+		baseURL    = flag.String("base-url", "", "Nuclino API base URL")
+		rps        = flag.Float64("rps", 0, "Maximum API requests per second")
+		cacheMode  = flag.String("cache", "", "Read cache backend: none or memory")
+		toolsets   = flag.String("toolsets", "", "Comma-separated toolsets to enable (default: all)")
+		readOnly   = flag.Bool("read-only", false, "Only expose tools that do not change Nuclino content")
+		auditLog   = flag.String("audit-log", "", "Audit log file for tool calls and API changes, or none to disable (default: $NUCLINO_AUDIT_LOG or the user cache dir's nuclino-mcp/audit.jsonl)")
+		tracingOpt = flag.String("tracing", "", "Trace exporter: none, otlp or file (default: $NUCLINO_TRACING or none)")
+		traceFile  = flag.String("trace-file", "", "JSON span file of the file exporter; implies -tracing file (default: $NUCLINO_TRACE_FILE or the user cache dir's nuclino-mcp/traces.jsonl)")
+		adminAddr  = flag.String("admin-addr", "", "Address to serve /metrics, /healthz and /readyz on, e.g. 127.0.0.1:9090 (default: $NUCLINO_ADMIN_ADDR; disabled when empty)")
+		demo       = flag.Bool("demo", false, "Serve sample data from a built-in fake of the Nuclino API; no API key needed")
 	)
 	flag.Parse()
 
 	if *version {
-		fmt.Println("nuclino-mcp-server v0.1.0")
+		fmt.Println("nuclino-mcp-server v" + serverVersion)
 		os.Exit(0)
 	}
 
@@ -42,22 +69,158 @@ func main() {
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
 
-	// Get API key from environment
-	apiKey := os.Getenv("NUCLINO_API_KEY")
-	if apiKey == "" {
-		log.Fatal().Msg("NUCLINO_API_KEY environment variable is required")
+	// Load the config profile; flags override the environment, which
+	// overrides the file
+	overrides := config.Overrides{
+		Profile:       *profile,
+		Accounts:      config.SplitList(*accounts),
+		BaseURL:       *baseURL,
+		RPS:           *rps,
+		Cache:         *cacheMode,
+		Toolsets:      config.SplitList(*toolsets),
+		SchedulesFile: *schedules,
+		AuditLog:      *auditLog,
+		AdminAddr:     *adminAddr,
+		Tracing:       *tracingOpt,
+		TraceFile:     *traceFile,
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "read-only" {
+			overrides.ReadOnly = readOnly
+		}
+	})
+
+	// The demo points the profile at an in-process fake API seeded with a
+	// sample team wiki, so the server runs offline without an account
+	if *demo {
+		fake := nuclinotest.NewServer()
+		defer fake.Close()
+		fake.SeedDemo()
+		overrides.APIKey = nuclinotest.DemoAPIKey
+		overrides.BaseURL = fake.URL
+		log.Info().Str("url", fake.URL).Msg("Serving demo data from the fake Nuclino API")
+	}
+	profiles, err := config.LoadAccounts(context.Background(), *configPath, overrides)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid configuration")
+	}
+
+	settings := profiles[0]
+
+	// One audit log records the tool calls and API changes of every account
+	var auditLogger *audit.Logger
+	if auditConfig, ok := settings.AuditConfig(); ok {
+		if auditLogger, err = audit.NewLogger(auditConfig); err != nil {
+			log.Fatal().Err(err).Msg("Failed to open audit log")
+		}
+		defer auditLogger.Close()
+		log.Info().Str("path", auditConfig.Path).Msg("Audit log enabled")
 	}
 
-	// Create Nuclino client
-	nuclinoClient := nuclino.NewClient(apiKey)
+	// Traces cover MCP requests, tool calls and API requests of every account
+	tracingConfig := settings.TracingConfig()
+	tracingConfig.ServiceVersion = serverVersion
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("Failed to flush traces")
+		}
+	}()
+	if tracingConfig.Exporter != tracing.ExporterNone {
+		log.Info().Str("exporter", tracingConfig.Exporter).Msg("Tracing enabled")
+	}
+
+	// One collector counts the API requests and tool calls of every account
+	metrics := monitoring.NewMetricsCollector()
+
+	// Each account gets its own client, and so its own rate limit and cache
+	var serverAccounts []tools.Account
+	for _, account := range profiles {
+		if err := tools.ValidateToolsets(account.Toolsets); err != nil {
+			log.Fatal().Err(err).Str("profile", account.Name).Msg("Invalid configuration")
+		}
+		policy := tools.Policy{
+			Allow:      account.Policy.Allow,
+			Deny:       account.Policy.Deny,
+			Categories: account.Policy.Categories,
+			Workspaces: account.Policy.Workspaces,
+			ReadOnly:   account.ReadOnly,
+		}
+		if err := policy.Validate(); err != nil {
+			log.Fatal().Err(err).Str("profile", account.Name).Msg("Invalid configuration")
+		}
+		log.Info().
+			Str("profile", account.Name).
+			Str("base_url", account.BaseURL).
+			Bool("read_only", account.ReadOnly).
+			Strs("toolsets", account.Toolsets).
+			Msg("Loaded configuration")
+
+		serverAccounts = append(serverAccounts, tools.Account{
+			Name:   account.Name,
+			Client: nuclino.NewAuditingClient(account.NewClient(metrics), auditLogger, account.Name),
+			Options: tools.Options{
+				Capabilities: nuclino.Capabilities{MoveItems: account.MoveItems},
+				Toolsets:     account.Toolsets,
+				Policy:       policy,
+				Templates:    templateConfig(account),
+				UploadRoot:   account.UploadRoot,
+				Audit:        auditLogger,
+				Metrics:      metrics,
+			},
+		})
+	}
+	nuclinoClient := serverAccounts[0].Client
 
 	// Create MCP server
-	mcpServer := server.NewNuclinoMCPServer(nuclinoClient)
+	mcpServer, err := server.NewNuclinoMCPServerWithAccounts(serverAccounts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create server")
+	}
+	mcpServer.SetMetrics(metrics)
+
+	// Serve metrics and health checks when an admin address is configured
+	var admin *monitoring.AdminServer
+	if settings.AdminAddr != "" {
+		admin = monitoring.NewAdminServer(settings.AdminAddr, metrics)
+		for _, account := range serverAccounts {
+			admin.AddReadinessCheck("nuclino_api:"+account.Name, monitoring.CachedCheck(apiCheck(account.Client), readinessCacheTTL))
+		}
+		if err := admin.Start(); err != nil {
+			log.Fatal().Err(err).Str("addr", settings.AdminAddr).Msg("Failed to start admin server")
+		}
+		log.Info().Str("addr", admin.Addr()).Msg("Admin server listening")
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = admin.Shutdown(shutdownCtx)
+		}()
+	}
 
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Start the recurring page scheduler when configured
+	if settings.SchedulesFile != "" {
+		if settings.ReadOnly {
+			log.Fatal().Msg("The page scheduler cannot run in read-only mode")
+		}
+		jobs, err := scheduler.LoadConfig(settings.SchedulesFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid schedule configuration")
+		}
+		pages := scheduler.New(nuclinoClient, templates.NewLibrary(nuclinoClient, templateConfig(settings)), jobs)
+		if err := pages.Start(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start page scheduler")
+		}
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
@@ -68,7 +231,30 @@ func main() {
 	}()
 
 	// Start server
+	if admin != nil {
+		admin.SetReady(true)
+	}
 	if err := mcpServer.Run(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Server failed")
 	}
 }
+
+// readinessCacheTTL is how long an API readiness result is reused, so
+// frequent probes do not spend the API rate limit
+const readinessCacheTTL = 30 * time.Second
+
+// apiCheck reports whether an account's API key works
+func apiCheck(client nuclino.Client) monitoring.ReadinessCheck {
+	return func(ctx context.Context) error {
+		_, err := client.ListWorkspaces(ctx, 1, 0)
+		return err
+	}
+}
+
+// templateConfig returns the template library settings of a profile
+func templateConfig(profile *config.Profile) templates.Config {
+	templateConfig := templates.DefaultConfig()
+	templateConfig.Dir = profile.TemplatesDir
+	templateConfig.UserID = profile.UserID
+	return templateConfig
+}
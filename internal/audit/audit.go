@@ -0,0 +1,120 @@
+// Package audit keeps a persistent log of tool calls and API mutations, so
+// it can later be reconstructed who asked for which change.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Record kinds
+const (
+	KindTool = "tool"
+	KindAPI  = "api"
+)
+
+// Outcomes
+const (
+	OutcomeSuccess   = "success"
+	OutcomeError     = "error"
+	OutcomeDenied    = "denied"
+	OutcomeConfirm   = "confirmation_required"
+	OutcomeCancelled = "cancelled"
+)
+
+// Record is one line of the audit log
+type Record struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Session *Session  `json:"session,omitempty"`
+	Account string    `json:"account,omitempty"`
+	// Tool is the MCP tool of a tool call record
+	Tool string `json:"tool,omitempty"`
+	// Operation is the client method of an API record, e.g. DeleteItem
+	Operation string                 `json:"operation,omitempty"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+	// Affected lists the IDs of the items, collections, workspaces and
+	// files the call named or created
+	Affected []string `json:"affected,omitempty"`
+	Outcome  string   `json:"outcome"`
+	Error    string   `json:"error,omitempty"`
+	// LatencyMS is how long the call took in milliseconds
+	LatencyMS int64 `json:"latency_ms"`
+}
+
+// Session identifies the MCP connection a call came from
+type Session struct {
+	ID            string `json:"id"`
+	ClientName    string `json:"client_name,omitempty"`
+	ClientVersion string `json:"client_version,omitempty"`
+}
+
+type sessionKey struct{}
+
+// WithSession returns a context whose audit records carry session
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFrom returns the session of ctx, or nil
+func SessionFrom(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey{}).(*Session)
+	return session
+}
+
+// redacted replaces secret argument values
+const redacted = "[REDACTED]"
+
+// maxValueLength is the longest string argument kept whole; longer values,
+// such as page content, are cut
+const maxValueLength = 256
+
+// secretKeys are argument name fragments whose values are never logged
+var secretKeys = []string{"token", "secret", "password", "passwd", "api_key", "apikey", "authorization", "credential"}
+
+// Redact returns a copy of args with secret values replaced and long
+// strings shortened
+func Redact(args map[string]interface{}) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if isSecret(key) {
+			out[key] = redacted
+			continue
+		}
+		out[key] = redactValue(value)
+	}
+	return out
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return Redact(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, entry := range v {
+			out[i] = redactValue(entry)
+		}
+		return out
+	case string:
+		if len(v) > maxValueLength {
+			return fmt.Sprintf("%s… (%d bytes)", strings.ToValidUTF8(v[:maxValueLength], ""), len(v))
+		}
+	}
+	return value
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range secretKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
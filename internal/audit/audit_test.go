@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	args := Redact(map[string]interface{}{
+		"item_id":       "item-1",
+		"confirm_token": "abc",
+		"nested":        map[string]interface{}{"API_KEY": "secret", "keep_id": "item-2"},
+		"content":       strings.Repeat("x", 1000),
+	})
+	assert.Equal(t, "item-1", args["item_id"])
+	assert.Equal(t, redacted, args["confirm_token"])
+	assert.Equal(t, redacted, args["nested"].(map[string]interface{})["API_KEY"])
+	assert.Equal(t, "item-2", args["nested"].(map[string]interface{})["keep_id"])
+	assert.Contains(t, args["content"], "(1000 bytes)")
+	assert.Nil(t, Redact(nil))
+}
+
+func TestLogger_RotateAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	logger, err := NewLogger(Config{Path: path, MaxSize: 300, MaxBackups: 2})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		tool := "nuclino_update_item"
+		if i%2 == 1 {
+			tool = "nuclino_delete_item"
+		}
+		logger.Log(Record{
+			Time:     start.Add(time.Duration(i) * time.Minute),
+			Kind:     KindTool,
+			Tool:     tool,
+			Affected: []string{"item-" + string(rune('a'+i))},
+			Outcome:  OutcomeSuccess,
+		})
+	}
+
+	_, err = os.Stat(path + ".2")
+	require.NoError(t, err)
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "only MaxBackups files are kept")
+
+	all, err := logger.Query(Filter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	assert.Less(t, len(all), 10, "the oldest records were rotated away")
+	assert.Equal(t, "item-j", all[0].Affected[0], "newest first")
+	for i := 1; i < len(all); i++ {
+		assert.True(t, all[i-1].Time.After(all[i].Time))
+	}
+
+	deletes, err := Read(path, Filter{Tool: "nuclino_delete_item", Since: start.Add(5 * time.Minute), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, deletes, 2)
+	assert.Equal(t, "item-j", deletes[0].Affected[0])
+	assert.Equal(t, "item-h", deletes[1].Affected[0])
+
+	byItem, err := Read(path, Filter{ItemID: "item-i"})
+	require.NoError(t, err)
+	require.Len(t, byItem, 1)
+	assert.Equal(t, "nuclino_update_item", byItem[0].Tool)
+
+	_, err = Read(filepath.Join(t.TempDir(), "missing.jsonl"), Filter{})
+	assert.Error(t, err)
+}
+
+func TestLogger_QueryDoesNotWaitForWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := NewLogger(Config{Path: path})
+	require.NoError(t, err)
+	defer logger.Close()
+	logger.Log(Record{Time: time.Now(), Kind: KindTool, Tool: "nuclino_get_item", Outcome: OutcomeSuccess})
+
+	// A writer holding the lock must not block readers
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	done := make(chan error, 1)
+	go func() {
+		_, err := logger.Query(Filter{})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Query waited for the writer lock")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	parsed, err := ParseTime("24h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), parsed)
+
+	parsed, err = ParseTime("7d", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -7), parsed)
+
+	parsed, err = ParseTime("2026-03-01T08:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), parsed)
+
+	_, err = ParseTime("yesterday", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Config configures the audit log file
+type Config struct {
+	// Path is the JSONL file records are appended to
+	Path string
+	// MaxSize is the size in bytes at which the file is rotated
+	MaxSize int64
+	// MaxBackups is how many rotated files are kept, as Path.1 (newest)
+	// to Path.N (oldest)
+	MaxBackups int
+}
+
+// DefaultConfig returns the default audit log settings
+func DefaultConfig() Config {
+	return Config{
+		Path:       DefaultPath(),
+		MaxSize:    10 << 20,
+		MaxBackups: 5,
+	}
+}
+
+// DefaultPath returns where the audit log is kept by default
+func DefaultPath() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "nuclino-mcp", "audit.jsonl")
+}
+
+// Logger appends records to a rotating JSONL file. A nil Logger discards
+// records, so callers need not check whether auditing is enabled.
+type Logger struct {
+	config Config
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+}
+
+// NewLogger opens the audit log, creating its directory. Zero sizes in
+// config fall back to DefaultConfig.
+func NewLogger(config Config) (*Logger, error) {
+	defaults := DefaultConfig()
+	if config.Path == "" {
+		config.Path = defaults.Path
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaults.MaxSize
+	}
+	if config.MaxBackups < 0 {
+		config.MaxBackups = 0
+	}
+
+	l := &Logger{config: config}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Path returns the current log file
+func (l *Logger) Path() string {
+	if l == nil {
+		return ""
+	}
+	return l.config.Path
+}
+
+func (l *Logger) open() error {
+	if err := os.MkdirAll(filepath.Dir(l.config.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(l.config.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// Log appends a record. Failures are logged rather than returned, so
+// auditing never fails the call being audited.
+func (l *Logger) Log(record Record) {
+	if l == nil {
+		return
+	}
+	if err := l.write(record); err != nil {
+		log.Error().Err(err).Str("path", l.config.Path).Msg("Failed to write audit record")
+	}
+}
+
+func (l *Logger) write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.config.MaxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// rotate shifts Path.N-1 to Path.N, ..., Path to Path.1 and starts a new
+// file, dropping the oldest
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+
+	path := l.config.Path
+	if l.config.MaxBackups == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return l.open()
+	}
+	for i := l.config.MaxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(path, i), backupPath(path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(path, backupPath(path, 1)); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+// Close closes the log file
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// Query reads matching records from the log and its backups. It does not
+// take the writer lock, so a slow query never stalls tool calls; Read skips
+// backups that a concurrent rotation removes.
+func (l *Logger) Query(filter Filter) ([]Record, error) {
+	if l == nil {
+		return nil, fmt.Errorf("audit log is disabled")
+	}
+	return Read(l.config.Path, filter)
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
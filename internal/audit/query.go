@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter selects audit records; zero fields match everything
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	// Tool matches the tool of tool records and the operation of API
+	// records
+	Tool string
+	// ItemID matches records that affected this ID
+	ItemID  string
+	Outcome string
+	Kind    string
+	// Limit keeps only the newest records (default: all)
+	Limit int
+}
+
+// Match reports whether a record passes the filter
+func (f Filter) Match(record *Record) bool {
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Time.After(f.Until) {
+		return false
+	}
+	if f.Tool != "" && record.Tool != f.Tool && record.Operation != f.Tool {
+		return false
+	}
+	if f.Outcome != "" && record.Outcome != f.Outcome {
+		return false
+	}
+	if f.Kind != "" && record.Kind != f.Kind {
+		return false
+	}
+	if f.ItemID != "" {
+		found := false
+		for _, id := range record.Affected {
+			if id == f.ItemID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Read returns the records of the log at path and its rotated backups that
+// match filter, newest first. Lines that cannot be parsed are skipped.
+func Read(path string, filter Filter) ([]Record, error) {
+	files, err := logFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, file := range files {
+		matched, err := readFile(file, filter)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Rotated away since the files were listed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, matched...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.After(records[j].Time)
+	})
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records, nil
+}
+
+// logFiles lists the log and its backups, oldest first
+func logFiles(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	backups := make(map[int]string)
+	var numbers []int
+	for _, match := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(match, path+"."))
+		if err != nil || n < 1 {
+			continue
+		}
+		backups[n] = match
+		numbers = append(numbers, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(numbers)))
+
+	var files []string
+	for _, n := range numbers {
+		files = append(files, backups[n])
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	} else if len(files) == 0 {
+		return nil, fmt.Errorf("no audit log at %s", path)
+	}
+	return files, nil
+}
+
+func readFile(path string, filter Filter) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if filter.Match(&record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// ParseTime reads a filter time given as RFC 3339, a date (2006-01-02) or a
+// duration before now such as 24h or 7d
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339, YYYY-MM-DD or a duration such as 24h or 7d", value)
+}
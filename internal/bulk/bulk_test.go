@@ -0,0 +1,347 @@
+package bulk
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// fakeClient keeps items in memory; methods not used here panic through the
+// embedded nil interface
+type fakeClient struct {
+	nuclino.Client
+	mu        sync.Mutex
+	items     map[string]*nuclino.Item
+	order     []string
+	failOn    map[string]error
+	rateLimit int
+	updates   int
+}
+
+func newFakeClient(items ...*nuclino.Item) *fakeClient {
+	f := &fakeClient{items: make(map[string]*nuclino.Item), failOn: make(map[string]error)}
+	for _, item := range items {
+		f.items[item.ID] = item
+		f.order = append(f.order, item.ID)
+	}
+	return f
+}
+
+func (f *fakeClient) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*nuclino.ItemsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &nuclino.ItemsResponse{}
+	for i := offset; i < len(f.order) && i < offset+limit; i++ {
+		item := *f.items[f.order[i]]
+		item.Content = ""
+		resp.Results = append(resp.Results, item)
+	}
+	return resp, nil
+}
+
+func (f *fakeClient) GetItem(ctx context.Context, itemID string) (*nuclino.Item, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item, ok := f.items[itemID]
+	if !ok {
+		return nil, nuclino.NewAPIError(404, "not found")
+	}
+	copied := *item
+	return &copied, nil
+}
+
+func (f *fakeClient) UpdateItem(ctx context.Context, itemID string, req *nuclino.UpdateItemRequest) (*nuclino.Item, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rateLimit > 0 {
+		f.rateLimit--
+		return nil, nuclino.NewAPIError(429, "rate limited")
+	}
+	if err := f.failOn[itemID]; err != nil {
+		delete(f.failOn, itemID)
+		return nil, err
+	}
+	item := f.items[itemID]
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+	if req.Content != nil {
+		item.Content = *req.Content
+	}
+	for key, value := range req.Fields {
+		if item.Fields == nil {
+			item.Fields = make(map[string]interface{})
+		}
+		item.Fields[key] = value
+	}
+	f.updates++
+	return item, nil
+}
+
+// MoveItem moves an item between collections' child lists; any other
+// collectionID puts it at the top level, as a workspace ID does
+func (f *fakeClient) MoveItem(ctx context.Context, itemID, collectionID string) (*nuclino.Item, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item, ok := f.items[itemID]
+	if !ok {
+		return nil, nuclino.NewAPIError(404, "not found")
+	}
+	for _, other := range f.items {
+		for i, childID := range other.ChildIDs {
+			if childID == itemID {
+				other.ChildIDs = append(other.ChildIDs[:i:i], other.ChildIDs[i+1:]...)
+				break
+			}
+		}
+	}
+	if target, ok := f.items[collectionID]; ok {
+		target.ChildIDs = append(target.ChildIDs, itemID)
+	}
+	return item, nil
+}
+
+func (f *fakeClient) parentOf(itemID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, other := range f.items {
+		for _, childID := range other.ChildIDs {
+			if childID == itemID {
+				return other.ID
+			}
+		}
+	}
+	return ""
+}
+
+func (f *fakeClient) DeleteItem(ctx context.Context, itemID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, itemID)
+	return nil
+}
+
+func testItems() []*nuclino.Item {
+	return []*nuclino.Item{
+		{ID: "a", Title: "Draft: Setup", Content: "Use the old API.\n```\nold API\n```\n"},
+		{ID: "b", Title: "Draft: Deploy", Content: "Deploy with the old API."},
+		{ID: "c", Title: "Reference", Content: "Nothing to change."},
+		{ID: "folder", Title: "Folder", Object: nuclino.ObjectCollection, ChildIDs: []string{"a", "b"}},
+	}
+}
+
+func fastExecutor(client nuclino.Client, store Store) *Executor {
+	return NewExecutor(client, store, ExecutorConfig{
+		Concurrency:       2,
+		RequestsPerSecond: 1000,
+		MaxRetries:        2,
+		RetryBackoff:      time.Millisecond,
+	})
+}
+
+func TestReplaceContent_SkipCode(t *testing.T) {
+	re := regexp.MustCompile(regexp.QuoteMeta("old"))
+	content := "old text\n```go\nold code\n```\nmore old\n~~~\nold\n"
+
+	out, count := ReplaceContent(content, re, "new", true)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "new text\n```go\nold code\n```\nmore new\n~~~\nold\n", out)
+
+	out, count = ReplaceContent(content, re, "new", false)
+	assert.Equal(t, 4, count)
+	assert.NotContains(t, out, "old")
+
+	// A shorter fence inside a longer one does not close the block
+	nested := "old\n````md\n```\nold\n```\nold\n````\nold\n"
+	out, count = ReplaceContent(nested, re, "new", true)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "new\n````md\n```\nold\n```\nold\n````\nnew\n", out)
+}
+
+func TestNewOperation_Validates(t *testing.T) {
+	_, err := NewOperation(OperationSpec{Type: OpMove})
+	assert.Error(t, err)
+	_, err = NewOperation(OperationSpec{Type: OpReplace})
+	assert.Error(t, err)
+	_, err = NewOperation(OperationSpec{Type: OpRetitle, Pattern: "("})
+	assert.Error(t, err)
+	_, err = NewOperation(OperationSpec{Type: "archive"})
+	assert.Error(t, err)
+}
+
+func TestPlanner_Plan(t *testing.T) {
+	client := newFakeClient(testItems()...)
+	planner := NewPlanner(client, PlannerConfig{})
+
+	op, err := NewOperation(OperationSpec{Type: OpReplace, Pattern: "old API", Replacement: "new API", SkipCode: true})
+	require.NoError(t, err)
+
+	plan, err := planner.Plan(context.Background(), Selector{WorkspaceID: "ws"}, op)
+	require.NoError(t, err)
+	assert.Equal(t, PlanPlanned, plan.Status)
+	assert.Equal(t, 4, plan.Scanned)
+	require.Len(t, plan.Steps, 2)
+	assert.Equal(t, "a", plan.Steps[0].ItemID)
+	assert.Equal(t, 1, plan.Steps[0].Matches)
+	assert.Contains(t, plan.Steps[0].After.Content, "```\nold API\n```")
+
+	retitle, err := NewOperation(OperationSpec{Type: OpRetitle, Pattern: `^Draft: `})
+	require.NoError(t, err)
+	plan, err = planner.Plan(context.Background(), Selector{ParentID: "folder", Query: "deploy"}, retitle)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 1)
+	assert.Equal(t, "Deploy", plan.Steps[0].After.Title)
+
+	_, err = planner.Plan(context.Background(), Selector{}, retitle)
+	assert.Error(t, err)
+}
+
+func TestExecutor_AppliesAndResumes(t *testing.T) {
+	client := newFakeClient(testItems()...)
+	store := NewMemoryStore()
+	op, err := NewOperation(OperationSpec{Type: OpSetField, Field: "Status", Value: "Done"})
+	require.NoError(t, err)
+	plan, err := NewPlanner(client, PlannerConfig{}).Plan(context.Background(), Selector{ItemIDs: []string{"a", "b", "c"}}, op)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 3)
+
+	client.failOn["b"] = nuclino.NewAPIError(400, "bad request")
+	client.rateLimit = 1
+
+	result, err := fastExecutor(client, store).Execute(context.Background(), plan, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, PlanPartial, result.Status)
+	assert.Equal(t, 2, result.Applied)
+	assert.Equal(t, 1, result.Failed)
+
+	saved, err := store.Load(plan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, PlanPartial, saved.Status)
+
+	result, err = fastExecutor(client, store).Execute(context.Background(), saved, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, PlanCompleted, result.Status)
+	assert.Equal(t, 3, result.Applied)
+	assert.Equal(t, 3, client.updates)
+	assert.Equal(t, "Done", client.items["b"].Fields["Status"])
+}
+
+func TestExecutor_AtomicCompensates(t *testing.T) {
+	client := newFakeClient(testItems()...)
+	op, err := NewOperation(OperationSpec{Type: OpRetitle, Pattern: `^Draft: `})
+	require.NoError(t, err)
+	plan, err := NewPlanner(client, PlannerConfig{}).Plan(context.Background(), Selector{WorkspaceID: "ws"}, op)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 2)
+
+	// Someone edits b after planning
+	client.items["b"].Title = "Draft: Deploy v2"
+
+	executor := NewExecutor(client, nil, ExecutorConfig{Concurrency: 1, RequestsPerSecond: 1000})
+	result, err := executor.Execute(context.Background(), plan, Options{Atomic: true})
+	require.NoError(t, err)
+	assert.Equal(t, PlanRolledBack, result.Status)
+	assert.Equal(t, 1, result.Conflicts)
+	assert.Equal(t, 1, result.Compensated)
+	assert.Equal(t, "Draft: Setup", client.items["a"].Title)
+	assert.Equal(t, "Draft: Deploy v2", client.items["b"].Title)
+}
+
+func TestExecutor_Revert(t *testing.T) {
+	client := newFakeClient(testItems()...)
+	op, err := NewOperation(OperationSpec{Type: OpReplace, Pattern: "old", Replacement: "new"})
+	require.NoError(t, err)
+	plan, err := NewPlanner(client, PlannerConfig{}).Plan(context.Background(), Selector{WorkspaceID: "ws"}, op)
+	require.NoError(t, err)
+
+	executor := fastExecutor(client, nil)
+	_, err = executor.Execute(context.Background(), plan, Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, client.items["a"].Content, "old")
+
+	// Someone edits a after the change was applied
+	edited := client.items["a"].Content + "More notes.\n"
+	client.items["a"].Content = edited
+
+	result, err := executor.Revert(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Equal(t, PlanPartial, result.Status)
+	assert.Equal(t, 1, result.Compensated)
+	assert.Equal(t, 1, result.Conflicts)
+	assert.Equal(t, "Deploy with the old API.", client.items["b"].Content)
+	assert.Equal(t, edited, client.items["a"].Content, "the later edit is kept")
+}
+
+func TestExecutor_RevertMove(t *testing.T) {
+	items := testItems()
+	for _, item := range items {
+		item.WorkspaceID = "ws"
+	}
+	archive := &nuclino.Item{ID: "archive", Title: "Archive", WorkspaceID: "ws", Object: nuclino.ObjectCollection}
+	client := newFakeClient(append(items, archive)...)
+
+	op, err := NewOperation(OperationSpec{Type: OpMove, TargetID: "archive"})
+	require.NoError(t, err)
+	plan, err := NewPlanner(client, PlannerConfig{}).Plan(context.Background(), Selector{ItemIDs: []string{"a", "c"}}, op)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 2)
+	assert.Equal(t, "folder", plan.Steps[0].Before.ParentID)
+	assert.Equal(t, "ws", plan.Steps[1].Before.ParentID, "top-level items move back to the workspace")
+	for _, step := range plan.Steps {
+		assert.True(t, step.Compensable())
+	}
+
+	executor := fastExecutor(client, nil)
+	_, err = executor.Execute(context.Background(), plan, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "archive", client.parentOf("a"))
+	assert.Equal(t, "archive", client.parentOf("c"))
+
+	result, err := executor.Revert(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Compensated)
+	assert.Equal(t, "folder", client.parentOf("a"))
+	assert.Equal(t, "", client.parentOf("c"))
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "plans"))
+
+	plan := &Plan{ID: "abc123", CreatedAt: time.Now(), Status: PlanPlanned,
+		Steps: []*Step{{ItemID: "a", Action: ActionDelete, Status: StepPending}}}
+	require.NoError(t, store.Save(plan))
+
+	loaded, err := store.Load("abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "a", loaded.Steps[0].ItemID)
+
+	plans, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, plans, 1)
+
+	_, err = store.Load("../etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "# Title\none\ntwo old\nthree\nfour\nfive\nsix old\n"
+	after := "# Title\none\ntwo new\nthree\nfour\nfive\nsix new\n"
+
+	diff := UnifiedDiff(before, after, 1)
+	assert.Equal(t, "@@ -2,3 +2,3 @@\n one\n-two old\n+two new\n three\n@@ -6,3 +6,3 @@\n five\n-six old\n+six new\n \n", diff)
+	assert.Empty(t, UnifiedDiff(before, before, 1))
+
+	diff = UnifiedDiff("a\nb\n", "a\nx\ny\nb\n", 0)
+	assert.Equal(t, "@@ -1,0 +2,2 @@\n+x\n+y\n", diff)
+
+	diff = UnifiedDiff("a\nb\nc", "a\nc", 0)
+	assert.Equal(t, "@@ -2,1 +1,0 @@\n-b\n", diff)
+}
@@ -0,0 +1,135 @@
+package bulk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the LCS table; larger inputs are compared line by line
+const maxDiffCells = 4_000_000
+
+type diffLine struct {
+	kind byte // ' ', '-' or '+'
+	text string
+	old  int
+	new  int
+}
+
+// UnifiedDiff returns a line diff of before and after in unified format with
+// the given number of context lines around each change
+func UnifiedDiff(before, after string, context int) string {
+	if before == after {
+		return ""
+	}
+	lines := diffLines(strings.Split(before, "\n"), strings.Split(after, "\n"))
+
+	var out strings.Builder
+	for start := 0; start < len(lines); {
+		// Find the next change
+		for start < len(lines) && lines[start].kind == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context lines
+		end := start
+		for i := start; i < len(lines); i++ {
+			if lines[i].kind != ' ' {
+				end = i
+			} else if i-end > 2*context {
+				break
+			}
+		}
+
+		from := max(start-context, 0)
+		to := min(end+context+1, len(lines))
+		oldCount, newCount := 0, 0
+		for _, line := range lines[from:to] {
+			if line.kind != '+' {
+				oldCount++
+			}
+			if line.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(lines[from].old, oldCount), hunkRange(lines[from].new, newCount))
+		for _, line := range lines[from:to] {
+			out.WriteByte(line.kind)
+			out.WriteString(line.text)
+			out.WriteByte('\n')
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange formats the start and line count of one side of a hunk; an
+// empty side starts at the line before the change, as in diff -u
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// diffLines aligns two line slices with a longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	if len(a)*len(b) > maxDiffCells {
+		return pairwiseLines(a, b)
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, diffLine{'+', b[j], i + 1, j + 1})
+			j++
+		default:
+			lines = append(lines, diffLine{'-', a[i], i + 1, j + 1})
+			i++
+		}
+	}
+	return lines
+}
+
+// pairwiseLines compares lines by position; good enough for replacements that
+// do not add or remove lines
+func pairwiseLines(a, b []string) []diffLine {
+	var lines []diffLine
+	for i := 0; i < max(len(a), len(b)); i++ {
+		switch {
+		case i < len(a) && i < len(b) && a[i] == b[i]:
+			lines = append(lines, diffLine{' ', a[i], i + 1, i + 1})
+		default:
+			if i < len(a) {
+				lines = append(lines, diffLine{'-', a[i], i + 1, i + 1})
+			}
+			if i < len(b) {
+				lines = append(lines, diffLine{'+', b[i], i + 1, i + 1})
+			}
+		}
+	}
+	return lines
+}
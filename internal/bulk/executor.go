@@ -0,0 +1,401 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// ErrConflict is returned when an item changed after the plan was made
+var ErrConflict = errors.New("item changed since the plan was made")
+
+// ErrChangedSinceApplied is returned when an applied step cannot be undone
+// because the item changed again after the step was applied
+var ErrChangedSinceApplied = errors.New("item changed since the step was applied")
+
+// ExecutorConfig holds executor configuration
+type ExecutorConfig struct {
+	Concurrency       int
+	RequestsPerSecond float64
+	MaxRetries        int
+	RetryBackoff      time.Duration
+}
+
+// DefaultExecutorConfig returns the default executor configuration. The rate
+// stays below the client's own limit so bulk runs leave room for other calls.
+func DefaultExecutorConfig() ExecutorConfig {
+	return ExecutorConfig{
+		Concurrency:       4,
+		RequestsPerSecond: 1.5,
+		MaxRetries:        3,
+		RetryBackoff:      time.Second,
+	}
+}
+
+// Options control a single run
+type Options struct {
+	// Atomic compensates every applied step when any step fails
+	Atomic bool
+}
+
+// Result reports the outcome of a run
+type Result struct {
+	PlanID      string     `json:"plan_id"`
+	Status      PlanStatus `json:"status"`
+	Total       int        `json:"total"`
+	Applied     int        `json:"applied"`
+	Failed      int        `json:"failed"`
+	Conflicts   int        `json:"conflicts"`
+	Compensated int        `json:"compensated"`
+	Pending     int        `json:"pending"`
+	Steps       []*Step    `json:"steps"`
+}
+
+// Executor applies plans
+type Executor struct {
+	client  nuclino.Client
+	store   Store
+	config  ExecutorConfig
+	limiter *rate.Limiter
+}
+
+// NewExecutor creates an executor. Progress is saved to store after every
+// step; store may be nil.
+func NewExecutor(client nuclino.Client, store Store, config ExecutorConfig) *Executor {
+	defaults := DefaultExecutorConfig()
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.RequestsPerSecond <= 0 {
+		config.RequestsPerSecond = defaults.RequestsPerSecond
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaults.RetryBackoff
+	}
+	burst := int(config.RequestsPerSecond)
+	if burst < 1 {
+		burst = 1
+	}
+	return &Executor{
+		client:  client,
+		store:   store,
+		config:  config,
+		limiter: rate.NewLimiter(rate.Limit(config.RequestsPerSecond), burst),
+	}
+}
+
+// Execute runs the pending steps of a plan. Steps already applied by an
+// earlier run are skipped, so calling Execute again resumes an interrupted
+// plan. If ctx is cancelled, the remaining steps stay pending.
+func (e *Executor) Execute(ctx context.Context, plan *Plan, opts Options) (*Result, error) {
+	if plan.Status == PlanCompleted || plan.Status == PlanRolledBack {
+		return newResult(plan), nil
+	}
+
+	var todo []*Step
+	for _, step := range plan.Steps {
+		if step.Status == StepPending || step.Status == StepFailed {
+			step.Error = ""
+			todo = append(todo, step)
+		}
+	}
+
+	plan.Status = PlanRunning
+	e.save(plan)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	steps := make(chan *Step)
+	for i := 0; i < e.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for step := range steps {
+				attempts, err := e.apply(runCtx, plan.Operation, step)
+
+				mu.Lock()
+				step.Attempts += attempts
+				switch {
+				case err == nil:
+					step.Status = StepApplied
+				case runCtx.Err() != nil && ctx.Err() == nil && failed:
+					// Cancelled because another step failed in atomic mode
+				case ctx.Err() != nil:
+					// Interrupted; leave the step pending for a resume
+				case errors.Is(err, ErrConflict):
+					step.Status = StepConflict
+					step.Error = err.Error()
+					failed = true
+				default:
+					step.Status = StepFailed
+					step.Error = err.Error()
+					failed = true
+				}
+				if failed && opts.Atomic {
+					cancel()
+				}
+				e.save(plan)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, step := range todo {
+		if runCtx.Err() != nil {
+			break
+		}
+		select {
+		case steps <- step:
+		case <-runCtx.Done():
+		}
+	}
+	close(steps)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		e.save(plan)
+		return newResult(plan), fmt.Errorf("bulk run interrupted, resume plan %s to continue: %w", plan.ID, err)
+	}
+
+	switch {
+	case failed && opts.Atomic:
+		e.compensate(ctx, plan)
+	case failed:
+		plan.Status = PlanPartial
+	default:
+		plan.Status = PlanCompleted
+	}
+	e.save(plan)
+	return newResult(plan), nil
+}
+
+// Revert undoes the applied steps of a plan in reverse order. Deletes cannot
+// be undone and are reported as failed compensations; items edited since a
+// step was applied are left alone and reported as conflicts.
+func (e *Executor) Revert(ctx context.Context, plan *Plan) (*Result, error) {
+	if plan.Count(StepApplied) == 0 {
+		return nil, fmt.Errorf("plan %s has no applied steps to revert", plan.ID)
+	}
+	e.compensate(ctx, plan)
+	e.save(plan)
+	return newResult(plan), nil
+}
+
+// compensate restores the before state of applied steps, newest first
+func (e *Executor) compensate(ctx context.Context, plan *Plan) {
+	clean := true
+	for i := len(plan.Steps) - 1; i >= 0; i-- {
+		step := plan.Steps[i]
+		if step.Status != StepApplied {
+			continue
+		}
+		if !step.Compensable() {
+			step.Status = StepCompensationFailed
+			step.Error = fmt.Sprintf("%s cannot be undone", step.Action)
+			clean = false
+			continue
+		}
+
+		attempts, err := e.undo(ctx, plan.Operation, step)
+		step.Attempts += attempts
+		switch {
+		case err == nil:
+			step.Status = StepCompensated
+		case errors.Is(err, ErrChangedSinceApplied):
+			step.Status = StepConflict
+			step.Error = err.Error()
+			clean = false
+		default:
+			step.Status = StepCompensationFailed
+			step.Error = err.Error()
+			clean = false
+			log.Warn().Err(err).Str("plan", plan.ID).Str("item", step.ItemID).Msg("Failed to compensate bulk step")
+		}
+		e.save(plan)
+	}
+
+	if clean {
+		plan.Status = PlanRolledBack
+	} else {
+		plan.Status = PlanPartial
+	}
+}
+
+// apply performs a single step after checking the item still matches the plan
+// and returns the number of requests made
+func (e *Executor) apply(ctx context.Context, spec OperationSpec, step *Step) (int, error) {
+	total := 0
+	if step.Action != ActionMove {
+		var current *nuclino.Item
+		attempts, err := e.withRetry(ctx, func() error {
+			var err error
+			current, err = e.client.GetItem(ctx, step.ItemID)
+			return err
+		})
+		total += attempts
+		if err != nil {
+			if step.Action == ActionDelete && nuclino.IsNotFound(err) {
+				return total, nil
+			}
+			return total, err
+		}
+		if step.After != nil && stateMatches(spec, current, step.After) {
+			// Already applied by an earlier, interrupted run
+			return total, nil
+		}
+		if !stateMatches(spec, current, step.Before) {
+			return total, ErrConflict
+		}
+	}
+
+	attempts, err := e.withRetry(ctx, func() error {
+		switch step.Action {
+		case ActionMove:
+			_, err := e.client.MoveItem(ctx, step.ItemID, step.TargetID)
+			return err
+		case ActionDelete:
+			return e.client.DeleteItem(ctx, step.ItemID)
+		default:
+			_, err := e.client.UpdateItem(ctx, step.ItemID, updateRequest(spec, step.After))
+			return err
+		}
+	})
+	return total + attempts, err
+}
+
+// undo restores the before state of an applied step after checking the item
+// still holds the state the step left, and returns the number of requests
+// made. Like apply, it cannot check moves.
+func (e *Executor) undo(ctx context.Context, spec OperationSpec, step *Step) (int, error) {
+	total := 0
+	if step.Action != ActionMove {
+		var current *nuclino.Item
+		attempts, err := e.withRetry(ctx, func() error {
+			var err error
+			current, err = e.client.GetItem(ctx, step.ItemID)
+			return err
+		})
+		total += attempts
+		if err != nil {
+			return total, err
+		}
+		if !stateMatches(spec, current, step.After) {
+			if stateMatches(spec, current, step.Before) {
+				// Already undone by an earlier, interrupted revert
+				return total, nil
+			}
+			return total, ErrChangedSinceApplied
+		}
+	}
+
+	attempts, err := e.withRetry(ctx, func() error {
+		if step.Action == ActionMove {
+			_, err := e.client.MoveItem(ctx, step.ItemID, step.Before.ParentID)
+			return err
+		}
+		_, err := e.client.UpdateItem(ctx, step.ItemID, updateRequest(spec, step.Before))
+		return err
+	})
+	return total + attempts, err
+}
+
+// withRetry waits for the rate limiter and retries rate-limited and server
+// errors with exponential backoff. It returns the number of attempts made.
+func (e *Executor) withRetry(ctx context.Context, call func() error) (int, error) {
+	backoff := e.config.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		if err := e.limiter.Wait(ctx); err != nil {
+			return attempt - 1, err
+		}
+		err := call()
+		if err == nil {
+			return attempt, nil
+		}
+		if attempt > e.config.MaxRetries || !(nuclino.IsRateLimited(err) || nuclino.IsServerError(err)) {
+			return attempt, err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		}
+	}
+}
+
+func (e *Executor) save(plan *Plan) {
+	plan.UpdatedAt = time.Now()
+	if e.store == nil {
+		return
+	}
+	if err := e.store.Save(plan); err != nil {
+		log.Warn().Err(err).Str("plan", plan.ID).Msg("Failed to save bulk plan progress")
+	}
+}
+
+// stateMatches reports whether the attributes an operation touches equal state
+func stateMatches(spec OperationSpec, item *nuclino.Item, state *ItemState) bool {
+	if state == nil {
+		return true
+	}
+	switch spec.Type {
+	case OpRetitle:
+		return item.Title == state.Title
+	case OpReplace:
+		return item.Content == state.Content
+	case OpSetField:
+		return reflect.DeepEqual(item.Fields[spec.Field], state.Fields[spec.Field])
+	case OpDelete:
+		return item.Title == state.Title && item.Content == state.Content
+	}
+	return true
+}
+
+// updateRequest builds the update that writes state for an operation
+func updateRequest(spec OperationSpec, state *ItemState) *nuclino.UpdateItemRequest {
+	req := &nuclino.UpdateItemRequest{}
+	switch spec.Type {
+	case OpRetitle:
+		title := state.Title
+		req.Title = &title
+	case OpReplace:
+		content := state.Content
+		req.Content = &content
+	case OpSetField:
+		req.Fields = map[string]interface{}{spec.Field: state.Fields[spec.Field]}
+	}
+	return req
+}
+
+func newResult(plan *Plan) *Result {
+	result := &Result{
+		PlanID:      plan.ID,
+		Status:      plan.Status,
+		Total:       len(plan.Steps),
+		Applied:     plan.Count(StepApplied),
+		Failed:      plan.Count(StepFailed) + plan.Count(StepCompensationFailed),
+		Conflicts:   plan.Count(StepConflict),
+		Compensated: plan.Count(StepCompensated),
+		Pending:     plan.Count(StepPending),
+		Steps:       plan.Steps,
+	}
+	return result
+}
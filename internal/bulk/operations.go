@@ -0,0 +1,213 @@
+package bulk
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/outline"
+)
+
+// Operation types
+const (
+	OpMove     = "move"
+	OpRetitle  = "retitle"
+	OpReplace  = "replace"
+	OpSetField = "set_field"
+	OpDelete   = "delete"
+)
+
+// OperationSpec is the serializable description of an operation, kept with
+// the plan so an interrupted run can be resumed
+type OperationSpec struct {
+	Type        string      `json:"type"`
+	Pattern     string      `json:"pattern,omitempty"`
+	Replacement string      `json:"replacement,omitempty"`
+	Regex       bool        `json:"regex,omitempty"`
+	IgnoreCase  bool        `json:"ignore_case,omitempty"`
+	SkipCode    bool        `json:"skip_code,omitempty"`
+	Field       string      `json:"field,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+	TargetID    string      `json:"target_id,omitempty"`
+}
+
+// Operation turns an item into a plan step
+type Operation interface {
+	Spec() OperationSpec
+	// Apply returns the step for an item, or nil when the item is unchanged
+	Apply(item *nuclino.Item) *Step
+}
+
+// NewOperation validates a spec and builds its operation
+func NewOperation(spec OperationSpec) (Operation, error) {
+	switch spec.Type {
+	case OpMove:
+		if spec.TargetID == "" {
+			return nil, fmt.Errorf("move requires a target collection ID")
+		}
+		return moveOp{spec: spec}, nil
+	case OpRetitle:
+		re, err := compilePattern(spec.Pattern, true, spec.IgnoreCase)
+		if err != nil {
+			return nil, err
+		}
+		return retitleOp{spec: spec, re: re}, nil
+	case OpReplace:
+		re, err := compilePattern(spec.Pattern, spec.Regex, spec.IgnoreCase)
+		if err != nil {
+			return nil, err
+		}
+		return replaceOp{spec: spec, re: re}, nil
+	case OpSetField:
+		if spec.Field == "" {
+			return nil, fmt.Errorf("set_field requires a field name")
+		}
+		return setFieldOp{spec: spec}, nil
+	case OpDelete:
+		return deleteOp{spec: spec}, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q (use move, retitle, replace, set_field or delete)", spec.Type)
+}
+
+func compilePattern(pattern string, regex, ignoreCase bool) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern must not be empty")
+	}
+	if !regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+type moveOp struct{ spec OperationSpec }
+
+func (o moveOp) Spec() OperationSpec { return o.spec }
+
+func (o moveOp) Apply(item *nuclino.Item) *Step {
+	if item.CollectionID == o.spec.TargetID {
+		return nil
+	}
+	return &Step{
+		ItemID:   item.ID,
+		Title:    item.Title,
+		Action:   ActionMove,
+		Before:   &ItemState{ParentID: item.CollectionID},
+		After:    &ItemState{ParentID: o.spec.TargetID},
+		TargetID: o.spec.TargetID,
+	}
+}
+
+type retitleOp struct {
+	spec OperationSpec
+	re   *regexp.Regexp
+}
+
+func (o retitleOp) Spec() OperationSpec { return o.spec }
+
+func (o retitleOp) Apply(item *nuclino.Item) *Step {
+	title := o.re.ReplaceAllString(item.Title, o.spec.Replacement)
+	if title == item.Title {
+		return nil
+	}
+	return updateStep(item, &ItemState{Title: item.Title}, &ItemState{Title: title}, 1)
+}
+
+type replaceOp struct {
+	spec OperationSpec
+	re   *regexp.Regexp
+}
+
+func (o replaceOp) Spec() OperationSpec { return o.spec }
+
+func (o replaceOp) Apply(item *nuclino.Item) *Step {
+	replacement := o.spec.Replacement
+	if !o.spec.Regex {
+		replacement = strings.ReplaceAll(replacement, "$", "$$")
+	}
+	content, count := ReplaceContent(item.Content, o.re, replacement, o.spec.SkipCode)
+	if count == 0 || content == item.Content {
+		return nil
+	}
+	return updateStep(item, &ItemState{Content: item.Content}, &ItemState{Content: content}, count)
+}
+
+type setFieldOp struct{ spec OperationSpec }
+
+func (o setFieldOp) Spec() OperationSpec { return o.spec }
+
+func (o setFieldOp) Apply(item *nuclino.Item) *Step {
+	current, exists := item.Fields[o.spec.Field]
+	if exists && reflect.DeepEqual(current, o.spec.Value) {
+		return nil
+	}
+	return updateStep(item,
+		&ItemState{Fields: map[string]interface{}{o.spec.Field: current}},
+		&ItemState{Fields: map[string]interface{}{o.spec.Field: o.spec.Value}}, 1)
+}
+
+type deleteOp struct{ spec OperationSpec }
+
+func (o deleteOp) Spec() OperationSpec { return o.spec }
+
+func (o deleteOp) Apply(item *nuclino.Item) *Step {
+	return &Step{
+		ItemID: item.ID,
+		Title:  item.Title,
+		Action: ActionDelete,
+		Before: &ItemState{Title: item.Title, Content: item.Content},
+	}
+}
+
+func updateStep(item *nuclino.Item, before, after *ItemState, matches int) *Step {
+	return &Step{
+		ItemID:  item.ID,
+		Title:   item.Title,
+		Action:  ActionUpdate,
+		Before:  before,
+		After:   after,
+		Matches: matches,
+	}
+}
+
+// ReplaceContent replaces every match of re in content and returns the new
+// content with the number of replacements. With skipCode, fenced code blocks
+// (``` or ~~~) are left untouched.
+func ReplaceContent(content string, re *regexp.Regexp, replacement string, skipCode bool) (string, int) {
+	if !skipCode {
+		return re.ReplaceAllString(content, replacement), len(re.FindAllStringIndex(content, -1))
+	}
+
+	var out strings.Builder
+	count := 0
+	segment := func(text string, code bool) {
+		if code {
+			out.WriteString(text)
+			return
+		}
+		count += len(re.FindAllStringIndex(text, -1))
+		out.WriteString(re.ReplaceAllString(text, replacement))
+	}
+
+	var fence outline.Fence
+	var pending strings.Builder
+	inCode := false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if code := fence.Code(line); code != inCode {
+			segment(pending.String(), inCode)
+			pending.Reset()
+			inCode = code
+		}
+		pending.WriteString(line)
+	}
+	segment(pending.String(), inCode)
+	return out.String(), count
+}
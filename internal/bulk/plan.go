@@ -0,0 +1,369 @@
+// Package bulk turns a selection of items and an operation into an explicit
+// plan, then executes it with bounded concurrency, persisted progress and
+// compensation of applied steps on failure.
+package bulk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// Step actions
+const (
+	ActionUpdate = "update"
+	ActionMove   = "move"
+	ActionDelete = "delete"
+)
+
+// StepStatus tracks a step through execution
+type StepStatus string
+
+const (
+	StepPending            StepStatus = "pending"
+	StepApplied            StepStatus = "applied"
+	StepFailed             StepStatus = "failed"
+	StepConflict           StepStatus = "conflict"
+	StepCompensated        StepStatus = "compensated"
+	StepCompensationFailed StepStatus = "compensation_failed"
+)
+
+// PlanStatus tracks a whole plan
+type PlanStatus string
+
+const (
+	PlanPlanned    PlanStatus = "planned"
+	PlanRunning    PlanStatus = "running"
+	PlanCompleted  PlanStatus = "completed"
+	PlanPartial    PlanStatus = "partial"
+	PlanRolledBack PlanStatus = "rolled_back"
+)
+
+// ItemState is the part of an item a step changes
+type ItemState struct {
+	Title    string                 `json:"title,omitempty"`
+	Content  string                 `json:"content,omitempty"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	ParentID string                 `json:"parent_id,omitempty"`
+}
+
+// Step is the change planned for one item
+type Step struct {
+	ItemID   string     `json:"item_id"`
+	Title    string     `json:"title"`
+	Action   string     `json:"action"`
+	Before   *ItemState `json:"before,omitempty"`
+	After    *ItemState `json:"after,omitempty"`
+	TargetID string     `json:"target_id,omitempty"`
+	Matches  int        `json:"matches,omitempty"`
+	Status   StepStatus `json:"status"`
+	Attempts int        `json:"attempts,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Compensable reports whether an applied step can be undone
+func (s *Step) Compensable() bool {
+	switch s.Action {
+	case ActionUpdate:
+		return s.Before != nil
+	case ActionMove:
+		return s.Before != nil && s.Before.ParentID != ""
+	}
+	return false
+}
+
+// Plan is an explicit list of steps produced from a selector and operation
+type Plan struct {
+	ID          string        `json:"id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Selector    Selector      `json:"selector"`
+	Operation   OperationSpec `json:"operation"`
+	Status      PlanStatus    `json:"status"`
+	Steps       []*Step       `json:"steps"`
+	Scanned     int           `json:"items_scanned"`
+	Description string        `json:"description,omitempty"`
+}
+
+// Count returns the number of steps with a status
+func (p *Plan) Count(status StepStatus) int {
+	n := 0
+	for _, step := range p.Steps {
+		if step.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Selector picks the items a plan applies to. Filters combine with AND.
+type Selector struct {
+	WorkspaceID string   `json:"workspace_id,omitempty"`
+	ParentID    string   `json:"parent_id,omitempty"`
+	ItemIDs     []string `json:"item_ids,omitempty"`
+	Query       string   `json:"query,omitempty"`
+	TitleRegex  string   `json:"title_regex,omitempty"`
+	Field       string   `json:"field,omitempty"`
+	FieldValue  string   `json:"field_value,omitempty"`
+}
+
+// Validate checks that the selector names where to look
+func (s Selector) Validate() error {
+	if s.WorkspaceID == "" && s.ParentID == "" && len(s.ItemIDs) == 0 {
+		return fmt.Errorf("selector needs a workspace_id, parent_id or item_ids")
+	}
+	if s.TitleRegex != "" {
+		if _, err := regexp.Compile(s.TitleRegex); err != nil {
+			return fmt.Errorf("invalid title_regex: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s Selector) matches(item *nuclino.Item, titleRe *regexp.Regexp) bool {
+	if s.Query != "" {
+		query := strings.ToLower(s.Query)
+		if !strings.Contains(strings.ToLower(item.Title), query) && !strings.Contains(strings.ToLower(item.Content), query) {
+			return false
+		}
+	}
+	if titleRe != nil && !titleRe.MatchString(item.Title) {
+		return false
+	}
+	if s.Field != "" {
+		value, ok := item.Fields[s.Field]
+		if !ok || fmt.Sprint(value) != s.FieldValue {
+			return false
+		}
+	}
+	return true
+}
+
+// PlannerConfig holds planner configuration
+type PlannerConfig struct {
+	PageSize    int
+	Concurrency int
+	MaxItems    int
+}
+
+// DefaultPlannerConfig returns the default planner configuration
+func DefaultPlannerConfig() PlannerConfig {
+	return PlannerConfig{PageSize: 100, Concurrency: 4, MaxItems: 2000}
+}
+
+// Planner resolves selectors against the API and builds plans
+type Planner struct {
+	client nuclino.Client
+	config PlannerConfig
+}
+
+// NewPlanner creates a planner
+func NewPlanner(client nuclino.Client, config PlannerConfig) *Planner {
+	defaults := DefaultPlannerConfig()
+	if config.PageSize <= 0 {
+		config.PageSize = defaults.PageSize
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.MaxItems <= 0 {
+		config.MaxItems = defaults.MaxItems
+	}
+	return &Planner{client: client, config: config}
+}
+
+// Plan selects items and records the step the operation makes for each.
+// Nothing is written to Nuclino.
+func (p *Planner) Plan(ctx context.Context, selector Selector, op Operation) (*Plan, error) {
+	if err := selector.Validate(); err != nil {
+		return nil, err
+	}
+	var titleRe *regexp.Regexp
+	if selector.TitleRegex != "" {
+		titleRe = regexp.MustCompile(selector.TitleRegex)
+	}
+
+	ids, err := p.candidates(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > p.config.MaxItems {
+		return nil, fmt.Errorf("selector matches %d items, more than the limit of %d; narrow it down", len(ids), p.config.MaxItems)
+	}
+
+	items, err := p.fetch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*nuclino.Item
+	for _, item := range items {
+		if !item.IsCollection() && selector.matches(item, titleRe) {
+			selected = append(selected, item)
+		}
+	}
+	if op.Spec().Type == OpMove {
+		if err := p.setParents(ctx, selected); err != nil {
+			return nil, err
+		}
+	}
+	plan := NewPlan(selector, op, selected)
+	plan.Scanned = len(items)
+	return plan, nil
+}
+
+// NewPlan builds a plan from items that were already selected
+func NewPlan(selector Selector, op Operation, items []*nuclino.Item) *Plan {
+	now := time.Now()
+	plan := &Plan{
+		ID:        newPlanID(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Selector:  selector,
+		Operation: op.Spec(),
+		Status:    PlanPlanned,
+		Steps:     []*Step{},
+		Scanned:   len(items),
+	}
+	for _, item := range items {
+		if step := op.Apply(item); step != nil {
+			step.Status = StepPending
+			plan.Steps = append(plan.Steps, step)
+		}
+	}
+	return plan
+}
+
+// setParents fills in the collection each item sits in, which the API leaves
+// empty, from its workspace tree. Top-level items get the workspace ID, which
+// the move endpoint also accepts, so every move records where to undo it to.
+func (p *Planner) setParents(ctx context.Context, items []*nuclino.Item) error {
+	parents := make(map[string]string)
+	listed := make(map[string]bool)
+	for _, item := range items {
+		if item.CollectionID != "" || item.WorkspaceID == "" {
+			continue
+		}
+		if !listed[item.WorkspaceID] {
+			listed[item.WorkspaceID] = true
+			entries, err := nuclino.ListAllItems(ctx, p.client, item.WorkspaceID, p.config.PageSize)
+			if err != nil {
+				return fmt.Errorf("failed to list workspace items: %w", err)
+			}
+			for _, entry := range entries {
+				for _, childID := range entry.ChildIDs {
+					parents[childID] = entry.ID
+				}
+			}
+		}
+		if parent, ok := parents[item.ID]; ok {
+			item.CollectionID = parent
+		} else {
+			item.CollectionID = item.WorkspaceID
+		}
+	}
+	return nil
+}
+
+// candidates returns the IDs of items under the selector's scope
+func (p *Planner) candidates(ctx context.Context, selector Selector) ([]string, error) {
+	if len(selector.ItemIDs) > 0 {
+		return selector.ItemIDs, nil
+	}
+	if selector.ParentID != "" {
+		return p.subtree(ctx, selector.ParentID)
+	}
+
+	var ids []string
+	err := nuclino.EachItemPage(ctx, p.client, selector.WorkspaceID, p.config.PageSize, func(page []nuclino.Item) bool {
+		for _, item := range page {
+			ids = append(ids, item.ID)
+		}
+		return len(ids) <= p.config.MaxItems
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace items: %w", err)
+	}
+	return ids, nil
+}
+
+// subtree walks childIds below a collection and returns every descendant
+func (p *Planner) subtree(ctx context.Context, rootID string) ([]string, error) {
+	root, err := p.client.GetItem(ctx, rootID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtree root %s: %w", rootID, err)
+	}
+	if !root.IsCollection() {
+		return []string{root.ID}, nil
+	}
+
+	var ids []string
+	queue := append([]string(nil), root.ChildIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		ids = append(ids, id)
+		if len(ids) > p.config.MaxItems {
+			return ids, nil
+		}
+
+		child, err := p.client.GetItem(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item %s: %w", id, err)
+		}
+		queue = append(queue, child.ChildIDs...)
+	}
+	return ids, nil
+}
+
+// fetch loads full items with bounded concurrency, keeping the input order
+func (p *Planner) fetch(ctx context.Context, ids []string) ([]*nuclino.Item, error) {
+	items := make([]*nuclino.Item, len(ids))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, p.config.Concurrency)
+
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item, err := p.client.GetItem(ctx, id)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get item %s: %w", id, err)
+				}
+				mu.Unlock()
+				return
+			}
+			items[i] = item
+		}(i, id)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return items, nil
+}
+
+func newPlanID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("plan-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
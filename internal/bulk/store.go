@@ -0,0 +1,164 @@
+package bulk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Store persists plans so an interrupted run can be resumed
+type Store interface {
+	Save(plan *Plan) error
+	Load(id string) (*Plan, error)
+	List() ([]*Plan, error)
+}
+
+var planIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FileStore keeps one JSON file per plan in a directory
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// DefaultStoreDir returns the directory plans are kept in by default
+func DefaultStoreDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "nuclino-mcp", "bulk")
+}
+
+// NewFileStore creates a file store; the directory is created on first save
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Save writes the plan atomically
+func (s *FileStore) Save(plan *Plan) error {
+	if !planIDPattern.MatchString(plan.ID) {
+		return fmt.Errorf("invalid plan ID %q", plan.ID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create plan directory: %w", err)
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	path := filepath.Join(s.dir, plan.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads a plan by ID
+func (s *FileStore) Load(id string) (*Plan, error) {
+	if !planIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid plan ID %q", id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(s.dir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("plan %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to decode plan %s: %w", id, err)
+	}
+	return &plan, nil
+}
+
+// List returns all stored plans, newest first
+func (s *FileStore) List() ([]*Plan, error) {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*Plan, 0, len(matches))
+	for _, path := range matches {
+		id := filepath.Base(path)
+		plan, err := s.Load(id[:len(id)-len(".json")])
+		if err != nil {
+			continue
+		}
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.After(plans[j].CreatedAt) })
+	return plans, nil
+}
+
+// MemoryStore keeps plans in memory
+type MemoryStore struct {
+	mu    sync.Mutex
+	plans map[string][]byte
+}
+
+// NewMemoryStore creates an in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{plans: make(map[string][]byte)}
+}
+
+// Save stores a copy of the plan
+func (s *MemoryStore) Save(plan *Plan) error {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plans[plan.ID] = data
+	return nil
+}
+
+// Load returns a copy of a stored plan
+func (s *MemoryStore) Load(id string) (*Plan, error) {
+	s.mu.Lock()
+	data, ok := s.plans[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("plan %s not found", id)
+	}
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// List returns all stored plans, newest first
+func (s *MemoryStore) List() ([]*Plan, error) {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.plans))
+	for id := range s.plans {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	plans := make([]*Plan, 0, len(ids))
+	for _, id := range ids {
+		if plan, err := s.Load(id); err == nil {
+			plans = append(plans, plan)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.After(plans[j].CreatedAt) })
+	return plans, nil
+}
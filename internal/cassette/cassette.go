@@ -0,0 +1,294 @@
+// Package cassette records HTTP interactions to files and replays them, so
+// client tests can run against captured API behavior without network
+// access. Secrets such as the Authorization header are scrubbed before
+// anything is written.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Modes
+const (
+	// ModeReplay answers requests from the cassette and never touches the
+	// network
+	ModeReplay = "replay"
+	// ModeRecord sends requests through the base transport and records them
+	ModeRecord = "record"
+)
+
+// Redacted replaces the values of scrubbed headers
+const Redacted = "REDACTED"
+
+// SensitiveHeaders are scrubbed from recorded requests and responses
+var SensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Cassette is the recorded interactions of one file
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one request and the response it got
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body holds a message body. It is written as a string when it is UTF-8
+// text and as {"base64": "..."} otherwise.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("body must be a string or {\"base64\": ...}: %w", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return fmt.Errorf("invalid base64 body: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating its directory
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Transport is an http.RoundTripper that records or replays a cassette
+type Transport struct {
+	mode string
+	path string
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New returns a transport for the cassette at path. ModeReplay loads the
+// file; ModeRecord starts an empty cassette, sends requests through base,
+// or http.DefaultTransport when nil, and writes the file on Save.
+func New(path, mode string, base http.RoundTripper) (*Transport, error) {
+	t := &Transport{mode: mode, path: path, base: base, cassette: &Cassette{}}
+	switch mode {
+	case ModeReplay:
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		t.cassette = c
+		t.used = make([]bool, len(c.Interactions))
+	case ModeRecord:
+		if t.base == nil {
+			t.base = http.DefaultTransport
+		}
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q (use %q or %q)", mode, ModeReplay, ModeRecord)
+	}
+	return t, nil
+}
+
+// Mode returns ModeReplay or ModeRecord
+func (t *Transport) Mode() string {
+	return t.mode
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeRecord {
+		return t.record(req)
+	}
+	return t.replay(req)
+}
+
+// replay answers req with the first unused interaction of the same method
+// and path with query. The host is ignored, so a cassette recorded against
+// the live API replays against any base URL, and repeated requests such as
+// retries get their responses in recorded order.
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// Drain the body like a real transport would
+		_, _ = io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || !matches(interaction.Request, req) {
+			continue
+		}
+		t.used[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("cassette %s: no recorded response for %s %s", filepath.Base(t.path), req.Method, req.URL.RequestURI())
+}
+
+func matches(recorded Request, req *http.Request) bool {
+	if !strings.EqualFold(recorded.Method, req.Method) {
+		return false
+	}
+	recordedReq, err := http.NewRequest(recorded.Method, recorded.URL, nil)
+	if err != nil {
+		return false
+	}
+	return recordedReq.URL.RequestURI() == req.URL.RequestURI()
+}
+
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	header := r.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// record sends req and keeps a scrubbed copy of it and its response
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, &Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: scrub(req.Header),
+			Body:    reqBody,
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: scrub(resp.Header),
+			Body:    respBody,
+		},
+	})
+	return resp, nil
+}
+
+// scrub returns a copy of header with sensitive values redacted
+func scrub(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range SensitiveHeaders {
+		if _, ok := clean[http.CanonicalHeaderKey(name)]; ok {
+			clean.Set(name, Redacted)
+		}
+	}
+	return clean
+}
+
+// Save writes the recorded interactions to the cassette file; it does
+// nothing in replay mode
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cassette.Save(t.path)
+}
+
+// Unused returns the recorded interactions not replayed yet, as
+// "METHOD url", so tests can check they made every expected request
+func (t *Transport) Unused() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var unused []string
+	for i, interaction := range t.cassette.Interactions {
+		if t.mode == ModeReplay && !t.used[i] {
+			unused = append(unused, interaction.Request.Method+" "+interaction.Request.URL)
+		}
+	}
+	return unused
+}
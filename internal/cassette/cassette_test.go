@@ -0,0 +1,84 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		switch r.URL.Path {
+		case "/binary":
+			_, _ = w.Write([]byte{0xff, 0x00, 0xfe})
+		default:
+			body, _ := io.ReadAll(r.Body)
+			_, _ = w.Write([]byte("echo:" + r.URL.RawQuery + ":" + string(body)))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "echo.json")
+	recorder, err := New(path, ModeRecord, nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder}
+
+	send := func(client *http.Client, method, url, body string) (int, string) {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "api-key-123")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	_, first := send(client, http.MethodGet, server.URL+"/items?n=1", "")
+	_, second := send(client, http.MethodPost, server.URL+"/items", `{"title":"x"}`)
+	_, third := send(client, http.MethodGet, server.URL+"/items?n=1", "")
+	_, binary := send(client, http.MethodGet, server.URL+"/binary", "")
+	require.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "api-key-123")
+	assert.NotContains(t, string(data), "session=secret")
+	assert.Contains(t, string(data), Redacted)
+
+	// Replay against another host, and in a different order for the
+	// unrelated request
+	replayer, err := New(path, ModeReplay, nil)
+	require.NoError(t, err)
+	client = &http.Client{Transport: replayer}
+	_, got := send(client, http.MethodPost, "http://api.invalid/items", `{"title":"x"}`)
+	assert.Equal(t, second, got)
+	_, got = send(client, http.MethodGet, "http://api.invalid/items?n=1", "")
+	assert.Equal(t, first, got)
+	assert.Equal(t, []string{"GET " + server.URL + "/items?n=1", "GET " + server.URL + "/binary"}, replayer.Unused())
+	_, got = send(client, http.MethodGet, "http://api.invalid/items?n=1", "")
+	assert.Equal(t, third, got)
+	_, got = send(client, http.MethodGet, "http://api.invalid/binary", "")
+	assert.Equal(t, binary, got)
+	assert.Empty(t, replayer.Unused())
+
+	_, err = client.Get("http://api.invalid/items?n=1")
+	require.Error(t, err, "each interaction replays once")
+	assert.Contains(t, err.Error(), "no recorded response for GET /items?n=1")
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	assert.Error(t, err)
+	_, err = New("x.json", "rewind", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,776 @@
+// Package config loads the server configuration: a YAML or TOML file with
+// named profiles, each describing one Nuclino account and how the server
+// talks to it. Settings are layered as defaults < file < environment <
+// flags, and the selected profile is validated before the server starts.
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+	"github.com/lukasz/nuclino-mcp-server/internal/cache"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
+)
+
+// Cache backends
+const (
+	CacheNone   = "none"
+	CacheMemory = "memory"
+)
+
+// AuditNone as the audit log file disables auditing
+const AuditNone = "none"
+
+// DefaultProfileName is used when neither the file nor the environment
+// selects a profile
+const DefaultProfileName = "default"
+
+// AllAccounts as the only account name serves every profile of the file
+const AllAccounts = "*"
+
+// apiKeyCommandTimeout bounds api_key_command, which may prompt a keychain
+const apiKeyCommandTimeout = 30 * time.Second
+
+// Duration is a time.Duration written as a string such as "30s" or "5m"
+type Duration time.Duration
+
+// UnmarshalText parses a duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// RateLimit limits the requests sent to the API
+type RateLimit struct {
+	RPS   float64 `yaml:"rps" toml:"rps"`
+	Burst int     `yaml:"burst" toml:"burst"`
+}
+
+// Retries controls retries of failed requests
+type Retries struct {
+	// Count is the number of retries after 429 and 5xx responses; nil keeps
+	// the default and 0 disables retries
+	Count *int     `yaml:"count" toml:"count"`
+	Delay Duration `yaml:"delay" toml:"delay"`
+}
+
+// Cache configures the read cache in front of the API
+type Cache struct {
+	// Backend is "none" (default) or "memory"
+	Backend string   `yaml:"backend" toml:"backend"`
+	TTL     Duration `yaml:"ttl" toml:"ttl"`
+	MaxSize int      `yaml:"max_size" toml:"max_size"`
+}
+
+// Audit configures the audit log of tool calls and API changes. The log is
+// shared by all accounts, so only the selected profile's settings are used.
+type Audit struct {
+	// File is the JSONL log; "none" disables auditing (default:
+	// audit.DefaultPath)
+	File string `yaml:"file" toml:"file"`
+	// MaxSizeMB is the size at which the file is rotated
+	MaxSizeMB int `yaml:"max_size_mb" toml:"max_size_mb"`
+	// MaxBackups is how many rotated files are kept; nil keeps the default
+	MaxBackups *int `yaml:"max_backups" toml:"max_backups"`
+}
+
+// Tracing configures OpenTelemetry spans of MCP requests, tool calls and API
+// requests. Only the selected profile's settings are used.
+type Tracing struct {
+	// Exporter is none, otlp or file (default: none)
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Endpoint is the OTLP/HTTP endpoint, e.g. http://localhost:4318;
+	// empty uses OTEL_EXPORTER_OTLP_ENDPOINT
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	// File receives JSON spans with the file exporter (default:
+	// tracing.DefaultFilePath)
+	File string `yaml:"file" toml:"file"`
+	// SampleRatio is the share of traces kept (default: 1)
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// Policy hides and blocks tools; see tools.Policy
+type Policy struct {
+	// Allow and Deny are tool name patterns such as "nuclino_delete_*"
+	Allow []string `yaml:"allow" toml:"allow"`
+	Deny  []string `yaml:"deny" toml:"deny"`
+	// Categories are the allowed tool categories: read, write, destructive
+	Categories []string `yaml:"categories" toml:"categories"`
+	// Workspaces is an allowlist of workspace IDs
+	Workspaces []string `yaml:"workspaces" toml:"workspaces"`
+}
+
+// Profile holds the settings of one Nuclino account
+type Profile struct {
+	Name string `yaml:"-" toml:"-"`
+
+	// APIKey is the key itself. Prefer one of the indirect sources below,
+	// tried in order when APIKey is empty.
+	APIKey string `yaml:"api_key" toml:"api_key"`
+	// APIKeyEnv names an environment variable holding the key
+	APIKeyEnv string `yaml:"api_key_env" toml:"api_key_env"`
+	// APIKeyFile is a file holding the key; a leading ~/ is expanded
+	APIKeyFile string `yaml:"api_key_file" toml:"api_key_file"`
+	// APIKeyCommand is run with sh -c and its output used as the key, e.g.
+	// "security find-generic-password -s nuclino -w" or "pass show nuclino"
+	APIKeyCommand string `yaml:"api_key_command" toml:"api_key_command"`
+
+	BaseURL   string    `yaml:"base_url" toml:"base_url"`
+	Timeout   Duration  `yaml:"timeout" toml:"timeout"`
+	RateLimit RateLimit `yaml:"rate_limit" toml:"rate_limit"`
+	Retries   Retries   `yaml:"retries" toml:"retries"`
+	Cache     Cache     `yaml:"cache" toml:"cache"`
+	Audit     Audit     `yaml:"audit" toml:"audit"`
+	Tracing   Tracing   `yaml:"tracing" toml:"tracing"`
+
+	// Toolsets limits the registered tools to these groups; empty enables
+	// every toolset
+	Toolsets []string `yaml:"toolsets" toml:"toolsets"`
+	// ReadOnly only exposes tools that do not change Nuclino content
+	ReadOnly bool `yaml:"read_only" toml:"read_only"`
+	// MoveItems enables the tools that move items between collections, for
+	// accounts whose API has the move endpoint. It is off by default since
+	// detecting the endpoint would take a move request.
+	MoveItems bool `yaml:"move_items" toml:"move_items"`
+	// Policy hides and blocks tools by name, category and workspace
+	Policy Policy `yaml:"policy" toml:"policy"`
+
+	TemplatesDir  string `yaml:"templates_dir" toml:"templates_dir"`
+	UserID        string `yaml:"user_id" toml:"user_id"`
+	SchedulesFile string `yaml:"schedules_file" toml:"schedules_file"`
+	// AdminAddr is the address of the /metrics, /healthz and /readyz
+	// listener, e.g. 127.0.0.1:9090; empty disables it
+	AdminAddr string `yaml:"admin_addr" toml:"admin_addr"`
+	// UploadRoot is the directory nuclino_upload_file may read local_path
+	// files from; empty disables local_path, so tools cannot read files of
+	// the machine the server runs on
+	UploadRoot string `yaml:"upload_root" toml:"upload_root"`
+}
+
+// Config is the configuration file
+type Config struct {
+	DefaultProfile string              `yaml:"default_profile" toml:"default_profile"`
+	Profiles       map[string]*Profile `yaml:"profiles" toml:"profiles"`
+	// Accounts names the profiles served next to the selected one, each as
+	// a separate Nuclino account; ["*"] serves every profile
+	Accounts []string `yaml:"accounts" toml:"accounts"`
+}
+
+// Overrides are settings taken from flags or environment variables. Zero
+// values leave the profile's setting unchanged.
+type Overrides struct {
+	Profile       string
+	Accounts      []string
+	APIKey        string
+	BaseURL       string
+	RPS           float64
+	Timeout       time.Duration
+	Cache         string
+	Toolsets      []string
+	ReadOnly      *bool
+	TemplatesDir  string
+	UserID        string
+	UploadRoot    string
+	SchedulesFile string
+	AuditLog      string
+	AdminAddr     string
+	Tracing       string
+	TraceFile     string
+}
+
+// DefaultPath returns the first existing config.yaml, config.yml or
+// config.toml in the user config directory's nuclino-mcp folder, or ""
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		path := filepath.Join(dir, "nuclino-mcp", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// ReadFile parses a configuration file; the format follows the extension
+// (.toml, otherwise YAML)
+func ReadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config Config
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		// Unknown keys are rejected like in YAML, so a typo cannot leave a
+		// setting at its default
+		var md toml.MetaData
+		if md, err = toml.Decode(string(data), &config); err == nil {
+			if undecoded := md.Undecoded(); len(undecoded) > 0 {
+				keys := make([]string, len(undecoded))
+				for i, key := range undecoded {
+					keys[i] = key.String()
+				}
+				err = fmt.Errorf("unknown keys %s", strings.Join(keys, ", "))
+			}
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&config); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	for name, profile := range config.Profiles {
+		if profile == nil {
+			profile = &Profile{}
+			config.Profiles[name] = profile
+		}
+		profile.Name = name
+	}
+	return &config, nil
+}
+
+// Profile returns a copy of the named profile. An empty name selects the
+// file's default_profile, its only profile, or "default". A configuration
+// without profiles yields an empty default profile, so the server still
+// runs from the environment alone.
+func (c *Config) Profile(name string) (*Profile, error) {
+	explicit := name != ""
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" && len(c.Profiles) == 1 {
+		for only := range c.Profiles {
+			name = only
+		}
+	}
+	if name == "" {
+		name = DefaultProfileName
+	}
+
+	if profile, ok := c.Profiles[name]; ok {
+		copied := *profile
+		copied.Toolsets = append([]string(nil), profile.Toolsets...)
+		return &copied, nil
+	}
+	if len(c.Profiles) == 0 && !explicit {
+		return &Profile{Name: name}, nil
+	}
+	return nil, fmt.Errorf("profile %q not found; available: %s", name, strings.Join(c.ProfileNames(), ", "))
+}
+
+// ProfileNames returns the profile names, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load resolves the profile the server runs with. path may be empty to use
+// NUCLINO_CONFIG or DefaultPath; a missing default file is not an error.
+// Environment overrides apply on top of the file and flags on top of the
+// environment, then the API key is resolved and the profile validated.
+func Load(ctx context.Context, path string, flags Overrides) (*Profile, error) {
+	profiles, err := load(ctx, path, flags, false)
+	if err != nil {
+		return nil, err
+	}
+	return profiles[0], nil
+}
+
+// LoadAccounts resolves the selected profile, as Load does, followed by the
+// other profiles named in the file's accounts list, NUCLINO_ACCOUNTS or the
+// Accounts flag. Overrides only apply to the selected profile; each other
+// account runs with its profile's settings alone.
+func LoadAccounts(ctx context.Context, path string, flags Overrides) ([]*Profile, error) {
+	return load(ctx, path, flags, true)
+}
+
+func load(ctx context.Context, path string, flags Overrides, accounts bool) ([]*Profile, error) {
+	env, err := EnvOverrides()
+	if err != nil {
+		return nil, err
+	}
+	overrides := env.Merge(flags)
+
+	if path == "" {
+		path = os.Getenv("NUCLINO_CONFIG")
+	}
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	config := &Config{}
+	if path != "" {
+		if config, err = ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	primary, err := config.Profile(overrides.Profile)
+	if err != nil {
+		return nil, err
+	}
+	overrides.Apply(primary)
+	if err := primary.resolve(ctx); err != nil {
+		return nil, err
+	}
+	profiles := []*Profile{primary}
+	if !accounts {
+		return profiles, nil
+	}
+
+	names := config.Accounts
+	if len(overrides.Accounts) > 0 {
+		names = overrides.Accounts
+	}
+	if len(names) == 1 && names[0] == AllAccounts {
+		names = config.ProfileNames()
+	}
+	seen := map[string]bool{primary.Name: true}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if _, ok := config.Profiles[name]; !ok {
+			return nil, fmt.Errorf("account %q has no profile; available: %s", name, strings.Join(config.ProfileNames(), ", "))
+		}
+		account, err := config.Profile(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := account.resolve(ctx); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, account)
+	}
+	return profiles, nil
+}
+
+// resolve applies defaults, resolves the API key and validates the profile
+func (p *Profile) resolve(ctx context.Context) error {
+	p.SetDefaults()
+	p.TemplatesDir = expandHome(p.TemplatesDir)
+	p.UploadRoot = expandHome(p.UploadRoot)
+	p.SchedulesFile = expandHome(p.SchedulesFile)
+	if p.Audit.File != AuditNone {
+		p.Audit.File = expandHome(p.Audit.File)
+	}
+	p.Tracing.File = expandHome(p.Tracing.File)
+
+	if err := p.ResolveAPIKey(ctx); err != nil {
+		return err
+	}
+	return p.Validate()
+}
+
+// EnvOverrides reads NUCLINO_PROFILE, NUCLINO_ACCOUNTS, NUCLINO_API_KEY, NUCLINO_BASE_URL,
+// NUCLINO_RATE_LIMIT, NUCLINO_TIMEOUT, NUCLINO_CACHE, NUCLINO_TOOLSETS,
+// NUCLINO_READ_ONLY, NUCLINO_TEMPLATES_DIR, NUCLINO_USER_ID,
+// NUCLINO_UPLOAD_ROOT, NUCLINO_SCHEDULES_FILE, NUCLINO_AUDIT_LOG, NUCLINO_ADMIN_ADDR,
+// NUCLINO_TRACING and NUCLINO_TRACE_FILE
+func EnvOverrides() (Overrides, error) {
+	overrides := Overrides{
+		Profile:       os.Getenv("NUCLINO_PROFILE"),
+		Accounts:      SplitList(os.Getenv("NUCLINO_ACCOUNTS")),
+		APIKey:        os.Getenv("NUCLINO_API_KEY"),
+		BaseURL:       os.Getenv("NUCLINO_BASE_URL"),
+		Cache:         os.Getenv("NUCLINO_CACHE"),
+		Toolsets:      SplitList(os.Getenv("NUCLINO_TOOLSETS")),
+		TemplatesDir:  os.Getenv("NUCLINO_TEMPLATES_DIR"),
+		UserID:        os.Getenv("NUCLINO_USER_ID"),
+		UploadRoot:    os.Getenv("NUCLINO_UPLOAD_ROOT"),
+		SchedulesFile: os.Getenv("NUCLINO_SCHEDULES_FILE"),
+		AuditLog:      os.Getenv("NUCLINO_AUDIT_LOG"),
+		AdminAddr:     os.Getenv("NUCLINO_ADMIN_ADDR"),
+		Tracing:       os.Getenv("NUCLINO_TRACING"),
+		TraceFile:     os.Getenv("NUCLINO_TRACE_FILE"),
+	}
+
+	if value := os.Getenv("NUCLINO_RATE_LIMIT"); value != "" {
+		rps, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return overrides, fmt.Errorf("invalid NUCLINO_RATE_LIMIT %q: %w", value, err)
+		}
+		overrides.RPS = rps
+	}
+	if value := os.Getenv("NUCLINO_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return overrides, fmt.Errorf("invalid NUCLINO_TIMEOUT %q: %w", value, err)
+		}
+		overrides.Timeout = timeout
+	}
+	if value := os.Getenv("NUCLINO_READ_ONLY"); value != "" {
+		readOnly, err := parseBool(value)
+		if err != nil {
+			return overrides, fmt.Errorf("invalid NUCLINO_READ_ONLY %q: %w", value, err)
+		}
+		overrides.ReadOnly = &readOnly
+	}
+	return overrides, nil
+}
+
+// Merge returns o with every setting that is set in other replaced
+func (o Overrides) Merge(other Overrides) Overrides {
+	merged := o
+	setString := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	setString(&merged.Profile, other.Profile)
+	setString(&merged.APIKey, other.APIKey)
+	setString(&merged.BaseURL, other.BaseURL)
+	setString(&merged.Cache, other.Cache)
+	setString(&merged.TemplatesDir, other.TemplatesDir)
+	setString(&merged.UserID, other.UserID)
+	setString(&merged.UploadRoot, other.UploadRoot)
+	setString(&merged.SchedulesFile, other.SchedulesFile)
+	setString(&merged.AuditLog, other.AuditLog)
+	setString(&merged.AdminAddr, other.AdminAddr)
+	setString(&merged.Tracing, other.Tracing)
+	setString(&merged.TraceFile, other.TraceFile)
+	if other.RPS > 0 {
+		merged.RPS = other.RPS
+	}
+	if other.Timeout > 0 {
+		merged.Timeout = other.Timeout
+	}
+	if len(other.Accounts) > 0 {
+		merged.Accounts = other.Accounts
+	}
+	if len(other.Toolsets) > 0 {
+		merged.Toolsets = other.Toolsets
+	}
+	if other.ReadOnly != nil {
+		merged.ReadOnly = other.ReadOnly
+	}
+	return merged
+}
+
+// Apply writes the overrides that are set into profile
+func (o Overrides) Apply(profile *Profile) {
+	if o.APIKey != "" {
+		profile.APIKey = o.APIKey
+	}
+	if o.BaseURL != "" {
+		profile.BaseURL = o.BaseURL
+	}
+	if o.RPS > 0 {
+		profile.RateLimit.RPS = o.RPS
+	}
+	if o.Timeout > 0 {
+		profile.Timeout = Duration(o.Timeout)
+	}
+	if o.Cache != "" {
+		profile.Cache.Backend = o.Cache
+	}
+	if len(o.Toolsets) > 0 {
+		profile.Toolsets = o.Toolsets
+	}
+	if o.ReadOnly != nil {
+		profile.ReadOnly = *o.ReadOnly
+	}
+	if o.TemplatesDir != "" {
+		profile.TemplatesDir = o.TemplatesDir
+	}
+	if o.UserID != "" {
+		profile.UserID = o.UserID
+	}
+	if o.UploadRoot != "" {
+		profile.UploadRoot = o.UploadRoot
+	}
+	if o.SchedulesFile != "" {
+		profile.SchedulesFile = o.SchedulesFile
+	}
+	if o.AuditLog != "" {
+		profile.Audit.File = o.AuditLog
+	}
+	if o.AdminAddr != "" {
+		profile.AdminAddr = o.AdminAddr
+	}
+	if o.Tracing != "" {
+		profile.Tracing.Exporter = o.Tracing
+	}
+	if o.TraceFile != "" {
+		profile.Tracing.File = o.TraceFile
+		if o.Tracing == "" {
+			profile.Tracing.Exporter = tracing.ExporterFile
+		}
+	}
+}
+
+// SetDefaults fills unset settings with the client defaults
+func (p *Profile) SetDefaults() {
+	defaults := nuclino.DefaultClientConfig()
+	if p.BaseURL == "" {
+		p.BaseURL = defaults.BaseURL
+	}
+	if p.Timeout == 0 {
+		p.Timeout = Duration(defaults.Timeout)
+	}
+	if p.RateLimit.RPS == 0 {
+		p.RateLimit.RPS = defaults.RateLimit
+	}
+	if p.RateLimit.Burst == 0 {
+		p.RateLimit.Burst = defaults.RateBurst
+	}
+	if p.Retries.Count == nil {
+		count := defaults.RetryCount
+		p.Retries.Count = &count
+	}
+	if p.Retries.Delay == 0 {
+		p.Retries.Delay = Duration(defaults.RetryDelay)
+	}
+	if p.Cache.Backend == "" {
+		p.Cache.Backend = CacheNone
+	}
+	cacheDefaults := cache.DefaultCacheConfig()
+	if p.Cache.TTL == 0 {
+		p.Cache.TTL = Duration(cacheDefaults.DefaultTTL)
+	}
+	if p.Cache.MaxSize == 0 {
+		p.Cache.MaxSize = cacheDefaults.MaxSize
+	}
+	auditDefaults := audit.DefaultConfig()
+	if p.Audit.File == "" {
+		p.Audit.File = auditDefaults.Path
+	}
+	if p.Audit.MaxSizeMB == 0 {
+		p.Audit.MaxSizeMB = int(auditDefaults.MaxSize >> 20)
+	}
+	if p.Audit.MaxBackups == nil {
+		backups := auditDefaults.MaxBackups
+		p.Audit.MaxBackups = &backups
+	}
+	if p.Tracing.Exporter == "" {
+		p.Tracing.Exporter = tracing.ExporterNone
+	}
+	if p.Tracing.SampleRatio == 0 {
+		p.Tracing.SampleRatio = 1
+	}
+}
+
+// ResolveAPIKey fills APIKey from api_key_env, api_key_file or
+// api_key_command, in that order, unless it is already set
+func (p *Profile) ResolveAPIKey(ctx context.Context) error {
+	switch {
+	case p.APIKey != "":
+		return nil
+	case p.APIKeyEnv != "":
+		p.APIKey = os.Getenv(p.APIKeyEnv)
+		if p.APIKey == "" {
+			return fmt.Errorf("profile %q: environment variable %s is empty", p.Name, p.APIKeyEnv)
+		}
+	case p.APIKeyFile != "":
+		data, err := os.ReadFile(expandHome(p.APIKeyFile))
+		if err != nil {
+			return fmt.Errorf("profile %q: failed to read API key file: %w", p.Name, err)
+		}
+		p.APIKey = strings.TrimSpace(string(data))
+	case p.APIKeyCommand != "":
+		ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+		defer cancel()
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "sh", "-c", p.APIKeyCommand)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("profile %q: API key command failed: %w: %s", p.Name, err, strings.TrimSpace(stderr.String()))
+		}
+		p.APIKey = strings.TrimSpace(string(output))
+	}
+	return nil
+}
+
+// Validate checks the profile after defaults and overrides are applied
+func (p *Profile) Validate() error {
+	if p.APIKey == "" {
+		return fmt.Errorf("profile %q: no API key; set NUCLINO_API_KEY or one of api_key, api_key_env, api_key_file, api_key_command", p.Name)
+	}
+	if strings.ContainsAny(p.APIKey, "\r\n") {
+		return fmt.Errorf("profile %q: API key contains a line break", p.Name)
+	}
+
+	parsed, err := url.Parse(p.BaseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("profile %q: base_url %q must be an http(s) URL", p.Name, p.BaseURL)
+	}
+	if p.Timeout <= 0 {
+		return fmt.Errorf("profile %q: timeout must be positive", p.Name)
+	}
+	if p.RateLimit.RPS <= 0 {
+		return fmt.Errorf("profile %q: rate_limit.rps must be positive", p.Name)
+	}
+	if p.RateLimit.Burst < 1 {
+		return fmt.Errorf("profile %q: rate_limit.burst must be at least 1", p.Name)
+	}
+	if p.Retries.Count != nil && *p.Retries.Count < 0 {
+		return fmt.Errorf("profile %q: retries.count must not be negative", p.Name)
+	}
+	switch p.Cache.Backend {
+	case CacheNone, CacheMemory:
+	default:
+		return fmt.Errorf("profile %q: unknown cache backend %q (use %q or %q)", p.Name, p.Cache.Backend, CacheNone, CacheMemory)
+	}
+	if p.Cache.TTL < 0 || p.Cache.MaxSize < 0 {
+		return fmt.Errorf("profile %q: cache ttl and max_size must not be negative", p.Name)
+	}
+	if p.Audit.MaxSizeMB < 0 || (p.Audit.MaxBackups != nil && *p.Audit.MaxBackups < 0) {
+		return fmt.Errorf("profile %q: audit max_size_mb and max_backups must not be negative", p.Name)
+	}
+	switch p.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterFile:
+	default:
+		return fmt.Errorf("profile %q: unknown tracing exporter %q (use %q, %q or %q)", p.Name, p.Tracing.Exporter,
+			tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterFile)
+	}
+	if p.Tracing.SampleRatio < 0 || p.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("profile %q: tracing sample_ratio must be between 0 and 1", p.Name)
+	}
+	if p.UploadRoot != "" {
+		if info, err := os.Stat(p.UploadRoot); err != nil || !info.IsDir() {
+			return fmt.Errorf("profile %q: upload_root %q must be a directory", p.Name, p.UploadRoot)
+		}
+	}
+	if p.AdminAddr != "" {
+		if _, _, err := net.SplitHostPort(p.AdminAddr); err != nil {
+			return fmt.Errorf("profile %q: admin_addr %q must be host:port", p.Name, p.AdminAddr)
+		}
+	}
+	return nil
+}
+
+// AuditConfig returns the audit log settings, or false when auditing is
+// disabled
+func (p *Profile) AuditConfig() (audit.Config, bool) {
+	if p.Audit.File == AuditNone {
+		return audit.Config{}, false
+	}
+	config := audit.DefaultConfig()
+	if p.Audit.File != "" {
+		config.Path = p.Audit.File
+	}
+	if p.Audit.MaxSizeMB > 0 {
+		config.MaxSize = int64(p.Audit.MaxSizeMB) << 20
+	}
+	if p.Audit.MaxBackups != nil {
+		config.MaxBackups = *p.Audit.MaxBackups
+	}
+	return config, true
+}
+
+// TracingConfig returns the tracing settings of the profile
+func (p *Profile) TracingConfig() tracing.Config {
+	return tracing.Config{
+		Exporter:    p.Tracing.Exporter,
+		Endpoint:    p.Tracing.Endpoint,
+		File:        p.Tracing.File,
+		SampleRatio: p.Tracing.SampleRatio,
+	}
+}
+
+// ClientConfig returns the client settings of the profile
+func (p *Profile) ClientConfig() nuclino.ClientConfig {
+	config := nuclino.ClientConfig{
+		APIKey:     p.APIKey,
+		BaseURL:    p.BaseURL,
+		Timeout:    time.Duration(p.Timeout),
+		RateLimit:  p.RateLimit.RPS,
+		RateBurst:  p.RateLimit.Burst,
+		RetryCount: nuclino.DefaultClientConfig().RetryCount,
+		RetryDelay: time.Duration(p.Retries.Delay),
+	}
+	if p.Retries.Count != nil {
+		config.RetryCount = *p.Retries.Count
+	}
+	return config
+}
+
+// NewClient creates the profile's client, behind a read cache when the
+// memory backend is configured. metrics, if not nil, records every API
+// request.
+func (p *Profile) NewClient(metrics nuclino.RequestRecorder) nuclino.Client {
+	clientConfig := p.ClientConfig()
+	clientConfig.Metrics = metrics
+	client := nuclino.NewClientFromConfig(clientConfig)
+	if p.Cache.Backend != CacheMemory {
+		return client
+	}
+	return nuclino.NewCachingClient(client, cache.CacheConfig{
+		MaxSize:    p.Cache.MaxSize,
+		DefaultTTL: time.Duration(p.Cache.TTL),
+	})
+}
+
+// SplitList splits a comma-separated list, dropping empty entries
+func SplitList(value string) []string {
+	var list []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true, nil
+	case "0", "false", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("not a boolean")
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
+)
+
+// clearEnv unsets every variable EnvOverrides reads for the test's duration
+func clearEnv(t *testing.T) {
+	for _, name := range []string{
+		"NUCLINO_CONFIG", "NUCLINO_PROFILE", "NUCLINO_ACCOUNTS", "NUCLINO_API_KEY", "NUCLINO_BASE_URL",
+		"NUCLINO_RATE_LIMIT", "NUCLINO_TIMEOUT", "NUCLINO_CACHE", "NUCLINO_TOOLSETS",
+		"NUCLINO_READ_ONLY", "NUCLINO_TEMPLATES_DIR", "NUCLINO_USER_ID", "NUCLINO_SCHEDULES_FILE",
+		"NUCLINO_UPLOAD_ROOT", "NUCLINO_AUDIT_LOG", "NUCLINO_ADMIN_ADDR", "NUCLINO_TRACING", "NUCLINO_TRACE_FILE",
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const yamlConfig = `default_profile: work
+profiles:
+  work:
+    api_key_env: WORK_NUCLINO_KEY
+    timeout: 10s
+    rate_limit:
+      rps: 2.5
+      burst: 5
+    retries:
+      count: 0
+    cache:
+      backend: memory
+      ttl: 1m
+    toolsets: [items, workspaces]
+    move_items: true
+  personal:
+    api_key_command: echo personal-key
+    read_only: true
+`
+
+func TestLoad_YAMLProfiles(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("WORK_NUCLINO_KEY", "work-key")
+	path := writeFile(t, "config.yaml", yamlConfig)
+
+	profile, err := Load(context.Background(), path, Overrides{})
+	require.NoError(t, err)
+	assert.Equal(t, "work", profile.Name)
+	assert.Equal(t, "work-key", profile.APIKey)
+	assert.Equal(t, "https://api.nuclino.com", profile.BaseURL)
+	assert.Equal(t, []string{"items", "workspaces"}, profile.Toolsets)
+	assert.True(t, profile.MoveItems)
+
+	client := profile.ClientConfig()
+	assert.Equal(t, 10*time.Second, client.Timeout)
+	assert.Equal(t, 2.5, client.RateLimit)
+	assert.Equal(t, 5, client.RateBurst)
+	assert.Equal(t, 0, client.RetryCount)
+	assert.Equal(t, CacheMemory, profile.Cache.Backend)
+	assert.Equal(t, Duration(time.Minute), profile.Cache.TTL)
+
+	profile, err = Load(context.Background(), path, Overrides{Profile: "personal"})
+	require.NoError(t, err)
+	assert.Equal(t, "personal-key", profile.APIKey)
+	assert.True(t, profile.ReadOnly)
+	assert.False(t, profile.MoveItems)
+	assert.Equal(t, 3, profile.ClientConfig().RetryCount)
+
+	_, err = Load(context.Background(), path, Overrides{Profile: "missing"})
+	assert.ErrorContains(t, err, "available: personal, work")
+}
+
+func TestLoad_TOML(t *testing.T) {
+	clearEnv(t)
+	keyFile := writeFile(t, "key", "file-key\n")
+	path := writeFile(t, "config.toml", `
+[profiles.team]
+api_key_file = "`+keyFile+`"
+base_url = "http://localhost:8080"
+timeout = "5s"
+`)
+
+	profile, err := Load(context.Background(), path, Overrides{})
+	require.NoError(t, err)
+	assert.Equal(t, "team", profile.Name)
+	assert.Equal(t, "file-key", profile.APIKey)
+	assert.Equal(t, "http://localhost:8080", profile.BaseURL)
+	assert.Equal(t, Duration(5*time.Second), profile.Timeout)
+}
+
+func TestLoad_TOMLRejectsUnknownKeys(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+[profiles.team]
+api_key = "key"
+read_onyl = true
+
+[profiles.team.polcy]
+deny = ["nuclino_delete_*"]
+`)
+
+	_, err := Load(context.Background(), path, Overrides{})
+	assert.ErrorContains(t, err, "unknown keys profiles.team.read_onyl, profiles.team.polcy")
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("WORK_NUCLINO_KEY", "work-key")
+	t.Setenv("NUCLINO_CONFIG", writeFile(t, "config.yml", yamlConfig))
+	t.Setenv("NUCLINO_API_KEY", "env-key")
+	t.Setenv("NUCLINO_RATE_LIMIT", "7")
+	t.Setenv("NUCLINO_READ_ONLY", "true")
+	t.Setenv("NUCLINO_TOOLSETS", "files")
+
+	readOnly := false
+	profile, err := Load(context.Background(), "", Overrides{RPS: 1, ReadOnly: &readOnly})
+	require.NoError(t, err)
+	assert.Equal(t, "env-key", profile.APIKey)
+	assert.Equal(t, 1.0, profile.RateLimit.RPS)
+	assert.False(t, profile.ReadOnly)
+	assert.Equal(t, []string{"files"}, profile.Toolsets)
+
+	t.Setenv("NUCLINO_READ_ONLY", "maybe")
+	_, err = Load(context.Background(), "", Overrides{})
+	assert.ErrorContains(t, err, "NUCLINO_READ_ONLY")
+}
+
+func TestLoad_WithoutFile(t *testing.T) {
+	clearEnv(t)
+	_, err := Load(context.Background(), "", Overrides{})
+	assert.ErrorContains(t, err, "no API key")
+
+	t.Setenv("NUCLINO_API_KEY", "env-key")
+	profile, err := Load(context.Background(), "", Overrides{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfileName, profile.Name)
+	assert.Equal(t, CacheNone, profile.Cache.Backend)
+	auditConfig, ok := profile.AuditConfig()
+	assert.True(t, ok)
+	assert.Equal(t, int64(10<<20), auditConfig.MaxSize)
+
+	assert.Equal(t, tracing.ExporterNone, profile.TracingConfig().Exporter)
+
+	t.Setenv("NUCLINO_AUDIT_LOG", AuditNone)
+	t.Setenv("NUCLINO_TRACE_FILE", "/tmp/traces.jsonl")
+	profile, err = Load(context.Background(), "", Overrides{})
+	require.NoError(t, err)
+	_, ok = profile.AuditConfig()
+	assert.False(t, ok)
+	assert.Equal(t, tracing.ExporterFile, profile.TracingConfig().Exporter, "a trace file implies the file exporter")
+	assert.Equal(t, "/tmp/traces.jsonl", profile.TracingConfig().File)
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Profile {
+		profile := &Profile{Name: "p", APIKey: "key"}
+		profile.SetDefaults()
+		return profile
+	}
+	require.NoError(t, valid().Validate())
+
+	cases := map[string]func(*Profile){
+		"base_url":      func(p *Profile) { p.BaseURL = "api.nuclino.com" },
+		"rps":           func(p *Profile) { p.RateLimit.RPS = -1 },
+		"burst":         func(p *Profile) { p.RateLimit.Burst = -1 },
+		"cache backend": func(p *Profile) { p.Cache.Backend = "redis" },
+		"line break":    func(p *Profile) { p.APIKey = "key\n" },
+		"admin_addr":    func(p *Profile) { p.AdminAddr = "9090" },
+		"exporter":      func(p *Profile) { p.Tracing.Exporter = "jaeger" },
+		"sample_ratio":  func(p *Profile) { p.Tracing.SampleRatio = 2 },
+		"upload_root":   func(p *Profile) { p.UploadRoot = "/nonexistent/uploads" },
+	}
+	for want, mutate := range cases {
+		profile := valid()
+		mutate(profile)
+		assert.ErrorContains(t, profile.Validate(), want)
+	}
+
+	_, err := ReadFile(writeFile(t, "bad.yaml", "profiles:\n  p:\n    rate_limt: {rps: 1}\n"))
+	assert.ErrorContains(t, err, "rate_limt")
+}
+
+func TestLoadAccounts(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("WORK_NUCLINO_KEY", "work-key")
+	t.Setenv("NUCLINO_API_KEY", "env-key")
+	path := writeFile(t, "config.yaml", yamlConfig+"accounts: [personal]\n")
+
+	profiles, err := LoadAccounts(context.Background(), path, Overrides{RPS: 1})
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "work", profiles[0].Name)
+	assert.Equal(t, "env-key", profiles[0].APIKey)
+	assert.Equal(t, 1.0, profiles[0].RateLimit.RPS)
+
+	// Overrides only apply to the selected profile
+	assert.Equal(t, "personal", profiles[1].Name)
+	assert.Equal(t, "personal-key", profiles[1].APIKey)
+	assert.Equal(t, 10.0, profiles[1].RateLimit.RPS)
+
+	profiles, err = LoadAccounts(context.Background(), path, Overrides{Profile: "personal", Accounts: []string{AllAccounts}})
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "personal", profiles[0].Name)
+	assert.Equal(t, "work", profiles[1].Name)
+
+	_, err = LoadAccounts(context.Background(), path, Overrides{Accounts: []string{"other"}})
+	assert.ErrorContains(t, err, `account "other" has no profile`)
+}
@@ -0,0 +1,408 @@
+// Package dedup finds near-duplicate items using word shingles, MinHash
+// signatures and locality-sensitive hashing, and proposes how to merge them.
+package dedup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const maxPassageChars = 300
+
+// Document is the text of one item
+type Document struct {
+	ID         string
+	Title      string
+	Content    string
+	ModifiedAt time.Time
+}
+
+// Config holds detector configuration
+type Config struct {
+	// ShingleSize is the number of consecutive words per shingle
+	ShingleSize int
+	// NumHashes is the MinHash signature length; it must be a multiple of Bands
+	NumHashes int
+	// Bands is the number of LSH bands; more bands find lower similarities
+	Bands int
+	// Threshold is the minimum Jaccard similarity reported
+	Threshold float64
+	// MinWords skips documents too short to compare meaningfully
+	MinWords int
+	// MaxPassages limits the overlapping passages reported per pair
+	MaxPassages int
+}
+
+// DefaultConfig returns the default detector configuration
+func DefaultConfig() Config {
+	return Config{
+		ShingleSize: 4,
+		NumHashes:   128,
+		Bands:       32,
+		Threshold:   0.5,
+		MinWords:    10,
+		MaxPassages: 3,
+	}
+}
+
+// Passage is a run of text shared by two documents
+type Passage struct {
+	Text  string `json:"text"`
+	Words int    `json:"words"`
+}
+
+// Pair is two documents whose similarity reached the threshold
+type Pair struct {
+	A          string    `json:"a"`
+	B          string    `json:"b"`
+	Similarity float64   `json:"similarity"`
+	Estimated  float64   `json:"estimated_similarity"`
+	Passages   []Passage `json:"overlapping_passages"`
+}
+
+// Member is a document belonging to a cluster
+type Member struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Words int    `json:"words"`
+}
+
+// Cluster groups documents connected by similar pairs
+type Cluster struct {
+	Members       []Member         `json:"members"`
+	MaxSimilarity float64          `json:"max_similarity"`
+	Pairs         []Pair           `json:"pairs"`
+	Suggestion    *MergeSuggestion `json:"merge_suggestion"`
+}
+
+// MergeSuggestion proposes which document to keep and what the others add
+type MergeSuggestion struct {
+	KeepID    string           `json:"keep_id"`
+	KeepTitle string           `json:"keep_title"`
+	Reason    string           `json:"reason"`
+	Merge     []MergeCandidate `json:"merge"`
+}
+
+// MergeCandidate is a document to fold into the kept one
+type MergeCandidate struct {
+	ID               string `json:"id"`
+	Title            string `json:"title"`
+	UniqueParagraphs int    `json:"unique_paragraphs"`
+}
+
+// Detector finds near-duplicate documents
+type Detector struct {
+	config Config
+	seeds  []uint64
+}
+
+// NewDetector creates a detector, filling unset configuration with defaults
+func NewDetector(config Config) (*Detector, error) {
+	defaults := DefaultConfig()
+	if config.ShingleSize <= 0 {
+		config.ShingleSize = defaults.ShingleSize
+	}
+	if config.NumHashes <= 0 {
+		config.NumHashes = defaults.NumHashes
+	}
+	if config.Bands <= 0 {
+		config.Bands = defaults.Bands
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = defaults.Threshold
+	}
+	if config.MinWords <= 0 {
+		config.MinWords = defaults.MinWords
+	}
+	if config.MaxPassages <= 0 {
+		config.MaxPassages = defaults.MaxPassages
+	}
+	if config.NumHashes%config.Bands != 0 {
+		return nil, fmt.Errorf("num hashes (%d) must be a multiple of bands (%d)", config.NumHashes, config.Bands)
+	}
+	if config.Threshold > 1 {
+		return nil, fmt.Errorf("threshold must be between 0 and 1, got %v", config.Threshold)
+	}
+
+	seeds := make([]uint64, config.NumHashes)
+	for i := range seeds {
+		seeds[i] = mix(uint64(i+1) * 0x9e3779b97f4a7c15)
+	}
+	return &Detector{config: config, seeds: seeds}, nil
+}
+
+// prepared holds the derived data of one document
+type prepared struct {
+	doc       Document
+	words     []string
+	positions []uint64
+	set       map[uint64]struct{}
+	signature []uint64
+}
+
+func (d *Detector) prepare(doc Document) *prepared {
+	words := splitWords(doc.Content)
+	lower := make([]string, len(words))
+	for i, w := range words {
+		lower[i] = strings.ToLower(w)
+	}
+	positions, set := shingles(lower, d.config.ShingleSize)
+	return &prepared{doc: doc, words: words, positions: positions, set: set, signature: signature(set, d.seeds)}
+}
+
+// Find returns clusters of near-duplicate documents, most similar first.
+// Candidate pairs come from LSH buckets and are verified with the exact
+// Jaccard similarity of their shingle sets.
+func (d *Detector) Find(docs []Document) []Cluster {
+	var prep []*prepared
+	for _, doc := range docs {
+		p := d.prepare(doc)
+		if len(p.words) >= d.config.MinWords {
+			prep = append(prep, p)
+		}
+	}
+
+	rows := d.config.NumHashes / d.config.Bands
+	buckets := make(map[uint64][]int)
+	for i, p := range prep {
+		for band := 0; band < d.config.Bands; band++ {
+			key := bandKey(band, p.signature[band*rows:(band+1)*rows])
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	candidates := make(map[[2]int]bool)
+	for _, bucket := range buckets {
+		for x := 0; x < len(bucket); x++ {
+			for y := x + 1; y < len(bucket); y++ {
+				candidates[[2]int{bucket[x], bucket[y]}] = true
+			}
+		}
+	}
+
+	parent := make([]int, len(prep))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	pairsByRoot := make(map[int][]Pair)
+	var pairs [][2]int
+	for pair := range candidates {
+		a, b := prep[pair[0]], prep[pair[1]]
+		if jaccard(a.set, b.set) >= d.config.Threshold {
+			pairs = append(pairs, pair)
+			parent[find(pair[0])] = find(pair[1])
+		}
+	}
+
+	for _, pair := range pairs {
+		a, b := prep[pair[0]], prep[pair[1]]
+		if a.doc.ID > b.doc.ID {
+			a, b = b, a
+		}
+		root := find(pair[0])
+		pairsByRoot[root] = append(pairsByRoot[root], Pair{
+			A:          a.doc.ID,
+			B:          b.doc.ID,
+			Similarity: round(jaccard(a.set, b.set)),
+			Estimated:  round(estimateJaccard(a.signature, b.signature)),
+			Passages:   d.passages(a, b),
+		})
+	}
+
+	clusters := make([]Cluster, 0, len(pairsByRoot))
+	for root, clusterPairs := range pairsByRoot {
+		var members []*prepared
+		for i, p := range prep {
+			if find(i) == root {
+				members = append(members, p)
+			}
+		}
+		clusters = append(clusters, d.buildCluster(members, clusterPairs))
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].MaxSimilarity != clusters[j].MaxSimilarity {
+			return clusters[i].MaxSimilarity > clusters[j].MaxSimilarity
+		}
+		return clusters[i].Members[0].ID < clusters[j].Members[0].ID
+	})
+	return clusters
+}
+
+func (d *Detector) buildCluster(members []*prepared, pairs []Pair) Cluster {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		return pairs[i].A+pairs[i].B < pairs[j].A+pairs[j].B
+	})
+
+	// Keep the longest document, preferring the most recently modified
+	sort.Slice(members, func(i, j int) bool {
+		if len(members[i].words) != len(members[j].words) {
+			return len(members[i].words) > len(members[j].words)
+		}
+		if !members[i].doc.ModifiedAt.Equal(members[j].doc.ModifiedAt) {
+			return members[i].doc.ModifiedAt.After(members[j].doc.ModifiedAt)
+		}
+		return members[i].doc.ID < members[j].doc.ID
+	})
+
+	cluster := Cluster{Pairs: pairs, MaxSimilarity: pairs[0].Similarity}
+	for _, m := range members {
+		cluster.Members = append(cluster.Members, Member{ID: m.doc.ID, Title: m.doc.Title, Words: len(m.words)})
+	}
+
+	keep := members[0]
+	suggestion := &MergeSuggestion{
+		KeepID:    keep.doc.ID,
+		KeepTitle: keep.doc.Title,
+		Reason:    "longest item in the cluster",
+	}
+	for _, m := range members[1:] {
+		suggestion.Merge = append(suggestion.Merge, MergeCandidate{
+			ID:               m.doc.ID,
+			Title:            m.doc.Title,
+			UniqueParagraphs: len(d.uniqueParagraphs(keep.set, keep.doc.Content, m.doc.Content)),
+		})
+	}
+	cluster.Suggestion = suggestion
+	return cluster
+}
+
+// passages returns the longest runs of words of a that also appear in b
+func (d *Detector) passages(a, b *prepared) []Passage {
+	size := d.config.ShingleSize
+	if len(a.words) < size {
+		size = len(a.words)
+	}
+
+	type span struct{ start, end int } // word indexes, end exclusive
+	var spans []span
+	current := span{start: -1}
+	for i, h := range a.positions {
+		if _, shared := b.set[h]; shared {
+			if current.start >= 0 && i <= current.end-size+1 {
+				current.end = i + size
+				continue
+			}
+			if current.start >= 0 {
+				spans = append(spans, current)
+			}
+			current = span{start: i, end: i + size}
+		}
+	}
+	if current.start >= 0 {
+		spans = append(spans, current)
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].end-spans[i].start > spans[j].end-spans[j].start
+	})
+	if len(spans) > d.config.MaxPassages {
+		spans = spans[:d.config.MaxPassages]
+	}
+
+	passages := make([]Passage, 0, len(spans))
+	for _, s := range spans {
+		text := strings.Join(a.words[s.start:s.end], " ")
+		if runes := []rune(text); len(runes) > maxPassageChars {
+			text = string(runes[:maxPassageChars]) + "…"
+		}
+		passages = append(passages, Passage{Text: text, Words: s.end - s.start})
+	}
+	return passages
+}
+
+// uniqueParagraphs returns the paragraphs of content that are mostly absent
+// from the kept document. Paragraphs shorter than a shingle are compared as
+// plain text.
+func (d *Detector) uniqueParagraphs(keep map[uint64]struct{}, keepContent, content string) []string {
+	var unique []string
+	keepLower := strings.ToLower(keepContent)
+	for _, paragraph := range splitParagraphs(content) {
+		words := splitWords(paragraph)
+		if len(words) == 0 {
+			continue
+		}
+		if len(words) < d.config.ShingleSize {
+			if !strings.Contains(keepLower, strings.ToLower(paragraph)) {
+				unique = append(unique, paragraph)
+			}
+			continue
+		}
+		for i := range words {
+			words[i] = strings.ToLower(words[i])
+		}
+		positions, _ := shingles(words, d.config.ShingleSize)
+		shared := 0
+		for _, h := range positions {
+			if _, ok := keep[h]; ok {
+				shared++
+			}
+		}
+		if float64(shared)/float64(len(positions)) < 0.5 {
+			unique = append(unique, paragraph)
+		}
+	}
+	return unique
+}
+
+// MergePlan is the proposed content of the kept document after merging
+type MergePlan struct {
+	KeepID  string         `json:"keep_id"`
+	Content string         `json:"content"`
+	Added   map[string]int `json:"added_paragraphs"`
+}
+
+// PlanMerge appends the paragraphs of each merged document that the kept
+// document does not already contain, under a heading naming their source
+func (d *Detector) PlanMerge(keep Document, merge []Document) *MergePlan {
+	plan := &MergePlan{KeepID: keep.ID, Content: strings.TrimRight(keep.Content, "\n"), Added: make(map[string]int)}
+	keepSet := d.prepare(keep).set
+
+	for _, doc := range merge {
+		unique := d.uniqueParagraphs(keepSet, plan.Content, doc.Content)
+		plan.Added[doc.ID] = len(unique)
+		if len(unique) == 0 {
+			continue
+		}
+		plan.Content += fmt.Sprintf("\n\n## Merged from %q\n\n%s", doc.Title, strings.Join(unique, "\n\n"))
+		for h := range d.prepare(Document{Content: strings.Join(unique, "\n\n")}).set {
+			keepSet[h] = struct{}{}
+		}
+	}
+	plan.Content += "\n"
+	return plan
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func splitParagraphs(content string) []string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+func round(v float64) float64 {
+	return float64(int(v*1000+0.5)) / 1000
+}
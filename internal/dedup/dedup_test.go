@@ -0,0 +1,93 @@
+package dedup
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const onboarding = `# Onboarding
+
+New engineers should request laptop access from IT on their first day and join the engineering Slack channels.
+
+Set up the development environment by cloning the monorepo, installing Go and running the bootstrap script.
+
+Schedule intro meetings with your team lead and your onboarding buddy during the first week.`
+
+func newTestDetector(t *testing.T) *Detector {
+	detector, err := NewDetector(DefaultConfig())
+	require.NoError(t, err)
+	return detector
+}
+
+func TestFind_ClustersNearDuplicates(t *testing.T) {
+	detector := newTestDetector(t)
+
+	copyEdited := strings.Replace(onboarding, "first week.", "first week.\n\nAsk questions in the help channel whenever you are stuck.", 1)
+	docs := []Document{
+		{ID: "a", Title: "Onboarding", Content: onboarding},
+		{ID: "b", Title: "Onboarding (copy)", Content: copyEdited},
+		{ID: "c", Title: "Release process", Content: "Releases are cut every Tuesday from the main branch after the test suite passes and QA signs off on staging."},
+		{ID: "d", Title: "Tiny", Content: "too short"},
+	}
+
+	clusters := detector.Find(docs)
+
+	require.Len(t, clusters, 1)
+	cluster := clusters[0]
+	assert.Len(t, cluster.Members, 2)
+	assert.Greater(t, cluster.MaxSimilarity, 0.7)
+
+	pair := cluster.Pairs[0]
+	assert.Equal(t, "a", pair.A)
+	assert.Equal(t, "b", pair.B)
+	require.NotEmpty(t, pair.Passages)
+	assert.Contains(t, pair.Passages[0].Text, "request laptop access")
+
+	require.NotNil(t, cluster.Suggestion)
+	assert.Equal(t, "b", cluster.Suggestion.KeepID, "the longer item is kept")
+	require.Len(t, cluster.Suggestion.Merge, 1)
+	assert.Equal(t, "a", cluster.Suggestion.Merge[0].ID)
+	assert.Zero(t, cluster.Suggestion.Merge[0].UniqueParagraphs)
+}
+
+func TestFind_ScalesWithoutFalsePositives(t *testing.T) {
+	detector := newTestDetector(t)
+
+	var docs []Document
+	for i := 0; i < 500; i++ {
+		words := make([]string, 40)
+		for j := range words {
+			words[j] = fmt.Sprintf("doc%dword%d", i, j)
+		}
+		docs = append(docs, Document{ID: fmt.Sprintf("item-%03d", i), Content: strings.Join(words, " ")})
+	}
+	docs = append(docs, Document{ID: "dup", Content: docs[42].Content + " extra"})
+
+	clusters := detector.Find(docs)
+
+	require.Len(t, clusters, 1)
+	assert.ElementsMatch(t, []string{"dup", "item-042"}, []string{clusters[0].Members[0].ID, clusters[0].Members[1].ID})
+}
+
+func TestPlanMerge(t *testing.T) {
+	detector := newTestDetector(t)
+
+	keep := Document{ID: "a", Title: "Onboarding", Content: onboarding}
+	other := Document{ID: "b", Title: "Old onboarding", Content: onboarding + "\n\nParking permits are handled by the facilities team at reception.\n\nFAQ"}
+
+	plan := detector.PlanMerge(keep, []Document{other})
+
+	assert.Equal(t, 2, plan.Added["b"])
+	assert.Contains(t, plan.Content, `## Merged from "Old onboarding"`)
+	assert.Contains(t, plan.Content, "Parking permits")
+	assert.Equal(t, 1, strings.Count(plan.Content, "request laptop access"))
+}
+
+func TestNewDetector_InvalidBands(t *testing.T) {
+	_, err := NewDetector(Config{NumHashes: 100, Bands: 30})
+	assert.Error(t, err)
+}
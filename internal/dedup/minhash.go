@@ -0,0 +1,98 @@
+package dedup
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// shingles hashes every run of size consecutive words. The returned slice is
+// positional (shingle i starts at word i); the set holds the distinct hashes.
+func shingles(words []string, size int) ([]uint64, map[uint64]struct{}) {
+	if len(words) < size {
+		if len(words) == 0 {
+			return nil, map[uint64]struct{}{}
+		}
+		size = len(words)
+	}
+
+	hashes := make([]uint64, 0, len(words)-size+1)
+	set := make(map[uint64]struct{}, len(words)-size+1)
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		for _, word := range words[i : i+size] {
+			h.Write([]byte(word))
+			h.Write([]byte{0})
+		}
+		sum := h.Sum64()
+		hashes = append(hashes, sum)
+		set[sum] = struct{}{}
+	}
+	return hashes, set
+}
+
+// mix is the splitmix64 finalizer, used to derive independent hash functions
+// from one shingle hash and a per-function seed
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// signature computes the MinHash signature of a shingle set
+func signature(set map[uint64]struct{}, seeds []uint64) []uint64 {
+	sig := make([]uint64, len(seeds))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for shingle := range set {
+		for i, seed := range seeds {
+			if h := mix(shingle ^ seed); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+// estimateJaccard returns the fraction of matching signature positions
+func estimateJaccard(a, b []uint64) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// jaccard computes the exact Jaccard similarity of two shingle sets
+func jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for h := range a {
+		if _, ok := b[h]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// bandKey hashes one LSH band of a signature
+func bandKey(band int, rows []uint64) uint64 {
+	h := uint64(band) * 0x9e3779b97f4a7c15
+	for _, v := range rows {
+		h = mix(h ^ v)
+	}
+	return h
+}
@@ -0,0 +1,433 @@
+// Package docsync mirrors a Nuclino workspace into a directory of Markdown
+// files and pushes local edits back, detecting changes on both sides.
+package docsync
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+const (
+	defaultPageSize = 100
+	conflictSuffix  = ".conflict.md"
+)
+
+// Action describes what the engine did with a single item
+type Action string
+
+const (
+	ActionPulled          Action = "pulled"
+	ActionPushed          Action = "pushed"
+	ActionCreated         Action = "created"
+	ActionConflict        Action = "conflict"
+	ActionConflictPending Action = "conflict_pending"
+	ActionRemoteDeleted   Action = "remote_deleted"
+	ActionLocalMissing    Action = "local_missing"
+	ActionError           Action = "error"
+)
+
+// Config holds sync engine configuration
+type Config struct {
+	WorkspaceID string
+	Dir         string
+	StateFile   string // defaults to Dir/.nuclino-sync.json
+	GitCommit   bool
+	DryRun      bool
+	PageSize    int
+}
+
+// Change is a single entry of a sync report
+type Change struct {
+	ItemID  string `json:"item_id,omitempty"`
+	Path    string `json:"path"`
+	Action  Action `json:"action"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report summarizes a sync run
+type Report struct {
+	Changes   []Change `json:"changes"`
+	Unchanged int      `json:"unchanged"`
+	Committed bool     `json:"committed"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+// Count returns the number of changes with the given action
+func (r *Report) Count(action Action) int {
+	count := 0
+	for _, change := range r.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *Report) add(itemID, path string, action Action, message string) {
+	r.Changes = append(r.Changes, Change{ItemID: itemID, Path: path, Action: action, Message: message})
+}
+
+// Engine performs two-way synchronization between a workspace and a directory
+type Engine struct {
+	client nuclino.Client
+	config Config
+}
+
+type localFile struct {
+	path string // relative to the sync directory
+	doc  Document
+}
+
+// NewEngine creates a sync engine for the given workspace and directory
+func NewEngine(client nuclino.Client, config Config) (*Engine, error) {
+	if config.WorkspaceID == "" {
+		return nil, fmt.Errorf("workspace ID is required")
+	}
+	if config.Dir == "" {
+		return nil, fmt.Errorf("sync directory is required")
+	}
+	if config.StateFile == "" {
+		config.StateFile = filepath.Join(config.Dir, DefaultStateFile)
+	}
+	if config.PageSize <= 0 {
+		config.PageSize = defaultPageSize
+	}
+
+	return &Engine{client: client, config: config}, nil
+}
+
+// Sync runs one synchronization pass. Per-item failures are recorded in the
+// report; only failures that make the whole run meaningless are returned.
+func (e *Engine) Sync(ctx context.Context) (*Report, error) {
+	if err := os.MkdirAll(e.config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create sync directory: %w", err)
+	}
+
+	state, err := LoadState(e.config.StateFile, e.config.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := e.listRemote(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: e.config.DryRun}
+	local, untracked, err := e.scanLocal(report)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, tracked := range state.Items {
+		item, inRemote := remote[id]
+		file, inLocal := local[id]
+		e.syncTracked(ctx, state, tracked, id, item, inRemote, file, inLocal, report)
+	}
+
+	for id, item := range remote {
+		if _, tracked := state.Items[id]; tracked {
+			continue
+		}
+		e.syncUntrackedRemote(ctx, state, item, local, report)
+	}
+
+	for _, file := range untracked {
+		e.createRemote(ctx, state, file, report)
+	}
+
+	if e.config.DryRun {
+		return report, nil
+	}
+
+	state.LastSync = time.Now().UTC()
+	if err := state.Save(e.config.StateFile); err != nil {
+		return report, err
+	}
+
+	if e.config.GitCommit {
+		message := fmt.Sprintf("Sync Nuclino workspace %s (%d pulled, %d pushed, %d created, %d conflicts)",
+			e.config.WorkspaceID, report.Count(ActionPulled), report.Count(ActionPushed),
+			report.Count(ActionCreated), report.Count(ActionConflict))
+		committed, err := commitToGit(ctx, e.config.Dir, message)
+		if err != nil {
+			return report, err
+		}
+		report.Committed = committed
+	}
+
+	return report, nil
+}
+
+func (e *Engine) syncTracked(ctx context.Context, state *State, tracked *ItemState, id string,
+	item nuclino.Item, inRemote bool, file localFile, inLocal bool, report *Report) {
+	if !inRemote {
+		if !tracked.RemoteDeleted {
+			report.add(id, tracked.Path, ActionRemoteDeleted, "item no longer exists in the workspace; local file kept")
+			tracked.RemoteDeleted = true
+		}
+		return
+	}
+	tracked.RemoteDeleted = false
+
+	if !inLocal {
+		report.add(id, tracked.Path, ActionLocalMissing, "local file was removed; remote item left untouched")
+		return
+	}
+	tracked.Path = file.path
+
+	if tracked.Conflict {
+		if e.exists(conflictPath(file.path)) {
+			report.add(id, file.path, ActionConflictPending, "resolve the conflict and delete "+conflictPath(file.path))
+			return
+		}
+		tracked.Conflict = false
+	}
+
+	localHash := file.doc.Hash()
+	localChanged := localHash != tracked.Hash
+	remoteChanged := !item.ModifiedAt().Equal(tracked.RemoteUpdatedAt)
+
+	var full *nuclino.Item
+	remoteHash := tracked.Hash
+	if remoteChanged {
+		fetched, err := e.client.GetItem(ctx, id)
+		if err != nil {
+			report.add(id, file.path, ActionError, err.Error())
+			return
+		}
+		full = fetched
+		remoteHash = contentHash(full.Title, full.Content)
+		if remoteHash == tracked.Hash {
+			// Only metadata moved, the synced fields are identical
+			remoteChanged = false
+			tracked.RemoteUpdatedAt = full.ModifiedAt()
+		}
+	}
+
+	switch {
+	case !localChanged && !remoteChanged:
+		report.Unchanged++
+
+	case remoteChanged && !localChanged:
+		doc := Document{ID: id, Title: full.Title, ParentID: file.doc.ParentID, Content: full.Content}
+		if err := e.writeFile(file.path, doc); err != nil {
+			report.add(id, file.path, ActionError, err.Error())
+			return
+		}
+		tracked.Hash = remoteHash
+		tracked.RemoteUpdatedAt = full.ModifiedAt()
+		report.add(id, file.path, ActionPulled, "")
+
+	case localChanged && !remoteChanged:
+		if e.config.DryRun {
+			report.add(id, file.path, ActionPushed, "")
+			return
+		}
+		title := file.doc.Title
+		content := file.doc.Content
+		updated, err := e.client.UpdateItem(ctx, id, &nuclino.UpdateItemRequest{Title: &title, Content: &content})
+		if err != nil {
+			report.add(id, file.path, ActionError, err.Error())
+			return
+		}
+		tracked.Hash = localHash
+		tracked.RemoteUpdatedAt = updated.ModifiedAt()
+		report.add(id, file.path, ActionPushed, "")
+
+	default:
+		if localHash == remoteHash {
+			tracked.Hash = localHash
+			tracked.RemoteUpdatedAt = full.ModifiedAt()
+			report.Unchanged++
+			return
+		}
+		e.writeConflict(state, id, file, full, report)
+	}
+}
+
+func (e *Engine) syncUntrackedRemote(ctx context.Context, state *State, item nuclino.Item,
+	local map[string]localFile, report *Report) {
+	full, err := e.client.GetItem(ctx, item.ID)
+	if err != nil {
+		report.add(item.ID, "", ActionError, err.Error())
+		return
+	}
+	remoteHash := contentHash(full.Title, full.Content)
+
+	// A file already carries this ID but the state was lost: adopt it if the
+	// contents agree, otherwise treat it as a conflict.
+	if file, ok := local[item.ID]; ok {
+		if file.doc.Hash() == remoteHash {
+			state.Items[item.ID] = &ItemState{Path: file.path, RemoteUpdatedAt: full.ModifiedAt(), Hash: remoteHash}
+			report.Unchanged++
+			return
+		}
+		// The remote version is the base, so keeping the local file once the
+		// conflict is resolved counts as a local change and gets pushed
+		state.Items[item.ID] = &ItemState{Path: file.path, RemoteUpdatedAt: full.ModifiedAt(), Hash: remoteHash}
+		e.writeConflict(state, item.ID, file, full, report)
+		return
+	}
+
+	path := e.allocatePath(state, full.Title, full.ID)
+	doc := Document{ID: full.ID, Title: full.Title, Content: full.Content}
+	if err := e.writeFile(path, doc); err != nil {
+		report.add(full.ID, path, ActionError, err.Error())
+		return
+	}
+	state.Items[full.ID] = &ItemState{Path: path, RemoteUpdatedAt: full.ModifiedAt(), Hash: remoteHash}
+	report.add(full.ID, path, ActionPulled, "")
+}
+
+func (e *Engine) createRemote(ctx context.Context, state *State, file localFile, report *Report) {
+	title := file.doc.Title
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(file.path), filepath.Ext(file.path))
+	}
+
+	if e.config.DryRun {
+		report.add("", file.path, ActionCreated, "")
+		return
+	}
+
+	item, err := e.client.CreateItem(ctx, &nuclino.CreateItemRequest{
+		Title:       title,
+		Content:     file.doc.Content,
+		WorkspaceID: e.config.WorkspaceID,
+		ParentID:    file.doc.ParentID,
+	})
+	if err != nil {
+		report.add("", file.path, ActionError, err.Error())
+		return
+	}
+
+	doc := Document{ID: item.ID, Title: title, ParentID: file.doc.ParentID, Content: file.doc.Content}
+	if err := e.writeFile(file.path, doc); err != nil {
+		report.add(item.ID, file.path, ActionError, err.Error())
+		return
+	}
+	state.Items[item.ID] = &ItemState{Path: file.path, RemoteUpdatedAt: item.ModifiedAt(), Hash: doc.Hash()}
+	report.add(item.ID, file.path, ActionCreated, "")
+}
+
+// writeConflict stores the remote version next to the local file and marks the
+// item as conflicted until the conflict file is removed.
+func (e *Engine) writeConflict(state *State, id string, file localFile, remote *nuclino.Item, report *Report) {
+	path := conflictPath(file.path)
+	doc := Document{ID: id, Title: remote.Title, ParentID: file.doc.ParentID, Content: remote.Content}
+	if err := e.writeFile(path, doc); err != nil {
+		report.add(id, file.path, ActionError, err.Error())
+		return
+	}
+
+	tracked := state.Items[id]
+	tracked.Conflict = true
+	tracked.RemoteUpdatedAt = remote.ModifiedAt()
+	report.add(id, file.path, ActionConflict, "remote version written to "+path)
+}
+
+func (e *Engine) listRemote(ctx context.Context) (map[string]nuclino.Item, error) {
+	listed, err := nuclino.ListAllItems(ctx, e.client, e.config.WorkspaceID, e.config.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace items: %w", err)
+	}
+	items := make(map[string]nuclino.Item, len(listed))
+	for _, item := range listed {
+		items[item.ID] = item
+	}
+	return items, nil
+}
+
+// scanLocal returns files that carry a Nuclino ID, keyed by ID, and files
+// that do not yet exist remotely.
+func (e *Engine) scanLocal(report *Report) (map[string]localFile, []localFile, error) {
+	tracked := make(map[string]localFile)
+	var untracked []localFile
+
+	stateFile, _ := filepath.Abs(e.config.StateFile)
+	err := filepath.WalkDir(e.config.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if abs, _ := filepath.Abs(path); abs == stateFile {
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") || strings.HasSuffix(path, conflictSuffix) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(e.config.Dir, path)
+		if err != nil {
+			return err
+		}
+
+		file := localFile{path: rel, doc: ParseDocument(string(data))}
+		if file.doc.ID == "" {
+			untracked = append(untracked, file)
+			return nil
+		}
+		if existing, dup := tracked[file.doc.ID]; dup {
+			report.add(file.doc.ID, rel, ActionError, "duplicate nuclino_id, already used by "+existing.path)
+			return nil
+		}
+		tracked[file.doc.ID] = file
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan sync directory: %w", err)
+	}
+	return tracked, untracked, nil
+}
+
+func (e *Engine) allocatePath(state *State, title, id string) string {
+	path := slugify(title) + ".md"
+	if !state.pathInUse(path, id) && !e.exists(path) {
+		return path
+	}
+	suffix := id
+	if len(suffix) > 8 {
+		suffix = suffix[:8]
+	}
+	return slugify(title) + "-" + suffix + ".md"
+}
+
+func (e *Engine) writeFile(rel string, doc Document) error {
+	if e.config.DryRun {
+		return nil
+	}
+	path := filepath.Join(e.config.Dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	log.Debug().Str("path", rel).Str("item_id", doc.ID).Msg("Writing synced document")
+	return os.WriteFile(path, []byte(doc.Render()), 0o644)
+}
+
+func (e *Engine) exists(rel string) bool {
+	_, err := os.Stat(filepath.Join(e.config.Dir, rel))
+	return err == nil
+}
+
+func conflictPath(path string) string {
+	return strings.TrimSuffix(path, ".md") + conflictSuffix
+}
@@ -0,0 +1,249 @@
+package docsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// fakeClient keeps items in memory; methods not used by the engine panic
+// through the embedded nil interface.
+type fakeClient struct {
+	nuclino.Client
+	items  map[string]*nuclino.Item
+	nextID int
+	clock  time.Time
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{items: make(map[string]*nuclino.Item), clock: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClient) tick() time.Time {
+	f.clock = f.clock.Add(time.Minute)
+	return f.clock
+}
+
+func (f *fakeClient) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*nuclino.ItemsResponse, error) {
+	ids := make([]string, 0, len(f.items))
+	for id := range f.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var results []nuclino.Item
+	for _, id := range ids {
+		listed := *f.items[id]
+		listed.Content = "" // the list endpoint omits content
+		results = append(results, listed)
+	}
+	if offset >= len(results) {
+		return &nuclino.ItemsResponse{}, nil
+	}
+	results = results[offset:]
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return &nuclino.ItemsResponse{Results: results}, nil
+}
+
+func (f *fakeClient) GetItem(ctx context.Context, itemID string) (*nuclino.Item, error) {
+	item, ok := f.items[itemID]
+	if !ok {
+		return nil, nuclino.NewAPIError(404, "Item not found")
+	}
+	copied := *item
+	return &copied, nil
+}
+
+func (f *fakeClient) CreateItem(ctx context.Context, req *nuclino.CreateItemRequest) (*nuclino.Item, error) {
+	f.nextID++
+	item := &nuclino.Item{ID: fmt.Sprintf("new-%d", f.nextID), Title: req.Title, Content: req.Content,
+		WorkspaceID: req.WorkspaceID, LastUpdatedAt: f.tick()}
+	f.items[item.ID] = item
+	copied := *item
+	return &copied, nil
+}
+
+func (f *fakeClient) UpdateItem(ctx context.Context, itemID string, req *nuclino.UpdateItemRequest) (*nuclino.Item, error) {
+	item, ok := f.items[itemID]
+	if !ok {
+		return nil, nuclino.NewAPIError(404, "Item not found")
+	}
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+	if req.Content != nil {
+		item.Content = *req.Content
+	}
+	item.LastUpdatedAt = f.tick()
+	copied := *item
+	return &copied, nil
+}
+
+func (f *fakeClient) put(id, title, content string) {
+	f.items[id] = &nuclino.Item{ID: id, Title: title, Content: content, WorkspaceID: "ws", LastUpdatedAt: f.tick()}
+}
+
+func newTestEngine(t *testing.T, client nuclino.Client) (*Engine, string) {
+	dir := t.TempDir()
+	engine, err := NewEngine(client, Config{WorkspaceID: "ws", Dir: dir, PageSize: 2})
+	require.NoError(t, err)
+	return engine, dir
+}
+
+func readDoc(t *testing.T, dir, rel string) Document {
+	data, err := os.ReadFile(filepath.Join(dir, rel))
+	require.NoError(t, err)
+	return ParseDocument(string(data))
+}
+
+func TestDocument_RoundTrip(t *testing.T) {
+	doc := Document{ID: "abc", Title: `Say "hi"`, ParentID: "p1", Content: "# Body\n\ntext\n"}
+	parsed := ParseDocument(doc.Render())
+	assert.Equal(t, doc, parsed)
+
+	// Titles survive repeated syncs unchanged, escapes included
+	doc.Title = "C:\\Users\\docs\tdraft \x01"
+	parsed = doc
+	for i := 0; i < 3; i++ {
+		parsed = ParseDocument(parsed.Render())
+	}
+	assert.Equal(t, doc, parsed)
+
+	plain := ParseDocument("# Heading Title\n\nbody")
+	assert.Equal(t, "", plain.ID)
+	assert.Equal(t, "Heading Title", plain.Title)
+}
+
+func TestSync_PullsRemoteItems(t *testing.T) {
+	client := newFakeClient()
+	client.put("a", "Alpha Page", "alpha")
+	client.put("b", "Beta Page", "beta")
+	client.put("c", "Gamma", "gamma")
+	engine, dir := newTestEngine(t, client)
+
+	report, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Count(ActionPulled))
+
+	doc := readDoc(t, dir, "alpha-page.md")
+	assert.Equal(t, "a", doc.ID)
+	assert.Equal(t, "alpha", doc.Content)
+
+	report, err = engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, 3, report.Unchanged)
+}
+
+func TestSync_PushesLocalEditsAndCreatesNewFiles(t *testing.T) {
+	client := newFakeClient()
+	client.put("a", "Alpha", "alpha")
+	engine, dir := newTestEngine(t, client)
+
+	_, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+
+	doc := readDoc(t, dir, "alpha.md")
+	doc.Content = "edited locally"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "alpha.md"), []byte(doc.Render()), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# Fresh Notes\n\nhello"), 0o644))
+
+	report, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Count(ActionPushed))
+	assert.Equal(t, 1, report.Count(ActionCreated))
+	assert.Equal(t, "edited locally", client.items["a"].Content)
+
+	created := readDoc(t, dir, "notes.md")
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "Fresh Notes", client.items[created.ID].Title)
+
+	report, err = engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+}
+
+func TestSync_ReportsConflictsWithoutOverwriting(t *testing.T) {
+	client := newFakeClient()
+	client.put("a", "Alpha", "original")
+	engine, dir := newTestEngine(t, client)
+
+	_, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+
+	doc := readDoc(t, dir, "alpha.md")
+	doc.Content = "local version"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "alpha.md"), []byte(doc.Render()), 0o644))
+	client.put("a", "Alpha", "remote version")
+
+	report, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Count(ActionConflict))
+	assert.Equal(t, "remote version", client.items["a"].Content)
+	assert.Equal(t, "local version", readDoc(t, dir, "alpha.md").Content)
+	assert.Equal(t, "remote version", readDoc(t, dir, "alpha.conflict.md").Content)
+
+	report, err = engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Count(ActionConflictPending))
+
+	// Resolving the conflict pushes the merged local file
+	require.NoError(t, os.Remove(filepath.Join(dir, "alpha.conflict.md")))
+	report, err = engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Count(ActionPushed))
+	assert.Equal(t, "local version", client.items["a"].Content)
+}
+
+func TestSync_LostStateConflictPushesKeptLocalFile(t *testing.T) {
+	client := newFakeClient()
+	client.put("a", "Alpha", "original")
+	engine, dir := newTestEngine(t, client)
+
+	_, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+
+	doc := readDoc(t, dir, "alpha.md")
+	doc.Content = "local version"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "alpha.md"), []byte(doc.Render()), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(dir, DefaultStateFile)))
+
+	report, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Count(ActionConflict))
+	assert.Equal(t, "original", readDoc(t, dir, "alpha.conflict.md").Content)
+
+	// Keeping the local file as it is still pushes it
+	require.NoError(t, os.Remove(filepath.Join(dir, "alpha.conflict.md")))
+	report, err = engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Count(ActionPushed))
+	assert.Equal(t, "local version", client.items["a"].Content)
+}
+
+func TestSync_DryRunWritesNothing(t *testing.T) {
+	client := newFakeClient()
+	client.put("a", "Alpha", "alpha")
+	dir := t.TempDir()
+	engine, err := NewEngine(client, Config{WorkspaceID: "ws", Dir: dir, DryRun: true})
+	require.NoError(t, err)
+
+	report, err := engine.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Count(ActionPulled))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
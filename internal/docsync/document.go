@@ -0,0 +1,120 @@
+package docsync
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const frontMatterDelimiter = "---"
+
+// Document is the local Markdown representation of a Nuclino item
+type Document struct {
+	ID       string
+	Title    string
+	ParentID string
+	Content  string
+}
+
+// ParseDocument reads a Markdown file with an optional front matter header.
+// Files without front matter are treated as new items whose title is taken
+// from the first H1 heading, if any.
+func ParseDocument(data string) Document {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+
+	var doc Document
+	if strings.HasPrefix(data, frontMatterDelimiter+"\n") {
+		rest := data[len(frontMatterDelimiter)+1:]
+		if end := strings.Index(rest, "\n"+frontMatterDelimiter+"\n"); end >= 0 {
+			parseFrontMatter(rest[:end], &doc)
+			doc.Content = rest[end+len(frontMatterDelimiter)+2:]
+			return doc
+		}
+	}
+
+	doc.Content = data
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "# ") {
+			doc.Title = strings.TrimSpace(line[2:])
+			break
+		}
+	}
+	return doc
+}
+
+func parseFrontMatter(header string, doc *Document) {
+	for _, line := range strings.Split(header, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = unquote(strings.TrimSpace(value))
+		switch strings.TrimSpace(key) {
+		case "nuclino_id":
+			doc.ID = value
+		case "title":
+			doc.Title = value
+		case "parent_id":
+			doc.ParentID = value
+		}
+	}
+}
+
+// unquote reads a value written by Render, which quotes titles with
+// strconv.Quote. Hand-edited values that are not valid Go strings keep
+// their text between the quotes.
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// Render serializes the document back to Markdown with front matter
+func (d Document) Render() string {
+	var b strings.Builder
+	b.WriteString(frontMatterDelimiter + "\n")
+	if d.ID != "" {
+		fmt.Fprintf(&b, "nuclino_id: %s\n", d.ID)
+	}
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(d.Title))
+	if d.ParentID != "" {
+		fmt.Fprintf(&b, "parent_id: %s\n", d.ParentID)
+	}
+	b.WriteString(frontMatterDelimiter + "\n")
+	b.WriteString(d.Content)
+	return b.String()
+}
+
+// Hash returns a stable fingerprint of the synced fields (title and content)
+func (d Document) Hash() string {
+	return contentHash(d.Title, d.Content)
+}
+
+func contentHash(title, content string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + strings.ReplaceAll(content, "\r\n", "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify converts an item title into a file-system friendly name
+func slugify(title string) string {
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 80 {
+		slug = strings.TrimRight(slug[:80], "-")
+	}
+	if slug == "" {
+		slug = "untitled"
+	}
+	return slug
+}
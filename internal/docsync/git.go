@@ -0,0 +1,52 @@
+package docsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// commitToGit records the current state of dir as a git commit, initializing
+// the repository on first use. It is a no-op when nothing changed.
+func commitToGit(ctx context.Context, dir, message string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if _, err := runGit(ctx, dir, "init"); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := runGit(ctx, dir, "add", "-A"); err != nil {
+		return false, err
+	}
+
+	status, err := runGit(ctx, dir, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(status) == "" {
+		return false, nil
+	}
+
+	if _, err := runGit(ctx, dir, "commit", "-m", message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,83 @@
+package docsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultStateFile is the name of the state file kept in the sync directory
+const DefaultStateFile = ".nuclino-sync.json"
+
+// State records what both sides looked like after the last successful sync
+type State struct {
+	WorkspaceID string                `json:"workspace_id"`
+	LastSync    time.Time             `json:"last_sync"`
+	Items       map[string]*ItemState `json:"items"`
+}
+
+// ItemState tracks a single synced item
+type ItemState struct {
+	Path            string    `json:"path"`
+	RemoteUpdatedAt time.Time `json:"remote_updated_at"`
+	Hash            string    `json:"hash"`
+	Conflict        bool      `json:"conflict,omitempty"`
+	RemoteDeleted   bool      `json:"remote_deleted,omitempty"`
+}
+
+func newState(workspaceID string) *State {
+	return &State{
+		WorkspaceID: workspaceID,
+		Items:       make(map[string]*ItemState),
+	}
+}
+
+// LoadState reads the state file, returning an empty state if it does not exist
+func LoadState(path, workspaceID string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newState(workspaceID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state %s: %w", path, err)
+	}
+	if state.WorkspaceID != "" && state.WorkspaceID != workspaceID {
+		return nil, fmt.Errorf("sync state %s belongs to workspace %s, not %s", path, state.WorkspaceID, workspaceID)
+	}
+	if state.Items == nil {
+		state.Items = make(map[string]*ItemState)
+	}
+	state.WorkspaceID = workspaceID
+	return &state, nil
+}
+
+// Save writes the state file atomically
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// pathInUse reports whether a relative path is already claimed by another item
+func (s *State) pathInUse(rel, exceptID string) bool {
+	for id, item := range s.Items {
+		if id != exceptID && filepath.Clean(item.Path) == filepath.Clean(rel) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+// Package extract turns attached files (PDF, DOCX, XLSX, CSV, HTML and plain
+// text) into plain text so they can be read and searched like item content.
+package extract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/cache"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+const (
+	defaultMaxFileSize = 20 * 1024 * 1024
+	defaultCacheSize   = 200
+	defaultCacheTTL    = time.Hour
+)
+
+// TextExtractor converts raw file bytes into plain text
+type TextExtractor interface {
+	Extract(data []byte) (string, error)
+}
+
+// TextExtractorFunc adapts a function to the TextExtractor interface
+type TextExtractorFunc func(data []byte) (string, error)
+
+// Extract calls f(data)
+func (f TextExtractorFunc) Extract(data []byte) (string, error) {
+	return f(data)
+}
+
+// Result is the extracted text of a single file
+type Result struct {
+	FileID      string    `json:"file_id"`
+	FileName    string    `json:"file_name"`
+	MimeType    string    `json:"mime_type"`
+	Format      string    `json:"format"`
+	Size        int       `json:"size_bytes"`
+	Text        string    `json:"text"`
+	ExtractedAt time.Time `json:"extracted_at"`
+}
+
+// Config holds extraction service configuration
+type Config struct {
+	MaxFileSize int64
+	CacheSize   int
+	CacheTTL    time.Duration
+}
+
+// DefaultConfig returns the default extraction configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxFileSize: defaultMaxFileSize,
+		CacheSize:   defaultCacheSize,
+		CacheTTL:    defaultCacheTTL,
+	}
+}
+
+// Service downloads files through the Nuclino client and caches their text
+type Service struct {
+	client     nuclino.Client
+	cache      *cache.Cache
+	config     Config
+	extractors map[string]TextExtractor
+}
+
+// NewService creates an extraction service with the built-in format handlers
+func NewService(client nuclino.Client, config Config) *Service {
+	defaults := DefaultConfig()
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = defaults.MaxFileSize
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaults.CacheSize
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+
+	s := &Service{
+		client:     client,
+		cache:      cache.NewCache(config.CacheSize, config.CacheTTL),
+		config:     config,
+		extractors: make(map[string]TextExtractor),
+	}
+
+	s.Register("pdf", TextExtractorFunc(extractPDF))
+	s.Register("docx", TextExtractorFunc(func(data []byte) (string, error) {
+		return extractDOCX(data, config.MaxFileSize)
+	}))
+	s.Register("xlsx", TextExtractorFunc(func(data []byte) (string, error) {
+		return extractXLSX(data, config.MaxFileSize)
+	}))
+	s.Register("csv", TextExtractorFunc(extractCSV))
+	s.Register("html", TextExtractorFunc(extractHTML))
+	s.Register("text", TextExtractorFunc(extractPlainText))
+
+	return s
+}
+
+// Register adds or replaces the extractor for a format
+func (s *Service) Register(format string, extractor TextExtractor) {
+	s.extractors[format] = extractor
+}
+
+// ExtractFile returns the plain text of a Nuclino file, using the cache when
+// the file has been extracted before
+func (s *Service) ExtractFile(ctx context.Context, fileID string) (*Result, error) {
+	if cached, ok := s.cache.Get(fileID); ok {
+		if result, ok := cached.(*Result); ok {
+			return result, nil
+		}
+	}
+
+	file, err := s.client.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.Size > s.config.MaxFileSize {
+		return nil, fmt.Errorf("file %s is %d bytes, larger than the %d byte extraction limit", fileID, file.Size, s.config.MaxFileSize)
+	}
+
+	// The API does not always report a size, so the download is capped too
+	var data bytes.Buffer
+	limited := &limitedWriter{w: &data, remaining: s.config.MaxFileSize}
+	if _, err := s.client.DownloadFileTo(ctx, file, limited); err != nil {
+		if errors.Is(err, errTooLarge) {
+			return nil, fmt.Errorf("file %s is larger than the %d byte extraction limit", fileID, s.config.MaxFileSize)
+		}
+		return nil, err
+	}
+
+	result, err := s.ExtractBytes(file.DisplayName(), file.MimeType, data.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract text from file %s: %w", fileID, err)
+	}
+	result.FileID = fileID
+
+	s.cache.Set(fileID, result)
+	return result, nil
+}
+
+// errTooLarge is returned by limitedWriter once its limit is exceeded
+var errTooLarge = errors.New("extraction limit exceeded")
+
+// limitedWriter fails once more than the allowed number of bytes is written
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		return 0, errTooLarge
+	}
+	l.remaining -= int64(len(p))
+	return l.w.Write(p)
+}
+
+// ExtractBytes extracts text from in-memory file content
+func (s *Service) ExtractBytes(fileName, mimeType string, data []byte) (*Result, error) {
+	format := DetectFormat(fileName, mimeType, data)
+	extractor, ok := s.extractors[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported file format %q (%s)", format, mimeType)
+	}
+
+	text, err := extractor.Extract(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	return &Result{
+		FileName:    fileName,
+		MimeType:    mimeType,
+		Format:      format,
+		Size:        len(data),
+		Text:        normalizeWhitespace(text),
+		ExtractedAt: time.Now(),
+	}, nil
+}
+
+// DetectFormat picks an extractor format from the MIME type, the file
+// extension and finally the content itself
+func DetectFormat(fileName, mimeType string, data []byte) string {
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName)))
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = mimeType
+	}
+
+	switch mediaType {
+	case "application/pdf":
+		return "pdf"
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return "docx"
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return "xlsx"
+	case "text/csv", "application/csv":
+		return "csv"
+	case "text/html", "application/xhtml+xml":
+		return "html"
+	}
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".docx":
+		return "docx"
+	case ".xlsx":
+		return "xlsx"
+	case ".csv":
+		return "csv"
+	}
+
+	if strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || mediaType == "application/xml" {
+		return "text"
+	}
+	return mediaType
+}
+
+// normalizeWhitespace collapses runs of blank lines and trailing spaces
+func normalizeWhitespace(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
@@ -0,0 +1,183 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestExtractBytes_DOCX(t *testing.T) {
+	docx := buildZip(t, map[string]string{
+		"word/document.xml": `<?xml version="1.0"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Quarterly</w:t></w:r><w:r><w:t xml:space="preserve"> report</w:t></w:r></w:p>
+<w:p><w:r><w:t>Revenue grew</w:t></w:r></w:p>
+</w:body></w:document>`,
+	})
+
+	service := NewService(nil, DefaultConfig())
+	result, err := service.ExtractBytes("report.docx", "", docx)
+
+	require.NoError(t, err)
+	assert.Equal(t, "docx", result.Format)
+	assert.Equal(t, "Quarterly report\nRevenue grew", result.Text)
+}
+
+func TestExtractBytes_XLSX(t *testing.T) {
+	xlsx := buildZip(t, map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>Name</t></si><si><t>Budget</t></si><si><r><t>Ali</t></r><r><t>ce</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row>
+<row><c t="s"><v>2</v></c><c><v>1200</v></c></row>
+</sheetData></worksheet>`,
+	})
+
+	service := NewService(nil, DefaultConfig())
+	result, err := service.ExtractBytes("budget.xlsx", "", xlsx)
+
+	require.NoError(t, err)
+	assert.Equal(t, "xlsx", result.Format)
+	assert.Contains(t, result.Text, "Name\tBudget")
+	assert.Contains(t, result.Text, "Alice\t1200")
+}
+
+func TestExtractBytes_XLSXLayout(t *testing.T) {
+	service := NewService(nil, DefaultConfig())
+	sheet := func(value string) string {
+		return `<worksheet><sheetData><row r="1"><c r="A1"><v>` + value + `</v></c><c r="C1"><v>3</v></c></row></sheetData></worksheet>`
+	}
+
+	// Sheets follow the workbook's tabs, and sparse cells keep their column
+	xlsx := buildZip(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>
+<sheet name="Summary" sheetId="2" r:id="rId2"/><sheet name="Data" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   sheet("data"),
+		"xl/worksheets/sheet2.xml":   sheet("summary"),
+	})
+	result, err := service.ExtractBytes("book.xlsx", "", xlsx)
+	require.NoError(t, err)
+	assert.Equal(t, "## Summary\nsummary\t\t3\n\n## Data\ndata\t\t3", result.Text)
+
+	// Without a workbook part, sheets are ordered by number
+	xlsx = buildZip(t, map[string]string{
+		"xl/worksheets/sheet10.xml": sheet("ten"),
+		"xl/worksheets/sheet2.xml":  sheet("two"),
+	})
+	result, err = service.ExtractBytes("book.xlsx", "", xlsx)
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(result.Text, "## sheet2"), strings.Index(result.Text, "## sheet10"))
+}
+
+func TestExtractBytes_ZipMemberLimit(t *testing.T) {
+	docx := buildZip(t, map[string]string{
+		"word/document.xml": `<w:document><w:body><w:p><w:r><w:t>` + strings.Repeat("a", 4096) + `</w:t></w:r></w:p></w:body></w:document>`,
+	})
+	xlsx := buildZip(t, map[string]string{
+		"xl/sharedStrings.xml":     `<sst><si><t>` + strings.Repeat("b", 4096) + `</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData></sheetData></worksheet>`,
+	})
+
+	// The limit applies to the inflated members, not the compressed upload
+	service := NewService(nil, Config{MaxFileSize: 1024})
+	require.Less(t, len(docx), 1024)
+	_, err := service.ExtractBytes("big.docx", "", docx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extraction limit")
+
+	require.Less(t, len(xlsx), 1024)
+	_, err = service.ExtractBytes("big.xlsx", "", xlsx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extraction limit")
+}
+
+func TestExtractBytes_MalformedPDF(t *testing.T) {
+	service := NewService(nil, DefaultConfig())
+	for _, data := range []string{"%PDF-1.4\n", "%PDF-1.4\ntrailer\n<< /Root 1 0 R >>\nstartxref\n0\n%%EOF", "not a pdf"} {
+		assert.NotPanics(t, func() {
+			_, err := service.ExtractBytes("broken.pdf", "application/pdf", []byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExtractBytes_CSVAndHTML(t *testing.T) {
+	service := NewService(nil, DefaultConfig())
+
+	csvResult, err := service.ExtractBytes("data.csv", "text/csv", []byte("a,b\n\"x, y\",2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "a\tb\nx, y\t2", csvResult.Text)
+
+	htmlResult, err := service.ExtractBytes("page.html", "", []byte(
+		"<html><head><style>p{}</style><script>var x;</script></head><body><h1>Title</h1><p>Hello <b>world</b></p></body></html>"))
+	require.NoError(t, err)
+	assert.Equal(t, "html", htmlResult.Format)
+	assert.Contains(t, htmlResult.Text, "Title")
+	assert.Contains(t, htmlResult.Text, "Hello world")
+	assert.NotContains(t, htmlResult.Text, "var x")
+}
+
+func TestExtractBytes_Unsupported(t *testing.T) {
+	service := NewService(nil, DefaultConfig())
+
+	_, err := service.ExtractBytes("video.mp4", "video/mp4", []byte{0, 0, 0})
+	assert.Error(t, err)
+}
+
+type fakeClient struct {
+	nuclino.Client
+	downloads int
+}
+
+func (f *fakeClient) GetFile(ctx context.Context, fileID string) (*nuclino.File, error) {
+	return &nuclino.File{ID: fileID, Name: "notes.txt", MimeType: "text/plain"}, nil
+}
+
+func (f *fakeClient) DownloadFileTo(ctx context.Context, file *nuclino.File, w io.Writer) (int64, error) {
+	f.downloads++
+	n, err := io.WriteString(w, "plain   notes\n\n\n\nsecond line")
+	return int64(n), err
+}
+
+func TestExtractFile_UsesCache(t *testing.T) {
+	client := &fakeClient{}
+	service := NewService(client, DefaultConfig())
+
+	first, err := service.ExtractFile(context.Background(), "file-1")
+	require.NoError(t, err)
+	assert.Equal(t, "plain   notes\n\nsecond line", first.Text)
+
+	second, err := service.ExtractFile(context.Background(), "file-1")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, client.downloads)
+}
+
+func TestExtractFile_LimitsUnsizedDownloads(t *testing.T) {
+	client := &fakeClient{}
+	service := NewService(client, Config{MaxFileSize: 10})
+
+	_, err := service.ExtractFile(context.Background(), "file-1")
+	assert.EqualError(t, err, "file file-1 is larger than the 10 byte extraction limit")
+}
@@ -0,0 +1,407 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+)
+
+func extractPlainText(data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("file is not valid UTF-8 text")
+	}
+	return string(data), nil
+}
+
+// extractPDF turns a panic of the PDF parser on a malformed file into an
+// error rather than taking the server down
+func extractPDF(data []byte) (_ string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid PDF: %w", err)
+	}
+	text, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("failed to read PDF text: %w", err)
+	}
+	out, err := io.ReadAll(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to read PDF text: %w", err)
+	}
+	return string(out), nil
+}
+
+func extractCSV(data []byte) (string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var b strings.Builder
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid CSV: %w", err)
+		}
+		b.WriteString(strings.Join(record, "\t"))
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// blockElements start a new line when rendering HTML as text
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "pre": true, "blockquote": true, "table": true,
+}
+
+func extractHTML(data []byte) (string, error) {
+	tokenizer := html.NewTokenizer(bytes.NewReader(data))
+	var b strings.Builder
+	skip := 0
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return "", fmt.Errorf("invalid HTML: %w", err)
+			}
+			return b.String(), nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			if tag == "script" || tag == "style" {
+				skip++
+			}
+			if blockElements[tag] {
+				b.WriteByte('\n')
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			if (tag == "script" || tag == "style") && skip > 0 {
+				skip--
+			}
+			if blockElements[tag] {
+				b.WriteByte('\n')
+			}
+		case html.TextToken:
+			if skip == 0 {
+				b.WriteString(strings.Join(strings.Fields(string(tokenizer.Text())), " "))
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// extractDOCX reads word/document.xml and emits one line per paragraph;
+// maxSize bounds the uncompressed size of the XML
+func extractDOCX(data []byte, maxSize int64) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid DOCX: %w", err)
+	}
+	document, err := readZipFile(archive, "word/document.xml", maxSize)
+	if err != nil {
+		return "", fmt.Errorf("invalid DOCX: %w", err)
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	var b strings.Builder
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid DOCX: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteByte('\t')
+			case "br", "cr":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+}
+
+// extractXLSX renders every worksheet as tab-separated rows; maxSize
+// bounds the uncompressed size of each part
+func extractXLSX(data []byte, maxSize int64) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid XLSX: %w", err)
+	}
+
+	var shared []string
+	raw, err := readZipFile(archive, "xl/sharedStrings.xml", maxSize)
+	switch {
+	case err == nil:
+		shared, err = parseSharedStrings(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid XLSX shared strings: %w", err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return "", fmt.Errorf("invalid XLSX: %w", err)
+	}
+
+	sheets, err := workbookSheets(archive, maxSize)
+	if err != nil {
+		return "", fmt.Errorf("invalid XLSX: %w", err)
+	}
+	if len(sheets) == 0 {
+		return "", fmt.Errorf("invalid XLSX: no worksheets found")
+	}
+
+	var b strings.Builder
+	for _, sheet := range sheets {
+		raw, err := readZipFile(archive, sheet.path, maxSize)
+		if err != nil {
+			return "", fmt.Errorf("invalid XLSX: %w", err)
+		}
+		rows, err := parseSheet(raw, shared)
+		if err != nil {
+			return "", fmt.Errorf("invalid XLSX sheet %s: %w", sheet.name, err)
+		}
+		fmt.Fprintf(&b, "## %s\n", sheet.name)
+		for _, row := range rows {
+			b.WriteString(strings.Join(row, "\t"))
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// worksheet is a sheet of a workbook and the archive member holding it
+type worksheet struct {
+	name, path string
+}
+
+// workbookSheets lists the worksheets in the order of the workbook's tabs.
+// Without a workbook part, the worksheet members are taken in the numeric
+// order of their names, so sheet10 follows sheet2.
+func workbookSheets(archive *zip.Reader, maxSize int64) ([]worksheet, error) {
+	workbook, err := readZipFile(archive, "xl/workbook.xml", maxSize)
+	if err == nil {
+		var rels []byte
+		if rels, err = readZipFile(archive, "xl/_rels/workbook.xml.rels", maxSize); err == nil {
+			return parseWorkbook(workbook, rels)
+		}
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var sheets []worksheet
+	for _, f := range archive.File {
+		if strings.HasPrefix(f.Name, "xl/worksheets/") && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, worksheet{name: strings.TrimSuffix(path.Base(f.Name), ".xml"), path: f.Name})
+		}
+	}
+	sort.Slice(sheets, func(i, j int) bool {
+		a, b := sheetNumber(sheets[i].name), sheetNumber(sheets[j].name)
+		if a != b {
+			return a < b
+		}
+		return sheets[i].name < sheets[j].name
+	})
+	return sheets, nil
+}
+
+// parseWorkbook reads the sheet names and order from workbook.xml and their
+// members from its relationships
+func parseWorkbook(workbook, rels []byte) ([]worksheet, error) {
+	var book struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			// The relationship ID is r:id, in the relationships namespace
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(workbook, &book); err != nil {
+		return nil, fmt.Errorf("workbook: %w", err)
+	}
+	var relationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(rels, &relationships); err != nil {
+		return nil, fmt.Errorf("workbook relationships: %w", err)
+	}
+	targets := make(map[string]string, len(relationships.Items))
+	for _, rel := range relationships.Items {
+		// Targets are relative to xl/ unless absolute within the package
+		if strings.HasPrefix(rel.Target, "/") {
+			targets[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+		} else {
+			targets[rel.ID] = path.Join("xl", rel.Target)
+		}
+	}
+
+	sheets := make([]worksheet, 0, len(book.Sheets))
+	for _, sheet := range book.Sheets {
+		target, ok := targets[sheet.ID]
+		if !ok {
+			return nil, fmt.Errorf("sheet %q has no part", sheet.Name)
+		}
+		sheets = append(sheets, worksheet{name: sheet.Name, path: target})
+	}
+	return sheets, nil
+}
+
+// sheetNumber returns the number a member name such as "sheet12" ends with
+func sheetNumber(name string) int {
+	digits := len(name)
+	for digits > 0 && name[digits-1] >= '0' && name[digits-1] <= '9' {
+		digits--
+	}
+	n, _ := strconv.Atoi(name[digits:])
+	return n
+}
+
+func parseSharedStrings(raw []byte) ([]string, error) {
+	var doc struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := xml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(doc.Items))
+	for i, item := range doc.Items {
+		text := item.Text
+		for _, run := range item.Runs {
+			text += run.Text
+		}
+		strs[i] = text
+	}
+	return strs, nil
+}
+
+func parseSheet(raw []byte, shared []string) ([][]string, error) {
+	var doc struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(doc.Rows))
+	for _, row := range doc.Rows {
+		cells := make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			// Empty cells are left out of sparse rows, so a cell goes to
+			// the column of its reference, e.g. "C5"; cells without one
+			// follow the previous cell
+			if column, ok := cellColumn(cell.Ref); ok && column >= len(cells) && column < maxColumns {
+				for len(cells) < column {
+					cells = append(cells, "")
+				}
+			}
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				if idx, err := strconv.Atoi(cell.Value); err == nil && idx >= 0 && idx < len(shared) {
+					value = shared[idx]
+				}
+			case "inlineStr":
+				value = cell.Inline
+			}
+			cells = append(cells, value)
+		}
+		rows = append(rows, cells)
+	}
+	return rows, nil
+}
+
+// maxColumns is the number of columns of a worksheet, XFD being the last
+const maxColumns = 16384
+
+// cellColumn returns the zero-based column of a cell reference such as "C5"
+func cellColumn(ref string) (int, bool) {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, false
+	}
+	return column - 1, true
+}
+
+// readZipFile reads a member of archive, failing once it inflates past
+// maxSize bytes so a small archive cannot exhaust memory
+func readZipFile(archive *zip.Reader, name string, maxSize int64) ([]byte, error) {
+	f, err := archive.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%s is larger than the %d byte extraction limit", name, maxSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,333 @@
+// Package linkgraph builds the graph of links between the items of a
+// workspace so backlinks, orphan items and broken links can be reported.
+package linkgraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/cache"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+const (
+	defaultPageSize    = 100
+	defaultConcurrency = 4
+	defaultCacheTTL    = 5 * time.Minute
+)
+
+// Reasons a link target could not be resolved
+const (
+	ReasonNotFound     = "not_found"
+	ReasonInaccessible = "inaccessible"
+	ReasonUnresolved   = "unresolved"
+)
+
+// Node is a workspace item together with its links
+type Node struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	URL       string   `json:"url,omitempty"`
+	Outgoing  []string `json:"outgoing"`
+	Backlinks []string `json:"backlinks"`
+	External  []string `json:"external,omitempty"`
+}
+
+// BrokenLink is a link whose target was deleted or cannot be read
+type BrokenLink struct {
+	SourceID    string `json:"source_id"`
+	SourceTitle string `json:"source_title"`
+	TargetID    string `json:"target_id"`
+	Reason      string `json:"reason"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// Graph is the link graph of one workspace
+type Graph struct {
+	WorkspaceID string                   `json:"workspace_id"`
+	BuiltAt     time.Time                `json:"built_at"`
+	Nodes       map[string]*Node         `json:"nodes"`
+	Broken      []BrokenLink             `json:"broken"`
+	Items       map[string]*nuclino.Item `json:"-"`
+}
+
+// Node returns the node for an item ID
+func (g *Graph) Node(id string) (*Node, bool) {
+	node, ok := g.Nodes[id]
+	return node, ok
+}
+
+// Orphans returns the items no other item links to, sorted by title
+func (g *Graph) Orphans() []*Node {
+	var orphans []*Node
+	for _, node := range g.Nodes {
+		if len(node.Backlinks) == 0 {
+			orphans = append(orphans, node)
+		}
+	}
+	sortNodes(orphans)
+	return orphans
+}
+
+// BrokenFrom returns the broken links originating from an item
+func (g *Graph) BrokenFrom(id string) []BrokenLink {
+	var broken []BrokenLink
+	for _, link := range g.Broken {
+		if link.SourceID == id {
+			broken = append(broken, link)
+		}
+	}
+	return broken
+}
+
+// MostLinked returns up to limit items with the most backlinks
+func (g *Graph) MostLinked(limit int) []*Node {
+	var nodes []*Node
+	for _, node := range g.Nodes {
+		if len(node.Backlinks) > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if len(nodes[i].Backlinks) != len(nodes[j].Backlinks) {
+			return len(nodes[i].Backlinks) > len(nodes[j].Backlinks)
+		}
+		return nodes[i].Title < nodes[j].Title
+	})
+	if limit > 0 && len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+	return nodes
+}
+
+// Config holds builder configuration
+type Config struct {
+	PageSize    int
+	Concurrency int
+	CacheTTL    time.Duration
+}
+
+// DefaultConfig returns the default builder configuration
+func DefaultConfig() Config {
+	return Config{
+		PageSize:    defaultPageSize,
+		Concurrency: defaultConcurrency,
+		CacheTTL:    defaultCacheTTL,
+	}
+}
+
+// Builder loads workspace items and builds their link graph. Built graphs are
+// cached per workspace for CacheTTL.
+type Builder struct {
+	client nuclino.Client
+	config Config
+	cache  *cache.Cache
+}
+
+// NewBuilder creates a link graph builder
+func NewBuilder(client nuclino.Client, config Config) *Builder {
+	defaults := DefaultConfig()
+	if config.PageSize <= 0 {
+		config.PageSize = defaults.PageSize
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+
+	return &Builder{
+		client: client,
+		config: config,
+		cache:  cache.NewCache(100, config.CacheTTL),
+	}
+}
+
+// Build returns the link graph of a workspace, reusing a cached graph unless
+// refresh is set
+func (b *Builder) Build(ctx context.Context, workspaceID string, refresh bool) (*Graph, error) {
+	if !refresh {
+		if cached, ok := b.cache.Get(workspaceID); ok {
+			if graph, ok := cached.(*Graph); ok {
+				return graph, nil
+			}
+		}
+	}
+
+	items, err := b.loadItems(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &Graph{
+		WorkspaceID: workspaceID,
+		BuiltAt:     time.Now(),
+		Nodes:       make(map[string]*Node, len(items)),
+		Items:       items,
+	}
+	for id, item := range items {
+		graph.Nodes[id] = &Node{ID: id, Title: item.Title, URL: item.URL, Outgoing: []string{}, Backlinks: []string{}}
+	}
+
+	missing := make(map[string][]string) // target ID -> source IDs
+	for _, id := range sortedKeys(items) {
+		node := graph.Nodes[id]
+		for _, target := range outgoingLinks(items[id]) {
+			if target == id {
+				continue
+			}
+			if targetNode, ok := graph.Nodes[target]; ok {
+				node.Outgoing = append(node.Outgoing, target)
+				targetNode.Backlinks = append(targetNode.Backlinks, id)
+				continue
+			}
+			missing[target] = append(missing[target], id)
+		}
+	}
+
+	if err := b.resolveMissing(ctx, graph, missing); err != nil {
+		return nil, err
+	}
+
+	b.cache.Set(workspaceID, graph)
+	return graph, nil
+}
+
+// Invalidate drops the cached graph of a workspace
+func (b *Builder) Invalidate(workspaceID string) {
+	b.cache.Delete(workspaceID)
+}
+
+// resolveMissing looks up link targets outside the workspace: items in other
+// workspaces are kept as external links, the rest are recorded as broken.
+func (b *Builder) resolveMissing(ctx context.Context, graph *Graph, missing map[string][]string) error {
+	for _, target := range sortedKeys(missing) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		reason, detail := "", ""
+		if _, err := b.client.GetItem(ctx, target); err != nil {
+			switch {
+			case nuclino.IsNotFound(err):
+				reason = ReasonNotFound
+			case nuclino.IsForbidden(err) || nuclino.IsUnauthorized(err):
+				reason = ReasonInaccessible
+			default:
+				reason, detail = ReasonUnresolved, err.Error()
+			}
+		}
+
+		for _, source := range missing[target] {
+			node := graph.Nodes[source]
+			if reason == "" {
+				node.External = append(node.External, target)
+				continue
+			}
+			graph.Broken = append(graph.Broken, BrokenLink{
+				SourceID:    source,
+				SourceTitle: node.Title,
+				TargetID:    target,
+				Reason:      reason,
+				Detail:      detail,
+			})
+		}
+	}
+	return nil
+}
+
+// loadItems lists the workspace and fetches every item's content with a
+// bounded number of concurrent requests. The first failed fetch cancels
+// the others, whose results would be thrown away.
+func (b *Builder) loadItems(ctx context.Context, workspaceID string) (map[string]*nuclino.Item, error) {
+	listed, err := nuclino.ListAllItems(ctx, b.client, workspaceID, b.config.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace items: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	items := make(map[string]*nuclino.Item, len(listed))
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, b.config.Concurrency)
+
+	for i := range listed {
+		summary := listed[i]
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item, err := b.client.GetItem(ctx, summary.ID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get item %s: %w", summary.ID, err)
+					cancel()
+				}
+				return
+			}
+			items[summary.ID] = item
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// outgoingLinks merges the API's contentMeta.itemIds with links parsed from
+// the Markdown content, without duplicates
+func outgoingLinks(item *nuclino.Item) []string {
+	seen := make(map[string]bool)
+	var targets []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			targets = append(targets, id)
+		}
+	}
+	for _, id := range item.ContentMeta.ItemIDs {
+		add(id)
+	}
+	for _, link := range ExtractLinks(item.Content) {
+		add(link.TargetID)
+	}
+	return targets
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Title != nodes[j].Title {
+			return nodes[i].Title < nodes[j].Title
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,161 @@
+package linkgraph
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+const (
+	idHome    = "11111111-1111-1111-1111-111111111111"
+	idGuide   = "22222222-2222-2222-2222-222222222222"
+	idFAQ     = "33333333-3333-3333-3333-333333333333"
+	idOrphan  = "44444444-4444-4444-4444-444444444444"
+	idDeleted = "55555555-5555-5555-5555-555555555555"
+	idPrivate = "66666666-6666-6666-6666-666666666666"
+	idOther   = "77777777-7777-7777-7777-777777777777"
+)
+
+type fakeClient struct {
+	nuclino.Client
+	items   map[string]*nuclino.Item
+	foreign map[string]error
+	lists   int
+	gets    int
+	// fail makes every GetItem fail
+	fail error
+}
+
+func (f *fakeClient) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*nuclino.ItemsResponse, error) {
+	f.lists++
+	ids := make([]string, 0, len(f.items))
+	for id := range f.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var results []nuclino.Item
+	for _, id := range ids {
+		listed := *f.items[id]
+		listed.Content = ""
+		results = append(results, listed)
+	}
+	if offset >= len(results) {
+		return &nuclino.ItemsResponse{}, nil
+	}
+	results = results[offset:]
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return &nuclino.ItemsResponse{Results: results}, nil
+}
+
+func (f *fakeClient) GetItem(ctx context.Context, itemID string) (*nuclino.Item, error) {
+	f.gets++
+	if f.fail != nil {
+		return nil, f.fail
+	}
+	if item, ok := f.items[itemID]; ok {
+		return item, nil
+	}
+	if err, ok := f.foreign[itemID]; ok {
+		if err != nil {
+			return nil, err
+		}
+		return &nuclino.Item{ID: itemID, WorkspaceID: "other"}, nil
+	}
+	return nil, nuclino.NewAPIError(404, "item not found")
+}
+
+func newWorkspace() *fakeClient {
+	return &fakeClient{
+		items: map[string]*nuclino.Item{
+			idHome: {ID: idHome, Title: "Home", Content: "See the [guide](https://app.nuclino.com/Acme/Docs/Guide-" + idGuide + ") and [FAQ](/Acme/Docs/" + idFAQ + ").",
+				ContentMeta: nuclino.ContentMeta{ItemIDs: []string{idGuide}}},
+			idGuide: {ID: idGuide, Title: "Guide", Content: "Back [home](https://app.nuclino.com/Acme/Docs/Home-" + idHome + ")\n" +
+				"[old page](https://app.nuclino.com/Acme/Docs/Old-" + idDeleted + ")\n" +
+				"[secret](https://app.nuclino.com/Acme/HR/Secret-" + idPrivate + ")\n" +
+				"[elsewhere](https://app.nuclino.com/Acme/Eng/Page-" + idOther + ")\n" +
+				"[external](https://example.com/" + idFAQ + ")"},
+			idFAQ:    {ID: idFAQ, Title: "FAQ", Content: "No links, only [myself](/Acme/Docs/" + idFAQ + ")"},
+			idOrphan: {ID: idOrphan, Title: "Orphan", Content: "Links to [FAQ](/Acme/Docs/FAQ-" + idFAQ + ")"},
+		},
+		foreign: map[string]error{
+			idPrivate: nuclino.NewAPIError(403, "forbidden"),
+			idOther:   nil,
+		},
+	}
+}
+
+func TestExtractLinks(t *testing.T) {
+	links := ExtractLinks("[a](https://app.nuclino.com/T/W/Title-" + idHome + "?x=1) [b](https://example.com/" + idGuide + ") [c](/T/W/" + idFAQ + " \"title\")")
+
+	require.Len(t, links, 2)
+	assert.Equal(t, idHome, links[0].TargetID)
+	assert.Equal(t, "a", links[0].Text)
+	assert.Equal(t, idFAQ, links[1].TargetID)
+}
+
+func TestBuild(t *testing.T) {
+	client := newWorkspace()
+	builder := NewBuilder(client, Config{PageSize: 2})
+
+	graph, err := builder.Build(context.Background(), "ws-1", false)
+	require.NoError(t, err)
+
+	home, _ := graph.Node(idHome)
+	assert.ElementsMatch(t, []string{idGuide, idFAQ}, home.Outgoing)
+	assert.Equal(t, []string{idGuide}, home.Backlinks)
+
+	faq, _ := graph.Node(idFAQ)
+	assert.ElementsMatch(t, []string{idHome, idOrphan}, faq.Backlinks)
+	assert.Empty(t, faq.Outgoing, "self links are ignored")
+
+	guide, _ := graph.Node(idGuide)
+	assert.Equal(t, []string{idOther}, guide.External)
+
+	orphans := graph.Orphans()
+	require.Len(t, orphans, 1)
+	assert.Equal(t, idOrphan, orphans[0].ID)
+
+	broken := graph.BrokenFrom(idGuide)
+	require.Len(t, broken, 2)
+	reasons := map[string]string{}
+	for _, link := range broken {
+		reasons[link.TargetID] = link.Reason
+	}
+	assert.Equal(t, ReasonNotFound, reasons[idDeleted])
+	assert.Equal(t, ReasonInaccessible, reasons[idPrivate])
+
+	assert.Equal(t, idFAQ, graph.MostLinked(1)[0].ID)
+}
+
+func TestBuild_UsesCache(t *testing.T) {
+	client := newWorkspace()
+	builder := NewBuilder(client, DefaultConfig())
+
+	_, err := builder.Build(context.Background(), "ws-1", false)
+	require.NoError(t, err)
+	_, err = builder.Build(context.Background(), "ws-1", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.lists)
+
+	_, err = builder.Build(context.Background(), "ws-1", true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.lists)
+}
+
+func TestBuild_StopsAtFirstFailedFetch(t *testing.T) {
+	client := newWorkspace()
+	client.fail = nuclino.NewAPIError(500, "unavailable")
+	builder := NewBuilder(client, Config{Concurrency: 1})
+
+	_, err := builder.Build(context.Background(), "ws-1", false)
+	require.Error(t, err)
+	assert.Equal(t, 1, client.gets, "no item is fetched after the first failure")
+}
@@ -0,0 +1,46 @@
+package linkgraph
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	itemIDPattern       = regexp.MustCompile(`([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})/?(?:[?#].*)?$`)
+)
+
+// MarkdownLink is a link to another Nuclino item found in Markdown content
+type MarkdownLink struct {
+	Text     string `json:"text"`
+	URL      string `json:"url"`
+	TargetID string `json:"target_id"`
+}
+
+// ExtractLinks returns the links in content that point at Nuclino items.
+// Item URLs end with the item ID, either bare or after a title slug
+// ("https://app.nuclino.com/Team/Workspace/Some-Title-<id>").
+func ExtractLinks(content string) []MarkdownLink {
+	var links []MarkdownLink
+	for _, match := range markdownLinkPattern.FindAllStringSubmatch(content, -1) {
+		url := match[2]
+		if !isItemURL(url) {
+			continue
+		}
+		id := itemIDPattern.FindStringSubmatch(url)
+		if id == nil {
+			continue
+		}
+		links = append(links, MarkdownLink{Text: match[1], URL: url, TargetID: strings.ToLower(id[1])})
+	}
+	return links
+}
+
+func isItemURL(url string) bool {
+	lower := strings.ToLower(url)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		return strings.Contains(lower, "nuclino.com/")
+	}
+	// Relative links such as "/Team/Workspace/Title-<id>" are item links too
+	return strings.HasPrefix(lower, "/")
+}
@@ -0,0 +1,257 @@
+// Package lint checks the Markdown content of workspace items against a set
+// of pluggable content quality rules.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// Severity ranks how serious an issue is
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// MarshalText encodes the severity by name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity parses "info", "warning" or "error"
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(name) {
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityInfo, fmt.Errorf("unknown severity %q (use info, warning or error)", name)
+}
+
+// Issue is a single rule violation
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Line     int      `json:"line,omitempty"`
+}
+
+// Rule checks one aspect of an item's content
+type Rule interface {
+	Name() string
+	Check(doc *Document) []Issue
+}
+
+// Document is the item being linted together with workspace context
+type Document struct {
+	Item     *nuclino.Item
+	Headings []Heading
+	Lines    []Line
+	// Broken holds the item's links to deleted or inaccessible items
+	Broken []linkgraph.BrokenLink
+	// TitleCount counts items per normalized title across the workspace
+	TitleCount map[string]int
+	Now        time.Time
+}
+
+// Config holds linter configuration
+type Config struct {
+	// StaleDays flags items not updated for this many days; 0 disables the rule
+	StaleDays int
+	// MaxChars flags items with more content characters; 0 disables the rule
+	MaxChars int
+	// Disabled lists rule names to skip
+	Disabled []string
+	// MinSeverity drops issues below this severity from reports
+	MinSeverity Severity
+}
+
+// DefaultConfig returns the default linter configuration
+func DefaultConfig() Config {
+	return Config{
+		StaleDays: 180,
+		MaxChars:  50000,
+	}
+}
+
+// Linter runs the registered rules over items
+type Linter struct {
+	config Config
+	rules  []Rule
+}
+
+// NewLinter creates a linter with the built-in rules
+func NewLinter(config Config) *Linter {
+	l := &Linter{config: config}
+
+	l.Register(missingH1Rule{})
+	l.Register(duplicateTitleRule{})
+	l.Register(emptySectionRule{})
+	l.Register(headingLevelsRule{})
+	l.Register(brokenLinkRule{})
+	l.Register(todoMarkerRule{})
+	if config.MaxChars > 0 {
+		l.Register(oversizedRule{maxChars: config.MaxChars})
+	}
+	if config.StaleDays > 0 {
+		l.Register(staleRule{maxAge: time.Duration(config.StaleDays) * 24 * time.Hour})
+	}
+
+	return l
+}
+
+// Register adds a rule unless it is disabled in the configuration
+func (l *Linter) Register(rule Rule) {
+	for _, name := range l.config.Disabled {
+		if name == rule.Name() {
+			return
+		}
+	}
+	l.rules = append(l.rules, rule)
+}
+
+// Rules returns the names of the active rules
+func (l *Linter) Rules() []string {
+	names := make([]string, len(l.rules))
+	for i, rule := range l.rules {
+		names[i] = rule.Name()
+	}
+	return names
+}
+
+// ItemReport holds the issues found in one item
+type ItemReport struct {
+	ItemID string  `json:"item_id"`
+	Title  string  `json:"title"`
+	URL    string  `json:"url,omitempty"`
+	Issues []Issue `json:"issues"`
+}
+
+// Report is the result of linting a workspace
+type Report struct {
+	WorkspaceID  string         `json:"workspace_id"`
+	Rules        []string       `json:"rules"`
+	ItemsChecked int            `json:"items_checked"`
+	Summary      map[string]int `json:"summary"`
+	Items        []ItemReport   `json:"items"`
+}
+
+// HasIssuesAtLeast reports whether any issue reaches the given severity
+func (r *Report) HasIssuesAtLeast(severity Severity) bool {
+	for _, item := range r.Items {
+		for _, issue := range item.Issues {
+			if issue.Severity >= severity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LintGraph lints every item of a link graph. When itemIDs is non-empty only
+// those items are reported, but workspace-wide rules still see all items.
+func (l *Linter) LintGraph(graph *linkgraph.Graph, itemIDs ...string) *Report {
+	titles := make(map[string]int, len(graph.Items))
+	for _, item := range graph.Items {
+		titles[normalizeTitle(item.Title)]++
+	}
+
+	ids := itemIDs
+	if len(ids) == 0 {
+		for id := range graph.Items {
+			ids = append(ids, id)
+		}
+	}
+
+	report := &Report{
+		WorkspaceID: graph.WorkspaceID,
+		Rules:       l.Rules(),
+		Summary:     map[string]int{"error": 0, "warning": 0, "info": 0},
+		Items:       []ItemReport{},
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		item, ok := graph.Items[id]
+		if !ok {
+			continue
+		}
+		report.ItemsChecked++
+
+		doc := newDocument(item)
+		doc.Broken = graph.BrokenFrom(id)
+		doc.TitleCount = titles
+		doc.Now = now
+
+		issues := l.check(doc)
+		if len(issues) == 0 {
+			continue
+		}
+		for _, issue := range issues {
+			report.Summary[issue.Severity.String()]++
+		}
+		report.Items = append(report.Items, ItemReport{ItemID: id, Title: item.Title, URL: item.URL, Issues: issues})
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		if report.Items[i].Title != report.Items[j].Title {
+			return report.Items[i].Title < report.Items[j].Title
+		}
+		return report.Items[i].ItemID < report.Items[j].ItemID
+	})
+	return report
+}
+
+// LintItem lints a single item without workspace context
+func (l *Linter) LintItem(item *nuclino.Item) []Issue {
+	doc := newDocument(item)
+	doc.TitleCount = map[string]int{normalizeTitle(item.Title): 1}
+	doc.Now = time.Now()
+	return l.check(doc)
+}
+
+func (l *Linter) check(doc *Document) []Issue {
+	var issues []Issue
+	for _, rule := range l.rules {
+		for _, issue := range rule.Check(doc) {
+			if issue.Severity < l.config.MinSeverity {
+				continue
+			}
+			issue.Rule = rule.Name()
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity > issues[j].Severity
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
@@ -0,0 +1,111 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func rulesHit(issues []Issue) map[string]int {
+	hits := make(map[string]int)
+	for _, issue := range issues {
+		hits[issue.Rule]++
+	}
+	return hits
+}
+
+func TestLintItem_Structure(t *testing.T) {
+	linter := NewLinter(DefaultConfig())
+	item := &nuclino.Item{
+		ID:            "item-1",
+		Title:         "Runbook",
+		LastUpdatedAt: time.Now(),
+		Content: strings.Join([]string{
+			"# Runbook",
+			"Intro text. TODO: fill in contacts",
+			"### Skipped level",
+			"details",
+			"## Empty",
+			"## Filled",
+			"```",
+			"# not a heading, FIXME in code is fine",
+			"```",
+			"## Trailing empty",
+		}, "\n"),
+	}
+
+	issues := linter.LintItem(item)
+	hits := rulesHit(issues)
+
+	assert.Equal(t, 1, hits["todo-marker"])
+	assert.Equal(t, 1, hits["heading-levels"])
+	assert.Equal(t, 2, hits["empty-section"])
+	assert.Zero(t, hits["missing-h1"])
+	assert.Zero(t, hits["stale"])
+}
+
+func TestLintItem_MissingH1StaleAndOversized(t *testing.T) {
+	linter := NewLinter(Config{StaleDays: 30, MaxChars: 10})
+	item := &nuclino.Item{
+		ID:            "item-1",
+		Title:         "Old",
+		Content:       "just a long paragraph",
+		LastUpdatedAt: time.Now().AddDate(0, 0, -45),
+	}
+
+	hits := rulesHit(linter.LintItem(item))
+
+	assert.Equal(t, 1, hits["missing-h1"])
+	assert.Equal(t, 1, hits["stale"])
+	assert.Equal(t, 1, hits["oversized"])
+}
+
+func TestLinter_DisabledAndMinSeverity(t *testing.T) {
+	linter := NewLinter(Config{Disabled: []string{"todo-marker"}, MinSeverity: SeverityWarning})
+	item := &nuclino.Item{Title: "Notes", Content: "TODO later"}
+
+	assert.NotContains(t, linter.Rules(), "todo-marker")
+	assert.Empty(t, linter.LintItem(item), "missing-h1 is info and filtered out")
+}
+
+func TestLintGraph(t *testing.T) {
+	graph := &linkgraph.Graph{
+		WorkspaceID: "ws-1",
+		Items: map[string]*nuclino.Item{
+			"a": {ID: "a", Title: "Setup", Content: "# Setup\nsee [old](https://app.nuclino.com/T/W/Old-dead)"},
+			"b": {ID: "b", Title: "setup ", Content: "# Setup\ncopy"},
+			"c": {ID: "c", Title: "Clean", Content: "# Clean\nAll good"},
+		},
+		Broken: []linkgraph.BrokenLink{{SourceID: "a", TargetID: "dead", Reason: linkgraph.ReasonNotFound}},
+	}
+
+	report := NewLinter(DefaultConfig()).LintGraph(graph)
+
+	assert.Equal(t, 3, report.ItemsChecked)
+	require.Len(t, report.Items, 2)
+	assert.Equal(t, "a", report.Items[0].ItemID)
+	assert.Equal(t, "broken-link", report.Items[0].Issues[0].Rule)
+	assert.Equal(t, 2, report.Items[0].Issues[0].Line)
+	assert.Equal(t, 1, report.Summary["error"])
+	assert.Equal(t, 2, report.Summary["warning"])
+	assert.True(t, report.HasIssuesAtLeast(SeverityError))
+
+	single := NewLinter(DefaultConfig()).LintGraph(graph, "b")
+	assert.Equal(t, 1, single.ItemsChecked)
+	assert.False(t, single.HasIssuesAtLeast(SeverityError))
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := ParseSeverity("Warning")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityWarning, s)
+
+	_, err = ParseSeverity("fatal")
+	assert.Error(t, err)
+}
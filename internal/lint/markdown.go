@@ -0,0 +1,50 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/outline"
+)
+
+// Line is a line of item content
+type Line struct {
+	Number int
+	Text   string
+	InCode bool
+}
+
+// Heading is an ATX heading ("## Title") outside code blocks
+type Heading struct {
+	Level int
+	Text  string
+	Line  int
+}
+
+func newDocument(item *nuclino.Item) *Document {
+	doc := &Document{Item: item}
+
+	var fence outline.Fence
+	for i, text := range strings.Split(strings.ReplaceAll(item.Content, "\r\n", "\n"), "\n") {
+		inCode := fence.Code(text)
+		doc.Lines = append(doc.Lines, Line{Number: i + 1, Text: text, InCode: inCode})
+		if inCode {
+			continue
+		}
+		if level, title, ok := outline.ParseHeading(text); ok {
+			doc.Headings = append(doc.Headings, Heading{Level: level, Text: title, Line: i + 1})
+		}
+	}
+	return doc
+}
+
+// sectionHasContent reports whether any non-blank line lies between two line
+// numbers (exclusive)
+func (d *Document) sectionHasContent(from, to int) bool {
+	for _, line := range d.Lines {
+		if line.Number > from && line.Number < to && strings.TrimSpace(line.Text) != "" {
+			return true
+		}
+	}
+	return false
+}
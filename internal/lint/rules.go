@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// missingH1Rule flags items whose content has no top-level heading
+type missingH1Rule struct{}
+
+func (missingH1Rule) Name() string { return "missing-h1" }
+
+func (missingH1Rule) Check(doc *Document) []Issue {
+	if strings.TrimSpace(doc.Item.Content) == "" {
+		return nil
+	}
+	h1 := 0
+	var second int
+	for _, h := range doc.Headings {
+		if h.Level == 1 {
+			h1++
+			if h1 == 2 {
+				second = h.Line
+			}
+		}
+	}
+	switch {
+	case h1 == 0:
+		return []Issue{{Severity: SeverityInfo, Message: "content has no H1 heading"}}
+	case h1 > 1:
+		return []Issue{{Severity: SeverityWarning, Message: fmt.Sprintf("content has %d H1 headings", h1), Line: second}}
+	}
+	return nil
+}
+
+// duplicateTitleRule flags items sharing their title with another item
+type duplicateTitleRule struct{}
+
+func (duplicateTitleRule) Name() string { return "duplicate-title" }
+
+func (duplicateTitleRule) Check(doc *Document) []Issue {
+	if count := doc.TitleCount[normalizeTitle(doc.Item.Title)]; count > 1 {
+		return []Issue{{Severity: SeverityWarning, Message: fmt.Sprintf("title %q is used by %d items", doc.Item.Title, count)}}
+	}
+	return nil
+}
+
+// emptySectionRule flags headings with neither text nor subsections below them
+type emptySectionRule struct{}
+
+func (emptySectionRule) Name() string { return "empty-section" }
+
+func (emptySectionRule) Check(doc *Document) []Issue {
+	var issues []Issue
+	for i, h := range doc.Headings {
+		end := len(doc.Lines) + 1
+		if i+1 < len(doc.Headings) {
+			next := doc.Headings[i+1]
+			if next.Level > h.Level {
+				continue // the section holds subsections
+			}
+			end = next.Line
+		}
+		if !doc.sectionHasContent(h.Line, end) {
+			issues = append(issues, Issue{Severity: SeverityWarning, Message: fmt.Sprintf("section %q is empty", h.Text), Line: h.Line})
+		}
+	}
+	return issues
+}
+
+// headingLevelsRule flags headings that skip a level, such as H1 followed by H3
+type headingLevelsRule struct{}
+
+func (headingLevelsRule) Name() string { return "heading-levels" }
+
+func (headingLevelsRule) Check(doc *Document) []Issue {
+	var issues []Issue
+	for i := 1; i < len(doc.Headings); i++ {
+		prev, h := doc.Headings[i-1], doc.Headings[i]
+		if h.Level > prev.Level+1 {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("heading %q jumps from H%d to H%d", h.Text, prev.Level, h.Level),
+				Line:     h.Line,
+			})
+		}
+	}
+	return issues
+}
+
+// brokenLinkRule flags links to deleted or inaccessible items
+type brokenLinkRule struct{}
+
+func (brokenLinkRule) Name() string { return "broken-link" }
+
+func (brokenLinkRule) Check(doc *Document) []Issue {
+	var issues []Issue
+	for _, link := range doc.Broken {
+		line := 0
+		for _, l := range doc.Lines {
+			if strings.Contains(l.Text, link.TargetID) {
+				line = l.Number
+				break
+			}
+		}
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("link to item %s is broken (%s)", link.TargetID, link.Reason),
+			Line:     line,
+		})
+	}
+	return issues
+}
+
+var todoPattern = regexp.MustCompile(`\b(TODO|FIXME|TBD|XXX)\b`)
+
+// todoMarkerRule flags TODO-style markers outside code blocks
+type todoMarkerRule struct{}
+
+func (todoMarkerRule) Name() string { return "todo-marker" }
+
+func (todoMarkerRule) Check(doc *Document) []Issue {
+	var issues []Issue
+	for _, line := range doc.Lines {
+		if line.InCode {
+			continue
+		}
+		if marker := todoPattern.FindString(line.Text); marker != "" {
+			issues = append(issues, Issue{Severity: SeverityWarning, Message: marker + " marker left in content", Line: line.Number})
+		}
+	}
+	return issues
+}
+
+// oversizedRule flags items whose content exceeds a character limit
+type oversizedRule struct {
+	maxChars int
+}
+
+func (oversizedRule) Name() string { return "oversized" }
+
+func (r oversizedRule) Check(doc *Document) []Issue {
+	if size := utf8.RuneCountInString(doc.Item.Content); size > r.maxChars {
+		return []Issue{{Severity: SeverityWarning, Message: fmt.Sprintf("content is %d characters, over the %d limit; consider splitting it", size, r.maxChars)}}
+	}
+	return nil
+}
+
+// staleRule flags items not updated within maxAge
+type staleRule struct {
+	maxAge time.Duration
+}
+
+func (staleRule) Name() string { return "stale" }
+
+func (r staleRule) Check(doc *Document) []Issue {
+	modified := doc.Item.ModifiedAt()
+	if modified.IsZero() {
+		return nil
+	}
+	if age := doc.Now.Sub(modified); age > r.maxAge {
+		return []Issue{{Severity: SeverityWarning, Message: fmt.Sprintf("not updated for %d days (last update %s)", int(age.Hours()/24), modified.Format("2006-01-02"))}}
+	}
+	return nil
+}
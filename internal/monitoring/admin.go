@@ -0,0 +1,188 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// readinessTimeout bounds each readiness check
+const readinessTimeout = 5 * time.Second
+
+// ReadinessCheck reports whether a dependency is ready to serve
+type ReadinessCheck func(ctx context.Context) error
+
+// AdminServer serves /metrics, /healthz and /readyz on a separate listener
+// from MCP traffic
+type AdminServer struct {
+	collector *MetricsCollector
+	ready     atomic.Bool
+
+	mu     sync.Mutex
+	checks map[string]ReadinessCheck
+
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewAdminServer creates an admin server for collector listening on addr,
+// e.g. "127.0.0.1:9090"
+func NewAdminServer(addr string, collector *MetricsCollector) *AdminServer {
+	s := &AdminServer{
+		collector: collector,
+		checks:    make(map[string]ReadinessCheck),
+	}
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// AddReadinessCheck adds a check /readyz runs on every request
+func (s *AdminServer) AddReadinessCheck(name string, check ReadinessCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
+// SetReady marks whether the server accepts MCP requests; /readyz fails
+// until it is set
+func (s *AdminServer) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+// Handler returns the admin HTTP handler
+func (s *AdminServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+	return mux
+}
+
+// Start listens and serves in the background
+func (s *AdminServer) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("Admin server failed")
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server listens on once started
+func (s *AdminServer) Addr() string {
+	if s.listener == nil {
+		return s.server.Addr
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops the server, waiting for open requests
+func (s *AdminServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *AdminServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", PrometheusContentType)
+	if err := s.collector.WritePrometheus(w); err != nil {
+		log.Debug().Err(err).Msg("Failed to write metrics")
+	}
+}
+
+// handleHealth reports the metric-based health checks; it fails on high
+// error rates, slow responses or an open circuit breaker
+func (s *AdminServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := s.collector.HealthCheck()
+	status := http.StatusOK
+	if !health.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, health)
+}
+
+// handleReady runs the readiness checks
+func (s *AdminServer) handleReady(w http.ResponseWriter, r *http.Request) {
+	result := HealthStatus{
+		Healthy:   true,
+		Timestamp: time.Now(),
+		Checks:    make(map[string]CheckResult),
+	}
+	if s.ready.Load() {
+		result.Checks["server"] = CheckResult{Healthy: true, Message: "serving"}
+	} else {
+		result.Healthy = false
+		result.Checks["server"] = CheckResult{Healthy: false, Message: "not serving yet"}
+	}
+
+	s.mu.Lock()
+	names := make([]string, 0, len(s.checks))
+	for name := range s.checks {
+		names = append(names, name)
+	}
+	checks := make(map[string]ReadinessCheck, len(s.checks))
+	for name, check := range s.checks {
+		checks[name] = check
+	}
+	s.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		err := checks[name](ctx)
+		cancel()
+		if err != nil {
+			result.Healthy = false
+			result.Checks[name] = CheckResult{Healthy: false, Message: err.Error()}
+			continue
+		}
+		result.Checks[name] = CheckResult{Healthy: true, Message: "ok"}
+	}
+
+	status := http.StatusOK
+	if !result.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, result)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// CachedCheck runs check at most once per ttl, reusing its last result, so
+// frequent probes do not spend API rate limit
+func CachedCheck(check ReadinessCheck, ttl time.Duration) ReadinessCheck {
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		last    error
+	)
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !checked.IsZero() && time.Since(checked) < ttl {
+			return last
+		}
+		last = check(ctx)
+		checked = time.Now()
+		return last
+	}
+}
@@ -0,0 +1,97 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.RecordRequest(true, 100*time.Millisecond, "")
+	collector.RecordRequest(false, 50*time.Millisecond, "http_429")
+	collector.RecordToolCall("nuclino_get_item", true, 20*time.Millisecond)
+	collector.RecordToolCall("nuclino_get_item", false, 40*time.Millisecond)
+	collector.IncrementActiveConnections()
+
+	var out strings.Builder
+	require.NoError(t, collector.WritePrometheus(&out))
+	text := out.String()
+
+	assert.Contains(t, text, "# TYPE nuclino_mcp_api_requests_total counter\n")
+	assert.Contains(t, text, "nuclino_mcp_api_requests_total{outcome=\"success\"} 1\n")
+	assert.Contains(t, text, "nuclino_mcp_api_errors_total{type=\"http_429\"} 1\n")
+	assert.Contains(t, text, "nuclino_mcp_tool_calls_total{tool=\"nuclino_get_item\",outcome=\"failure\"} 1\n")
+	assert.Contains(t, text, "nuclino_mcp_tool_latency_seconds{tool=\"nuclino_get_item\",stat=\"max\"} 0.04\n")
+	assert.Contains(t, text, "nuclino_mcp_active_connections 1\n")
+	assert.Contains(t, text, "go_goroutines ")
+	assert.Contains(t, text, "go_memstats_heap_inuse_bytes ")
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		assert.Len(t, strings.Fields(line), 2, "sample line %q", line)
+	}
+}
+
+func TestAdminServer_Endpoints(t *testing.T) {
+	collector := NewMetricsCollector()
+	admin := NewAdminServer("127.0.0.1:0", collector)
+	failing := errors.New("unauthorized")
+	var apiErr error
+	admin.AddReadinessCheck("nuclino_api", func(ctx context.Context) error { return apiErr })
+
+	server := httptest.NewServer(admin.Handler())
+	defer server.Close()
+
+	get := func(path string) (int, map[string]interface{}) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		}
+		return resp.StatusCode, body
+	}
+
+	status, _ := get("/metrics")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body := get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, body["healthy"])
+
+	status, _ = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status, "not ready before SetReady")
+
+	admin.SetReady(true)
+	status, _ = get("/readyz")
+	assert.Equal(t, http.StatusOK, status)
+
+	apiErr = failing
+	status, body = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	checks := body["checks"].(map[string]interface{})
+	assert.Equal(t, "unauthorized", checks["nuclino_api"].(map[string]interface{})["message"])
+}
+
+func TestCachedCheck(t *testing.T) {
+	calls := 0
+	check := CachedCheck(func(ctx context.Context) error {
+		calls++
+		return nil
+	}, time.Hour)
+	require.NoError(t, check(context.Background()))
+	require.NoError(t, check(context.Background()))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,44 @@
+package monitoring
+
+import (
+	"runtime/metrics"
+	"sync"
+)
+
+// CPU time estimates of the Go runtime, in CPU seconds since start
+const (
+	cpuTotalMetric = "/cpu/classes/total:cpu-seconds"
+	cpuIdleMetric  = "/cpu/classes/idle:cpu-seconds"
+)
+
+// cpuSampler measures CPU usage between snapshots from the runtime's CPU
+// time estimates. The first snapshot covers the time since start.
+type cpuSampler struct {
+	mu    sync.Mutex
+	busy  float64
+	total float64
+	last  float64
+}
+
+// usage returns the percentage of available CPU time spent busy since the
+// previous call
+func (s *cpuSampler) usage() float64 {
+	samples := []metrics.Sample{{Name: cpuTotalMetric}, {Name: cpuIdleMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindFloat64 || samples[1].Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	total := samples[0].Value.Float64()
+	busy := total - samples[1].Value.Float64()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deltaTotal := total - s.total
+	deltaBusy := busy - s.busy
+	if deltaTotal <= 0 {
+		return s.last
+	}
+	s.total, s.busy = total, busy
+	s.last = deltaBusy / deltaTotal * 100
+	return s.last
+}
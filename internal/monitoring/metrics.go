@@ -3,6 +3,7 @@ package monitoring
 import (
 	"encoding/json"
 	"fmt"
+	"runtime"
 	"sync"
 	"time"
 
@@ -18,6 +19,7 @@ type MetricsCollector struct {
 	toolMetrics   map[string]*ToolMetrics
 	cache         *cache.Cache
 	rateLimiter   *ratelimit.RateLimiter
+	cpu           cpuSampler
 }
 
 // ServerMetrics tracks overall server performance
@@ -48,13 +50,20 @@ type ToolMetrics struct {
 	latencies       []time.Duration // Internal for calculations
 }
 
-// SystemMetrics provides system-level metrics
+// SystemMetrics provides system-level metrics of the Go runtime
 type SystemMetrics struct {
-	CPUUsage       float64 `json:"cpu_usage"`
-	MemoryUsed     int64   `json:"memory_used_bytes"`
-	MemoryTotal    int64   `json:"memory_total_bytes"`
-	GoroutineCount int     `json:"goroutine_count"`
-	GCPauses       int64   `json:"gc_pauses_total"`
+	// CPUUsage is the share of available CPU time used since the previous
+	// snapshot, in percent
+	CPUUsage float64 `json:"cpu_usage"`
+	// MemoryUsed is the heap in use and MemoryTotal the memory obtained
+	// from the OS
+	MemoryUsed     int64         `json:"memory_used_bytes"`
+	MemoryTotal    int64         `json:"memory_total_bytes"`
+	HeapObjects    int64         `json:"heap_objects"`
+	GoroutineCount int           `json:"goroutine_count"`
+	GCPauses       int64         `json:"gc_pauses_total"`
+	GCPauseTotal   time.Duration `json:"gc_pause_total"`
+	LastGC         time.Time     `json:"last_gc,omitempty"`
 }
 
 // CombinedMetrics aggregates all metrics
@@ -314,17 +323,24 @@ func (m *MetricsCollector) updateToolStats(tool *ToolMetrics) {
 	}
 }
 
-// getSystemMetrics returns current system metrics
+// getSystemMetrics returns current runtime metrics
 func (m *MetricsCollector) getSystemMetrics() SystemMetrics {
-	// For demonstration, return basic values
-	// In production, you'd integrate with system monitoring libraries
-	return SystemMetrics{
-		CPUUsage:       0, // Would use runtime/pprof or external library
-		MemoryUsed:     0, // Would use runtime.MemStats
-		MemoryTotal:    0, // Would get from system
-		GoroutineCount: 0, // Would use runtime.NumGoroutine()
-		GCPauses:       0, // Would use runtime.MemStats
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	system := SystemMetrics{
+		CPUUsage:       m.cpu.usage(),
+		MemoryUsed:     int64(stats.HeapInuse),
+		MemoryTotal:    int64(stats.Sys),
+		HeapObjects:    int64(stats.HeapObjects),
+		GoroutineCount: runtime.NumGoroutine(),
+		GCPauses:       int64(stats.NumGC),
+		GCPauseTotal:   time.Duration(stats.PauseTotalNs),
 	}
+	if stats.LastGC > 0 {
+		system.LastGC = time.Unix(0, int64(stats.LastGC))
+	}
+	return system
 }
 
 // Reset resets all metrics
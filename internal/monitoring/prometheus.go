@@ -0,0 +1,149 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PrometheusContentType is the content type of the text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricPrefix namespaces the server's own metrics
+const metricPrefix = "nuclino_mcp_"
+
+// promWriter writes metric families in the Prometheus text format
+type promWriter struct {
+	w *bufio.Writer
+}
+
+// family writes the HELP and TYPE lines of a metric
+func (p *promWriter) family(name, kind, help string) {
+	fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one value; labels alternate names and values
+func (p *promWriter) sample(name string, value float64, labels ...string) {
+	p.w.WriteString(name)
+	if len(labels) > 0 {
+		p.w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				p.w.WriteByte(',')
+			}
+			fmt.Fprintf(p.w, "%s=%q", labels[i], escapeLabel(labels[i+1]))
+		}
+		p.w.WriteByte('}')
+	}
+	fmt.Fprintf(p.w, " %g\n", value)
+}
+
+// metric writes a family with a single unlabelled value
+func (p *promWriter) metric(name, kind, help string, value float64) {
+	p.family(name, kind, help)
+	p.sample(name, value)
+}
+
+// escapeLabel makes a label value valid UTF-8; %q then escapes
+// backslashes, quotes and newlines as Prometheus expects
+func escapeLabel(value string) string {
+	return strings.ToValidUTF8(value, "")
+}
+
+func seconds(d time.Duration) float64 {
+	return d.Seconds()
+}
+
+// WritePrometheus writes a metrics snapshot in the Prometheus text
+// exposition format
+func (m *MetricsCollector) WritePrometheus(w io.Writer) error {
+	metrics := m.GetMetrics()
+	p := &promWriter{w: bufio.NewWriter(w)}
+
+	p.metric(metricPrefix+"uptime_seconds", "gauge", "Time since the server started.", seconds(metrics.Server.Uptime))
+	p.metric(metricPrefix+"active_connections", "gauge", "Open MCP connections.", float64(metrics.Server.ActiveConnections))
+
+	// Nuclino API requests
+	name := metricPrefix + "api_requests_total"
+	p.family(name, "counter", "Nuclino API requests by outcome.")
+	p.sample(name, float64(metrics.Server.RequestsSuccessful), "outcome", "success")
+	p.sample(name, float64(metrics.Server.RequestsFailed), "outcome", "failure")
+
+	name = metricPrefix + "api_errors_total"
+	p.family(name, "counter", "Failed Nuclino API requests by error type.")
+	for _, errorType := range sortedKeys(metrics.Server.ErrorsByType) {
+		p.sample(name, float64(metrics.Server.ErrorsByType[errorType]), "type", errorType)
+	}
+
+	name = metricPrefix + "api_response_time_seconds"
+	p.family(name, "gauge", "Nuclino API response time over the last 1000 requests.")
+	p.sample(name, seconds(metrics.Server.ResponseTimeAvg), "stat", "avg")
+	p.sample(name, seconds(metrics.Server.ResponseTimeP95), "stat", "p95")
+	p.sample(name, seconds(metrics.Server.ResponseTimeP99), "stat", "p99")
+
+	// MCP tool calls
+	tools := make([]string, 0, len(metrics.Tools))
+	for tool := range metrics.Tools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	name = metricPrefix + "tool_calls_total"
+	p.family(name, "counter", "MCP tool calls by tool and outcome.")
+	for _, tool := range tools {
+		p.sample(name, float64(metrics.Tools[tool].CallsSuccessful), "tool", tool, "outcome", "success")
+		p.sample(name, float64(metrics.Tools[tool].CallsFailed), "tool", tool, "outcome", "failure")
+	}
+
+	name = metricPrefix + "tool_latency_seconds"
+	p.family(name, "gauge", "MCP tool call latency over the last 1000 calls of each tool.")
+	for _, tool := range tools {
+		p.sample(name, seconds(metrics.Tools[tool].AverageLatency), "tool", tool, "stat", "avg")
+		p.sample(name, seconds(metrics.Tools[tool].MinLatency), "tool", tool, "stat", "min")
+		p.sample(name, seconds(metrics.Tools[tool].MaxLatency), "tool", tool, "stat", "max")
+	}
+
+	if metrics.Cache != nil {
+		p.metric(metricPrefix+"cache_hits_total", "counter", "Read cache hits.", float64(metrics.Cache.Hits))
+		p.metric(metricPrefix+"cache_misses_total", "counter", "Read cache misses.", float64(metrics.Cache.Misses))
+		p.metric(metricPrefix+"cache_evictions_total", "counter", "Read cache evictions.", float64(metrics.Cache.Evictions))
+		p.metric(metricPrefix+"cache_entries", "gauge", "Read cache entries.", float64(metrics.Cache.Size))
+	}
+
+	if metrics.RateLimit != nil {
+		name = metricPrefix + "rate_limit_requests_total"
+		p.family(name, "counter", "Requests seen by the rate limiter by decision.")
+		p.sample(name, float64(metrics.RateLimit.AllowedRequests), "decision", "allowed")
+		p.sample(name, float64(metrics.RateLimit.RejectedRequests), "decision", "rejected")
+		p.metric(metricPrefix+"circuit_breaker_trips_total", "counter", "Times the circuit breaker opened.", float64(metrics.RateLimit.CircuitBreakerTrips))
+		open := 0.0
+		if metrics.RateLimit.CircuitBreakerState == "open" {
+			open = 1
+		}
+		p.metric(metricPrefix+"circuit_breaker_open", "gauge", "Whether the circuit breaker is open.", open)
+	}
+
+	// Go runtime, named like the Prometheus Go collector's metrics
+	system := metrics.System
+	p.metric("go_goroutines", "gauge", "Number of goroutines that currently exist.", float64(system.GoroutineCount))
+	p.metric("go_memstats_heap_inuse_bytes", "gauge", "Number of heap bytes that are in use.", float64(system.MemoryUsed))
+	p.metric("go_memstats_sys_bytes", "gauge", "Number of bytes obtained from system.", float64(system.MemoryTotal))
+	p.metric("go_memstats_heap_objects", "gauge", "Number of allocated objects.", float64(system.HeapObjects))
+	p.metric("go_gc_cycles_total", "counter", "Number of completed GC cycles.", float64(system.GCPauses))
+	p.metric("go_gc_pause_seconds_total", "counter", "Total GC stop-the-world pause time.", seconds(system.GCPauseTotal))
+	p.metric(metricPrefix+"cpu_usage_percent", "gauge", "Share of available CPU time used by the process.", system.CPUUsage)
+
+	return p.w.Flush()
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
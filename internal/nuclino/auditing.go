@@ -0,0 +1,162 @@
+package nuclino
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+)
+
+// auditingClient records every mutation in the audit log; reads pass
+// through unrecorded
+type auditingClient struct {
+	Client
+	log     *audit.Logger
+	account string
+}
+
+// NewAuditingClient wraps a client so its create, update, move, delete and
+// upload calls are appended to log, tagged with account
+func NewAuditingClient(inner Client, log *audit.Logger, account string) Client {
+	if log == nil {
+		return inner
+	}
+	return &auditingClient{Client: inner, log: log, account: account}
+}
+
+// record logs one API call. ids are the IDs the call named; created is the
+// ID of a created object, if any.
+func (c *auditingClient) record(ctx context.Context, operation string, start time.Time, request interface{}, err error, ids ...string) {
+	record := audit.Record{
+		Time:      start.UTC(),
+		Kind:      audit.KindAPI,
+		Session:   audit.SessionFrom(ctx),
+		Account:   c.account,
+		Operation: operation,
+		Args:      requestArgs(request),
+		Outcome:   audit.OutcomeSuccess,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	for _, id := range ids {
+		if id != "" {
+			record.Affected = append(record.Affected, id)
+		}
+	}
+	if err != nil {
+		record.Outcome = audit.OutcomeError
+		record.Error = err.Error()
+	}
+	c.log.Log(record)
+}
+
+// requestArgs turns a request body into redacted record arguments
+func requestArgs(request interface{}) map[string]interface{} {
+	if request == nil {
+		return nil
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil
+	}
+	var args map[string]interface{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil
+	}
+	return audit.Redact(args)
+}
+
+func (c *auditingClient) CreateWorkspace(ctx context.Context, req *CreateWorkspaceRequest) (*Workspace, error) {
+	start := time.Now()
+	workspace, err := c.Client.CreateWorkspace(ctx, req)
+	c.record(ctx, "CreateWorkspace", start, req, err, idOf(workspace, err, func(w *Workspace) string { return w.ID }))
+	return workspace, err
+}
+
+func (c *auditingClient) UpdateWorkspace(ctx context.Context, workspaceID string, req *UpdateWorkspaceRequest) (*Workspace, error) {
+	start := time.Now()
+	workspace, err := c.Client.UpdateWorkspace(ctx, workspaceID, req)
+	c.record(ctx, "UpdateWorkspace", start, req, err, workspaceID)
+	return workspace, err
+}
+
+func (c *auditingClient) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	start := time.Now()
+	err := c.Client.DeleteWorkspace(ctx, workspaceID)
+	c.record(ctx, "DeleteWorkspace", start, nil, err, workspaceID)
+	return err
+}
+
+func (c *auditingClient) CreateCollection(ctx context.Context, req *CreateCollectionRequest) (*Collection, error) {
+	start := time.Now()
+	collection, err := c.Client.CreateCollection(ctx, req)
+	c.record(ctx, "CreateCollection", start, req, err, idOf(collection, err, func(c *Collection) string { return c.ID }), req.WorkspaceID, req.ParentID)
+	return collection, err
+}
+
+func (c *auditingClient) UpdateCollection(ctx context.Context, collectionID string, req *UpdateCollectionRequest) (*Collection, error) {
+	start := time.Now()
+	collection, err := c.Client.UpdateCollection(ctx, collectionID, req)
+	c.record(ctx, "UpdateCollection", start, req, err, collectionID)
+	return collection, err
+}
+
+func (c *auditingClient) DeleteCollection(ctx context.Context, collectionID string) error {
+	start := time.Now()
+	err := c.Client.DeleteCollection(ctx, collectionID)
+	c.record(ctx, "DeleteCollection", start, nil, err, collectionID)
+	return err
+}
+
+func (c *auditingClient) CreateItem(ctx context.Context, req *CreateItemRequest) (*Item, error) {
+	start := time.Now()
+	item, err := c.Client.CreateItem(ctx, req)
+	c.record(ctx, "CreateItem", start, req, err, idOf(item, err, func(i *Item) string { return i.ID }), req.WorkspaceID, req.ParentID)
+	return item, err
+}
+
+func (c *auditingClient) UpdateItem(ctx context.Context, itemID string, req *UpdateItemRequest) (*Item, error) {
+	start := time.Now()
+	item, err := c.Client.UpdateItem(ctx, itemID, req)
+	c.record(ctx, "UpdateItem", start, req, err, itemID)
+	return item, err
+}
+
+func (c *auditingClient) DeleteItem(ctx context.Context, itemID string) error {
+	start := time.Now()
+	err := c.Client.DeleteItem(ctx, itemID)
+	c.record(ctx, "DeleteItem", start, nil, err, itemID)
+	return err
+}
+
+func (c *auditingClient) MoveItem(ctx context.Context, itemID, collectionID string) (*Item, error) {
+	start := time.Now()
+	item, err := c.Client.MoveItem(ctx, itemID, collectionID)
+	c.record(ctx, "MoveItem", start, map[string]string{"collectionId": collectionID}, err, itemID, collectionID)
+	return item, err
+}
+
+func (c *auditingClient) UploadFile(ctx context.Context, workspaceID, filename string, data []byte) (*File, error) {
+	start := time.Now()
+	file, err := c.Client.UploadFile(ctx, workspaceID, filename, data)
+	c.record(ctx, "UploadFile", start, map[string]interface{}{"filename": filename, "size": len(data)}, err,
+		idOf(file, err, func(f *File) string { return f.ID }), workspaceID)
+	return file, err
+}
+
+func (c *auditingClient) UploadFileFrom(ctx context.Context, workspaceID, filename string, r io.Reader) (*File, error) {
+	start := time.Now()
+	file, err := c.Client.UploadFileFrom(ctx, workspaceID, filename, r)
+	c.record(ctx, "UploadFile", start, map[string]string{"filename": filename}, err,
+		idOf(file, err, func(f *File) string { return f.ID }), workspaceID)
+	return file, err
+}
+
+// idOf returns the ID of a created object, or "" when the call failed
+func idOf[T any](v *T, err error, id func(*T) string) string {
+	if err != nil || v == nil {
+		return ""
+	}
+	return id(v)
+}
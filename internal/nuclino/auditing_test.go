@@ -0,0 +1,42 @@
+package nuclino
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+)
+
+func TestAuditingClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.NewLogger(audit.Config{Path: path})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	client := NewAuditingClient(&countingClient{}, logger, "work")
+	ctx := audit.WithSession(context.Background(), &audit.Session{ID: "s1", ClientName: "test"})
+
+	title := "New title"
+	_, err = client.UpdateItem(ctx, "item-1", &UpdateItemRequest{Title: &title})
+	require.NoError(t, err)
+	_, err = client.GetItem(ctx, "item-1")
+	require.NoError(t, err)
+
+	records, err := audit.Read(path, audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1, "reads are not audited")
+	record := records[0]
+	assert.Equal(t, audit.KindAPI, record.Kind)
+	assert.Equal(t, "UpdateItem", record.Operation)
+	assert.Equal(t, "work", record.Account)
+	assert.Equal(t, "s1", record.Session.ID)
+	assert.Equal(t, []string{"item-1"}, record.Affected)
+	assert.Equal(t, "New title", record.Args["title"])
+	assert.Equal(t, audit.OutcomeSuccess, record.Outcome)
+
+	assert.Same(t, client, NewAuditingClient(client, nil, "work"), "a nil log disables auditing")
+}
@@ -0,0 +1,230 @@
+package nuclino
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/cache"
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
+)
+
+// cachingClient serves reads from an in-memory cache. Any successful
+// mutation clears the whole cache, since a changed item also changes the
+// parent's childIds, list pages and search results.
+type cachingClient struct {
+	Client
+	cache  *cache.Cache
+	config cache.CacheConfig
+}
+
+// NewCachingClient wraps a client with an in-memory read cache. Zero TTLs in
+// config fall back to cache.DefaultCacheConfig.
+func NewCachingClient(inner Client, config cache.CacheConfig) Client {
+	defaults := cache.DefaultCacheConfig()
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaults.MaxSize
+	}
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = defaults.DefaultTTL
+	}
+	if config.ItemTTL <= 0 {
+		config.ItemTTL = config.DefaultTTL
+	}
+	if config.WorkspaceTTL <= 0 {
+		config.WorkspaceTTL = config.DefaultTTL
+	}
+	if config.CollectionTTL <= 0 {
+		config.CollectionTTL = config.DefaultTTL
+	}
+	if config.SearchTTL <= 0 {
+		config.SearchTTL = config.DefaultTTL
+	}
+	return &cachingClient{
+		Client: inner,
+		cache:  cache.NewCache(config.MaxSize, config.DefaultTTL),
+		config: config,
+	}
+}
+
+// cached returns a copy of the cached value for key, or loads and caches it.
+// The lookup gets a span recording whether it hit, with the API request of
+// a miss inside it.
+func cached[T any](ctx context.Context, c *cachingClient, key string, ttl time.Duration, load func(ctx context.Context) (*T, error)) (_ *T, err error) {
+	ctx, span := tracing.Start(ctx, "nuclino.cache", attribute.String("nuclino.cache_key", key))
+	defer func() { tracing.End(span, err) }()
+
+	if value, ok := c.cache.Get(key); ok {
+		if v, ok := value.(*T); ok {
+			span.SetAttributes(attribute.Bool("nuclino.cache_hit", true))
+			copied := *v
+			return &copied, nil
+		}
+	}
+	span.SetAttributes(attribute.Bool("nuclino.cache_hit", false))
+	v, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	copied := *v
+	c.cache.SetWithTTL(key, &copied, ttl)
+	return v, nil
+}
+
+// invalidate clears the cache after a successful mutation
+func (c *cachingClient) invalidate(err error) {
+	if err == nil {
+		c.cache.Clear()
+	}
+}
+
+func (c *cachingClient) GetUser(ctx context.Context, userID string) (*User, error) {
+	return cached(ctx, c, "user:"+userID, c.config.DefaultTTL, func(ctx context.Context) (*User, error) {
+		return c.Client.GetUser(ctx, userID)
+	})
+}
+
+func (c *cachingClient) ListTeams(ctx context.Context, limit, offset int) (*TeamsResponse, error) {
+	return cached(ctx, c, fmt.Sprintf("teams:%d:%d", limit, offset), c.config.DefaultTTL, func(ctx context.Context) (*TeamsResponse, error) {
+		return c.Client.ListTeams(ctx, limit, offset)
+	})
+}
+
+func (c *cachingClient) GetTeam(ctx context.Context, teamID string) (*Team, error) {
+	return cached(ctx, c, "team:"+teamID, c.config.DefaultTTL, func(ctx context.Context) (*Team, error) {
+		return c.Client.GetTeam(ctx, teamID)
+	})
+}
+
+func (c *cachingClient) ListWorkspaces(ctx context.Context, limit, offset int) (*WorkspacesResponse, error) {
+	return cached(ctx, c, fmt.Sprintf("workspaces:%d:%d", limit, offset), c.config.WorkspaceTTL, func(ctx context.Context) (*WorkspacesResponse, error) {
+		return c.Client.ListWorkspaces(ctx, limit, offset)
+	})
+}
+
+func (c *cachingClient) GetWorkspace(ctx context.Context, workspaceID string) (*Workspace, error) {
+	return cached(ctx, c, "workspace:"+workspaceID, c.config.WorkspaceTTL, func(ctx context.Context) (*Workspace, error) {
+		return c.Client.GetWorkspace(ctx, workspaceID)
+	})
+}
+
+func (c *cachingClient) CreateWorkspace(ctx context.Context, req *CreateWorkspaceRequest) (*Workspace, error) {
+	workspace, err := c.Client.CreateWorkspace(ctx, req)
+	c.invalidate(err)
+	return workspace, err
+}
+
+func (c *cachingClient) UpdateWorkspace(ctx context.Context, workspaceID string, req *UpdateWorkspaceRequest) (*Workspace, error) {
+	workspace, err := c.Client.UpdateWorkspace(ctx, workspaceID, req)
+	c.invalidate(err)
+	return workspace, err
+}
+
+func (c *cachingClient) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	err := c.Client.DeleteWorkspace(ctx, workspaceID)
+	c.invalidate(err)
+	return err
+}
+
+func (c *cachingClient) ListCollections(ctx context.Context, workspaceID string, limit, offset int) (*CollectionsResponse, error) {
+	key := fmt.Sprintf("collections:%s:%d:%d", workspaceID, limit, offset)
+	return cached(ctx, c, key, c.config.CollectionTTL, func(ctx context.Context) (*CollectionsResponse, error) {
+		return c.Client.ListCollections(ctx, workspaceID, limit, offset)
+	})
+}
+
+func (c *cachingClient) GetCollection(ctx context.Context, collectionID string) (*Collection, error) {
+	return cached(ctx, c, "collection:"+collectionID, c.config.CollectionTTL, func(ctx context.Context) (*Collection, error) {
+		return c.Client.GetCollection(ctx, collectionID)
+	})
+}
+
+func (c *cachingClient) CreateCollection(ctx context.Context, req *CreateCollectionRequest) (*Collection, error) {
+	collection, err := c.Client.CreateCollection(ctx, req)
+	c.invalidate(err)
+	return collection, err
+}
+
+func (c *cachingClient) UpdateCollection(ctx context.Context, collectionID string, req *UpdateCollectionRequest) (*Collection, error) {
+	collection, err := c.Client.UpdateCollection(ctx, collectionID, req)
+	c.invalidate(err)
+	return collection, err
+}
+
+func (c *cachingClient) DeleteCollection(ctx context.Context, collectionID string) error {
+	err := c.Client.DeleteCollection(ctx, collectionID)
+	c.invalidate(err)
+	return err
+}
+
+func (c *cachingClient) SearchItems(ctx context.Context, req *SearchItemsRequest) (*ItemsResponse, error) {
+	key := fmt.Sprintf("search:%s:%d:%d:%s", req.WorkspaceID, req.Limit, req.Offset, req.Query)
+	return cached(ctx, c, key, c.config.SearchTTL, func(ctx context.Context) (*ItemsResponse, error) {
+		return c.Client.SearchItems(ctx, req)
+	})
+}
+
+func (c *cachingClient) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*ItemsResponse, error) {
+	key := fmt.Sprintf("items:%s:%d:%d", workspaceID, limit, offset)
+	return cached(ctx, c, key, c.config.ItemTTL, func(ctx context.Context) (*ItemsResponse, error) {
+		return c.Client.ListItems(ctx, workspaceID, limit, offset)
+	})
+}
+
+func (c *cachingClient) GetItem(ctx context.Context, itemID string) (*Item, error) {
+	return cached(ctx, c, "item:"+itemID, c.config.ItemTTL, func(ctx context.Context) (*Item, error) {
+		return c.Client.GetItem(ctx, itemID)
+	})
+}
+
+func (c *cachingClient) CreateItem(ctx context.Context, req *CreateItemRequest) (*Item, error) {
+	item, err := c.Client.CreateItem(ctx, req)
+	c.invalidate(err)
+	return item, err
+}
+
+func (c *cachingClient) UpdateItem(ctx context.Context, itemID string, req *UpdateItemRequest) (*Item, error) {
+	item, err := c.Client.UpdateItem(ctx, itemID, req)
+	c.invalidate(err)
+	return item, err
+}
+
+func (c *cachingClient) DeleteItem(ctx context.Context, itemID string) error {
+	err := c.Client.DeleteItem(ctx, itemID)
+	c.invalidate(err)
+	return err
+}
+
+func (c *cachingClient) MoveItem(ctx context.Context, itemID, collectionID string) (*Item, error) {
+	item, err := c.Client.MoveItem(ctx, itemID, collectionID)
+	c.invalidate(err)
+	return item, err
+}
+
+func (c *cachingClient) ListFiles(ctx context.Context, workspaceID string, limit, offset int) (*FilesResponse, error) {
+	key := fmt.Sprintf("files:%s:%d:%d", workspaceID, limit, offset)
+	return cached(ctx, c, key, c.config.DefaultTTL, func(ctx context.Context) (*FilesResponse, error) {
+		return c.Client.ListFiles(ctx, workspaceID, limit, offset)
+	})
+}
+
+// GetFile is not cached: the signed download URL it carries expires sooner
+// than cached entries do
+func (c *cachingClient) GetFile(ctx context.Context, fileID string) (*File, error) {
+	return c.Client.GetFile(ctx, fileID)
+}
+
+func (c *cachingClient) UploadFile(ctx context.Context, workspaceID, filename string, data []byte) (*File, error) {
+	file, err := c.Client.UploadFile(ctx, workspaceID, filename, data)
+	c.invalidate(err)
+	return file, err
+}
+
+func (c *cachingClient) UploadFileFrom(ctx context.Context, workspaceID, filename string, r io.Reader) (*File, error) {
+	file, err := c.Client.UploadFileFrom(ctx, workspaceID, filename, r)
+	c.invalidate(err)
+	return file, err
+}
@@ -0,0 +1,73 @@
+package nuclino
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/cache"
+)
+
+// countingClient counts item reads; methods not used here panic through
+// the embedded nil interface
+type countingClient struct {
+	Client
+	gets int
+}
+
+func (c *countingClient) GetItem(ctx context.Context, itemID string) (*Item, error) {
+	c.gets++
+	if itemID == "missing" {
+		return nil, NewAPIError(404, "not found")
+	}
+	return &Item{ID: itemID, Title: "Title"}, nil
+}
+
+func (c *countingClient) GetFile(ctx context.Context, fileID string) (*File, error) {
+	c.gets++
+	return &File{ID: fileID, Download: &FileDownload{URL: "https://files.example/" + fileID}}, nil
+}
+
+func (c *countingClient) UpdateItem(ctx context.Context, itemID string, req *UpdateItemRequest) (*Item, error) {
+	return &Item{ID: itemID, Title: *req.Title}, nil
+}
+
+func TestCachingClient(t *testing.T) {
+	inner := &countingClient{}
+	client := NewCachingClient(inner, cache.CacheConfig{DefaultTTL: time.Minute})
+	ctx := context.Background()
+
+	item, err := client.GetItem(ctx, "item-1")
+	require.NoError(t, err)
+	item.Title = "changed by caller"
+
+	item, err = client.GetItem(ctx, "item-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Title", item.Title, "callers get copies")
+	assert.Equal(t, 1, inner.gets)
+
+	// Errors are not cached
+	_, err = client.GetItem(ctx, "missing")
+	assert.True(t, IsNotFound(err))
+	_, err = client.GetItem(ctx, "missing")
+	assert.Error(t, err)
+	assert.Equal(t, 3, inner.gets)
+
+	// Mutations invalidate
+	title := "New"
+	_, err = client.UpdateItem(ctx, "item-1", &UpdateItemRequest{Title: &title})
+	require.NoError(t, err)
+	_, err = client.GetItem(ctx, "item-1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, inner.gets)
+
+	// File metadata carries an expiring download URL and is always fetched
+	_, err = client.GetFile(ctx, "file-1")
+	require.NoError(t, err)
+	_, err = client.GetFile(ctx, "file-1")
+	require.NoError(t, err)
+	assert.Equal(t, 6, inner.gets)
+}
@@ -0,0 +1,67 @@
+package nuclino
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Capabilities records which optional API features work for the configured
+// account
+type Capabilities struct {
+	// Collections is true when workspaces expose childIds and entries carry
+	// their object type, so collections can be listed and created
+	Collections bool `json:"collections"`
+	// MoveItems is true when the item move endpoint exists. It cannot be
+	// probed without sending a move request, so it is configured rather
+	// than probed.
+	MoveItems bool `json:"move_items"`
+}
+
+// AllCapabilities assumes every optional feature is available
+func AllCapabilities() Capabilities {
+	return Capabilities{Collections: true, MoveItems: true}
+}
+
+// ProbeCapabilities checks optional features against the live API using
+// read-only requests. Features that cannot be confirmed are reported as
+// unavailable; MoveItems is taken from moveItems, the account's setting.
+func ProbeCapabilities(ctx context.Context, c Client, moveItems bool) Capabilities {
+	caps := Capabilities{
+		Collections: probeCollections(ctx, c),
+		MoveItems:   moveItems,
+	}
+	log.Info().
+		Bool("collections", caps.Collections).
+		Bool("move_items", caps.MoveItems).
+		Msg("Probed Nuclino API capabilities")
+	return caps
+}
+
+func probeCollections(ctx context.Context, c Client) bool {
+	workspaces, err := c.ListWorkspaces(ctx, 1, 0)
+	if err != nil {
+		log.Debug().Err(err).Msg("Collections probe: cannot list workspaces")
+		return false
+	}
+	if len(workspaces.Results) == 0 {
+		// Nothing to inspect; collections are part of the documented API
+		return true
+	}
+
+	workspace, err := c.GetWorkspace(ctx, workspaces.Results[0].ID)
+	if err != nil {
+		log.Debug().Err(err).Msg("Collections probe: cannot get workspace")
+		return false
+	}
+	if len(workspace.ChildIDs) == 0 {
+		return workspace.ChildIDs != nil
+	}
+
+	item, err := c.GetItem(ctx, workspace.ChildIDs[0])
+	if err != nil {
+		log.Debug().Err(err).Msg("Collections probe: cannot get workspace entry")
+		return false
+	}
+	return item.Object != ""
+}
@@ -1,16 +1,26 @@
 package nuclino
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/time/rate"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
+	"github.com/lukasz/nuclino-mcp-server/internal/validate"
 )
 
 const (
@@ -58,7 +68,9 @@ type Client interface {
 	ListFiles(ctx context.Context, workspaceID string, limit, offset int) (*FilesResponse, error)
 	GetFile(ctx context.Context, fileID string) (*File, error)
 	UploadFile(ctx context.Context, workspaceID, filename string, data []byte) (*File, error)
+	UploadFileFrom(ctx context.Context, workspaceID, filename string, r io.Reader) (*File, error)
 	DownloadFile(ctx context.Context, fileID string) ([]byte, error)
+	DownloadFileTo(ctx context.Context, file *File, w io.Writer) (int64, error)
 }
 
 // client implements the Client interface
@@ -67,74 +79,126 @@ type client struct {
 	rateLimiter *rate.Limiter
 	apiKey      string
 	baseURL     string
+	metrics     RequestRecorder
+	retryCount  int
+	retryDelay  time.Duration
 }
 
-// NewClient creates a new Nuclino API client
-func NewClient(apiKey string) Client {
-	httpClient := resty.New().
-		SetBaseURL(defaultBaseURL).
-		SetTimeout(defaultTimeout).
-		SetRetryCount(defaultRetryCount).
-		SetRetryWaitTime(defaultRetryDelay).
-		SetHeader("Authorization", apiKey). // Nuclino API expects just the token, without "Bearer"
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Accept", "application/json")
-
-	// Add retry conditions
-	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
-		return r.StatusCode() >= 500 || r.StatusCode() == 429
-	})
+// ClientConfig holds the settings of a client
+type ClientConfig struct {
+	APIKey     string
+	BaseURL    string
+	Timeout    time.Duration
+	RateLimit  float64 // requests per second
+	RateBurst  int
+	RetryCount int
+	RetryDelay time.Duration
+	// Metrics, when set, records each request's outcome and latency
+	Metrics RequestRecorder
+	// Transport, when set, replaces the HTTP transport, e.g. with a
+	// cassette.Transport that records or replays API interactions
+	Transport http.RoundTripper
+}
 
-	rateLimiter := rate.NewLimiter(rate.Limit(defaultRateLimit), defaultRateBurst)
+// RequestRecorder receives the outcome of every API request;
+// monitoring.MetricsCollector implements it
+type RequestRecorder interface {
+	RecordRequest(success bool, responseTime time.Duration, errorType string)
+}
 
-	return &client{
-		httpClient:  httpClient,
-		rateLimiter: rateLimiter,
-		apiKey:      apiKey,
-		baseURL:     defaultBaseURL,
+// DefaultClientConfig returns the default client settings
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		BaseURL:    defaultBaseURL,
+		Timeout:    defaultTimeout,
+		RateLimit:  defaultRateLimit,
+		RateBurst:  defaultRateBurst,
+		RetryCount: defaultRetryCount,
+		RetryDelay: defaultRetryDelay,
 	}
 }
 
+// NewClient creates a new Nuclino API client
+func NewClient(apiKey string) Client {
+	config := DefaultClientConfig()
+	config.APIKey = apiKey
+	return NewClientFromConfig(config)
+}
+
 // NewClientWithConfig creates a new client with custom configuration
 func NewClientWithConfig(apiKey, baseURL string, rateLimitRPS int, timeout time.Duration) Client {
-	if baseURL == "" {
-		baseURL = defaultBaseURL
+	config := DefaultClientConfig()
+	config.APIKey = apiKey
+	config.BaseURL = baseURL
+	config.RateLimit = float64(rateLimitRPS)
+	config.RateBurst = rateLimitRPS * 2
+	config.Timeout = timeout
+	return NewClientFromConfig(config)
+}
+
+// NewClientFromConfig creates a client from settings; zero values fall back
+// to the defaults
+func NewClientFromConfig(config ClientConfig) Client {
+	defaults := DefaultClientConfig()
+	if config.BaseURL == "" {
+		config.BaseURL = defaults.BaseURL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.RateLimit <= 0 {
+		config.RateLimit = defaults.RateLimit
+	}
+	if config.RateBurst <= 0 {
+		config.RateBurst = defaults.RateBurst
 	}
-	if rateLimitRPS <= 0 {
-		rateLimitRPS = defaultRateLimit
+	if config.RetryCount < 0 {
+		config.RetryCount = 0
 	}
-	if timeout <= 0 {
-		timeout = defaultTimeout
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaults.RetryDelay
 	}
 
 	httpClient := resty.New().
-		SetBaseURL(baseURL).
-		SetTimeout(timeout).
-		SetRetryCount(defaultRetryCount).
-		SetRetryWaitTime(defaultRetryDelay).
-		SetHeader("Authorization", apiKey). // Nuclino API expects just the token, without "Bearer"
+		SetBaseURL(config.BaseURL).
+		SetTimeout(config.Timeout).
+		SetRetryCount(config.RetryCount).
+		SetRetryWaitTime(config.RetryDelay).
+		SetHeader("Authorization", config.APIKey). // Nuclino API expects just the token, without "Bearer"
 		SetHeader("Content-Type", "application/json").
 		SetHeader("Accept", "application/json")
+	transport := config.Transport
+	if transport == nil {
+		transport = httpClient.GetClient().Transport
+	}
+	httpClient.SetTransport(tracing.Transport(transport))
 
+	// Add retry conditions
 	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
 		return r.StatusCode() >= 500 || r.StatusCode() == 429
 	})
 
-	rateLimiter := rate.NewLimiter(rate.Limit(rateLimitRPS), rateLimitRPS*2)
-
 	return &client{
 		httpClient:  httpClient,
-		rateLimiter: rateLimiter,
-		apiKey:      apiKey,
-		baseURL:     baseURL,
+		rateLimiter: rate.NewLimiter(rate.Limit(config.RateLimit), config.RateBurst),
+		apiKey:      config.APIKey,
+		baseURL:     config.BaseURL,
+		metrics:     config.Metrics,
+		retryCount:  config.RetryCount,
+		retryDelay:  config.RetryDelay,
 	}
 }
 
-func (c *client) makeRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+func (c *client) makeRequest(ctx context.Context, method, path string, body interface{}, result interface{}) (err error) {
+	ctx, span := startRequestSpan(ctx, method, path)
+	defer func() { tracing.End(span, err) }()
+
 	// Apply rate limiting
-	if err := c.rateLimiter.Wait(ctx); err != nil {
+	if err := c.wait(ctx); err != nil {
+		c.record(false, 0, "rate_limit_wait")
 		return fmt.Errorf("rate limit wait failed: %w", err)
 	}
+	start := time.Now()
 
 	// Add debug logging
 	log.Debug().
@@ -151,7 +215,6 @@ func (c *client) makeRequest(ctx context.Context, method, path string, body inte
 	}
 
 	var resp *resty.Response
-	var err error
 
 	switch method {
 	case http.MethodGet:
@@ -168,7 +231,9 @@ func (c *client) makeRequest(ctx context.Context, method, path string, body inte
 		return fmt.Errorf("unsupported HTTP method: %s", method)
 	}
 
+	traceResponse(span, resp)
 	if err != nil {
+		c.record(false, time.Since(start), "network")
 		log.Error().
 			Err(err).
 			Str("method", method).
@@ -185,6 +250,7 @@ func (c *client) makeRequest(ctx context.Context, method, path string, body inte
 		Msg("Received API response")
 
 	if resp.StatusCode() >= 400 {
+		c.record(false, time.Since(start), "http_"+strconv.Itoa(resp.StatusCode()))
 		log.Error().
 			Str("method", method).
 			Str("path", path).
@@ -192,51 +258,94 @@ func (c *client) makeRequest(ctx context.Context, method, path string, body inte
 			Str("response_body", string(resp.Body())).
 			Msg("API request failed")
 
-		// Try to parse Nuclino API error format first
-		var nuclinoErr struct {
-			Status  string `json:"status"`
-			Message string `json:"message"`
-		}
-		if err := json.Unmarshal(resp.Body(), &nuclinoErr); err == nil && nuclinoErr.Status == "fail" {
-			return NewAPIError(resp.StatusCode(), nuclinoErr.Message)
-		}
-		
-		// Fallback to generic API error
-		var apiErr APIError
-		if err := json.Unmarshal(resp.Body(), &apiErr); err != nil {
-			return NewAPIError(resp.StatusCode(), string(resp.Body()))
-		}
-		return &apiErr
+		return parseAPIError(resp.StatusCode(), resp.Body())
 	}
 
+	c.record(true, time.Since(start), "")
+
 	// Parse Nuclino wrapped response if result is provided
 	if result != nil {
-		// Try to parse as wrapped response {status: "success", data: {...}}
-		var wrappedResp struct {
-			Status string          `json:"status"`
-			Data   json.RawMessage `json:"data"`
-		}
-		
-		if err := json.Unmarshal(resp.Body(), &wrappedResp); err == nil && wrappedResp.Status == "success" {
-			log.Debug().
-				Str("method", method).
-				Str("path", path).
-				Msg("Successfully parsed wrapped response")
-			// Unmarshal the data field into result
-			return json.Unmarshal(wrappedResp.Data, result)
-		}
-		
-		log.Debug().
-			Str("method", method).
-			Str("path", path).
-			Msg("Using direct response parsing (not wrapped)")
-		// Fallback: try direct unmarshaling
-		return json.Unmarshal(resp.Body(), result)
+		return decodeResponse(resp.Body(), result)
 	}
 
 	return nil
 }
 
+// wait applies the rate limit in a span of its own, so a slow request shows
+// whether it was queued
+func (c *client) wait(ctx context.Context) error {
+	_, span := tracing.Start(ctx, "rate_limiter.wait")
+	err := c.rateLimiter.Wait(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+// startRequestSpan starts the span of one API call, which covers the rate
+// limiter wait and every HTTP attempt
+func startRequestSpan(ctx context.Context, method, path string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "nuclino "+method,
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", path))
+}
+
+// traceResponse adds the status code and the number of retries resty made
+// to a request span
+func traceResponse(span trace.Span, resp *resty.Response) {
+	if resp == nil || resp.Request == nil {
+		return
+	}
+	span.SetAttributes(
+		attribute.Int("http.response.status_code", resp.StatusCode()),
+		attribute.Int("nuclino.retry_count", max(resp.Request.Attempt-1, 0)))
+}
+
+// record reports a request to the metrics recorder, if any
+func (c *client) record(success bool, responseTime time.Duration, errorType string) {
+	if c.metrics != nil {
+		c.metrics.RecordRequest(success, responseTime, errorType)
+	}
+}
+
+// parseAPIError converts an error response body into an APIError
+func parseAPIError(statusCode int, body []byte) error {
+	// Try to parse Nuclino API error format first
+	var nuclinoErr struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &nuclinoErr); err == nil && nuclinoErr.Status == "fail" {
+		return NewAPIError(statusCode, nuclinoErr.Message)
+	}
+
+	// Fallback to generic API error
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return NewAPIError(statusCode, string(body))
+	}
+	if apiErr.StatusCode == 0 {
+		apiErr.StatusCode = statusCode
+	}
+	return &apiErr
+}
+
+// decodeResponse unmarshals a response body into result, unwrapping the
+// Nuclino {status: "success", data: {...}} envelope when present
+func decodeResponse(body []byte, result interface{}) error {
+	var wrappedResp struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &wrappedResp); err == nil && wrappedResp.Status == "success" {
+		log.Debug().Msg("Successfully parsed wrapped response")
+		return json.Unmarshal(wrappedResp.Data, result)
+	}
+
+	log.Debug().Msg("Using direct response parsing (not wrapped)")
+	// Fallback: try direct unmarshaling
+	return json.Unmarshal(body, result)
+}
+
 // User methods
 func (c *client) GetUser(ctx context.Context, userID string) (*User, error) {
 	var user User
@@ -297,6 +406,9 @@ func (c *client) GetWorkspace(ctx context.Context, workspaceID string) (*Workspa
 }
 
 func (c *client) CreateWorkspace(ctx context.Context, req *CreateWorkspaceRequest) (*Workspace, error) {
+	if err := validate.Struct(req); err != nil {
+		return nil, err
+	}
 	var workspace Workspace
 	err := c.makeRequest(ctx, http.MethodPost, "/v0/workspaces", req, &workspace)
 	return &workspace, err
@@ -313,36 +425,35 @@ func (c *client) DeleteWorkspace(ctx context.Context, workspaceID string) error
 }
 
 // Collection methods
+//
+// Collections are items with object "collection"; they are listed from the
+// workspace's item pages and managed through the items endpoints.
 func (c *client) ListCollections(ctx context.Context, workspaceID string, limit, offset int) (*CollectionsResponse, error) {
-	var resp CollectionsResponse
-	path := fmt.Sprintf("/v0/workspaces/%s/collections", workspaceID)
-	if limit > 0 || offset > 0 {
-		path += "?limit=" + strconv.Itoa(limit) + "&offset=" + strconv.Itoa(offset)
-	}
-	err := c.makeRequest(ctx, http.MethodGet, path, nil, &resp)
-	return &resp, err
+	return listCollections(ctx, c, workspaceID, limit, offset)
 }
 
 func (c *client) GetCollection(ctx context.Context, collectionID string) (*Collection, error) {
-	var collection Collection
-	err := c.makeRequest(ctx, http.MethodGet, fmt.Sprintf("/v0/collections/%s", collectionID), nil, &collection)
-	return &collection, err
+	return getCollection(ctx, c, collectionID)
 }
 
 func (c *client) CreateCollection(ctx context.Context, req *CreateCollectionRequest) (*Collection, error) {
-	var collection Collection
-	err := c.makeRequest(ctx, http.MethodPost, "/v0/collections", req, &collection)
-	return &collection, err
+	item, err := c.CreateItem(ctx, createCollectionRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	return collectionFromItem(item), nil
 }
 
 func (c *client) UpdateCollection(ctx context.Context, collectionID string, req *UpdateCollectionRequest) (*Collection, error) {
-	var collection Collection
-	err := c.makeRequest(ctx, http.MethodPatch, fmt.Sprintf("/v0/collections/%s", collectionID), req, &collection)
-	return &collection, err
+	item, err := c.UpdateItem(ctx, collectionID, &UpdateItemRequest{Title: req.Title})
+	if err != nil {
+		return nil, err
+	}
+	return collectionFromItem(item), nil
 }
 
 func (c *client) DeleteCollection(ctx context.Context, collectionID string) error {
-	return c.makeRequest(ctx, http.MethodDelete, fmt.Sprintf("/v0/collections/%s", collectionID), nil, nil)
+	return c.DeleteItem(ctx, collectionID)
 }
 
 // Item methods
@@ -395,6 +506,9 @@ func (c *client) GetItem(ctx context.Context, itemID string) (*Item, error) {
 }
 
 func (c *client) CreateItem(ctx context.Context, req *CreateItemRequest) (*Item, error) {
+	if err := validate.Struct(req); err != nil {
+		return nil, err
+	}
 	var item Item
 	err := c.makeRequest(ctx, http.MethodPost, "/v0/items", req, &item)
 	return &item, err
@@ -435,55 +549,216 @@ func (c *client) GetFile(ctx context.Context, fileID string) (*File, error) {
 }
 
 func (c *client) UploadFile(ctx context.Context, workspaceID, filename string, data []byte) (*File, error) {
+	return c.UploadFileFrom(ctx, workspaceID, filename, bytes.NewReader(data))
+}
+
+// UploadFileFrom streams the file contents from r as a multipart upload.
+// Uploads that fail with 429 or 5xx are retried like other requests when r
+// is an io.Seeker, rewinding it for every attempt; any other reader gets a
+// single attempt, since its contents cannot be sent twice.
+func (c *client) UploadFileFrom(ctx context.Context, workspaceID, filename string, r io.Reader) (file *File, err error) {
+	ctx, span := startRequestSpan(ctx, http.MethodPost, "/v0/files")
+	defer func() { tracing.End(span, err) }()
+
+	attempts := 1
+	seeker, seekable := r.(io.Seeker)
+	var offset int64
+	if seekable {
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err == nil {
+			attempts += c.retryCount
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("file upload failed: cannot rewind the contents: %w", err)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("file upload failed: %w", ctx.Err())
+			case <-time.After(c.retryDelay):
+			}
+		}
+
+		var status int
+		file, status, err = c.upload(ctx, workspaceID, filename, r)
+		span.SetAttributes(
+			attribute.Int("http.response.status_code", status),
+			attribute.Int("nuclino.retry_count", attempt-1))
+		if attempt < attempts && (status == http.StatusTooManyRequests || status >= 500) {
+			continue
+		}
+		return file, err
+	}
+}
+
+// errUploadFinished stops the body writer of an upload attempt that returned
+// before the whole body was sent
+var errUploadFinished = errors.New("upload attempt finished")
+
+// upload makes one upload attempt, writing the multipart body through a
+// pipe so the contents are never held in memory, and returns the response
+// status, or 0 when no response arrived
+func (c *client) upload(ctx context.Context, workspaceID, filename string, r io.Reader) (*File, int, error) {
 	// Apply rate limiting
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit wait failed: %w", err)
+	if err := c.wait(ctx); err != nil {
+		c.record(false, 0, "rate_limit_wait")
+		return nil, 0, fmt.Errorf("rate limit wait failed: %w", err)
 	}
+	start := time.Now()
+
+	body, pipe := io.Pipe()
+	form := multipart.NewWriter(pipe)
+	done := make(chan struct{})
+	// The writer must have stopped reading r before the caller rewinds it
+	// for a retry, so closing the body unblocks it and every return waits
+	defer func() {
+		body.CloseWithError(errUploadFinished)
+		<-done
+	}()
+	go func() {
+		defer close(done)
+		err := form.WriteField("workspaceId", workspaceID)
+		if err == nil {
+			var part io.Writer
+			if part, err = form.CreateFormFile("file", filename); err == nil {
+				_, err = io.Copy(part, r)
+			}
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pipe.CloseWithError(err)
+	}()
 
-	var file File
-	resp, err := c.httpClient.R().
-		SetContext(ctx).
-		SetFile("file", filename).
-		SetFormData(map[string]string{"workspaceId": workspaceID}).
-		SetResult(&file).
-		Post("/v0/files")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.baseURL, "/")+"/v0/files", body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("file upload failed: %w", err)
+	}
+	req.Header.Set("Authorization", c.apiKey)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
 
+	// The resty client would retry with the reader already drained, so the
+	// request goes through its underlying HTTP client
+	resp, err := c.httpClient.GetClient().Do(req)
+	if err != nil {
+		c.record(false, time.Since(start), "network")
+		return nil, 0, fmt.Errorf("file upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("file upload failed: %w", err)
+		c.record(false, time.Since(start), "network")
+		return nil, resp.StatusCode, fmt.Errorf("file upload failed: %w", err)
 	}
 
-	if resp.StatusCode() >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(resp.Body(), &apiErr); err != nil {
-			return nil, NewAPIError(resp.StatusCode(), string(resp.Body()))
-		}
-		return nil, &apiErr
+	if resp.StatusCode >= 400 {
+		c.record(false, time.Since(start), "http_"+strconv.Itoa(resp.StatusCode))
+		return nil, resp.StatusCode, parseAPIError(resp.StatusCode, data)
 	}
+	c.record(true, time.Since(start), "")
 
-	return &file, nil
+	file := &File{}
+	if err := decodeResponse(data, file); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to parse upload response: %w", err)
+	}
+	return file, resp.StatusCode, nil
 }
 
 func (c *client) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	file, err := c.GetFile(ctx, fileID)
+	if IsNotFound(err) {
+		// Some files are only reachable through the download endpoint
+		file, err = &File{ID: fileID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.DownloadFileTo(ctx, file, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadFileTo streams the contents of a file, as returned by GetFile,
+// into w and returns the number of bytes written. Files that carry a signed
+// download URL that has not expired are fetched from it directly; otherwise
+// the API download endpoint is used.
+func (c *client) DownloadFileTo(ctx context.Context, file *File, w io.Writer) (int64, error) {
+	if file.Download != nil && file.Download.URL != "" &&
+		(file.Download.ExpiresAt.IsZero() || time.Now().Before(file.Download.ExpiresAt)) {
+		return downloadURL(ctx, c.httpClient.GetClient().Transport, file.Download.URL, w)
+	}
+	return c.download(ctx, file.ID, w)
+}
+
+// download fetches a file from the API download endpoint
+func (c *client) download(ctx context.Context, fileID string, w io.Writer) (n int64, err error) {
+	path := fmt.Sprintf("/v0/files/%s/download", fileID)
+	ctx, span := startRequestSpan(ctx, http.MethodGet, path)
+	defer func() { tracing.End(span, err) }()
+
 	// Apply rate limiting
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit wait failed: %w", err)
+	if err := c.wait(ctx); err != nil {
+		c.record(false, 0, "rate_limit_wait")
+		return 0, fmt.Errorf("rate limit wait failed: %w", err)
 	}
+	start := time.Now()
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
-		Get(fmt.Sprintf("/v0/files/%s/download", fileID))
+		SetDoNotParseResponse(true).
+		Get(path)
 
+	traceResponse(span, resp)
 	if err != nil {
-		return nil, fmt.Errorf("file download failed: %w", err)
+		c.record(false, time.Since(start), "network")
+		return 0, fmt.Errorf("file download failed: %w", err)
 	}
+	body := resp.RawBody()
+	defer body.Close()
 
 	if resp.StatusCode() >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(resp.Body(), &apiErr); err != nil {
-			return nil, NewAPIError(resp.StatusCode(), string(resp.Body()))
-		}
-		return nil, &apiErr
+		c.record(false, time.Since(start), "http_"+strconv.Itoa(resp.StatusCode()))
+		data, _ := io.ReadAll(io.LimitReader(body, 64*1024))
+		return 0, parseAPIError(resp.StatusCode(), data)
+	}
+
+	n, err = io.Copy(w, body)
+	if err != nil {
+		c.record(false, time.Since(start), "network")
+		return n, fmt.Errorf("file download failed: %w", err)
+	}
+	c.record(true, time.Since(start), "")
+	return n, nil
+}
+
+// downloadURL fetches a pre-signed download URL. The API key is deliberately
+// not sent since the URL points outside the Nuclino API; the transport is
+// shared so downloads are traced and recorded like API requests.
+func downloadURL(ctx context.Context, transport http.RoundTripper, url string, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("invalid download URL: %w", err)
 	}
 
-	return resp.Body(), nil
+	resp, err := (&http.Client{Transport: transport, Timeout: 5 * defaultTimeout}).Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("file download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return 0, NewAPIError(resp.StatusCode, "file download failed: "+resp.Status)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("file download failed: %w", err)
+	}
+	return n, nil
 }
@@ -0,0 +1,77 @@
+package nuclino
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// itemGetter is the subset of Client needed to look up workspace entries
+type itemGetter interface {
+	GetWorkspace(ctx context.Context, workspaceID string) (*Workspace, error)
+	GetItem(ctx context.Context, itemID string) (*Item, error)
+}
+
+// collectionFromItem converts a collection item to a Collection
+func collectionFromItem(item *Item) *Collection {
+	return &Collection{
+		ID:          item.ID,
+		Title:       item.Title,
+		WorkspaceID: item.WorkspaceID,
+		URL:         item.URL,
+		ChildIDs:    item.ChildIDs,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.ModifiedAt(),
+	}
+}
+
+// listCollections pages through the workspace's items and returns its
+// collections in list order, paginated with limit and offset. Listing stops
+// once the requested page is filled, so Total is the number of collections
+// up to the end of the page unless the workspace was listed to its end.
+func listCollections(ctx context.Context, c ItemLister, workspaceID string, limit, offset int) (*CollectionsResponse, error) {
+	var collections []Collection
+	err := EachItemPage(ctx, c, workspaceID, MaxPageSize, func(page []Item) bool {
+		for i := range page {
+			if page[i].IsCollection() {
+				collections = append(collections, *collectionFromItem(&page[i]))
+			}
+		}
+		return limit <= 0 || len(collections) < offset+limit
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace items: %w", err)
+	}
+
+	resp := &CollectionsResponse{Total: len(collections), Limit: limit, Offset: offset, Results: []Collection{}}
+	if offset < len(collections) {
+		page := collections[offset:]
+		if limit > 0 && len(page) > limit {
+			page = page[:limit]
+		}
+		resp.Results = page
+	}
+	return resp, nil
+}
+
+// getCollection fetches an item and checks that it is a collection
+func getCollection(ctx context.Context, c itemGetter, collectionID string) (*Collection, error) {
+	item, err := c.GetItem(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsCollection() {
+		return nil, NewAPIError(http.StatusNotFound, fmt.Sprintf("item %s is not a collection", collectionID))
+	}
+	return collectionFromItem(item), nil
+}
+
+// createCollectionRequest maps a collection request onto the items endpoint
+func createCollectionRequest(req *CreateCollectionRequest) *CreateItemRequest {
+	return &CreateItemRequest{
+		Object:      ObjectCollection,
+		Title:       req.Title,
+		WorkspaceID: req.WorkspaceID,
+		ParentID:    req.ParentID,
+	}
+}
@@ -0,0 +1,119 @@
+package nuclino
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTree serves a workspace tree; methods not used here panic through the
+// embedded nil interface
+type fakeTree struct {
+	Client
+	workspace *Workspace
+	items     map[string]*Item
+	order     []string
+	listed    []int
+}
+
+func (f *fakeTree) ListWorkspaces(ctx context.Context, limit, offset int) (*WorkspacesResponse, error) {
+	return &WorkspacesResponse{Results: []Workspace{{ID: f.workspace.ID}}}, nil
+}
+
+func (f *fakeTree) GetWorkspace(ctx context.Context, workspaceID string) (*Workspace, error) {
+	return f.workspace, nil
+}
+
+func (f *fakeTree) GetItem(ctx context.Context, itemID string) (*Item, error) {
+	if item, ok := f.items[itemID]; ok {
+		return item, nil
+	}
+	return nil, NewAPIError(404, "not found")
+}
+
+// ListItems lists the tree in the order the entries were added
+func (f *fakeTree) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*ItemsResponse, error) {
+	f.listed = append(f.listed, offset)
+	resp := &ItemsResponse{}
+	for i := offset; i < len(f.order) && i < offset+limit; i++ {
+		resp.Results = append(resp.Results, *f.items[f.order[i]])
+	}
+	return resp, nil
+}
+
+func newFakeTree() *fakeTree {
+	return &fakeTree{
+		workspace: &Workspace{ID: "ws", ChildIDs: []string{"guides", "readme"}},
+		items: map[string]*Item{
+			"guides":  {ID: "guides", Object: ObjectCollection, Title: "Guides", ChildIDs: []string{"setup", "archive"}},
+			"archive": {ID: "archive", Object: ObjectCollection, Title: "Archive"},
+			"setup":   {ID: "setup", Object: ObjectItem, Title: "Setup"},
+			"readme":  {ID: "readme", Object: ObjectItem, Title: "Readme"},
+		},
+		order: []string{"guides", "setup", "archive", "readme"},
+	}
+}
+
+func TestListCollections_ListsItemPages(t *testing.T) {
+	tree := newFakeTree()
+
+	resp, err := listCollections(context.Background(), tree, "ws", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Total)
+	assert.Equal(t, "guides", resp.Results[0].ID)
+	assert.Equal(t, []string{"setup", "archive"}, resp.Results[0].ChildIDs)
+	assert.Equal(t, "archive", resp.Results[1].ID)
+
+	page, err := listCollections(context.Background(), tree, "ws", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "archive", page.Results[0].ID)
+}
+
+func TestListCollections_StopsAtTheRequestedPage(t *testing.T) {
+	tree := &fakeTree{items: make(map[string]*Item)}
+	for i := 0; i < 3*MaxPageSize; i++ {
+		id := fmt.Sprintf("entry-%d", i)
+		object := ObjectItem
+		if i%10 == 0 {
+			object = ObjectCollection
+		}
+		tree.items[id] = &Item{ID: id, Object: object}
+		tree.order = append(tree.order, id)
+	}
+
+	page, err := listCollections(context.Background(), tree, "ws", 2, 9)
+	require.NoError(t, err)
+	require.Len(t, page.Results, 2)
+	assert.Equal(t, "entry-90", page.Results[0].ID)
+	assert.Equal(t, "entry-100", page.Results[1].ID)
+	assert.Equal(t, []int{0, MaxPageSize}, tree.listed, "the third page is never requested")
+}
+
+func TestGetCollection_RejectsPlainItems(t *testing.T) {
+	tree := newFakeTree()
+
+	_, err := getCollection(context.Background(), tree, "readme")
+	assert.True(t, IsNotFound(err))
+
+	collection, err := getCollection(context.Background(), tree, "guides")
+	require.NoError(t, err)
+	assert.Equal(t, "Guides", collection.Title)
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	tree := newFakeTree()
+
+	caps := ProbeCapabilities(context.Background(), tree, false)
+	assert.True(t, caps.Collections)
+	assert.False(t, caps.MoveItems)
+
+	tree.items["guides"].Object = ""
+
+	caps = ProbeCapabilities(context.Background(), tree, true)
+	assert.False(t, caps.Collections)
+	assert.True(t, caps.MoveItems)
+}
@@ -1,8 +1,10 @@
 package nuclino
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -11,6 +13,9 @@ import (
 	"github.com/lukasz/nuclino-mcp-server/internal/cache"
 	"github.com/lukasz/nuclino-mcp-server/internal/errors"
 	"github.com/lukasz/nuclino-mcp-server/internal/ratelimit"
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
+	"github.com/lukasz/nuclino-mcp-server/internal/validate"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // EnhancedClient provides advanced features like caching, rate limiting, and comprehensive error handling
@@ -33,6 +38,9 @@ type EnhancedClientConfig struct {
 	CacheConfig     cache.CacheConfig
 	EnableCache     bool
 	EnableMetrics   bool
+	// Transport, when set, replaces the HTTP transport, e.g. with a
+	// cassette.Transport that records or replays API interactions
+	Transport http.RoundTripper
 }
 
 // ClientMetrics tracks client performance
@@ -73,6 +81,11 @@ func NewEnhancedClient(config EnhancedClientConfig, logger errors.Logger) *Enhan
 		SetHeader("Content-Type", "application/json").
 		SetHeader("Accept", "application/json").
 		SetHeader("User-Agent", "nuclino-mcp-server/1.0")
+	transport := config.Transport
+	if transport == nil {
+		transport = httpClient.GetClient().Transport
+	}
+	httpClient.SetTransport(tracing.Transport(transport))
 
 	client := &EnhancedClient{
 		httpClient:   httpClient,
@@ -90,10 +103,12 @@ func NewEnhancedClient(config EnhancedClientConfig, logger errors.Logger) *Enhan
 }
 
 // executeRequest performs a request with all enhancements (rate limiting, caching, error handling, retries)
-func (c *EnhancedClient) executeRequest(ctx context.Context, method, path string, body interface{}, result interface{}, cacheKey string, cacheTTL time.Duration) error {
+func (c *EnhancedClient) executeRequest(ctx context.Context, method, path string, body interface{}, result interface{}, cacheKey string, cacheTTL time.Duration) (err error) {
 	startTime := time.Now()
+	ctx, span := startRequestSpan(ctx, method, path)
 	defer func() {
 		c.updateMetrics(time.Since(startTime))
+		tracing.End(span, err)
 	}()
 
 	// Check cache first (for GET requests)
@@ -104,9 +119,11 @@ func (c *EnhancedClient) executeRequest(ctx context.Context, method, path string
 			}
 			// Copy cached result to result interface
 			if err := c.copyCachedResult(cached, result); err == nil {
+				span.SetAttributes(attribute.Bool("nuclino.cache_hit", true))
 				return nil
 			}
 		}
+		span.SetAttributes(attribute.Bool("nuclino.cache_hit", false))
 		if c.config.EnableMetrics {
 			c.metrics.CacheMisses++
 		}
@@ -115,15 +132,13 @@ func (c *EnhancedClient) executeRequest(ctx context.Context, method, path string
 	// Execute request with retries
 	var lastErr error
 	for attempt := 0; attempt <= c.config.RetryConfig.MaxRetries; attempt++ {
-		// Apply rate limiting
-		if err := c.rateLimiter.Wait(ctx); err != nil {
+		span.SetAttributes(attribute.Int("nuclino.retry_count", attempt))
+		err := c.attempt(ctx, attempt, method, path, body, result)
+		if err == errRateLimitWait {
 			lastErr = errors.NewRateLimitError(time.Now().Add(time.Second))
 			continue
 		}
 
-		// Execute the actual HTTP request
-		err := c.doHTTPRequest(ctx, method, path, body, result)
-
 		if err == nil {
 			// Success - record metrics and cache result
 			c.rateLimiter.OnSuccess()
@@ -163,6 +178,24 @@ func (c *EnhancedClient) executeRequest(ctx context.Context, method, path string
 	return lastErr
 }
 
+// errRateLimitWait reports that an attempt could not pass the rate limiter
+var errRateLimitWait = fmt.Errorf("rate limit wait failed")
+
+// attempt makes one try of a request in a span of its own, waiting for the
+// rate limiter first
+func (c *EnhancedClient) attempt(ctx context.Context, attempt int, method, path string, body interface{}, result interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "attempt", attribute.Int("nuclino.attempt", attempt+1))
+	defer func() { tracing.End(span, err) }()
+
+	_, wait := tracing.Start(ctx, "rate_limiter.wait")
+	err = c.rateLimiter.Wait(ctx)
+	tracing.End(wait, err)
+	if err != nil {
+		return errRateLimitWait
+	}
+	return c.doHTTPRequest(ctx, method, path, body, result)
+}
+
 // doHTTPRequest performs the actual HTTP request
 func (c *EnhancedClient) doHTTPRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
 	req := c.httpClient.R().SetContext(ctx)
@@ -335,6 +368,9 @@ func (c *EnhancedClient) GetWorkspace(ctx context.Context, workspaceID string) (
 }
 
 func (c *EnhancedClient) CreateWorkspace(ctx context.Context, req *CreateWorkspaceRequest) (*Workspace, error) {
+	if err := validate.Struct(req); err != nil {
+		return nil, err
+	}
 	var result Workspace
 	err := c.executeRequest(ctx, "POST", "/workspaces", req, &result, "", 0)
 	if err != nil {
@@ -368,61 +404,33 @@ func (c *EnhancedClient) DeleteWorkspace(ctx context.Context, workspaceID string
 	return nil
 }
 
+// Collections are items with object "collection"; see listCollections
 func (c *EnhancedClient) ListCollections(ctx context.Context, workspaceID string, limit, offset int) (*CollectionsResponse, error) {
-	var result CollectionsResponse
-	path := fmt.Sprintf("/workspaces/%s/collections?limit=%d&offset=%d", workspaceID, limit, offset)
-	cacheKey := c.generateCacheKey("GET", path, nil)
-	err := c.executeRequest(ctx, "GET", path, nil, &result,
-		cacheKey, c.config.CacheConfig.CollectionTTL)
-	if err != nil {
-		return nil, err
-	}
-	return &result, nil
+	return listCollections(ctx, c, workspaceID, limit, offset)
 }
 
 func (c *EnhancedClient) GetCollection(ctx context.Context, collectionID string) (*Collection, error) {
-	var result Collection
-	cacheKey := c.generateCacheKey("GET", "/collections/"+collectionID, nil)
-	err := c.executeRequest(ctx, "GET", "/collections/"+collectionID, nil, &result,
-		cacheKey, c.config.CacheConfig.CollectionTTL)
-	if err != nil {
-		return nil, err
-	}
-	return &result, nil
+	return getCollection(ctx, c, collectionID)
 }
 
 func (c *EnhancedClient) CreateCollection(ctx context.Context, req *CreateCollectionRequest) (*Collection, error) {
-	var result Collection
-	err := c.executeRequest(ctx, "POST", "/collections", req, &result, "", 0)
+	item, err := c.CreateItem(ctx, createCollectionRequest(req))
 	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return collectionFromItem(item), nil
 }
 
 func (c *EnhancedClient) UpdateCollection(ctx context.Context, collectionID string, req *UpdateCollectionRequest) (*Collection, error) {
-	var result Collection
-	err := c.executeRequest(ctx, "PATCH", "/collections/"+collectionID, req, &result, "", 0)
+	item, err := c.UpdateItem(ctx, collectionID, &UpdateItemRequest{Title: req.Title})
 	if err != nil {
 		return nil, err
 	}
-	// Invalidate cache
-	if c.cache != nil {
-		c.cache.Delete(c.generateCacheKey("GET", "/collections/"+collectionID, nil))
-	}
-	return &result, nil
+	return collectionFromItem(item), nil
 }
 
 func (c *EnhancedClient) DeleteCollection(ctx context.Context, collectionID string) error {
-	err := c.executeRequest(ctx, "DELETE", "/collections/"+collectionID, nil, nil, "", 0)
-	if err != nil {
-		return err
-	}
-	// Invalidate cache
-	if c.cache != nil {
-		c.cache.Delete(c.generateCacheKey("GET", "/collections/"+collectionID, nil))
-	}
-	return nil
+	return c.DeleteItem(ctx, collectionID)
 }
 
 func (c *EnhancedClient) SearchItems(ctx context.Context, req *SearchItemsRequest) (*ItemsResponse, error) {
@@ -461,6 +469,9 @@ func (c *EnhancedClient) GetItem(ctx context.Context, itemID string) (*Item, err
 }
 
 func (c *EnhancedClient) CreateItem(ctx context.Context, req *CreateItemRequest) (*Item, error) {
+	if err := validate.Struct(req); err != nil {
+		return nil, err
+	}
 	var result Item
 	err := c.executeRequest(ctx, "POST", "/items", req, &result, "", 0)
 	if err != nil {
@@ -532,27 +543,73 @@ func (c *EnhancedClient) GetFile(ctx context.Context, fileID string) (*File, err
 }
 
 func (c *EnhancedClient) UploadFile(ctx context.Context, workspaceID, filename string, data []byte) (*File, error) {
-	// File upload implementation would be more complex with multipart form data
-	// This is a simplified version
-	var result File
-	req := map[string]interface{}{
-		"filename": filename,
-		"data":     data,
+	return c.UploadFileFrom(ctx, workspaceID, filename, bytes.NewReader(data))
+}
+
+// UploadFileFrom streams the file contents from r as a multipart upload.
+// Streams cannot be replayed, so uploads are not retried.
+func (c *EnhancedClient) UploadFileFrom(ctx context.Context, workspaceID, filename string, r io.Reader) (*File, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, errors.NewRateLimitError(time.Now().Add(time.Second)).WithCause(err)
 	}
-	err := c.executeRequest(ctx, "POST", "/workspaces/"+workspaceID+"/files", req, &result, "", 0)
+
+	var result File
+	path := "/workspaces/" + workspaceID + "/files"
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetFileReader("file", filename, r).
+		SetResult(&result).
+		Post(path)
 	if err != nil {
-		return nil, err
+		c.rateLimiter.OnFailure()
+		return nil, c.errorHandler.Handle(errors.NewNetworkError("POST "+path, err))
 	}
+	if err := c.handleHTTPResponse(resp); err != nil {
+		c.rateLimiter.OnFailure()
+		return nil, c.errorHandler.Handle(err)
+	}
+
+	c.rateLimiter.OnSuccess()
 	return &result, nil
 }
 
 func (c *EnhancedClient) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
-	var result []byte
-	err := c.executeRequest(ctx, "GET", "/files/"+fileID+"/download", nil, &result, "", 0)
-	if err != nil {
+	var buf bytes.Buffer
+	if _, err := c.DownloadFileTo(ctx, &File{ID: fileID}, &buf); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return buf.Bytes(), nil
+}
+
+// DownloadFileTo streams the file contents into w without buffering them
+func (c *EnhancedClient) DownloadFileTo(ctx context.Context, file *File, w io.Writer) (int64, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return 0, errors.NewRateLimitError(time.Now().Add(time.Second)).WithCause(err)
+	}
+
+	path := "/files/" + file.ID + "/download"
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(path)
+	if err != nil {
+		c.rateLimiter.OnFailure()
+		return 0, c.errorHandler.Handle(errors.NewNetworkError("GET "+path, err))
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if err := c.handleHTTPResponse(resp); err != nil {
+		c.rateLimiter.OnFailure()
+		return 0, c.errorHandler.Handle(err)
+	}
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, c.errorHandler.Handle(errors.NewNetworkError("GET "+path, err))
+	}
+	c.rateLimiter.OnSuccess()
+	return n, nil
 }
 
 // GetMetrics returns client performance metrics
@@ -0,0 +1,274 @@
+// Package nuclinotest provides an in-process fake of the Nuclino v0 API for
+// tests and offline demos. It keeps teams, workspaces, the item and
+// collection tree and files in memory, answers with the same JSON envelopes
+// as the live API and can inject rate limit and server errors.
+//
+//	server := nuclinotest.NewServer()
+//	defer server.Close()
+//	workspaceID := server.AddWorkspace(server.AddTeam("Acme"), "Docs")
+//	server.AddItem(workspaceID, "Welcome", "Hello")
+//	client := nuclino.NewClientWithConfig("key", server.URL, 0, 0)
+package nuclinotest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DemoAPIKey is the API key the demo backend accepts
+const DemoAPIKey = "demo"
+
+const (
+	objectItem       = "item"
+	objectCollection = "collection"
+
+	// maxLimit is the largest page the API returns
+	maxLimit = 100
+)
+
+var errNotFound = errors.New("not found")
+
+// epoch starts the fake clock, so timestamps are stable across runs
+var epoch = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+// Fault makes matching requests fail instead of reaching the fake API
+type Fault struct {
+	// Method matches the request method; empty matches any
+	Method string
+	// Path is a path.Match pattern for the request path, e.g.
+	// "/v0/items/*"; empty matches any
+	Path string
+	// Status is the response status, e.g. 429 or 503
+	Status int
+	// RetryAfter is sent as the Retry-After header when set
+	RetryAfter time.Duration
+	// Count is how many requests fail; 0 fails until ClearFaults
+	Count int
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+		return false
+	}
+	if f.Path == "" {
+		return true
+	}
+	ok, _ := path.Match(f.Path, r.URL.Path)
+	return ok
+}
+
+// API is the fake Nuclino API. It is an http.Handler and safe for
+// concurrent use; seed it with the Add methods.
+type API struct {
+	mu sync.Mutex
+
+	apiKey string
+	userID string
+	lastID int
+	now    time.Time
+
+	users          map[string]*user
+	teams          map[string]*team
+	teamOrder      []string
+	workspaces     map[string]*workspace
+	workspaceOrder []string
+	items          map[string]*item
+	itemOrder      []string
+	files          map[string]*file
+	fileOrder      []string
+
+	faults   []*Fault
+	requests []string
+}
+
+// New returns an empty fake API with one user, who is the author of
+// everything created through it
+func New() *API {
+	a := &API{
+		now:        epoch,
+		users:      make(map[string]*user),
+		teams:      make(map[string]*team),
+		workspaces: make(map[string]*workspace),
+		items:      make(map[string]*item),
+		files:      make(map[string]*file),
+	}
+	a.userID = a.addUser("Demo", "User", "demo@example.com")
+	return a
+}
+
+// Server is a fake API listening on a local port
+type Server struct {
+	*httptest.Server
+	*API
+}
+
+// NewServer starts a fake API server; call Close when done
+func NewServer() *Server {
+	api := New()
+	return &Server{Server: httptest.NewServer(api), API: api}
+}
+
+// SetAPIKey makes the API reject requests without key in the Authorization
+// header, with or without a "Bearer " prefix. By default any non-empty key
+// is accepted.
+func (a *API) SetAPIKey(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.apiKey = key
+}
+
+// UserID returns the ID of the user that authors all content
+func (a *API) UserID() string {
+	return a.userID
+}
+
+// AddTeam adds a team and returns its ID
+func (a *API) AddTeam(name string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.addTeam(name)
+}
+
+// AddWorkspace adds a workspace to a team and returns its ID
+func (a *API) AddWorkspace(teamID, name string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.addWorkspace(teamID, name)
+}
+
+// AddCollection adds a collection at the end of a workspace or collection
+// and returns its ID. It panics if the parent does not exist.
+func (a *API) AddCollection(parentID, title string) string {
+	return a.mustAddEntry(objectCollection, parentID, title, "")
+}
+
+// AddItem adds an item at the end of a workspace or collection and returns
+// its ID. It panics if the parent does not exist.
+func (a *API) AddItem(parentID, title, content string) string {
+	return a.mustAddEntry(objectItem, parentID, title, content)
+}
+
+func (a *API) mustAddEntry(object, parentID, title, content string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry, err := a.addEntry(object, parentID, title, content, -1)
+	if err != nil {
+		panic("nuclinotest: cannot add " + object + " to " + parentID + ": " + err.Error())
+	}
+	return entry.ID
+}
+
+// AddFile attaches a file to an item and returns its ID. It panics if the
+// item does not exist.
+func (a *API) AddFile(itemID, fileName string, data []byte) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f, err := a.addFile(itemID, fileName, data)
+	if err != nil {
+		panic("nuclinotest: cannot add file to " + itemID + ": " + err.Error())
+	}
+	return f.ID
+}
+
+// InjectFault makes requests matching f fail
+func (a *API) InjectFault(f Fault) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults = append(a.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (a *API) ClearFaults() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults = nil
+}
+
+// Requests returns the requests served so far as "METHOD /path?query",
+// including failed ones
+func (a *API) Requests() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.requests...)
+}
+
+// ServeHTTP implements http.Handler
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.requests = append(a.requests, r.Method+" "+r.URL.RequestURI())
+
+	// Signed download URLs are outside the API and need no key
+	if strings.HasPrefix(r.URL.Path, "/downloads/") {
+		a.serveDownload(w, r, strings.TrimPrefix(r.URL.Path, "/downloads/"))
+		return
+	}
+
+	if a.fault(w, r) {
+		return
+	}
+	if !a.authorized(r) {
+		fail(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v0"), "/"), "/")
+	if !strings.HasPrefix(r.URL.Path, "/v0/") || len(segments) == 0 {
+		fail(w, http.StatusNotFound, "Not found")
+		return
+	}
+	switch segments[0] {
+	case "users":
+		a.serveUsers(w, r, segments[1:])
+	case "teams":
+		a.serveTeams(w, r, segments[1:])
+	case "workspaces":
+		a.serveWorkspaces(w, r, segments[1:])
+	case "items":
+		a.serveItems(w, r, segments[1:])
+	case "files":
+		a.serveFiles(w, r, segments[1:])
+	default:
+		fail(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// fault answers r with the first matching fault, if any
+func (a *API) fault(w http.ResponseWriter, r *http.Request) bool {
+	for i, f := range a.faults {
+		if !f.matches(r) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				a.faults = append(a.faults[:i], a.faults[i+1:]...)
+			}
+		}
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Round(time.Second)/time.Second)))
+		}
+		message := http.StatusText(f.Status)
+		if f.Status == http.StatusTooManyRequests {
+			message = "Rate limit exceeded"
+		}
+		fail(w, f.Status, message)
+		return true
+	}
+	return false
+}
+
+func (a *API) authorized(r *http.Request) bool {
+	key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if a.apiKey == "" {
+		return key != ""
+	}
+	return key == a.apiKey
+}
@@ -0,0 +1,213 @@
+package nuclinotest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino/nuclinotest"
+)
+
+func newClient(t *testing.T, server *nuclinotest.Server) nuclino.Client {
+	t.Helper()
+	return nuclino.NewClientFromConfig(nuclino.ClientConfig{
+		APIKey:     nuclinotest.DemoAPIKey,
+		BaseURL:    server.URL,
+		RateLimit:  1000,
+		RetryCount: 2,
+		RetryDelay: time.Millisecond,
+	})
+}
+
+func TestServer_Tree(t *testing.T) {
+	server := nuclinotest.NewServer()
+	defer server.Close()
+	workspaceID := server.AddWorkspace(server.AddTeam("Acme"), "Docs")
+	guides := server.AddCollection(workspaceID, "Guides")
+	nested := server.AddCollection(guides, "Nested")
+	itemID := server.AddItem(nested, "Setup", "Install the tools")
+	rootItem := server.AddItem(workspaceID, "Readme", "See [Setup](https://app.nuclino.com/t/b/"+itemID+")")
+
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	workspace, err := client.GetWorkspace(ctx, workspaceID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{guides, rootItem}, workspace.ChildIDs)
+
+	collections, err := client.ListCollections(ctx, workspaceID, 0, 0)
+	require.NoError(t, err)
+	titles := make([]string, 0, len(collections.Results))
+	for _, collection := range collections.Results {
+		titles = append(titles, collection.Title)
+	}
+	assert.ElementsMatch(t, []string{"Guides", "Nested"}, titles)
+
+	items, err := client.ListItems(ctx, workspaceID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, items.Results, 4)
+	for _, listed := range items.Results {
+		assert.Empty(t, listed.Content, "lists omit content")
+	}
+
+	item, err := client.GetItem(ctx, rootItem)
+	require.NoError(t, err)
+	assert.Contains(t, item.Content, "See [Setup]")
+	assert.Equal(t, []string{itemID}, item.ContentMeta.ItemIDs)
+
+	created, err := client.CreateItem(ctx, &nuclino.CreateItemRequest{WorkspaceID: workspaceID, ParentID: guides, Title: "New"})
+	require.NoError(t, err)
+	moved, err := client.MoveItem(ctx, created.ID, nested)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, moved.ID)
+	collection, err := client.GetCollection(ctx, nested)
+	require.NoError(t, err)
+	assert.Equal(t, []string{itemID, created.ID}, collection.ChildIDs)
+
+	require.NoError(t, client.DeleteCollection(ctx, guides))
+	_, err = client.GetItem(ctx, itemID)
+	assert.True(t, nuclino.IsNotFound(err), "deleting a collection deletes its children")
+}
+
+func TestServer_SearchAndPagination(t *testing.T) {
+	server := nuclinotest.NewServer()
+	defer server.Close()
+	workspaceID := server.AddWorkspace(server.AddTeam("Acme"), "Docs")
+	for i := 0; i < 5; i++ {
+		server.AddItem(workspaceID, "Note", "Nothing to see")
+	}
+	server.AddItem(workspaceID, "Billing", "Invoices are sent monthly by the billing service")
+
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	found, err := client.SearchItems(ctx, &nuclino.SearchItemsRequest{WorkspaceID: workspaceID, Query: "invoices"})
+	require.NoError(t, err)
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, "Billing", found.Results[0].Title)
+	assert.Contains(t, found.Results[0].Highlight, "<mark>Invoices</mark>")
+
+	first, err := client.ListItems(ctx, workspaceID, 4, 0)
+	require.NoError(t, err)
+	second, err := client.ListItems(ctx, workspaceID, 4, 4)
+	require.NoError(t, err)
+	assert.Len(t, first.Results, 4)
+	assert.Len(t, second.Results, 2)
+	assert.NotEqual(t, first.Results[3].ID, second.Results[0].ID)
+
+	_, err = client.ListItems(ctx, "", 0, 0)
+	assert.True(t, nuclino.IsBadRequest(err), "listing items needs a workspace or team")
+}
+
+func TestServer_Files(t *testing.T) {
+	server := nuclinotest.NewServer()
+	defer server.Close()
+	workspaceID := server.AddWorkspace(server.AddTeam("Acme"), "Docs")
+	fileID := server.AddFile(server.AddItem(workspaceID, "Report", ""), "report.txt", []byte("quarterly numbers"))
+
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	file, err := client.GetFile(ctx, fileID)
+	require.NoError(t, err)
+	assert.Equal(t, "report.txt", file.DisplayName())
+	require.NotNil(t, file.Download)
+
+	data, err := client.DownloadFile(ctx, fileID)
+	require.NoError(t, err)
+	assert.Equal(t, "quarterly numbers", string(data))
+
+	uploaded, err := client.UploadFile(ctx, workspaceID, "notes.txt", []byte("hello"))
+	require.NoError(t, err)
+	files, err := client.ListFiles(ctx, workspaceID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, files.Results, 2)
+	assert.Equal(t, uploaded.ID, files.Results[1].ID)
+}
+
+func TestServer_Faults(t *testing.T) {
+	server := nuclinotest.NewServer()
+	defer server.Close()
+	workspaceID := server.AddWorkspace(server.AddTeam("Acme"), "Docs")
+	itemID := server.AddItem(workspaceID, "Readme", "")
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	server.InjectFault(nuclinotest.Fault{Path: "/v0/items/*", Status: http.StatusTooManyRequests, Count: 1})
+	_, err := client.GetItem(ctx, itemID)
+	require.NoError(t, err, "a single 429 is retried")
+	assert.Equal(t, []string{"GET /v0/items/" + itemID, "GET /v0/items/" + itemID}, server.Requests())
+
+	server.InjectFault(nuclinotest.Fault{Method: http.MethodGet, Status: http.StatusServiceUnavailable})
+	_, err = client.GetItem(ctx, itemID)
+	assert.True(t, nuclino.IsServerError(err))
+
+	server.ClearFaults()
+	_, err = client.GetItem(ctx, itemID)
+	require.NoError(t, err)
+}
+
+func TestServer_UploadRetry(t *testing.T) {
+	server := nuclinotest.NewServer()
+	defer server.Close()
+	workspaceID := server.AddWorkspace(server.AddTeam("Acme"), "Docs")
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	// A retried upload sends the contents again rather than an empty file
+	server.InjectFault(nuclinotest.Fault{Method: http.MethodPost, Path: "/v0/files", Status: http.StatusServiceUnavailable, Count: 1})
+	uploaded, err := client.UploadFileFrom(ctx, workspaceID, "notes.txt", strings.NewReader("hello"))
+	require.NoError(t, err)
+	data, err := client.DownloadFile(ctx, uploaded.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	// A reader that cannot be rewound gets a single attempt
+	server.InjectFault(nuclinotest.Fault{Method: http.MethodPost, Path: "/v0/files", Status: http.StatusServiceUnavailable, Count: 1})
+	_, err = client.UploadFileFrom(ctx, workspaceID, "notes.txt", io.MultiReader(strings.NewReader("hello")))
+	assert.True(t, nuclino.IsServerError(err))
+	files, err := client.ListFiles(ctx, workspaceID, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, files.Results, 1)
+}
+
+func TestServer_APIKey(t *testing.T) {
+	server := nuclinotest.NewServer()
+	defer server.Close()
+	server.SetAPIKey("secret")
+
+	_, err := newClient(t, server).ListTeams(context.Background(), 0, 0)
+	assert.True(t, nuclino.IsUnauthorized(err))
+
+	client := nuclino.NewClientWithConfig("secret", server.URL, 100, time.Second)
+	_, err = client.ListTeams(context.Background(), 0, 0)
+	require.NoError(t, err)
+}
+
+func TestSeedDemo(t *testing.T) {
+	server := nuclinotest.NewServer()
+	defer server.Close()
+	teamID := server.SeedDemo()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	team, err := client.GetTeam(ctx, teamID)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", team.Name)
+
+	workspaces, err := client.ListWorkspaces(ctx, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, workspaces.Results, 2)
+
+	found, err := client.SearchItems(ctx, &nuclino.SearchItemsRequest{WorkspaceID: workspaces.Results[0].ID, Query: "postmortem"})
+	require.NoError(t, err)
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, "Incident response", found.Results[0].Title)
+}
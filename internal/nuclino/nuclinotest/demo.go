@@ -0,0 +1,61 @@
+package nuclinotest
+
+import "fmt"
+
+// SeedDemo fills the API with a small team wiki: two workspaces with nested
+// collections, cross-linked items and an attached file. It returns the team
+// ID.
+func (a *API) SeedDemo() string {
+	teamID := a.AddTeam("Acme")
+
+	engineering := a.AddWorkspace(teamID, "Engineering")
+	handbook := a.AddCollection(engineering, "Handbook")
+	onboarding := a.AddItem(handbook, "Onboarding", "# Onboarding\n\n"+
+		"Welcome to the engineering team! Your first week:\n\n"+
+		"1. Set up your laptop following the development setup.\n"+
+		"2. Read the architecture overview.\n"+
+		"3. Pair with your onboarding buddy on a small bug fix.\n\n"+
+		"## Accounts\n\nAsk IT for access to the code host, the CI system and the on-call rota.\n")
+	setup := a.AddItem(handbook, "Development setup", "# Development setup\n\n"+
+		"Install Go 1.23 and Docker, then run `make dev` to start the local stack.\n\n"+
+		"## Troubleshooting\n\nIf the database does not start, remove the `data/` volume and retry.\n")
+	a.AddItem(handbook, "Code review guidelines", "# Code review guidelines\n\n"+
+		"- Keep pull requests small and focused.\n"+
+		"- Reviewers answer within one working day.\n"+
+		"- Approve when the change is an improvement, even if not perfect.\n")
+
+	runbooks := a.AddCollection(engineering, "Runbooks")
+	incidents := a.AddCollection(runbooks, "Incidents")
+	a.AddItem(incidents, "Incident response", "# Incident response\n\n"+
+		"1. Acknowledge the page within 5 minutes.\n"+
+		"2. Open an incident channel and name an incident lead.\n"+
+		"3. Post updates every 30 minutes until resolved.\n\n"+
+		"## After the incident\n\nWrite a blameless postmortem within two days.\n")
+	a.AddItem(runbooks, "Database failover", "# Database failover\n\n"+
+		"Promote the replica with `make db-failover` and update the connection string.\n")
+	architecture := a.AddItem(engineering, "Architecture overview", "# Architecture overview\n\n"+
+		"The platform is a set of Go services behind an API gateway.\n\n"+
+		"## Services\n\n- **gateway**: routing and authentication\n- **billing**: invoices and payments\n\n"+
+		"## Data\n\nEach service owns its PostgreSQL database.\n")
+	diagram := a.AddFile(architecture, "architecture.txt", []byte("gateway -> billing\ngateway -> accounts\n"))
+
+	product := a.AddWorkspace(teamID, "Product")
+	roadmap := a.AddCollection(product, "Roadmap")
+	a.AddItem(roadmap, "Q1 goals", "# Q1 goals\n\n- Launch self-serve billing\n- Cut onboarding time in half\n")
+	a.AddItem(roadmap, "Q2 goals", "# Q2 goals\n\n- Public API\n- Team workspaces\n")
+	a.AddItem(product, "Meeting notes", "# Meeting notes\n\n## Weekly sync\n\nDiscussed the billing launch and the onboarding survey.\n")
+
+	// Link items and the file now that their IDs exist
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	link := func(id, title string) string {
+		return fmt.Sprintf("[%s](https://app.nuclino.com/t/b/%s)", title, id)
+	}
+	onboardingItem := a.items[onboarding]
+	a.setContent(onboardingItem, onboardingItem.content+
+		"\nSee also "+link(setup, "Development setup")+" and "+link(architecture, "Architecture overview")+".\n")
+	architectureItem := a.items[architecture]
+	a.setContent(architectureItem, architectureItem.content+
+		"\n[architecture.txt](https://files.nuclino.com/files/"+diagram+"/architecture.txt)\n")
+	return teamID
+}
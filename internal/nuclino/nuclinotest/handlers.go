@@ -0,0 +1,563 @@
+package nuclinotest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// downloadTTL is how long signed download URLs stay valid
+const downloadTTL = 10 * time.Minute
+
+// list is the data of a list response
+type list struct {
+	Object  string      `json:"object"`
+	Results interface{} `json:"results"`
+}
+
+// respond writes the success envelope {"status":"success","data":...}
+func respond(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "data": data})
+}
+
+// fail writes the error envelope the API uses: status "fail" for client
+// errors and "error" for server errors
+func fail(w http.ResponseWriter, status int, message string) {
+	result := "fail"
+	if status >= 500 {
+		result = "error"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": result, "message": message})
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	fail(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// decode reads a JSON request body into v
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		fail(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// page applies the limit, offset and after query parameters to ids. The
+// live API pages with after, the ID of the last entry seen; offset is
+// what the nuclino client sends.
+func page(w http.ResponseWriter, r *http.Request, ids []string) ([]string, bool) {
+	query := r.URL.Query()
+	limit := maxLimit
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > maxLimit {
+			fail(w, http.StatusBadRequest, "limit must be a number between 1 and "+strconv.Itoa(maxLimit))
+			return nil, false
+		}
+		// The client sends limit=0 along with an offset to mean the default
+		if n > 0 {
+			limit = n
+		}
+	}
+
+	start := 0
+	if value := query.Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			fail(w, http.StatusBadRequest, "offset must be a non-negative number")
+			return nil, false
+		}
+		start = n
+	}
+	if after := query.Get("after"); after != "" {
+		i := slices.Index(ids, after)
+		if i < 0 {
+			fail(w, http.StatusBadRequest, "Unknown after cursor")
+			return nil, false
+		}
+		start = i + 1
+	}
+
+	if start > len(ids) {
+		start = len(ids)
+	}
+	return ids[start:min(start+limit, len(ids))], true
+}
+
+func (a *API) serveUsers(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 {
+		fail(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	u, ok := a.users[segments[0]]
+	if !ok {
+		fail(w, http.StatusNotFound, "User not found")
+		return
+	}
+	respond(w, http.StatusOK, u)
+}
+
+func (a *API) serveTeams(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	switch len(segments) {
+	case 0:
+		ids, ok := page(w, r, a.teamOrder)
+		if !ok {
+			return
+		}
+		results := make([]*team, 0, len(ids))
+		for _, id := range ids {
+			results = append(results, a.teams[id])
+		}
+		respond(w, http.StatusOK, list{Object: "list", Results: results})
+	case 1:
+		t, ok := a.teams[segments[0]]
+		if !ok {
+			fail(w, http.StatusNotFound, "Team not found")
+			return
+		}
+		respond(w, http.StatusOK, t)
+	default:
+		fail(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (a *API) serveWorkspaces(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			a.listWorkspaces(w, r)
+		case http.MethodPost:
+			a.createWorkspace(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	ws, ok := a.workspaces[segments[0]]
+	if !ok {
+		fail(w, http.StatusNotFound, "Workspace not found")
+		return
+	}
+	if len(segments) == 2 && segments[1] == "files" && r.Method == http.MethodGet {
+		a.listFiles(w, r, ws.ID)
+		return
+	}
+	if len(segments) != 1 {
+		fail(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		respond(w, http.StatusOK, ws)
+	case http.MethodPatch, http.MethodPut:
+		var req struct {
+			Name *string `json:"name"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != nil {
+			if *req.Name == "" {
+				fail(w, http.StatusBadRequest, "name must not be empty")
+				return
+			}
+			ws.Name = *req.Name
+		}
+		respond(w, http.StatusOK, ws)
+	case http.MethodDelete:
+		for _, childID := range ws.ChildIDs {
+			if child, ok := a.items[childID]; ok {
+				a.deleteEntry(child)
+			}
+		}
+		delete(a.workspaces, ws.ID)
+		a.workspaceOrder = remove(a.workspaceOrder, ws.ID)
+		respond(w, http.StatusOK, map[string]string{"id": ws.ID})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (a *API) listWorkspaces(w http.ResponseWriter, r *http.Request) {
+	teamID := r.URL.Query().Get("teamId")
+	var ids []string
+	for _, id := range a.workspaceOrder {
+		if teamID == "" || a.workspaces[id].TeamID == teamID {
+			ids = append(ids, id)
+		}
+	}
+	ids, ok := page(w, r, ids)
+	if !ok {
+		return
+	}
+	results := make([]*workspace, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, a.workspaces[id])
+	}
+	respond(w, http.StatusOK, list{Object: "list", Results: results})
+}
+
+func (a *API) createWorkspace(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamID string `json:"teamId"`
+		Name   string `json:"name"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		fail(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if _, ok := a.teams[req.TeamID]; !ok {
+		fail(w, http.StatusBadRequest, "teamId must be the ID of an existing team")
+		return
+	}
+	respond(w, http.StatusOK, a.workspaces[a.addWorkspace(req.TeamID, req.Name)])
+}
+
+func (a *API) serveItems(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			a.listItems(w, r)
+		case http.MethodPost:
+			a.createItem(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	entry, ok := a.items[segments[0]]
+	if !ok {
+		fail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if len(segments) == 2 && segments[1] == "move" && r.Method == http.MethodPatch {
+		a.moveItem(w, r, entry)
+		return
+	}
+	if len(segments) != 1 {
+		fail(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		respond(w, http.StatusOK, a.render(entry, true))
+	case http.MethodPut, http.MethodPatch:
+		a.updateItem(w, r, entry)
+	case http.MethodDelete:
+		a.detach(entry)
+		a.deleteEntry(entry)
+		respond(w, http.StatusOK, map[string]string{"id": entry.ID})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// render returns a copy of entry as the API sends it; only single items
+// carry their content
+func (a *API) render(entry *item, withContent bool) *item {
+	out := *entry
+	if withContent {
+		content := entry.content
+		out.Content = &content
+	}
+	return &out
+}
+
+// listItems lists the items and collections of a workspace or team, or
+// searches them when the search parameter is set
+func (a *API) listItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	workspaceID, teamID, search := query.Get("workspaceId"), query.Get("teamId"), query.Get("search")
+	if workspaceID == "" && teamID == "" {
+		fail(w, http.StatusBadRequest, "Either workspaceId or teamId must be provided")
+		return
+	}
+
+	var ids []string
+	highlights := make(map[string]string)
+	for _, id := range a.itemOrder {
+		entry := a.items[id]
+		if workspaceID != "" && entry.WorkspaceID != workspaceID {
+			continue
+		}
+		if teamID != "" && a.workspaces[entry.WorkspaceID].TeamID != teamID {
+			continue
+		}
+		if search != "" {
+			fragment, ok := highlight(entry.content, search)
+			if !ok {
+				fragment, ok = highlight(entry.Title, search)
+			}
+			if !ok {
+				continue
+			}
+			highlights[id] = fragment
+		}
+		ids = append(ids, id)
+	}
+
+	ids, ok := page(w, r, ids)
+	if !ok {
+		return
+	}
+	results := make([]*item, 0, len(ids))
+	for _, id := range ids {
+		out := a.render(a.items[id], false)
+		out.Highlight = highlights[id]
+		results = append(results, out)
+	}
+	respond(w, http.StatusOK, list{Object: "list", Results: results})
+}
+
+func (a *API) createItem(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		WorkspaceID string `json:"workspaceId"`
+		ParentID    string `json:"parentId"`
+		Object      string `json:"object"`
+		Title       string `json:"title"`
+		Content     string `json:"content"`
+		Index       *int   `json:"index"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Object == "" {
+		req.Object = objectItem
+	}
+	if req.Object != objectItem && req.Object != objectCollection {
+		fail(w, http.StatusBadRequest, `object must be "item" or "collection"`)
+		return
+	}
+
+	parentID := req.ParentID
+	if parentID == "" {
+		parentID = req.WorkspaceID
+	}
+	if parentID == "" {
+		fail(w, http.StatusBadRequest, "Either workspaceId or parentId must be provided")
+		return
+	}
+	if req.ParentID != "" && req.WorkspaceID != "" {
+		if parent, ok := a.items[req.ParentID]; ok && parent.WorkspaceID != req.WorkspaceID {
+			fail(w, http.StatusBadRequest, "parentId is not in the given workspace")
+			return
+		}
+	}
+	index := -1
+	if req.Index != nil {
+		index = *req.Index
+	}
+
+	entry, err := a.addEntry(req.Object, parentID, req.Title, req.Content, index)
+	if err == errNotFound {
+		fail(w, http.StatusNotFound, "Parent not found")
+		return
+	}
+	if err != nil {
+		fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respond(w, http.StatusOK, a.render(entry, true))
+}
+
+func (a *API) updateItem(w http.ResponseWriter, r *http.Request, entry *item) {
+	var req struct {
+		Title   *string                `json:"title"`
+		Content *string                `json:"content"`
+		Fields  map[string]interface{} `json:"fields"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Title != nil {
+		entry.Title = *req.Title
+	}
+	if req.Content != nil {
+		a.setContent(entry, *req.Content)
+	}
+	for name, value := range req.Fields {
+		entry.Fields[name] = value
+	}
+	entry.LastUpdatedAt = a.tick()
+	entry.LastUpdatedUserID = a.userID
+	respond(w, http.StatusOK, a.render(entry, true))
+}
+
+// moveItem moves an entry to the end of another collection or to the top
+// level of a workspace
+func (a *API) moveItem(w http.ResponseWriter, r *http.Request, entry *item) {
+	var req struct {
+		CollectionID string `json:"collectionId"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	var workspaceID string
+	var siblings *[]string
+	if ws, ok := a.workspaces[req.CollectionID]; ok {
+		workspaceID, siblings = ws.ID, &ws.ChildIDs
+	} else if target, ok := a.items[req.CollectionID]; ok && target.Object == objectCollection {
+		for id := target.ID; id != ""; {
+			if id == entry.ID {
+				fail(w, http.StatusBadRequest, "Cannot move a collection into itself")
+				return
+			}
+			parent, ok := a.items[id]
+			if !ok {
+				break
+			}
+			id = parent.parentID
+		}
+		workspaceID, siblings = target.WorkspaceID, &target.ChildIDs
+	} else {
+		fail(w, http.StatusBadRequest, "collectionId must be the ID of a collection or workspace")
+		return
+	}
+
+	a.detach(entry)
+	entry.parentID = req.CollectionID
+	*siblings = append(*siblings, entry.ID)
+	a.setWorkspace(entry, workspaceID)
+	entry.LastUpdatedAt = a.tick()
+	respond(w, http.StatusOK, a.render(entry, true))
+}
+
+// setWorkspace moves entry and everything under it to a workspace
+func (a *API) setWorkspace(entry *item, workspaceID string) {
+	entry.WorkspaceID = workspaceID
+	for _, childID := range entry.ChildIDs {
+		if child, ok := a.items[childID]; ok {
+			a.setWorkspace(child, workspaceID)
+		}
+	}
+}
+
+func (a *API) serveFiles(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		a.uploadFile(w, r)
+		return
+	}
+
+	f, ok := a.files[segments[0]]
+	if !ok {
+		fail(w, http.StatusNotFound, "File not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	switch {
+	case len(segments) == 1:
+		out := *f
+		out.Download = &fileDownload{
+			URL:       "http://" + r.Host + "/downloads/" + f.ID,
+			ExpiresAt: a.now.Add(downloadTTL),
+		}
+		respond(w, http.StatusOK, &out)
+	case len(segments) == 2 && segments[1] == "download":
+		writeFile(w, f)
+	default:
+		fail(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (a *API) listFiles(w http.ResponseWriter, r *http.Request, workspaceID string) {
+	var ids []string
+	for _, id := range a.fileOrder {
+		if a.files[id].workspaceID == workspaceID {
+			ids = append(ids, id)
+		}
+	}
+	ids, ok := page(w, r, ids)
+	if !ok {
+		return
+	}
+	results := make([]*file, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, a.files[id])
+	}
+	respond(w, http.StatusOK, list{Object: "list", Results: results})
+}
+
+// uploadFile accepts a multipart upload with the contents in the "file" field
+// and an itemId or workspaceId form value
+func (a *API) uploadFile(w http.ResponseWriter, r *http.Request) {
+	upload, header, err := r.FormFile("file")
+	if err != nil {
+		fail(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer upload.Close()
+	data, err := io.ReadAll(upload)
+	if err != nil {
+		fail(w, http.StatusBadRequest, "Failed to read upload: "+err.Error())
+		return
+	}
+
+	itemID, workspaceID := r.FormValue("itemId"), r.FormValue("workspaceId")
+	if itemID == "" {
+		if _, ok := a.workspaces[workspaceID]; !ok {
+			fail(w, http.StatusBadRequest, "Either itemId or the ID of an existing workspace must be provided")
+			return
+		}
+	}
+	f, err := a.addFile(itemID, header.Filename, data)
+	if err != nil {
+		fail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if itemID == "" {
+		f.workspaceID = workspaceID
+	}
+	respond(w, http.StatusOK, f)
+}
+
+// serveDownload serves the signed download URLs handed out with files
+func (a *API) serveDownload(w http.ResponseWriter, r *http.Request, fileID string) {
+	f, ok := a.files[fileID]
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+	writeFile(w, f)
+}
+
+func writeFile(w http.ResponseWriter, f *file) {
+	contentType := http.DetectContentType(f.data)
+	if strings.HasSuffix(f.FileName, ".md") {
+		contentType = "text/markdown; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(f.data)))
+	_, _ = w.Write(f.data)
+}
@@ -0,0 +1,302 @@
+package nuclinotest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// The records below mirror the JSON the live v0 API sends, which differs in
+// places from the nuclino package's types (createdUserId, no totals in
+// lists, content only on single items)
+
+type user struct {
+	Object    string `json:"object"`
+	ID        string `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+}
+
+type team struct {
+	Object        string    `json:"object"`
+	ID            string    `json:"id"`
+	URL           string    `json:"url"`
+	Name          string    `json:"name"`
+	CreatedAt     time.Time `json:"createdAt"`
+	CreatedUserID string    `json:"createdUserId"`
+}
+
+type workspace struct {
+	Object        string        `json:"object"`
+	ID            string        `json:"id"`
+	TeamID        string        `json:"teamId"`
+	Name          string        `json:"name"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	CreatedUserID string        `json:"createdUserId"`
+	Fields        []interface{} `json:"fields"`
+	ChildIDs      []string      `json:"childIds"`
+}
+
+type contentMeta struct {
+	ItemIDs []string `json:"itemIds"`
+	FileIDs []string `json:"fileIds"`
+}
+
+type item struct {
+	Object            string                 `json:"object"`
+	ID                string                 `json:"id"`
+	WorkspaceID       string                 `json:"workspaceId"`
+	URL               string                 `json:"url"`
+	Title             string                 `json:"title"`
+	CreatedAt         time.Time              `json:"createdAt"`
+	CreatedUserID     string                 `json:"createdUserId"`
+	LastUpdatedAt     time.Time              `json:"lastUpdatedAt"`
+	LastUpdatedUserID string                 `json:"lastUpdatedUserId"`
+	ChildIDs          []string               `json:"childIds,omitempty"`
+	Content           *string                `json:"content,omitempty"`
+	ContentMeta       contentMeta            `json:"contentMeta"`
+	Fields            map[string]interface{} `json:"fields"`
+	Highlight         string                 `json:"highlight,omitempty"`
+
+	// parentID is the workspace or collection listing the item
+	parentID string
+	content  string
+}
+
+type fileDownload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type file struct {
+	Object        string        `json:"object"`
+	ID            string        `json:"id"`
+	ItemID        string        `json:"itemId,omitempty"`
+	FileName      string        `json:"fileName"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	CreatedUserID string        `json:"createdUserId"`
+	Download      *fileDownload `json:"download,omitempty"`
+
+	workspaceID string
+	data        []byte
+}
+
+// newID returns the next ID, formatted like the API's UUIDs
+func (a *API) newID() string {
+	a.lastID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", a.lastID)
+}
+
+// tick advances the fake clock, so every change gets a distinct time
+func (a *API) tick() time.Time {
+	a.now = a.now.Add(time.Minute)
+	return a.now
+}
+
+func (a *API) addUser(firstName, lastName, email string) string {
+	u := &user{Object: "user", ID: a.newID(), FirstName: firstName, LastName: lastName, Email: email}
+	a.users[u.ID] = u
+	return u.ID
+}
+
+func (a *API) addTeam(name string) string {
+	t := &team{
+		Object:        "team",
+		ID:            a.newID(),
+		URL:           "https://app.nuclino.com/" + strings.ReplaceAll(name, " ", "-"),
+		Name:          name,
+		CreatedAt:     a.tick(),
+		CreatedUserID: a.userID,
+	}
+	a.teams[t.ID] = t
+	a.teamOrder = append(a.teamOrder, t.ID)
+	return t.ID
+}
+
+func (a *API) addWorkspace(teamID, name string) string {
+	w := &workspace{
+		Object:        "workspace",
+		ID:            a.newID(),
+		TeamID:        teamID,
+		Name:          name,
+		CreatedAt:     a.tick(),
+		CreatedUserID: a.userID,
+		Fields:        []interface{}{},
+		ChildIDs:      []string{},
+	}
+	a.workspaces[w.ID] = w
+	a.workspaceOrder = append(a.workspaceOrder, w.ID)
+	return w.ID
+}
+
+// addEntry creates an item or collection under a workspace or collection,
+// at index in the parent's childIds or at the end when index is negative
+func (a *API) addEntry(object, parentID, title, content string, index int) (*item, error) {
+	var workspaceID string
+	var siblings *[]string
+	if w, ok := a.workspaces[parentID]; ok {
+		workspaceID = w.ID
+		siblings = &w.ChildIDs
+	} else if parent, ok := a.items[parentID]; ok {
+		if parent.Object != objectCollection {
+			return nil, fmt.Errorf("parent %s is not a collection", parentID)
+		}
+		workspaceID = parent.WorkspaceID
+		siblings = &parent.ChildIDs
+	} else {
+		return nil, errNotFound
+	}
+
+	now := a.tick()
+	entry := &item{
+		Object:            object,
+		ID:                a.newID(),
+		WorkspaceID:       workspaceID,
+		Title:             title,
+		CreatedAt:         now,
+		CreatedUserID:     a.userID,
+		LastUpdatedAt:     now,
+		LastUpdatedUserID: a.userID,
+		Fields:            map[string]interface{}{},
+		parentID:          parentID,
+	}
+	entry.URL = "https://app.nuclino.com/t/b/" + entry.ID
+	if object == objectCollection {
+		entry.ChildIDs = []string{}
+	}
+	a.items[entry.ID] = entry
+	a.itemOrder = append(a.itemOrder, entry.ID)
+	*siblings = insert(*siblings, entry.ID, index)
+	a.setContent(entry, content)
+	return entry, nil
+}
+
+func (a *API) addFile(itemID, fileName string, data []byte) (*file, error) {
+	entry, ok := a.items[itemID]
+	if itemID != "" && !ok {
+		return nil, errNotFound
+	}
+	f := &file{
+		Object:        "file",
+		ID:            a.newID(),
+		ItemID:        itemID,
+		FileName:      fileName,
+		CreatedAt:     a.tick(),
+		CreatedUserID: a.userID,
+		data:          append([]byte(nil), data...),
+	}
+	if entry != nil {
+		f.workspaceID = entry.WorkspaceID
+	}
+	a.files[f.ID] = f
+	a.fileOrder = append(a.fileOrder, f.ID)
+	return f, nil
+}
+
+// idPattern finds IDs referenced from content, e.g. in item links
+var idPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// setContent stores content and derives contentMeta from the item and file
+// IDs it mentions
+func (a *API) setContent(entry *item, content string) {
+	entry.content = content
+	entry.ContentMeta = contentMeta{ItemIDs: []string{}, FileIDs: []string{}}
+	seen := make(map[string]bool)
+	for _, id := range idPattern.FindAllString(content, -1) {
+		if seen[id] || id == entry.ID {
+			continue
+		}
+		seen[id] = true
+		if _, ok := a.items[id]; ok {
+			entry.ContentMeta.ItemIDs = append(entry.ContentMeta.ItemIDs, id)
+		} else if _, ok := a.files[id]; ok {
+			entry.ContentMeta.FileIDs = append(entry.ContentMeta.FileIDs, id)
+		}
+	}
+}
+
+// detach removes an entry from its parent's childIds
+func (a *API) detach(entry *item) {
+	if w, ok := a.workspaces[entry.parentID]; ok {
+		w.ChildIDs = remove(w.ChildIDs, entry.ID)
+	} else if parent, ok := a.items[entry.parentID]; ok {
+		parent.ChildIDs = remove(parent.ChildIDs, entry.ID)
+	}
+}
+
+// deleteEntry removes an entry and, for a collection, everything under it
+func (a *API) deleteEntry(entry *item) {
+	for _, childID := range entry.ChildIDs {
+		if child, ok := a.items[childID]; ok {
+			a.deleteEntry(child)
+		}
+	}
+	delete(a.items, entry.ID)
+	a.itemOrder = remove(a.itemOrder, entry.ID)
+}
+
+func insert(ids []string, id string, index int) []string {
+	if index < 0 || index >= len(ids) {
+		return append(ids, id)
+	}
+	ids = append(ids, "")
+	copy(ids[index+1:], ids[index:])
+	ids[index] = id
+	return ids
+}
+
+func remove(ids []string, id string) []string {
+	kept := ids[:0]
+	for _, existing := range ids {
+		if existing != id {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
+
+// highlight returns the text around the first match of query with every
+// match in it wrapped in <mark>, or false when text does not match
+func highlight(text, query string) (string, bool) {
+	lower := strings.ToLower(text)
+	needle := strings.ToLower(query)
+	at := strings.Index(lower, needle)
+	if at < 0 {
+		return "", false
+	}
+
+	const around = 40
+	start, end := max(at-around, 0), min(at+len(needle)+around, len(text))
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	fragment, lowerFragment := text[start:end], lower[start:end]
+	for {
+		i := strings.Index(lowerFragment, needle)
+		if i < 0 {
+			b.WriteString(fragment)
+			break
+		}
+		b.WriteString(fragment[:i])
+		b.WriteString("<mark>" + fragment[i:i+len(needle)] + "</mark>")
+		fragment, lowerFragment = fragment[i+len(needle):], lowerFragment[i+len(needle):]
+	}
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,78 @@
+package nuclino
+
+import "context"
+
+// MaxPageSize is the largest limit the list endpoints accept
+const MaxPageSize = 100
+
+// ItemLister is the subset of Client needed to page through a workspace
+type ItemLister interface {
+	ListItems(ctx context.Context, workspaceID string, limit, offset int) (*ItemsResponse, error)
+}
+
+// WorkspaceLister is the subset of Client needed to page through workspaces
+type WorkspaceLister interface {
+	ListWorkspaces(ctx context.Context, limit, offset int) (*WorkspacesResponse, error)
+}
+
+// EachItemPage lists the items and collections of a workspace page by page,
+// calling fn with every page until the workspace is exhausted or fn returns
+// false. Page sizes outside 1 to MaxPageSize use MaxPageSize.
+func EachItemPage(ctx context.Context, c ItemLister, workspaceID string, pageSize int, fn func(page []Item) bool) error {
+	return eachPage(pageSize, func(limit, offset int) ([]Item, error) {
+		page, err := c.ListItems(ctx, workspaceID, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		return page.Results, nil
+	}, fn)
+}
+
+// ListAllItems returns every item and collection of a workspace
+func ListAllItems(ctx context.Context, c ItemLister, workspaceID string, pageSize int) ([]Item, error) {
+	var items []Item
+	err := EachItemPage(ctx, c, workspaceID, pageSize, func(page []Item) bool {
+		items = append(items, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListAllWorkspaces returns every workspace the API key can see
+func ListAllWorkspaces(ctx context.Context, c WorkspaceLister, pageSize int) ([]Workspace, error) {
+	var workspaces []Workspace
+	err := eachPage(pageSize, func(limit, offset int) ([]Workspace, error) {
+		page, err := c.ListWorkspaces(ctx, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		return page.Results, nil
+	}, func(page []Workspace) bool {
+		workspaces = append(workspaces, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// eachPage requests pages until one comes back short, which the API only
+// sends for the last page, or fn stops early
+func eachPage[T any](pageSize int, list func(limit, offset int) ([]T, error), fn func(page []T) bool) error {
+	if pageSize <= 0 || pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	for offset := 0; ; offset += pageSize {
+		page, err := list(pageSize, offset)
+		if err != nil {
+			return err
+		}
+		if !fn(page) || len(page) < pageSize {
+			return nil
+		}
+	}
+}
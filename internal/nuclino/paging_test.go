@@ -0,0 +1,70 @@
+package nuclino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedItems serves count items and records the pages asked for
+type pagedItems struct {
+	count    int
+	requests [][2]int
+	fail     error
+}
+
+func (p *pagedItems) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*ItemsResponse, error) {
+	p.requests = append(p.requests, [2]int{limit, offset})
+	if p.fail != nil {
+		return nil, p.fail
+	}
+	resp := &ItemsResponse{}
+	for i := offset; i < p.count && i < offset+limit; i++ {
+		resp.Results = append(resp.Results, Item{ID: fmt.Sprintf("item-%d", i)})
+	}
+	return resp, nil
+}
+
+func TestListAllItems_StopsAtTheShortPage(t *testing.T) {
+	for _, tc := range []struct {
+		count    int
+		requests [][2]int
+	}{
+		{count: 0, requests: [][2]int{{10, 0}}},
+		{count: 7, requests: [][2]int{{10, 0}}},
+		{count: 20, requests: [][2]int{{10, 0}, {10, 10}, {10, 20}}},
+		{count: 25, requests: [][2]int{{10, 0}, {10, 10}, {10, 20}}},
+	} {
+		lister := &pagedItems{count: tc.count}
+		items, err := ListAllItems(context.Background(), lister, "ws", 10)
+		require.NoError(t, err)
+		assert.Len(t, items, tc.count)
+		assert.Equal(t, tc.requests, lister.requests, "%d items", tc.count)
+	}
+
+	// Page sizes the API rejects fall back to the largest one it accepts
+	lister := &pagedItems{count: 3}
+	_, err := ListAllItems(context.Background(), lister, "ws", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int{{MaxPageSize, 0}}, lister.requests)
+}
+
+func TestEachItemPage_StopsEarly(t *testing.T) {
+	lister := &pagedItems{count: 50}
+	seen := 0
+	err := EachItemPage(context.Background(), lister, "ws", 10, func(page []Item) bool {
+		seen += len(page)
+		return seen < 15
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 20, seen)
+	assert.Len(t, lister.requests, 2)
+
+	lister.fail = errors.New("boom")
+	_, err = ListAllItems(context.Background(), lister, "ws", 10)
+	assert.EqualError(t, err, "boom")
+}
@@ -43,35 +43,73 @@ type Workspace struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
 	TeamID    string    `json:"teamId"`
+	ChildIDs  []string  `json:"childIds,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
-// Collection represents a Nuclino collection
+// Object types of entries in the workspace tree
+const (
+	ObjectItem       = "item"
+	ObjectCollection = "collection"
+)
+
+// Collection represents a Nuclino collection. The API models collections as
+// items with object "collection" whose children are listed in childIds.
 type Collection struct {
 	ID          string    `json:"id"`
 	Title       string    `json:"title"`
 	WorkspaceID string    `json:"workspaceId"`
+	URL         string    `json:"url,omitempty"`
+	ChildIDs    []string  `json:"childIds,omitempty"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
 // Item represents a Nuclino item
 type Item struct {
-	ID           string    `json:"id"`
-	Title        string    `json:"title"`
-	Content      string    `json:"content"`
-	CollectionID string    `json:"collectionId"`
-	WorkspaceID  string    `json:"workspaceId"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
-	CreatedBy    string    `json:"createdBy"`
-	UpdatedBy    string    `json:"updatedBy"`
-	URL          string    `json:"url"`
+	ID            string                 `json:"id"`
+	Object        string                 `json:"object,omitempty"`
+	Title         string                 `json:"title"`
+	Content       string                 `json:"content"`
+	CollectionID  string                 `json:"collectionId"`
+	WorkspaceID   string                 `json:"workspaceId"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	UpdatedAt     time.Time              `json:"updatedAt"`
+	LastUpdatedAt time.Time              `json:"lastUpdatedAt,omitempty"`
+	CreatedBy     string                 `json:"createdBy"`
+	UpdatedBy     string                 `json:"updatedBy"`
+	URL           string                 `json:"url"`
+	ContentMeta   ContentMeta            `json:"contentMeta"`
+	ChildIDs      []string               `json:"childIds,omitempty"`
+	Fields        map[string]interface{} `json:"fields,omitempty"`
+	// Highlight is the matching fragment of a search result
+	Highlight string `json:"highlight,omitempty"`
+}
+
+// ContentMeta lists the items and files referenced from an item's content
+type ContentMeta struct {
+	ItemIDs []string `json:"itemIds"`
+	FileIDs []string `json:"fileIds"`
+}
+
+// ModifiedAt returns the last modification time reported by the API.
+// The live API sends lastUpdatedAt; updatedAt is kept for older payloads.
+func (i *Item) ModifiedAt() time.Time {
+	if !i.LastUpdatedAt.IsZero() {
+		return i.LastUpdatedAt
+	}
+	return i.UpdatedAt
+}
+
+// IsCollection reports whether the item is a collection
+func (i *Item) IsCollection() bool {
+	return i.Object == ObjectCollection
 }
 
 // CreateItemRequest represents the request to create a new item
 type CreateItemRequest struct {
+	Object      string `json:"object,omitempty"`
 	Title       string `json:"title" validate:"required"`
 	Content     string `json:"content"`
 	WorkspaceID string `json:"workspaceId" validate:"required"`
@@ -80,8 +118,9 @@ type CreateItemRequest struct {
 
 // UpdateItemRequest represents the request to update an item
 type UpdateItemRequest struct {
-	Title   *string `json:"title,omitempty"`
-	Content *string `json:"content,omitempty"`
+	Title   *string                `json:"title,omitempty"`
+	Content *string                `json:"content,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
 }
 
 // SearchItemsRequest represents the request for searching items
@@ -107,6 +146,7 @@ type UpdateWorkspaceRequest struct {
 type CreateCollectionRequest struct {
 	Title       string `json:"title" validate:"required"`
 	WorkspaceID string `json:"workspaceId" validate:"required"`
+	ParentID    string `json:"parentId,omitempty"`
 }
 
 // UpdateCollectionRequest represents the request to update a collection
@@ -126,13 +166,30 @@ type Field struct {
 
 // File represents a Nuclino file
 type File struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
+	ID        string        `json:"id"`
+	ItemID    string        `json:"itemId,omitempty"`
+	Name      string        `json:"name"`
+	FileName  string        `json:"fileName,omitempty"`
+	URL       string        `json:"url"`
+	Size      int64         `json:"size"`
+	MimeType  string        `json:"mimeType"`
+	Download  *FileDownload `json:"download,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// DisplayName returns the file name as sent by whichever API version answered
+func (f *File) DisplayName() string {
+	if f.FileName != "" {
+		return f.FileName
+	}
+	return f.Name
+}
+
+// FileDownload holds the short-lived signed URL the API returns for a file
+type FileDownload struct {
 	URL       string    `json:"url"`
-	Size      int64     `json:"size"`
-	MimeType  string    `json:"mimeType"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 // PaginatedResponse represents a paginated API response
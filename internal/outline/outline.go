@@ -0,0 +1,201 @@
+// Package outline extracts the heading structure of Markdown item content,
+// so that long items can be read a section at a time.
+package outline
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Section is a heading and the content under it, up to the next heading of
+// the same or a higher level
+type Section struct {
+	// Anchor identifies the section: the slug of its title, suffixed with
+	// "-1", "-2", ... for repeated titles, as on GitHub
+	Anchor string `json:"anchor"`
+	Title  string `json:"title"`
+	Level  int    `json:"level"`
+	// Words counts the words under the heading up to the next heading
+	Words int `json:"words"`
+	// TotalWords also counts the words of the subsections
+	TotalWords int `json:"total_words"`
+
+	// start and end are the byte offsets of the section in the content,
+	// from its heading line to the end of its last subsection
+	start, end int
+}
+
+// Outline is the heading structure of Markdown content
+type Outline struct {
+	// PreambleWords counts the words before the first heading
+	PreambleWords int `json:"preamble_words"`
+	// Words counts the words of the whole content, headings excluded
+	Words    int       `json:"words"`
+	Sections []Section `json:"sections"`
+}
+
+// Parse returns the outline of content. ATX headings ("## Title") are
+// recognised outside fenced code blocks.
+func Parse(content string) *Outline {
+	outline := &Outline{Sections: []Section{}}
+	anchors := make(map[string]int)
+	words := 0 // words since the last heading
+	flush := func() {
+		if n := len(outline.Sections); n > 0 {
+			outline.Sections[n-1].Words = words
+		} else {
+			outline.PreambleWords = words
+		}
+		outline.Words += words
+		words = 0
+	}
+
+	var fence Fence
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		start := offset
+		offset += len(line)
+		if !fence.Code(line) {
+			if level, title, ok := ParseHeading(line); ok {
+				flush()
+				anchor := Slug(title)
+				if n := anchors[anchor]; n > 0 {
+					anchors[anchor]++
+					anchor = fmt.Sprintf("%s-%d", anchor, n)
+				} else {
+					anchors[anchor] = 1
+				}
+				outline.Sections = append(outline.Sections, Section{Anchor: anchor, Title: title, Level: level, start: start})
+				continue
+			}
+		}
+		words += len(strings.Fields(line))
+	}
+	flush()
+
+	// A section ends where the next heading of the same or a higher level
+	// starts, and counts the words of everything in between
+	for i := range outline.Sections {
+		section := &outline.Sections[i]
+		section.end = len(content)
+		section.TotalWords = section.Words
+		for _, next := range outline.Sections[i+1:] {
+			if next.Level <= section.Level {
+				section.end = next.start
+				break
+			}
+			section.TotalWords += next.Words
+		}
+	}
+	return outline
+}
+
+// Find returns the section with the anchor or, failing that, the title
+// name, both matched case-insensitively
+func (o *Outline) Find(name string) (*Section, bool) {
+	name = strings.TrimSpace(name)
+	for i := range o.Sections {
+		if strings.EqualFold(o.Sections[i].Anchor, strings.TrimPrefix(name, "#")) {
+			return &o.Sections[i], true
+		}
+	}
+	for i := range o.Sections {
+		if strings.EqualFold(o.Sections[i].Title, name) {
+			return &o.Sections[i], true
+		}
+	}
+	return nil, false
+}
+
+// Anchors returns the anchors of every section, in order
+func (o *Outline) Anchors() []string {
+	anchors := make([]string, len(o.Sections))
+	for i, section := range o.Sections {
+		anchors[i] = section.Anchor
+	}
+	return anchors
+}
+
+// Text returns the section in content, the content the outline was parsed
+// from, with its heading line and subsections
+func (s *Section) Text(content string) string {
+	return strings.TrimRight(content[s.start:s.end], "\n")
+}
+
+// Slug turns a heading title into an anchor: lower case letters, digits,
+// "-" and "_", with spaces as "-"
+func Slug(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(title)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "section"
+	}
+	return b.String()
+}
+
+// ParseHeading parses an ATX heading line: up to three spaces of
+// indentation, one to six "#", a space and the title, optionally followed
+// by a closing sequence of "#"
+func ParseHeading(line string) (level int, title string, ok bool) {
+	line = strings.TrimRight(line, "\r\n")
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0, "", false
+	}
+	level = len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	if level < 1 || level > 6 {
+		return 0, "", false
+	}
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+	title = strings.TrimSpace(rest)
+	if closed := strings.TrimRight(title, "#"); closed == "" || strings.HasSuffix(closed, " ") {
+		title = strings.TrimSpace(closed)
+	}
+	if title == "" {
+		return 0, "", false
+	}
+	return level, title, true
+}
+
+// Fence follows fenced code blocks through content read line by line
+type Fence struct {
+	marker string
+}
+
+// Code reports whether line is part of a fenced code block, the opening
+// and closing fences included. A block closes at a fence of the same
+// character that is at least as long as the one that opened it.
+func (f *Fence) Code(line string) bool {
+	if marker := fenceMarker(strings.TrimSpace(line)); marker != "" && (f.marker == "" || strings.HasPrefix(marker, f.marker)) {
+		if f.marker == "" {
+			f.marker = marker
+		} else {
+			f.marker = ""
+		}
+		return true
+	}
+	return f.marker != ""
+}
+
+// fenceMarker returns the fence that opens or closes a code block on the
+// line, or ""
+func fenceMarker(line string) string {
+	for _, char := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, char))
+		if n >= 3 {
+			return strings.Repeat(char, n)
+		}
+	}
+	return ""
+}
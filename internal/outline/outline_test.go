@@ -0,0 +1,99 @@
+package outline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const design = `Status: draft for review
+
+# Payments Redesign
+
+Why we are doing this.
+
+## Goals
+
+Fewer failed charges.
+
+### Non-goals ###
+
+Refunds stay as they are.
+
+## Design
+
+` + "```" + `
+# not a heading
+` + "```" + `
+
+## Goals
+
+Repeated title.
+
+# Appendix: C#
+See below.
+`
+
+func TestParse(t *testing.T) {
+	outline := Parse(design)
+
+	assert.Equal(t, []string{"payments-redesign", "goals", "non-goals", "design", "goals-1", "appendix-c"}, outline.Anchors())
+	assert.Equal(t, 4, outline.PreambleWords)
+
+	require.Len(t, outline.Sections, 6)
+	top := outline.Sections[0]
+	assert.Equal(t, "Payments Redesign", top.Title)
+	assert.Equal(t, 1, top.Level)
+	assert.Equal(t, 5, top.Words)
+	assert.Equal(t, 5+3+5+6+2, top.TotalWords)
+
+	nonGoals := outline.Sections[2]
+	assert.Equal(t, "Non-goals", nonGoals.Title, "closing hashes are not part of the title")
+	assert.Equal(t, 3, nonGoals.Level)
+	assert.Equal(t, 6, outline.Sections[3].Words, "fenced code counts as words, not headings")
+	assert.Equal(t, "Appendix: C#", outline.Sections[5].Title)
+	assert.Equal(t, 4+5+3+5+6+2+2, outline.Words)
+}
+
+func TestSectionText(t *testing.T) {
+	outline := Parse(design)
+
+	goals, ok := outline.Find("goals")
+	require.True(t, ok)
+	assert.Equal(t, "## Goals\n\nFewer failed charges.\n\n### Non-goals ###\n\nRefunds stay as they are.", goals.Text(design))
+
+	repeated, ok := outline.Find("#goals-1")
+	require.True(t, ok)
+	assert.Equal(t, "## Goals\n\nRepeated title.", repeated.Text(design))
+
+	appendix, ok := outline.Find("appendix: c#")
+	require.True(t, ok, "found by title")
+	assert.Equal(t, "# Appendix: C#\nSee below.", appendix.Text(design))
+
+	_, ok = outline.Find("rollout")
+	assert.False(t, ok)
+}
+
+func TestParse_NoHeadings(t *testing.T) {
+	outline := Parse("just a few words")
+	assert.Empty(t, outline.Sections)
+	assert.Equal(t, 4, outline.PreambleWords)
+	assert.Equal(t, "section", Slug("!!!"))
+}
+
+func TestFence(t *testing.T) {
+	var fence Fence
+	var code []bool
+	for _, line := range []string{"text", "````md", "```", "still code", "````", "after"} {
+		code = append(code, fence.Code(line))
+	}
+	assert.Equal(t, []bool{false, true, true, true, true, false}, code, "a shorter fence does not close the block")
+
+	_, _, ok := ParseHeading("    # indented code")
+	assert.False(t, ok)
+	level, title, ok := ParseHeading("  ## Setup ##\n")
+	assert.True(t, ok)
+	assert.Equal(t, 2, level)
+	assert.Equal(t, "Setup", title)
+}
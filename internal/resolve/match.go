@@ -0,0 +1,91 @@
+package resolve
+
+import (
+	"strings"
+	"unicode"
+)
+
+// exactScore is the score of a name that matches a query exactly
+const exactScore = 4
+
+// match returns the indices of the names that match query best, or none
+// when no name matches at all
+func match(query string, names []string) []int {
+	best := 0
+	var matches []int
+	for i, name := range names {
+		switch s := score(query, name); {
+		case s > best:
+			best, matches = s, []int{i}
+		case s == best && s > 0:
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// score rates how well name matches query, ignoring case and punctuation:
+// 4 for the same text, 3 for a prefix, 2 when every word of the query
+// starts a word of the name, 1 for a close misspelling and 0 otherwise
+func score(query, name string) int {
+	q, n := normalize(query), normalize(name)
+	switch {
+	case q == "" || n == "":
+		return 0
+	case q == n:
+		return exactScore
+	case strings.HasPrefix(n, q):
+		return 3
+	case startsWords(strings.Fields(q), strings.Fields(n)):
+		return 2
+	case len([]rune(q)) >= 4 && distance([]rune(q), []rune(n)) <= max(1, len([]rune(q))/6):
+		return 1
+	}
+	return 0
+}
+
+// normalize lowercases text and turns runs of anything but letters and
+// digits into single spaces
+func normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// startsWords reports whether every query word starts a word of name
+func startsWords(query, name []string) bool {
+	for _, word := range query {
+		found := false
+		for _, candidate := range name {
+			if strings.HasPrefix(candidate, word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// distance is the Levenshtein distance between a and b
+func distance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
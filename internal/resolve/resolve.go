@@ -0,0 +1,459 @@
+// Package resolve turns the references people use for Nuclino content —
+// app URLs, workspace names, item titles and paths through the item tree
+// such as "Engineering / Onboarding" — into IDs.
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/cache"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+const (
+	defaultPageSize = 100
+	defaultCacheTTL = time.Minute
+
+	// maxCandidates bounds the candidates listed in an error
+	maxCandidates = 20
+)
+
+// Reasons a reference did not resolve
+const (
+	ReasonNotFound  = "not_found"
+	ReasonAmbiguous = "ambiguous"
+)
+
+var (
+	// IDs are the API's UUIDs, in lower case
+	idPattern    = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	urlIDPattern = regexp.MustCompile(`([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})/?$`)
+)
+
+// Candidate is a workspace, collection or item a reference may mean
+type Candidate struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	// Path is the workspace name and the titles leading to the candidate,
+	// usable as a reference itself
+	Path string `json:"path"`
+}
+
+// Error is a reference that did not resolve to exactly one ID
+type Error struct {
+	// Argument is the tool argument holding the reference
+	Argument   string      `json:"argument,omitempty"`
+	Reference  string      `json:"reference"`
+	Reason     string      `json:"reason"`
+	Message    string      `json:"message"`
+	Candidates []Candidate `json:"candidates,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Argument != "" {
+		return e.Argument + ": " + e.Message
+	}
+	return e.Message
+}
+
+// IsID reports whether ref is an ID rather than a reference to resolve
+func IsID(ref string) bool {
+	return idPattern.MatchString(ref)
+}
+
+// Config holds resolver configuration
+type Config struct {
+	PageSize int
+	// CacheTTL is how long the workspace list and item trees are reused
+	CacheTTL time.Duration
+}
+
+// DefaultConfig returns the default resolver configuration
+func DefaultConfig() Config {
+	return Config{
+		PageSize: defaultPageSize,
+		CacheTTL: defaultCacheTTL,
+	}
+}
+
+// Resolver resolves references through the workspace list and the item
+// trees of the workspaces, both cached for CacheTTL
+type Resolver struct {
+	client nuclino.Client
+	config Config
+	cache  *cache.Cache
+	// exact resolvers only take names and titles that match exactly
+	exact bool
+}
+
+// NewResolver creates a resolver
+func NewResolver(client nuclino.Client, config Config) *Resolver {
+	defaults := DefaultConfig()
+	if config.PageSize <= 0 {
+		config.PageSize = defaults.PageSize
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+
+	return &Resolver{
+		client: client,
+		config: config,
+		cache:  cache.NewCache(100, config.CacheTTL),
+	}
+}
+
+// Exact returns a resolver, sharing r's cache, that resolves names, titles
+// and paths only when they match exactly. Calls that change content use it,
+// so a typo never picks the wrong target.
+func (r *Resolver) Exact() *Resolver {
+	exact := *r
+	exact.exact = true
+	return &exact
+}
+
+// loose reports whether the one name matching query is a fuzzy match that
+// an exact resolver must not take
+func (r *Resolver) loose(query, name string) bool {
+	return r.exact && score(query, name) < exactScore
+}
+
+// Invalidate drops the cached lookups, after content was created, renamed
+// or moved
+func (r *Resolver) Invalidate() {
+	r.cache.Clear()
+}
+
+// Workspace resolves a workspace reference: an ID, a workspace or item URL,
+// or a workspace name
+func (r *Resolver) Workspace(ctx context.Context, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if IsID(ref) {
+		return ref, nil
+	}
+
+	name := strings.Trim(ref, "/ ")
+	if segments, ok := urlPath(ref); ok {
+		if id := urlItemID(ref); id != "" {
+			item, err := r.client.GetItem(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return item.WorkspaceID, nil
+		}
+		// Workspace URLs are "https://app.nuclino.com/<team>/<workspace>"
+		if len(segments) < 2 {
+			return "", &Error{Reference: ref, Reason: ReasonNotFound, Message: fmt.Sprintf("%s is not a workspace URL", ref)}
+		}
+		name = strings.ReplaceAll(segments[1], "-", " ")
+	}
+
+	workspaces, err := r.workspaces(ctx)
+	if err != nil {
+		return "", err
+	}
+	workspace, err := r.matchWorkspace(ref, name, workspaces)
+	if err != nil {
+		return "", err
+	}
+	return workspace.ID, nil
+}
+
+// Item resolves an item or collection reference: an ID, an item URL, a path
+// "Workspace / Collection / Item" or a title
+func (r *Resolver) Item(ctx context.Context, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if IsID(ref) {
+		return ref, nil
+	}
+	if _, ok := urlPath(ref); ok {
+		if id := urlItemID(ref); id != "" {
+			return id, nil
+		}
+		return "", &Error{Reference: ref, Reason: ReasonNotFound, Message: fmt.Sprintf("%s is not an item URL", ref)}
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(ref, "/") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) > 1 {
+		id, err := r.itemByPath(ctx, ref, segments)
+		var pathErr *Error
+		if errors.As(err, &pathErr) && pathErr.Reason == ReasonNotFound {
+			// Titles may contain slashes too, as in "CI/CD Pipeline"
+			if id, titleErr := r.itemByTitle(ctx, ref, ref); titleErr == nil {
+				return id, nil
+			}
+		}
+		return id, err
+	}
+	return r.itemByTitle(ctx, ref, strings.Join(segments, ""))
+}
+
+// itemByPath walks the item tree from the workspace named by the first
+// segment through the titles of the others
+func (r *Resolver) itemByPath(ctx context.Context, ref string, segments []string) (string, error) {
+	workspaces, err := r.workspaces(ctx)
+	if err != nil {
+		return "", err
+	}
+	workspace, err := r.matchWorkspace(ref, segments[0], workspaces)
+	if err != nil {
+		return "", err
+	}
+	tree, err := r.tree(ctx, workspace)
+	if err != nil {
+		return "", err
+	}
+
+	parent, children := "", workspace.ChildIDs
+	for _, segment := range segments[1:] {
+		titles := make([]string, len(children))
+		for i, id := range children {
+			titles[i] = tree.title(id)
+		}
+		matches := match(segment, titles)
+		switch len(matches) {
+		case 1:
+			if r.loose(segment, titles[matches[0]]) {
+				return "", &Error{
+					Reference:  ref,
+					Reason:     ReasonAmbiguous,
+					Message:    fmt.Sprintf("%q is not an exact title in %s; use the path or ID of the entry meant", segment, tree.path(parent)),
+					Candidates: tree.candidates([]string{children[matches[0]]}),
+				}
+			}
+			parent = children[matches[0]]
+			children = tree.children(parent)
+			continue
+		case 0:
+			return "", &Error{
+				Reference:  ref,
+				Reason:     ReasonNotFound,
+				Message:    fmt.Sprintf("nothing titled like %q in %s", segment, tree.path(parent)),
+				Candidates: tree.candidates(children),
+			}
+		}
+		picked := make([]string, len(matches))
+		for i, index := range matches {
+			picked[i] = children[index]
+		}
+		return "", &Error{
+			Reference:  ref,
+			Reason:     ReasonAmbiguous,
+			Message:    fmt.Sprintf("%q matches %d entries in %s; use one of their paths or IDs", segment, len(matches), tree.path(parent)),
+			Candidates: tree.candidates(picked),
+		}
+	}
+	return parent, nil
+}
+
+// itemByTitle finds the items titled like title in every workspace
+func (r *Resolver) itemByTitle(ctx context.Context, ref, title string) (string, error) {
+	workspaces, err := r.workspaces(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	type entry struct {
+		tree *tree
+		id   string
+	}
+	var entries []entry
+	var titles []string
+	for i := range workspaces {
+		tree, err := r.tree(ctx, &workspaces[i])
+		if err != nil {
+			return "", err
+		}
+		for _, id := range tree.order {
+			entries = append(entries, entry{tree, id})
+			titles = append(titles, tree.title(id))
+		}
+	}
+
+	matches := match(title, titles)
+	switch len(matches) {
+	case 1:
+		if !r.loose(title, titles[matches[0]]) {
+			return entries[matches[0]].id, nil
+		}
+	case 0:
+		return "", &Error{Reference: ref, Reason: ReasonNotFound, Message: fmt.Sprintf("no item or collection is titled like %q", title)}
+	}
+	var candidates []Candidate
+	for _, index := range matches {
+		candidates = append(candidates, entries[index].tree.candidates([]string{entries[index].id})...)
+	}
+	message := fmt.Sprintf("%q matches %d items; use one of their paths or IDs", title, len(matches))
+	if len(matches) == 1 {
+		message = fmt.Sprintf("%q is not an exact title; use the path or ID of the item meant", title)
+	}
+	return "", &Error{
+		Reference:  ref,
+		Reason:     ReasonAmbiguous,
+		Message:    message,
+		Candidates: limit(candidates),
+	}
+}
+
+// matchWorkspace picks the one workspace whose name matches name
+func (r *Resolver) matchWorkspace(ref, name string, workspaces []nuclino.Workspace) (*nuclino.Workspace, error) {
+	names := make([]string, len(workspaces))
+	for i, workspace := range workspaces {
+		names[i] = workspace.Name
+	}
+	matches := match(name, names)
+	if len(matches) == 1 && !r.loose(name, names[matches[0]]) {
+		return &workspaces[matches[0]], nil
+	}
+
+	err := &Error{Reference: ref, Reason: ReasonAmbiguous}
+	switch len(matches) {
+	case 0:
+		err.Reason = ReasonNotFound
+		err.Message = fmt.Sprintf("no workspace is named like %q", name)
+		for i := range workspaces {
+			matches = append(matches, i)
+		}
+	case 1:
+		err.Message = fmt.Sprintf("%q is not an exact workspace name; use the name or ID of the workspace meant", name)
+	default:
+		err.Message = fmt.Sprintf("%q matches %d workspaces; use an ID", name, len(matches))
+	}
+	for _, index := range matches {
+		err.Candidates = append(err.Candidates, Candidate{ID: workspaces[index].ID, Object: "workspace", Path: workspaces[index].Name})
+	}
+	err.Candidates = limit(err.Candidates)
+	return nil, err
+}
+
+// workspaces lists every workspace
+func (r *Resolver) workspaces(ctx context.Context) ([]nuclino.Workspace, error) {
+	if cached, ok := r.cache.Get("workspaces"); ok {
+		if workspaces, ok := cached.([]nuclino.Workspace); ok {
+			return workspaces, nil
+		}
+	}
+
+	workspaces, err := nuclino.ListAllWorkspaces(ctx, r.client, r.config.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	r.cache.Set("workspaces", workspaces)
+	return workspaces, nil
+}
+
+// tree is the item tree of a workspace
+type tree struct {
+	workspace *nuclino.Workspace
+	items     map[string]*nuclino.Item
+	order     []string
+	parents   map[string]string
+}
+
+// tree loads the item tree of a workspace
+func (r *Resolver) tree(ctx context.Context, workspace *nuclino.Workspace) (*tree, error) {
+	key := "tree:" + workspace.ID
+	if cached, ok := r.cache.Get(key); ok {
+		if tree, ok := cached.(*tree); ok {
+			return tree, nil
+		}
+	}
+
+	t := &tree{workspace: workspace, items: make(map[string]*nuclino.Item), parents: make(map[string]string)}
+	items, err := nuclino.ListAllItems(ctx, r.client, workspace.ID, r.config.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the items of workspace %s: %w", workspace.Name, err)
+	}
+	for i := range items {
+		item := &items[i]
+		t.items[item.ID] = item
+		t.order = append(t.order, item.ID)
+	}
+	for _, item := range t.items {
+		for _, child := range item.ChildIDs {
+			t.parents[child] = item.ID
+		}
+	}
+	r.cache.Set(key, t)
+	return t, nil
+}
+
+func (t *tree) title(id string) string {
+	if item, ok := t.items[id]; ok {
+		return item.Title
+	}
+	return ""
+}
+
+// children returns the entries under a collection
+func (t *tree) children(id string) []string {
+	if item, ok := t.items[id]; ok {
+		return item.ChildIDs
+	}
+	return nil
+}
+
+// path returns the workspace name and the titles leading to id, or the
+// workspace name for ""
+func (t *tree) path(id string) string {
+	var titles []string
+	for ; id != ""; id = t.parents[id] {
+		titles = append([]string{t.title(id)}, titles...)
+	}
+	return strings.Join(append([]string{t.workspace.Name}, titles...), " / ")
+}
+
+func (t *tree) candidates(ids []string) []Candidate {
+	candidates := make([]Candidate, 0, len(ids))
+	for _, id := range ids {
+		object := nuclino.ObjectItem
+		if item, ok := t.items[id]; ok && item.IsCollection() {
+			object = nuclino.ObjectCollection
+		}
+		candidates = append(candidates, Candidate{ID: id, Object: object, Path: t.path(id)})
+	}
+	return limit(candidates)
+}
+
+func limit(candidates []Candidate) []Candidate {
+	if len(candidates) > maxCandidates {
+		return candidates[:maxCandidates]
+	}
+	return candidates
+}
+
+// urlPath returns the path segments of a Nuclino app URL
+func urlPath(ref string) ([]string, bool) {
+	parsed, err := url.Parse(ref)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || !strings.HasSuffix(parsed.Host, "nuclino.com") {
+		return nil, false
+	}
+	var segments []string
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments, true
+}
+
+// urlItemID returns the item ID an item URL ends with, either bare
+// (".../t/b/<id>") or after a title slug (".../Some-Title-<id>")
+func urlItemID(ref string) string {
+	parsed, _ := url.Parse(ref)
+	if match := urlIDPattern.FindStringSubmatch(parsed.Path); match != nil {
+		return strings.ToLower(match[1])
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino/nuclinotest"
+)
+
+type countingClient struct {
+	nuclino.Client
+	lists int
+}
+
+func (c *countingClient) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*nuclino.ItemsResponse, error) {
+	c.lists++
+	return c.Client.ListItems(ctx, workspaceID, limit, offset)
+}
+
+func newDemo(t *testing.T) (*nuclinotest.Server, *countingClient) {
+	fake := nuclinotest.NewServer()
+	t.Cleanup(fake.Close)
+	fake.SeedDemo()
+	client := &countingClient{Client: nuclino.NewClientFromConfig(nuclino.ClientConfig{APIKey: nuclinotest.DemoAPIKey, BaseURL: fake.URL})}
+	return fake, client
+}
+
+func TestResolver_Workspace(t *testing.T) {
+	_, client := newDemo(t)
+	resolver := NewResolver(client, DefaultConfig())
+	ctx := context.Background()
+
+	engineering, err := resolver.Workspace(ctx, "Engineering")
+	require.NoError(t, err)
+	assert.True(t, IsID(engineering))
+
+	for _, ref := range []string{"engineering", "Enginering", "https://app.nuclino.com/Acme/Engineering", engineering} {
+		id, err := resolver.Workspace(ctx, ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, engineering, id, ref)
+	}
+
+	_, err = resolver.Workspace(ctx, "Marketing")
+	var resolveErr *Error
+	require.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, ReasonNotFound, resolveErr.Reason)
+	assert.Len(t, resolveErr.Candidates, 2, "every workspace is a candidate")
+}
+
+func TestResolver_Item(t *testing.T) {
+	fake, client := newDemo(t)
+	resolver := NewResolver(client, DefaultConfig())
+	ctx := context.Background()
+
+	onboarding, err := resolver.Item(ctx, "Engineering / Handbook / Onboarding")
+	require.NoError(t, err)
+	item, err := client.GetItem(ctx, onboarding)
+	require.NoError(t, err)
+	assert.Equal(t, "Onboarding", item.Title)
+
+	for _, ref := range []string{"engineering/handbook/onboard", "Onboarding", item.URL, "https://app.nuclino.com/Acme/Engineering/Onboarding-" + onboarding} {
+		id, err := resolver.Item(ctx, ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, onboarding, id, ref)
+	}
+	assert.Equal(t, 2, client.lists, "item trees are cached")
+
+	incidents, err := resolver.Item(ctx, "Engineering / Runbooks / Incidents")
+	require.NoError(t, err)
+	incident, err := client.GetItem(ctx, incidents)
+	require.NoError(t, err)
+	assert.True(t, incident.IsCollection())
+
+	// A second "Onboarding" makes the title ambiguous until the cache is
+	// invalidated
+	product, err := resolver.Workspace(ctx, "Product")
+	require.NoError(t, err)
+	fake.AddItem(product, "Onboarding survey", "")
+	fake.AddItem(product, "Onboarding", "")
+	_, err = resolver.Item(ctx, "Onboarding")
+	require.NoError(t, err)
+	resolver.Invalidate()
+
+	_, err = resolver.Item(ctx, "Onboarding")
+	var resolveErr *Error
+	require.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, ReasonAmbiguous, resolveErr.Reason)
+	var paths []string
+	for _, candidate := range resolveErr.Candidates {
+		paths = append(paths, candidate.Path)
+	}
+	assert.ElementsMatch(t, []string{"Engineering / Handbook / Onboarding", "Product / Onboarding"}, paths)
+
+	_, err = resolver.Item(ctx, "Engineering / Handbook / Offboarding")
+	require.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, ReasonNotFound, resolveErr.Reason)
+	assert.Contains(t, resolveErr.Message, "in Engineering / Handbook")
+	assert.Len(t, resolveErr.Candidates, 3, "the collection's entries are candidates")
+
+	// A title with a slash is not a path
+	engineering, err := resolver.Workspace(ctx, "Engineering")
+	require.NoError(t, err)
+	pipeline := fake.AddItem(engineering, "CI/CD Pipeline", "")
+	resolver.Invalidate()
+	id, err := resolver.Item(ctx, "CI/CD Pipeline")
+	require.NoError(t, err)
+	assert.Equal(t, pipeline, id)
+}
+
+func TestResolver_Exact(t *testing.T) {
+	_, client := newDemo(t)
+	resolver := NewResolver(client, DefaultConfig())
+	exact := resolver.Exact()
+	ctx := context.Background()
+
+	onboarding, err := resolver.Item(ctx, "Engineering / Handbook / Onboarding")
+	require.NoError(t, err)
+	id, err := exact.Item(ctx, "engineering / handbook / ONBOARDING")
+	require.NoError(t, err, "case and punctuation still do not matter")
+	assert.Equal(t, onboarding, id)
+
+	// A fuzzy match is offered as the one candidate instead of taken
+	var resolveErr *Error
+	for _, ref := range []string{"Engineering / Handbook / Onboard", "Onboardin"} {
+		_, err = exact.Item(ctx, ref)
+		require.True(t, errors.As(err, &resolveErr), ref)
+		assert.Equal(t, ReasonAmbiguous, resolveErr.Reason, ref)
+		require.Len(t, resolveErr.Candidates, 1, ref)
+		assert.Equal(t, onboarding, resolveErr.Candidates[0].ID, ref)
+	}
+
+	_, err = exact.Workspace(ctx, "Enginering")
+	require.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, ReasonAmbiguous, resolveErr.Reason)
+	require.Len(t, resolveErr.Candidates, 1)
+	assert.Equal(t, "Engineering", resolveErr.Candidates[0].Path)
+	assert.Equal(t, 2, client.lists, "the exact resolver shares the cache")
+}
+
+func TestScore(t *testing.T) {
+	assert.Equal(t, 4, score("on-boarding", "On Boarding"))
+	assert.Equal(t, 3, score("onboard", "Onboarding"))
+	assert.Equal(t, 2, score("review guide", "Code review guidelines"))
+	assert.Equal(t, 1, score("Enginering", "Engineering"))
+	assert.Equal(t, 0, score("Product", "Engineering"))
+
+	assert.Equal(t, []int{1}, match("Q1", []string{"Q1 goals", "Q1"}), "the best score wins")
+	assert.Equal(t, []int{0, 1}, match("Q", []string{"Q1 goals", "Q2 goals"}))
+
+	assert.True(t, IsID("00000000-0000-4000-8000-000000000001"))
+	assert.False(t, IsID("Engineering"))
+	assert.False(t, IsID("engineering"))
+	assert.False(t, IsID("release-notes"), "hyphenated titles are references")
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Job describes one recurring page
+type Job struct {
+	Name string `yaml:"name" json:"name"`
+	// Schedule is a five-field cron expression or a descriptor such as
+	// @weekly
+	Schedule string `yaml:"schedule" json:"schedule"`
+	// Timezone is an IANA zone name; the local zone is used when empty
+	Timezone    string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	WorkspaceID string `yaml:"workspace_id,omitempty" json:"workspace_id,omitempty"`
+	ParentID    string `yaml:"parent_id,omitempty" json:"parent_id,omitempty"`
+	// Title is a text/template for the page title, e.g. "Standup {{.Date}}".
+	// Pages are only created when no sibling has the rendered title.
+	Title string `yaml:"title" json:"title"`
+	// Template names a template from the template library; Content is an
+	// inline template used when Template is empty
+	Template string                 `yaml:"template,omitempty" json:"template,omitempty"`
+	Content  string                 `yaml:"content,omitempty" json:"content,omitempty"`
+	Values   map[string]interface{} `yaml:"values,omitempty" json:"values,omitempty"`
+	// Index is the title of an item under the same parent that links every
+	// page of the job; empty disables the index
+	Index string `yaml:"index,omitempty" json:"index,omitempty"`
+}
+
+// Config is the scheduler configuration file
+type Config struct {
+	Jobs []Job `yaml:"jobs" json:"jobs"`
+}
+
+// LoadConfig reads and validates a YAML scheduler configuration
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule config: %w", err)
+	}
+
+	// Unknown keys are rejected so a misspelled field such as "parent" does
+	// not silently create pages in the workspace root
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse schedule config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schedule config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Validate checks every job
+func (c *Config) Validate() error {
+	seen := make(map[string]bool)
+	for i, job := range c.Jobs {
+		if job.Name == "" {
+			return fmt.Errorf("job %d: name is required", i+1)
+		}
+		if seen[job.Name] {
+			return fmt.Errorf("job %q is defined twice", job.Name)
+		}
+		seen[job.Name] = true
+
+		if _, err := job.schedule(); err != nil {
+			return fmt.Errorf("job %q: %w", job.Name, err)
+		}
+		if job.WorkspaceID == "" && job.ParentID == "" {
+			return fmt.Errorf("job %q: workspace_id or parent_id is required", job.Name)
+		}
+		if job.Title == "" {
+			return fmt.Errorf("job %q: title is required", job.Name)
+		}
+	}
+	return nil
+}
+
+// schedule parses the job's cron expression in its time zone
+func (j Job) schedule() (cron.Schedule, error) {
+	spec := j.Schedule
+	if j.Timezone != "" {
+		if _, err := time.LoadLocation(j.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+		spec = "CRON_TZ=" + j.Timezone + " " + spec
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", j.Schedule, err)
+	}
+	return schedule, nil
+}
+
+// lookbacks are the windows searched for the latest activation, from short
+// to long so frequent schedules stay cheap
+var lookbacks = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	8 * 24 * time.Hour,
+	32 * 24 * time.Hour,
+	367 * 24 * time.Hour,
+}
+
+// previous returns the latest activation of schedule at or before now
+func previous(schedule cron.Schedule, now time.Time) (time.Time, bool) {
+	for _, window := range lookbacks {
+		var last time.Time
+		for t := schedule.Next(now.Add(-window)); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+			last = t
+		}
+		if !last.IsZero() {
+			return last, true
+		}
+	}
+	return time.Time{}, false
+}
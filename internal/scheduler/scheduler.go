@@ -0,0 +1,323 @@
+// Package scheduler creates recurring pages, such as weekly standup notes,
+// from cron schedules. Runs are idempotent: a page is only created when no
+// sibling already has its title, so the scheduler can run inside the server
+// or from an external cron job.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/templates"
+)
+
+// Outcome actions
+const (
+	ActionCreated = "created"
+	ActionExists  = "exists"
+	ActionPlanned = "planned"
+	ActionFailed  = "failed"
+)
+
+// Outcome reports what a job did for one activation
+type Outcome struct {
+	Job          string    `json:"job"`
+	Activation   time.Time `json:"activation"`
+	Title        string    `json:"title,omitempty"`
+	Action       string    `json:"action"`
+	ItemID       string    `json:"item_id,omitempty"`
+	IndexID      string    `json:"index_id,omitempty"`
+	IndexUpdated bool      `json:"index_updated,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Scheduler runs the jobs of a configuration
+type Scheduler struct {
+	client  nuclino.Client
+	library *templates.Library
+	config  *Config
+	// DryRun renders pages without creating or updating anything
+	DryRun bool
+}
+
+// New creates a scheduler
+func New(client nuclino.Client, library *templates.Library, config *Config) *Scheduler {
+	return &Scheduler{client: client, library: library, config: config}
+}
+
+// RunOnce runs the latest activation at or before now of every job, or of the
+// named jobs only. Pages that already exist are left alone, so calling it
+// repeatedly (for example hourly from cron) is safe.
+func (s *Scheduler) RunOnce(ctx context.Context, now time.Time, names ...string) ([]Outcome, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var outcomes []Outcome
+	for _, job := range s.config.Jobs {
+		if len(wanted) > 0 && !wanted[job.Name] {
+			continue
+		}
+		delete(wanted, job.Name)
+
+		schedule, err := job.schedule()
+		if err != nil {
+			return nil, err
+		}
+		activation, ok := previous(schedule, now)
+		if !ok {
+			continue
+		}
+		outcomes = append(outcomes, s.RunJob(ctx, job, activation))
+	}
+
+	for name := range wanted {
+		return outcomes, fmt.Errorf("unknown job %q", name)
+	}
+	return outcomes, nil
+}
+
+// Start runs every job on its schedule until ctx is cancelled. Missed
+// activations since the last period are caught up first. An activation is
+// skipped while the previous run of the same job is still going, since both
+// would find no page yet and create it twice.
+func (s *Scheduler) Start(ctx context.Context) error {
+	runner := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger{})))
+	for _, job := range s.config.Jobs {
+		job := job
+		schedule, err := job.schedule()
+		if err != nil {
+			return err
+		}
+		runner.Schedule(schedule, cron.FuncJob(func() {
+			activation, _ := previous(schedule, time.Now())
+			s.log(s.RunJob(ctx, job, activation))
+		}))
+	}
+
+	outcomes, err := s.RunOnce(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, outcome := range outcomes {
+		s.log(outcome)
+	}
+
+	runner.Start()
+	log.Info().Int("jobs", len(s.config.Jobs)).Msg("Page scheduler started")
+	go func() {
+		<-ctx.Done()
+		<-runner.Stop().Done()
+		log.Info().Msg("Page scheduler stopped")
+	}()
+	return nil
+}
+
+// cronLogger sends the cron runner's messages, such as skipped
+// activations, to the server log
+type cronLogger struct{}
+
+func (cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	log.Info().Fields(keysAndValues).Msg("Page scheduler: " + msg)
+}
+
+func (cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	log.Error().Err(err).Fields(keysAndValues).Msg("Page scheduler: " + msg)
+}
+
+func (s *Scheduler) log(outcome Outcome) {
+	event := log.Info()
+	if outcome.Error != "" {
+		event = log.Error().Str("error", outcome.Error)
+	}
+	event.Str("job", outcome.Job).Str("title", outcome.Title).Str("action", outcome.Action).Msg("Scheduled page run")
+}
+
+// RunJob creates the page of one activation unless it already exists and
+// refreshes the job's index item
+func (s *Scheduler) RunJob(ctx context.Context, job Job, activation time.Time) Outcome {
+	outcome := Outcome{Job: job.Name, Activation: activation}
+	fail := func(err error) Outcome {
+		outcome.Action = ActionFailed
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	workspaceID, siblingIDs, err := s.parent(ctx, job)
+	if err != nil {
+		return fail(err)
+	}
+
+	tmpl, err := s.template(ctx, job, workspaceID)
+	if err != nil {
+		return fail(err)
+	}
+	rendered, err := tmpl.Render(job.Title, job.Values, s.defaults(ctx, activation))
+	if err != nil {
+		return fail(err)
+	}
+	outcome.Title = rendered.Title
+
+	siblings, err := s.items(ctx, siblingIDs)
+	if err != nil {
+		return fail(err)
+	}
+
+	var page *nuclino.Item
+	for _, sibling := range siblings {
+		if !sibling.IsCollection() && sibling.Title == rendered.Title {
+			page = sibling
+			break
+		}
+	}
+
+	switch {
+	case page != nil:
+		outcome.Action = ActionExists
+		outcome.ItemID = page.ID
+	case s.DryRun:
+		outcome.Action = ActionPlanned
+	default:
+		page, err = s.client.CreateItem(ctx, &nuclino.CreateItemRequest{
+			Title:       rendered.Title,
+			Content:     rendered.Content,
+			WorkspaceID: workspaceID,
+			ParentID:    job.ParentID,
+		})
+		if err != nil {
+			return fail(fmt.Errorf("failed to create page: %w", err))
+		}
+		outcome.Action = ActionCreated
+		outcome.ItemID = page.ID
+		siblings = append(siblings, page)
+	}
+
+	if job.Index != "" && !s.DryRun {
+		index, updated, err := s.updateIndex(ctx, job, workspaceID, siblings)
+		if err != nil {
+			return fail(fmt.Errorf("failed to update index: %w", err))
+		}
+		outcome.IndexID = index.ID
+		outcome.IndexUpdated = updated
+	}
+	return outcome
+}
+
+// parent returns the workspace of a job and the IDs of its page's siblings
+func (s *Scheduler) parent(ctx context.Context, job Job) (string, []string, error) {
+	if job.ParentID != "" {
+		parent, err := s.client.GetItem(ctx, job.ParentID)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get parent %s: %w", job.ParentID, err)
+		}
+		return parent.WorkspaceID, parent.ChildIDs, nil
+	}
+
+	workspace, err := s.client.GetWorkspace(ctx, job.WorkspaceID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get workspace %s: %w", job.WorkspaceID, err)
+	}
+	return workspace.ID, workspace.ChildIDs, nil
+}
+
+// template returns the job's library template or its inline content
+func (s *Scheduler) template(ctx context.Context, job Job, workspaceID string) (*templates.Template, error) {
+	if job.Template != "" {
+		return s.library.Find(ctx, workspaceID, job.Template)
+	}
+	return templates.Parse(job.Name, "", job.Content)
+}
+
+// defaults are the template defaults with dates taken from the activation
+func (s *Scheduler) defaults(ctx context.Context, activation time.Time) map[string]interface{} {
+	defaults := s.library.Defaults(ctx, "")
+	_, week := activation.ISOWeek()
+	defaults["Date"] = activation.Format("2006-01-02")
+	defaults["Time"] = activation.Format("15:04")
+	defaults["DateTime"] = activation.Format(time.RFC3339)
+	defaults["Year"] = activation.Year()
+	defaults["Month"] = activation.Format("January")
+	defaults["Weekday"] = activation.Weekday().String()
+	defaults["Week"] = week
+	return defaults
+}
+
+func (s *Scheduler) items(ctx context.Context, ids []string) ([]*nuclino.Item, error) {
+	items := make([]*nuclino.Item, 0, len(ids))
+	for _, id := range ids {
+		item, err := s.client.GetItem(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item %s: %w", id, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// updateIndex writes the list of the job's pages, newest first, into the
+// index item, creating it when missing
+func (s *Scheduler) updateIndex(ctx context.Context, job Job, workspaceID string, siblings []*nuclino.Item) (*nuclino.Item, bool, error) {
+	pattern := titlePattern(job.Title)
+
+	var index *nuclino.Item
+	var pages []*nuclino.Item
+	for _, sibling := range siblings {
+		switch {
+		case sibling.IsCollection():
+		case sibling.Title == job.Index:
+			index = sibling
+		case pattern.MatchString(sibling.Title):
+			pages = append(pages, sibling)
+		}
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		if !pages[i].CreatedAt.Equal(pages[j].CreatedAt) {
+			return pages[i].CreatedAt.After(pages[j].CreatedAt)
+		}
+		return pages[i].Title > pages[j].Title
+	})
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "Pages created by the %q schedule, newest first.\n\n", job.Name)
+	for _, page := range pages {
+		fmt.Fprintf(&content, "- [%s](%s)\n", page.Title, page.URL)
+	}
+
+	if index == nil {
+		created, err := s.client.CreateItem(ctx, &nuclino.CreateItemRequest{
+			Title:       job.Index,
+			Content:     content.String(),
+			WorkspaceID: workspaceID,
+			ParentID:    job.ParentID,
+		})
+		return created, err == nil, err
+	}
+	if index.Content == content.String() {
+		return index, false, nil
+	}
+
+	text := content.String()
+	updated, err := s.client.UpdateItem(ctx, index.ID, &nuclino.UpdateItemRequest{Content: &text})
+	return updated, err == nil, err
+}
+
+var actionPattern = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// titlePattern turns a title template into a regexp matching its output:
+// literal text must match and each action matches any text
+func titlePattern(title string) *regexp.Regexp {
+	literals := actionPattern.Split(title, -1)
+	for i, literal := range literals {
+		literals[i] = regexp.QuoteMeta(literal)
+	}
+	return regexp.MustCompile("^" + strings.Join(literals, ".+") + "$")
+}
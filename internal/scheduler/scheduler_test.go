@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/templates"
+)
+
+// fakeClient keeps a parent collection in memory; methods not used here
+// panic through the embedded nil interface
+type fakeClient struct {
+	nuclino.Client
+	items   map[string]*nuclino.Item
+	created int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{items: map[string]*nuclino.Item{
+		"standups": {ID: "standups", Object: nuclino.ObjectCollection, WorkspaceID: "ws", Title: "Standups"},
+	}}
+}
+
+func (f *fakeClient) GetItem(ctx context.Context, itemID string) (*nuclino.Item, error) {
+	if item, ok := f.items[itemID]; ok {
+		return item, nil
+	}
+	return nil, nuclino.NewAPIError(404, "not found")
+}
+
+func (f *fakeClient) CreateItem(ctx context.Context, req *nuclino.CreateItemRequest) (*nuclino.Item, error) {
+	f.created++
+	id := fmt.Sprintf("item-%d", f.created)
+	item := &nuclino.Item{
+		ID: id, Title: req.Title, Content: req.Content, WorkspaceID: req.WorkspaceID,
+		URL: "https://app.nuclino.com/t/b/" + id, CreatedAt: time.Unix(int64(f.created), 0),
+	}
+	f.items[id] = item
+	parent := f.items[req.ParentID]
+	parent.ChildIDs = append(parent.ChildIDs, id)
+	return item, nil
+}
+
+func (f *fakeClient) UpdateItem(ctx context.Context, itemID string, req *nuclino.UpdateItemRequest) (*nuclino.Item, error) {
+	item := f.items[itemID]
+	item.Content = *req.Content
+	return item, nil
+}
+
+func standupConfig() *Config {
+	return &Config{Jobs: []Job{{
+		Name:     "standup",
+		Schedule: "0 9 * * MON",
+		ParentID: "standups",
+		Title:    "Standup {{.Date}}",
+		Content:  "Week {{.Week}} of {{.Year}}\n\n## Updates\n",
+		Index:    "Standup index",
+	}}}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`jobs:
+  - name: monthly-report
+    schedule: "@monthly"
+    timezone: Europe/Warsaw
+    workspace_id: ws
+    title: "Report {{.Month}} {{.Year}}"
+    template: Monthly report
+    values:
+      Team: Platform
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Len(t, config.Jobs, 1)
+	assert.Equal(t, "Platform", config.Jobs[0].Values["Team"])
+
+	require.NoError(t, os.WriteFile(path, []byte(`jobs:
+  - name: standup
+    schedule: "@weekly"
+    parent: standups
+    title: Standup
+`), 0o600))
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, "field parent not found")
+
+	invalid := &Config{Jobs: []Job{{Name: "x", Schedule: "every day", WorkspaceID: "ws", Title: "x"}}}
+	assert.ErrorContains(t, invalid.Validate(), "invalid schedule")
+	invalid = &Config{Jobs: []Job{{Name: "x", Schedule: "@daily", Title: "x"}}}
+	assert.ErrorContains(t, invalid.Validate(), "workspace_id or parent_id")
+}
+
+func TestPrevious(t *testing.T) {
+	schedule, err := Job{Schedule: "0 9 * * MON"}.schedule()
+	require.NoError(t, err)
+
+	// Wednesday 2024-05-15 goes back to Monday 2024-05-13 09:00
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	activation, ok := previous(schedule, now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 5, 13, 9, 0, 0, 0, time.Local), activation)
+
+	yearly, err := Job{Schedule: "@yearly"}.schedule()
+	require.NoError(t, err)
+	activation, ok = previous(yearly, now)
+	require.True(t, ok)
+	assert.Equal(t, 2024, activation.Year())
+}
+
+func TestRunOnce_IdempotentWithIndex(t *testing.T) {
+	client := newFakeClient()
+	scheduler := New(client, templates.NewLibrary(client, templates.Config{}), standupConfig())
+	monday := time.Date(2024, 5, 13, 10, 0, 0, 0, time.Local)
+
+	outcomes, err := scheduler.RunOnce(context.Background(), monday)
+	require.NoError(t, err)
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, ActionCreated, outcomes[0].Action, outcomes[0].Error)
+	assert.Equal(t, "Standup 2024-05-13", outcomes[0].Title)
+	assert.Equal(t, "Week 20 of 2024\n\n## Updates\n", client.items[outcomes[0].ItemID].Content)
+	assert.True(t, outcomes[0].IndexUpdated)
+
+	// Running again in the same week changes nothing
+	outcomes, err = scheduler.RunOnce(context.Background(), monday.Add(48*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, ActionExists, outcomes[0].Action)
+	assert.False(t, outcomes[0].IndexUpdated)
+	assert.Equal(t, 2, client.created)
+
+	// Next week adds a page and lists both, newest first
+	outcomes, err = scheduler.RunOnce(context.Background(), monday.Add(7*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, ActionCreated, outcomes[0].Action)
+	index := client.items[outcomes[0].IndexID]
+	assert.Contains(t, index.Content, "- [Standup 2024-05-20](https://app.nuclino.com/t/b/item-3)\n- [Standup 2024-05-13](https://app.nuclino.com/t/b/item-1)\n")
+
+	_, err = scheduler.RunOnce(context.Background(), monday, "missing")
+	assert.Error(t, err)
+}
+
+func TestRunOnce_DryRun(t *testing.T) {
+	client := newFakeClient()
+	scheduler := New(client, templates.NewLibrary(client, templates.Config{}), standupConfig())
+	scheduler.DryRun = true
+
+	outcomes, err := scheduler.RunOnce(context.Background(), time.Date(2024, 5, 13, 10, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	assert.Equal(t, ActionPlanned, outcomes[0].Action)
+	assert.Equal(t, 0, client.created)
+}
@@ -2,11 +2,14 @@ package server
 
 import (
 	"context"
+	"os"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/rs/zerolog/log"
 
+	"github.com/lukasz/nuclino-mcp-server/internal/monitoring"
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
 	"github.com/lukasz/nuclino-mcp-server/internal/tools"
 )
@@ -15,78 +18,154 @@ type NuclinoMCPServer struct {
 	nuclinoClient nuclino.Client
 	toolRegistry  *tools.Registry
 	mcpServer     server.MCPServer
+	metrics       *monitoring.MetricsCollector
 }
 
+// capabilityProbeTimeout bounds the API probing done at startup
+const capabilityProbeTimeout = 15 * time.Second
+
 func NewNuclinoMCPServer(nuclinoClient nuclino.Client) *NuclinoMCPServer {
+	options := tools.DefaultOptions()
+	options.Capabilities.MoveItems = false
+	return NewNuclinoMCPServerWithOptions(nuclinoClient, options)
+}
+
+// NewNuclinoMCPServerWithOptions creates a server whose tools are limited by
+// options, such as the toolsets and read-only mode of a config profile. The
+// capabilities in options are replaced by probing the API, except MoveItems,
+// which is kept as configured.
+func NewNuclinoMCPServerWithOptions(nuclinoClient nuclino.Client, options tools.Options) *NuclinoMCPServer {
+	options.Capabilities = probeCapabilities(nuclinoClient, options.Capabilities.MoveItems)
+	return newServer(nuclinoClient, tools.NewRegistryWithOptions(nuclinoClient, options))
+}
+
+// NewNuclinoMCPServerWithAccounts creates a server for several Nuclino
+// accounts; the first is the default. Capabilities are probed per account,
+// except MoveItems, which is kept as configured.
+func NewNuclinoMCPServerWithAccounts(accounts []tools.Account) (*NuclinoMCPServer, error) {
+	for i := range accounts {
+		accounts[i].Options.Capabilities = probeCapabilities(accounts[i].Client, accounts[i].Options.Capabilities.MoveItems)
+	}
+	registry, err := tools.NewMultiAccountRegistry(accounts)
+	if err != nil {
+		return nil, err
+	}
+	return newServer(accounts[0].Client, registry), nil
+}
+
+// probeCapabilities checks optional API features so only working tools are
+// registered
+func probeCapabilities(nuclinoClient nuclino.Client, moveItems bool) nuclino.Capabilities {
+	ctx, cancel := context.WithTimeout(context.Background(), capabilityProbeTimeout)
+	defer cancel()
+	return nuclino.ProbeCapabilities(ctx, nuclinoClient, moveItems)
+}
+
+func newServer(nuclinoClient nuclino.Client, registry *tools.Registry) *NuclinoMCPServer {
 	s := &NuclinoMCPServer{
 		nuclinoClient: nuclinoClient,
-		toolRegistry:  tools.NewRegistry(nuclinoClient),
+		toolRegistry:  registry,
 	}
 
 	// Create MCP server
-	s.mcpServer = server.NewDefaultServer("nuclino-mcp-server", "0.1.0")
+	defaultServer := server.NewDefaultServer("nuclino-mcp-server", "0.1.0").(*server.DefaultServer)
+	s.mcpServer = &toolServer{DefaultServer: defaultServer, registry: registry}
 
 	// Set up handlers
-	s.setupHandlers()
+	s.setupHandlers(defaultServer)
 
 	return s
 }
 
-func (s *NuclinoMCPServer) setupHandlers() {
-	// Cast to DefaultServer to access handler methods
-	if defaultServer, ok := s.mcpServer.(*server.DefaultServer); ok {
-		// Set initialize handler to advertise capabilities
-		defaultServer.HandleInitialize(func(ctx context.Context, capabilities mcp.ClientCapabilities, clientInfo mcp.Implementation, protocolVersion string) (*mcp.InitializeResult, error) {
-			return &mcp.InitializeResult{
-				ProtocolVersion: protocolVersion,
-				Capabilities: mcp.ServerCapabilities{
-					Tools: &mcp.ServerCapabilitiesTools{},
-				},
-				ServerInfo: mcp.Implementation{
-					Name:    "nuclino-mcp-server",
-					Version: "0.1.0",
-				},
-			}, nil
-		})
-
-		// Set tools handler
-		defaultServer.HandleCallTool(func(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			log.Info().Str("tool", name).Msg("Calling tool")
-
-			result, err := s.toolRegistry.CallTool(name, arguments)
-			if err != nil {
-				log.Error().Err(err).Str("tool", name).Msg("Tool call failed")
-				return &mcp.CallToolResult{
-					Content: []interface{}{
-						mcp.TextContent{
-							Type: "text",
-							Text: err.Error(),
-						},
+func (s *NuclinoMCPServer) setupHandlers(defaultServer *server.DefaultServer) {
+	// Set initialize handler to advertise capabilities
+	defaultServer.HandleInitialize(func(ctx context.Context, capabilities mcp.ClientCapabilities, clientInfo mcp.Implementation, protocolVersion string) (*mcp.InitializeResult, error) {
+		return &mcp.InitializeResult{
+			ProtocolVersion: protocolVersion,
+			Capabilities: mcp.ServerCapabilities{
+				Tools: &mcp.ServerCapabilitiesTools{},
+			},
+			ServerInfo: mcp.Implementation{
+				Name:    "nuclino-mcp-server",
+				Version: "0.1.0",
+			},
+		}, nil
+	})
+
+	// Set tools handler
+	defaultServer.HandleCallTool(func(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		log.Info().Str("tool", name).Msg("Calling tool")
+
+		result, err := s.toolRegistry.CallToolContext(ctx, name, arguments)
+		if err != nil {
+			log.Error().Err(err).Str("tool", name).Msg("Tool call failed")
+			return &mcp.CallToolResult{
+				Content: []interface{}{
+					mcp.TextContent{
+						Type: "text",
+						Text: err.Error(),
 					},
-					IsError: true,
-				}, nil
-			}
-
-			return result, nil
-		})
-
-		// Set tools list handler
-		defaultServer.HandleListTools(func(ctx context.Context, cursor *string) (*mcp.ListToolsResult, error) {
-			toolsList := s.toolRegistry.ListTools()
-			return &mcp.ListToolsResult{
-				Tools: toolsList,
+				},
+				IsError: true,
 			}, nil
-		})
+		}
 
-		// Handle initialized notification - this should not have a response
-		defaultServer.HandleNotification("notifications/initialized", func(ctx context.Context, params any) (any, error) {
-			log.Debug().Msg("Received initialized notification")
-			return nil, nil
-		})
-	}
+		return result, nil
+	})
+
+	// Handle initialized notification - this should not have a response
+	defaultServer.HandleNotification("notifications/initialized", func(ctx context.Context, params any) (any, error) {
+		log.Debug().Msg("Received initialized notification")
+		return nil, nil
+	})
+}
+
+// SetMetrics counts the server's connections in metrics
+func (s *NuclinoMCPServer) SetMetrics(metrics *monitoring.MetricsCollector) {
+	s.metrics = metrics
 }
 
+// Run serves MCP over stdin and stdout until the input ends or ctx is
+// cancelled
 func (s *NuclinoMCPServer) Run(ctx context.Context) error {
 	log.Info().Msg("Starting Nuclino MCP server")
-	return server.ServeStdio(s.mcpServer)
+	if s.metrics != nil {
+		s.metrics.IncrementActiveConnections()
+		defer s.metrics.DecrementActiveConnections()
+	}
+	return newStdioTransport(s.mcpServer, os.Stdin, os.Stdout).serve(ctx)
+}
+
+// toolServer answers tools/list with the registry's full definitions and
+// moves structured content into tools/call results, since mcp-go v0.4.0's
+// types predate output schemas and structuredContent. Every other method
+// goes to the DefaultServer.
+type toolServer struct {
+	*server.DefaultServer
+	registry *tools.Registry
+}
+
+// listToolsResult is mcp.ListToolsResult with tools.ToolDefinition entries
+type listToolsResult struct {
+	Tools []tools.ToolDefinition `json:"tools"`
+}
+
+func (s *toolServer) Request(ctx context.Context, request server.JSONRPCRequest) server.JSONRPCResponse {
+	if request.Method == "tools/list" {
+		definitions := s.registry.Definitions()
+		if definitions == nil {
+			definitions = []tools.ToolDefinition{}
+		}
+		return server.JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Result:  listToolsResult{Tools: definitions},
+		}
+	}
+
+	response := s.DefaultServer.Request(ctx, request)
+	if result, ok := response.Result.(*mcp.CallToolResult); ok && request.Method == "tools/call" {
+		response.Result = tools.WireResult(result)
+	}
+	return response
 }
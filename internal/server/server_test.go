@@ -0,0 +1,48 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino/nuclinotest"
+	"github.com/lukasz/nuclino-mcp-server/internal/tools"
+)
+
+func TestToolServer_StructuredOutput(t *testing.T) {
+	fake := nuclinotest.NewServer()
+	defer fake.Close()
+	fake.SeedDemo()
+	client := nuclino.NewClientFromConfig(nuclino.ClientConfig{APIKey: nuclinotest.DemoAPIKey, BaseURL: fake.URL})
+	s := newServer(client, tools.NewRegistry(client))
+	pipe := startTransport(t, s.mcpServer)
+
+	pipe.send(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	listed := pipe.receive()["result"].(map[string]interface{})["tools"].([]interface{})
+	var getItem map[string]interface{}
+	for _, tool := range listed {
+		if definition := tool.(map[string]interface{}); definition["name"] == "nuclino_get_item" {
+			getItem = definition
+		}
+	}
+	require.NotNil(t, getItem, "nuclino_get_item is listed")
+	input := getItem["inputSchema"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"item_id"}, input["required"])
+	assert.Equal(t, false, input["additionalProperties"])
+	output := getItem["outputSchema"].(map[string]interface{})
+	assert.Contains(t, output["properties"], "title")
+
+	pipe.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nuclino_list_workspaces","arguments":{}}}`)
+	result := pipe.receive()["result"].(map[string]interface{})
+	structured := result["structuredContent"].(map[string]interface{})
+	workspaces := structured["results"].([]interface{})
+	require.Len(t, workspaces, 2)
+	assert.Equal(t, "Engineering", workspaces[0].(map[string]interface{})["name"])
+	assert.NotContains(t, result, "_meta", "structured content is moved out of _meta")
+
+	text := result["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+	assert.Contains(t, text, "2 of 2 workspaces")
+	assert.Contains(t, text, "- Engineering [")
+}
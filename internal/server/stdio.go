@@ -0,0 +1,485 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+	"github.com/lukasz/nuclino-mcp-server/internal/tools"
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
+)
+
+// elicitationTimeout bounds how long a confirmation prompt waits for the user
+const elicitationTimeout = 10 * time.Minute
+
+// maxMessageSize is the longest JSON-RPC line read from the client; longer
+// lines are discarded and answered with an error
+const maxMessageSize = 16 << 20
+
+// errMessageTooLarge is returned by readLine for a line over the size limit
+var errMessageTooLarge = errors.New("message too large")
+
+// leadingID finds the id of a request in the start of a discarded line when
+// it comes before any nested object, such as the params
+var leadingID = regexp.MustCompile(`^\s*\{[^{]*?"id"\s*:\s*("(?:[^"\\]|\\.)*"|-?\d+)`)
+
+// rpcMessage is any JSON-RPC message: a request or notification from the
+// client, or the client's response to one of our requests
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// stdioTransport serves MCP over newline-delimited JSON-RPC, like
+// server.ServeStdio, but can also send requests to the client, which
+// elicitation needs. Client requests are handled one at a time by a worker
+// while the reader keeps routing the client's responses to our requests and
+// answering pings; the request queue is unbounded so the reader never waits
+// for a worker that is itself waiting for a response.
+type stdioTransport struct {
+	server server.MCPServer
+	in     io.Reader
+	out    io.Writer
+	// maxMessage is the longest line read from the client
+	maxMessage int
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan rpcMessage
+	nextID  int64
+	closed  bool
+
+	// elicitation is set when the client declares the capability
+	elicitation atomic.Bool
+	// session identifies the connection in audit records; only the worker
+	// touches it
+	session *audit.Session
+}
+
+func newStdioTransport(mcpServer server.MCPServer, in io.Reader, out io.Writer) *stdioTransport {
+	return &stdioTransport{
+		server:     mcpServer,
+		in:         in,
+		out:        out,
+		maxMessage: maxMessageSize,
+		pending:    make(map[string]chan rpcMessage),
+		session:    &audit.Session{ID: newSessionID()},
+	}
+}
+
+// serve handles messages until the input ends or ctx is cancelled
+func (t *stdioTransport) serve(ctx context.Context) error {
+	requests := newRequestQueue()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			request, ok := requests.pop(ctx)
+			if !ok {
+				return
+			}
+			t.handle(ctx, request)
+		}
+	}()
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- t.read(ctx, requests)
+		requests.close()
+	}()
+
+	select {
+	case <-ctx.Done():
+		t.shutdown()
+		return nil
+	case err := <-readErr:
+		t.shutdown()
+		<-done
+		return err
+	}
+}
+
+// read parses lines from the client, routing responses to pending requests,
+// answering pings and queueing everything else for the worker
+func (t *stdioTransport) read(ctx context.Context, requests *requestQueue) error {
+	reader := bufio.NewReaderSize(t.in, 64*1024)
+	for {
+		line, err := readLine(reader, t.maxMessage)
+		if errors.Is(err, errMessageTooLarge) {
+			log.Error().Int("limit", t.maxMessage).Msg("JSON-RPC message over the size limit discarded")
+			t.writeError(discardedID(line), -32600,
+				fmt.Sprintf("Invalid Request: message larger than %d bytes", t.maxMessage))
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		t.dispatch(ctx, bytes.TrimSpace(line), requests)
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// readLine reads one line. A line longer than limit is read to its end and
+// dropped; its start is returned with errMessageTooLarge so the request can
+// still be answered.
+func readLine(reader *bufio.Reader, limit int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(line)+len(bytes.TrimRight(chunk, "\r\n")) > limit {
+			head := append(line, chunk...)
+			head = append([]byte(nil), head[:min(len(head), 4096)]...)
+			for err == bufio.ErrBufferFull {
+				_, err = reader.ReadSlice('\n')
+			}
+			return head, errMessageTooLarge
+		}
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+// discardedID returns the id of a request from the start of its line, or
+// nil when it does not come before the params
+func discardedID(head []byte) interface{} {
+	match := leadingID.FindSubmatch(head)
+	if match == nil {
+		return nil
+	}
+	var id interface{}
+	if json.Unmarshal(match[1], &id) != nil {
+		return nil
+	}
+	return id
+}
+
+// dispatch handles one line from the client
+func (t *stdioTransport) dispatch(ctx context.Context, line []byte, requests *requestQueue) {
+	if len(line) == 0 {
+		return
+	}
+
+	var message rpcMessage
+	if err := json.Unmarshal(line, &message); err != nil {
+		log.Error().Err(err).Msg("Failed to parse JSON-RPC message")
+		t.writeError(nil, -32700, "Parse error")
+		return
+	}
+
+	if message.Method == "" {
+		t.deliver(message)
+		return
+	}
+	request := server.JSONRPCRequest{
+		JSONRPC: message.JSONRPC,
+		Method:  message.Method,
+		Params:  message.Params,
+	}
+	if len(message.ID) > 0 {
+		if err := json.Unmarshal(message.ID, &request.ID); err != nil {
+			log.Error().Err(err).Msg("Invalid JSON-RPC request id")
+			return
+		}
+	}
+
+	if request.Method == "ping" && request.ID != nil {
+		// Answered at once, so a client checking liveness while a tool
+		// waits for the user gets a reply
+		if err := t.write(t.server.Request(ctx, request)); err != nil {
+			log.Error().Err(err).Msg("Failed to write response")
+		}
+		return
+	}
+	requests.push(request)
+}
+
+// requestQueue is an unbounded FIFO of client requests with one consumer
+type requestQueue struct {
+	mu       sync.Mutex
+	requests []server.JSONRPCRequest
+	closed   bool
+	// ready is signalled when a request is pushed or the queue closes
+	ready chan struct{}
+}
+
+func newRequestQueue() *requestQueue {
+	return &requestQueue{ready: make(chan struct{}, 1)}
+}
+
+func (q *requestQueue) push(request server.JSONRPCRequest) {
+	q.mu.Lock()
+	q.requests = append(q.requests, request)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// close lets pop return false once the queued requests are taken
+func (q *requestQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *requestQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pop waits for the next request; it returns false when the queue is closed
+// and empty or ctx is cancelled
+func (q *requestQueue) pop(ctx context.Context) (server.JSONRPCRequest, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.requests) > 0 {
+			request := q.requests[0]
+			q.requests[0] = server.JSONRPCRequest{}
+			q.requests = q.requests[1:]
+			q.mu.Unlock()
+			return request, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			return server.JSONRPCRequest{}, false
+		}
+
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return server.JSONRPCRequest{}, false
+		}
+	}
+}
+
+// handle runs one client request, answering unless it is a notification
+func (t *stdioTransport) handle(ctx context.Context, request server.JSONRPCRequest) {
+	if request.Method == "initialize" {
+		t.initialize(request.Params)
+	}
+	ctx = audit.WithSession(ctx, t.session)
+	if t.elicitation.Load() {
+		ctx = tools.WithElicitor(ctx, t)
+	}
+
+	ctx, span := tracing.Start(ctx, "mcp "+request.Method,
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", request.Method),
+		attribute.String("mcp.session.id", t.session.ID))
+	if request.ID != nil {
+		span.SetAttributes(attribute.String("rpc.jsonrpc.request_id", fmt.Sprint(request.ID)))
+	}
+	response := t.server.Request(ctx, request)
+	if response.Error != nil {
+		span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", response.Error.Code))
+		tracing.Fail(span, response.Error.Message)
+	}
+	span.End()
+
+	if request.ID == nil {
+		return
+	}
+	if err := t.write(response); err != nil {
+		log.Error().Err(err).Msg("Failed to write response")
+	}
+}
+
+func (t *stdioTransport) write(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = fmt.Fprintf(t.out, "%s\n", data)
+	return err
+}
+
+// writeError answers a request that could not be handled
+func (t *stdioTransport) writeError(id interface{}, code int, message string) {
+	err := t.write(server.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}{Code: code, Message: message},
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write response")
+	}
+}
+
+// deliver passes a client response to the request waiting for it
+func (t *stdioTransport) deliver(message rpcMessage) {
+	id := string(message.ID)
+	t.mu.Lock()
+	reply, ok := t.pending[id]
+	delete(t.pending, id)
+	t.mu.Unlock()
+
+	if !ok {
+		log.Warn().Str("id", id).Msg("Response to an unknown request")
+		return
+	}
+	reply <- message
+}
+
+// shutdown fails every pending request
+func (t *stdioTransport) shutdown() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for id, reply := range t.pending {
+		close(reply)
+		delete(t.pending, id)
+	}
+}
+
+// call sends a request to the client and waits for its result
+func (t *stdioTransport) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("connection closed")
+	}
+	t.nextID++
+	id := strconv.Quote("nuclino-" + strconv.FormatInt(t.nextID, 10))
+	reply := make(chan rpcMessage, 1)
+	t.pending[id] = reply
+	t.mu.Unlock()
+
+	forget := func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		forget()
+		return nil, err
+	}
+	if err := t.write(rpcMessage{JSONRPC: "2.0", ID: json.RawMessage(id), Method: method, Params: body}); err != nil {
+		forget()
+		return nil, err
+	}
+
+	select {
+	case message, ok := <-reply:
+		if !ok {
+			return nil, fmt.Errorf("connection closed")
+		}
+		if message.Error != nil {
+			return nil, fmt.Errorf("%s failed: %s (code %d)", method, message.Error.Message, message.Error.Code)
+		}
+		return message.Result, nil
+	case <-ctx.Done():
+		forget()
+		return nil, ctx.Err()
+	}
+}
+
+// Confirm asks the user with an elicitation/create request; it implements
+// tools.Elicitor
+func (t *stdioTransport) Confirm(ctx context.Context, message, action string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, elicitationTimeout)
+	defer cancel()
+
+	result, err := t.call(ctx, "elicitation/create", map[string]interface{}{
+		"message": message,
+		"requestedSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"confirm": map[string]interface{}{
+					"type":        "boolean",
+					"title":       "Confirm",
+					"description": action,
+				},
+			},
+			"required": []string{"confirm"},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var response struct {
+		Action  string                 `json:"action"`
+		Content map[string]interface{} `json:"content"`
+	}
+	if err := json.Unmarshal(result, &response); err != nil {
+		return false, fmt.Errorf("invalid elicitation result: %w", err)
+	}
+	if response.Action != "accept" {
+		return false, nil
+	}
+	confirmed, _ := response.Content["confirm"].(bool)
+	return confirmed, nil
+}
+
+// initialize reads the client's name and capabilities.elicitation from
+// initialize params; mcp.ClientCapabilities predates the field
+func (t *stdioTransport) initialize(params json.RawMessage) {
+	var initialize struct {
+		Capabilities struct {
+			Elicitation *json.RawMessage `json:"elicitation"`
+		} `json:"capabilities"`
+		ClientInfo struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"clientInfo"`
+	}
+	if err := json.Unmarshal(params, &initialize); err != nil {
+		return
+	}
+	t.elicitation.Store(initialize.Capabilities.Elicitation != nil)
+	t.session = &audit.Session{
+		ID:            t.session.ID,
+		ClientName:    initialize.ClientInfo.Name,
+		ClientVersion: initialize.ClientInfo.Version,
+	}
+}
+
+// newSessionID returns a random ID for a connection
+func newSessionID() string {
+	raw := make([]byte, 8)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
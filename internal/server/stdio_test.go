@@ -0,0 +1,169 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/tools"
+)
+
+// pipeClient drives a stdioTransport like an MCP client would
+type pipeClient struct {
+	t   *testing.T
+	in  *io.PipeWriter
+	out *bufio.Scanner
+}
+
+func (c *pipeClient) send(message string) {
+	_, err := fmt.Fprintln(c.in, message)
+	require.NoError(c.t, err)
+}
+
+func (c *pipeClient) receive() map[string]interface{} {
+	require.True(c.t, c.out.Scan(), "expected a message")
+	var message map[string]interface{}
+	require.NoError(c.t, json.Unmarshal(c.out.Bytes(), &message))
+	return message
+}
+
+func startTransport(t *testing.T, mcpServer server.MCPServer, configure ...func(*stdioTransport)) *pipeClient {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	transport := newStdioTransport(mcpServer, inReader, outWriter)
+	for _, apply := range configure {
+		apply(transport)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- transport.serve(ctx) }()
+	t.Cleanup(func() {
+		inWriter.Close()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Error("transport did not stop")
+		}
+		cancel()
+		outWriter.Close()
+	})
+	return &pipeClient{t: t, in: inWriter, out: bufio.NewScanner(outReader)}
+}
+
+func confirmingServer() server.MCPServer {
+	mcpServer := server.NewDefaultServer("test", "0.0.0")
+	mcpServer.HandleCallTool(func(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		text := "no elicitation"
+		if elicitor := tools.ElicitorFrom(ctx); elicitor != nil {
+			accepted, err := elicitor.Confirm(ctx, "Delete?", "Proceed with the deletion")
+			if err != nil {
+				return nil, err
+			}
+			text = fmt.Sprintf("accepted=%v", accepted)
+		}
+		return &mcp.CallToolResult{Content: []interface{}{mcp.TextContent{Type: "text", Text: text}}}, nil
+	})
+	return mcpServer
+}
+
+func resultText(t *testing.T, message map[string]interface{}) string {
+	result, ok := message["result"].(map[string]interface{})
+	require.True(t, ok, "expected a result: %v", message)
+	return result["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+}
+
+func TestStdioTransport_Elicitation(t *testing.T) {
+	client := startTransport(t, confirmingServer())
+
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"elicitation":{}},"clientInfo":{"name":"test","version":"1"}}}`)
+	assert.EqualValues(t, 1, client.receive()["id"])
+	// Notifications get no response
+	client.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	client.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nuclino_delete_item","arguments":{}}}`)
+	request := client.receive()
+	assert.Equal(t, "elicitation/create", request["method"])
+	params := request["params"].(map[string]interface{})
+	assert.Equal(t, "Delete?", params["message"])
+	confirm := params["requestedSchema"].(map[string]interface{})["properties"].(map[string]interface{})["confirm"]
+	assert.Equal(t, "Proceed with the deletion", confirm.(map[string]interface{})["description"])
+
+	id, err := json.Marshal(request["id"])
+	require.NoError(t, err)
+	client.send(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"action":"accept","content":{"confirm":true}}}`, id))
+	response := client.receive()
+	assert.EqualValues(t, 2, response["id"])
+	assert.Equal(t, "accepted=true", resultText(t, response))
+}
+
+func TestStdioTransport_WithoutElicitation(t *testing.T) {
+	client := startTransport(t, confirmingServer())
+
+	client.send(`{"jsonrpc":"2.0","id":"a","method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	assert.Equal(t, "a", client.receive()["id"])
+
+	client.send(`{"jsonrpc":"2.0","id":"b","method":"tools/call","params":{"name":"nuclino_delete_item","arguments":{}}}`)
+	assert.Equal(t, "no elicitation", resultText(t, client.receive()))
+
+	client.send(`not json`)
+	assert.NotNil(t, client.receive()["error"])
+}
+
+func TestStdioTransport_ReaderStaysFreeDuringElicitation(t *testing.T) {
+	client := startTransport(t, confirmingServer())
+
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"elicitation":{}},"clientInfo":{"name":"test","version":"1"}}}`)
+	client.receive()
+	client.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nuclino_delete_item","arguments":{}}}`)
+	request := client.receive()
+	require.Equal(t, "elicitation/create", request["method"])
+
+	// More messages than any fixed queue holds arrive while the worker
+	// waits for the user; the reader still takes pings and the answer
+	for i := 0; i < 100; i++ {
+		client.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	}
+	client.send(`{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	assert.EqualValues(t, 3, client.receive()["id"])
+
+	id, err := json.Marshal(request["id"])
+	require.NoError(t, err)
+	client.send(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"action":"decline"}}`, id))
+	response := client.receive()
+	assert.EqualValues(t, 2, response["id"])
+	assert.Equal(t, "accepted=false", resultText(t, response))
+}
+
+func TestStdioTransport_AnswersOversizedMessages(t *testing.T) {
+	client := startTransport(t, confirmingServer(), func(transport *stdioTransport) {
+		transport.maxMessage = 1024
+	})
+	content := strings.Repeat("A", 200*1024)
+
+	client.send(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"nuclino_upload_file","arguments":{"content_base64":"` + content + `"}}}`)
+	response := client.receive()
+	assert.EqualValues(t, 5, response["id"])
+	assert.EqualValues(t, -32600, response["error"].(map[string]interface{})["code"])
+
+	// Without a leading id the error cannot name the request
+	client.send(`{"jsonrpc":"2.0","method":"tools/call","params":{"arguments":{"content_base64":"` + content + `"}},"id":6}`)
+	response = client.receive()
+	assert.Nil(t, response["id"])
+	assert.NotNil(t, response["error"])
+
+	// The connection keeps serving
+	client.send(`{"jsonrpc":"2.0","id":7,"method":"ping"}`)
+	assert.EqualValues(t, 7, client.receive()["id"])
+}
@@ -0,0 +1,241 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/cache"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// fileExtensions are the local files loaded as templates
+var fileExtensions = []string{".md", ".tmpl", ".txt"}
+
+// Config holds template library configuration
+type Config struct {
+	// Dir is a local directory of template files; empty disables them
+	Dir string
+	// CollectionTitle names the top-level collection holding templates in
+	// each workspace
+	CollectionTitle string
+	// UserID is the Nuclino user filled in as the default author
+	UserID   string
+	CacheTTL time.Duration
+}
+
+// DefaultConfig returns the default configuration, reading the template
+// directory and user from NUCLINO_TEMPLATES_DIR and NUCLINO_USER_ID
+func DefaultConfig() Config {
+	return Config{
+		Dir:             os.Getenv("NUCLINO_TEMPLATES_DIR"),
+		CollectionTitle: "Templates",
+		UserID:          os.Getenv("NUCLINO_USER_ID"),
+		CacheTTL:        5 * time.Minute,
+	}
+}
+
+// Library finds templates in Nuclino and on disk
+type Library struct {
+	client nuclino.Client
+	config Config
+	cache  *cache.Cache
+}
+
+// NewLibrary creates a template library
+func NewLibrary(client nuclino.Client, config Config) *Library {
+	defaults := DefaultConfig()
+	if config.CollectionTitle == "" {
+		config.CollectionTitle = defaults.CollectionTitle
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+	return &Library{
+		client: client,
+		config: config,
+		cache:  cache.NewCache(50, config.CacheTTL),
+	}
+}
+
+// List returns the templates available for a workspace: those in its
+// templates collection followed by local files. workspaceID may be empty.
+func (l *Library) List(ctx context.Context, workspaceID string) ([]*Template, error) {
+	var templates []*Template
+	if workspaceID != "" {
+		stored, err := l.nuclinoTemplates(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, stored...)
+	}
+
+	files, err := l.fileTemplates()
+	if err != nil {
+		return nil, err
+	}
+	return append(templates, files...), nil
+}
+
+// Find returns a template by name, case-insensitively. Templates stored in
+// Nuclino take precedence over files of the same name.
+func (l *Library) Find(ctx context.Context, workspaceID, name string) (*Template, error) {
+	templates, err := l.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+
+	names := make([]string, len(templates))
+	for i, t := range templates {
+		names[i] = t.Name
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("template %q not found: no templates in a %q collection or template directory", name, l.config.CollectionTitle)
+	}
+	return nil, fmt.Errorf("template %q not found; available: %s", name, strings.Join(names, ", "))
+}
+
+// Invalidate drops the cached templates of a workspace
+func (l *Library) Invalidate(workspaceID string) {
+	l.cache.Delete(workspaceID)
+}
+
+// Defaults returns the values every template can use: Date, Time, DateTime,
+// Year, Month, Weekday and, when a user is known, User, UserEmail and UserID
+func (l *Library) Defaults(ctx context.Context, userID string) map[string]interface{} {
+	now := time.Now()
+	defaults := map[string]interface{}{
+		"Date":     now.Format("2006-01-02"),
+		"Time":     now.Format("15:04"),
+		"DateTime": now.Format(time.RFC3339),
+		"Year":     now.Year(),
+		"Month":    now.Format("January"),
+		"Weekday":  now.Weekday().String(),
+	}
+
+	if userID == "" {
+		userID = l.config.UserID
+	}
+	if userID == "" {
+		return defaults
+	}
+	defaults["UserID"] = userID
+
+	user, err := l.client.GetUser(ctx, userID)
+	if err != nil {
+		log.Debug().Err(err).Str("user", userID).Msg("Failed to load template user defaults")
+		return defaults
+	}
+	defaults["User"] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	defaults["UserEmail"] = user.Email
+	return defaults
+}
+
+// nuclinoTemplates loads every item below the workspace's templates
+// collection, nested collections included
+func (l *Library) nuclinoTemplates(ctx context.Context, workspaceID string) ([]*Template, error) {
+	if cached, ok := l.cache.Get(workspaceID); ok {
+		if templates, ok := cached.([]*Template); ok {
+			return templates, nil
+		}
+	}
+
+	workspace, err := l.client.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	var root *nuclino.Item
+	for _, id := range workspace.ChildIDs {
+		child, err := l.client.GetItem(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item %s: %w", id, err)
+		}
+		if child.IsCollection() && strings.EqualFold(child.Title, l.config.CollectionTitle) {
+			root = child
+			break
+		}
+	}
+
+	templates := []*Template{}
+	if root != nil {
+		queue := append([]string(nil), root.ChildIDs...)
+		for len(queue) > 0 {
+			item, err := l.client.GetItem(ctx, queue[0])
+			if err != nil {
+				return nil, fmt.Errorf("failed to get template %s: %w", queue[0], err)
+			}
+			queue = queue[1:]
+
+			if item.IsCollection() {
+				queue = append(queue, item.ChildIDs...)
+				continue
+			}
+			t, err := Parse(item.Title, SourceNuclino, item.Content)
+			if err != nil {
+				log.Warn().Err(err).Str("item", item.ID).Msg("Skipping invalid template")
+				continue
+			}
+			t.ItemID = item.ID
+			templates = append(templates, t)
+		}
+	}
+
+	l.cache.Set(workspaceID, templates)
+	return templates, nil
+}
+
+// fileTemplates loads templates from the configured directory
+func (l *Library) fileTemplates() ([]*Template, error) {
+	if l.config.Dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(l.config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+
+	var templates []*Template
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !hasTemplateExtension(ext) {
+			continue
+		}
+
+		path := filepath.Join(l.config.Dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+		t, err := Parse(strings.TrimSuffix(entry.Name(), ext), SourceFile, string(data))
+		if err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("Skipping invalid template")
+			continue
+		}
+		t.Path = path
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+func hasTemplateExtension(ext string) bool {
+	for _, allowed := range fileExtensions {
+		if strings.EqualFold(ext, allowed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+// Package templates renders Nuclino items from Go text/template sources kept
+// either in a "Templates" collection of a workspace or in local files.
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// Template sources
+const (
+	SourceNuclino = "nuclino"
+	SourceFile    = "file"
+)
+
+// Template is a parsed item template
+type Template struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	// ItemID is set for templates stored in Nuclino
+	ItemID string `json:"item_id,omitempty"`
+	// Path is set for templates loaded from files
+	Path string `json:"path,omitempty"`
+	// Required lists the variables printed by the template
+	Required []string `json:"required"`
+	// Optional lists variables only used in conditions
+	Optional []string `json:"optional,omitempty"`
+	// Front matter settings
+	Description   string `json:"description,omitempty"`
+	TitleTemplate string `json:"title_template,omitempty"`
+	ParentID      string `json:"parent_id,omitempty"`
+
+	body string
+	tmpl *template.Template
+}
+
+// funcs are available in every template
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"date": func(layout string) string {
+		return time.Now().Format(layout)
+	},
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// Parse parses template text and records the variables it uses. The text may
+// start with a front matter block setting the title template, the parent
+// collection and a description:
+//
+//	---
+//	title: ADR {{.Number}}: {{.Subject}}
+//	parent: 5b1e...
+//	description: Architecture decision record
+//	---
+func Parse(name, source, text string) (*Template, error) {
+	meta, body := splitFrontMatter(text)
+	tmpl, err := template.New(name).Funcs(funcs).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+
+	required, optional := make(map[string]bool), make(map[string]bool)
+	if tmpl.Tree != nil {
+		collectFields(tmpl.Tree.Root, false, required, optional)
+	}
+
+	t := &Template{
+		Name:          name,
+		Source:        source,
+		Description:   meta["description"],
+		TitleTemplate: meta["title"],
+		ParentID:      meta["parent"],
+		body:          body,
+		tmpl:          tmpl,
+	}
+	if t.TitleTemplate != "" {
+		title, err := template.New(name + " title").Funcs(funcs).Parse(t.TitleTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("template %q title: %w", name, err)
+		}
+		collectFields(title.Tree.Root, false, required, optional)
+	}
+	for name := range required {
+		delete(optional, name)
+	}
+	t.Required = sortedNames(required)
+	t.Optional = sortedNames(optional)
+	return t, nil
+}
+
+// splitFrontMatter separates a leading "---" block of key: value lines
+func splitFrontMatter(text string) (map[string]string, string) {
+	meta := make(map[string]string)
+	if !strings.HasPrefix(text, "---\n") {
+		return meta, text
+	}
+	block, body, found := strings.Cut(text[len("---\n"):], "\n---\n")
+	if !found {
+		return meta, text
+	}
+	for _, line := range strings.Split(block, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			meta[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return meta, body
+}
+
+// Body returns the template text
+func (t *Template) Body() string {
+	return t.body
+}
+
+// Rendered is the output of a template
+type Rendered struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Render executes the template with values over defaults. The title is
+// rendered from titleTemplate, or the front matter title; without either a
+// leading "# " heading of the output becomes the title and is removed from
+// the content.
+func (t *Template) Render(titleTemplate string, values, defaults map[string]interface{}) (*Rendered, error) {
+	if titleTemplate == "" {
+		titleTemplate = t.TitleTemplate
+	}
+	data := make(map[string]interface{}, len(defaults)+len(values))
+	for key, value := range defaults {
+		data[key] = value
+	}
+	for key, value := range values {
+		data[key] = value
+	}
+
+	var missing []string
+	for _, name := range t.Required {
+		if _, ok := data[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %q needs values for: %s", t.Name, strings.Join(missing, ", "))
+	}
+	for _, name := range t.Optional {
+		if _, ok := data[name]; !ok {
+			data[name] = ""
+		}
+	}
+
+	var content bytes.Buffer
+	if err := t.tmpl.Execute(&content, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %q: %w", t.Name, err)
+	}
+	rendered := &Rendered{Content: content.String()}
+
+	switch {
+	case titleTemplate != "":
+		title, err := Parse(t.Name+" title", "", titleTemplate)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := title.tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render title: %w", err)
+		}
+		rendered.Title = strings.TrimSpace(buf.String())
+	default:
+		rendered.Title, rendered.Content = splitHeading(rendered.Content)
+	}
+
+	if rendered.Title == "" {
+		rendered.Title = fmt.Sprintf("%s %s", t.Name, data["Date"])
+	}
+	return rendered, nil
+}
+
+// splitHeading takes a leading H1 off content
+func splitHeading(content string) (string, string) {
+	trimmed := strings.TrimLeft(content, "\n")
+	if !strings.HasPrefix(trimmed, "# ") {
+		return "", content
+	}
+	line, rest, _ := strings.Cut(trimmed, "\n")
+	return strings.TrimSpace(strings.TrimPrefix(line, "# ")), strings.TrimLeft(rest, "\n")
+}
+
+// collectFields walks a parse tree and records top-level fields such as
+// {{.Owner}}. Fields only used in if/with conditions are optional.
+func collectFields(node parse.Node, condition bool, required, optional map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, condition, required, optional)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, condition, required, optional)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		// Values passed through default may be missing
+		guarded := condition
+		for _, cmd := range n.Cmds {
+			if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "default" {
+				guarded = true
+			}
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				collectFields(arg, guarded, required, optional)
+			}
+		}
+	case *parse.FieldNode:
+		if condition {
+			optional[n.Ident[0]] = true
+		} else {
+			required[n.Ident[0]] = true
+		}
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, required, optional)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, required, optional)
+	case *parse.RangeNode:
+		collectFields(n.Pipe, false, required, optional)
+	}
+}
+
+func collectBranch(n *parse.BranchNode, required, optional map[string]bool) {
+	collectFields(n.Pipe, true, required, optional)
+	// Fields in the branches are only printed when the condition holds
+	collectFields(n.List, true, required, optional)
+	collectFields(n.ElseList, true, required, optional)
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
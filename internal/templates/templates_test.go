@@ -0,0 +1,140 @@
+package templates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// fakeClient serves a workspace with a Templates collection; methods not used
+// here panic through the embedded nil interface
+type fakeClient struct {
+	nuclino.Client
+	workspace *nuclino.Workspace
+	items     map[string]*nuclino.Item
+	gets      int
+}
+
+func (f *fakeClient) GetWorkspace(ctx context.Context, workspaceID string) (*nuclino.Workspace, error) {
+	return f.workspace, nil
+}
+
+func (f *fakeClient) GetItem(ctx context.Context, itemID string) (*nuclino.Item, error) {
+	f.gets++
+	if item, ok := f.items[itemID]; ok {
+		return item, nil
+	}
+	return nil, nuclino.NewAPIError(404, "not found")
+}
+
+func (f *fakeClient) GetUser(ctx context.Context, userID string) (*nuclino.User, error) {
+	return &nuclino.User{ID: userID, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"}, nil
+}
+
+const adrTemplate = `---
+title: ADR {{.Number}}: {{.Subject}}
+parent: decisions
+description: Architecture decision record
+---
+Status: {{default "Proposed" .Status}}
+Date: {{.Date}}
+Author: {{.User}}
+{{if .Context}}
+## Context
+{{.Context}}
+{{end}}`
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		workspace: &nuclino.Workspace{ID: "ws", ChildIDs: []string{"docs", "templates"}},
+		items: map[string]*nuclino.Item{
+			"docs":      {ID: "docs", Object: nuclino.ObjectCollection, Title: "Docs"},
+			"templates": {ID: "templates", Object: nuclino.ObjectCollection, Title: "Templates", ChildIDs: []string{"adr", "meetings"}},
+			"meetings":  {ID: "meetings", Object: nuclino.ObjectCollection, Title: "Meetings", ChildIDs: []string{"notes"}},
+			"adr":       {ID: "adr", Object: nuclino.ObjectItem, Title: "ADR", Content: adrTemplate},
+			"notes":     {ID: "notes", Object: nuclino.ObjectItem, Title: "Meeting notes", Content: "# Meeting {{.Date}}\n\nAttendees: {{.Attendees}}\n"},
+		},
+	}
+}
+
+func TestParse_Variables(t *testing.T) {
+	tmpl, err := Parse("ADR", SourceNuclino, adrTemplate)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Date", "Number", "Subject", "User"}, tmpl.Required)
+	assert.Equal(t, []string{"Context", "Status"}, tmpl.Optional)
+	assert.Equal(t, "decisions", tmpl.ParentID)
+	assert.Equal(t, "Architecture decision record", tmpl.Description)
+
+	_, err = Parse("broken", SourceFile, "{{.Open")
+	assert.Error(t, err)
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl, err := Parse("ADR", SourceNuclino, adrTemplate)
+	require.NoError(t, err)
+	defaults := map[string]interface{}{"Date": "2024-05-01", "User": "Ada Lovelace"}
+
+	_, err = tmpl.Render("", map[string]interface{}{"Number": 7}, defaults)
+	assert.ErrorContains(t, err, "Subject")
+
+	rendered, err := tmpl.Render("", map[string]interface{}{"Number": 7, "Subject": "Use Postgres"}, defaults)
+	require.NoError(t, err)
+	assert.Equal(t, "ADR 7: Use Postgres", rendered.Title)
+	assert.Contains(t, rendered.Content, "Status: Proposed\nDate: 2024-05-01\nAuthor: Ada Lovelace")
+	assert.NotContains(t, rendered.Content, "## Context")
+
+	notes, err := Parse("Meeting notes", SourceNuclino, "# Meeting {{.Date}}\n\nAttendees: {{.Attendees}}\n")
+	require.NoError(t, err)
+	rendered, err = notes.Render("", map[string]interface{}{"Attendees": "Ada"}, defaults)
+	require.NoError(t, err)
+	assert.Equal(t, "Meeting 2024-05-01", rendered.Title)
+	assert.Equal(t, "Attendees: Ada\n", rendered.Content)
+}
+
+func TestLibrary_ListAndFind(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "incident.md"), []byte("# Incident {{.Date}}\n\nImpact: {{.Impact}}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "adr.md"), []byte("local copy"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.json"), []byte("{}"), 0o600))
+
+	client := newFakeClient()
+	library := NewLibrary(client, Config{Dir: dir})
+
+	templates, err := library.List(context.Background(), "ws")
+	require.NoError(t, err)
+	require.Len(t, templates, 4)
+	assert.Equal(t, "ADR", templates[0].Name)
+	assert.Equal(t, "Meeting notes", templates[1].Name)
+	assert.Equal(t, "adr", templates[2].Name)
+	assert.Equal(t, "incident", templates[3].Name)
+
+	adr, err := library.Find(context.Background(), "ws", "adr")
+	require.NoError(t, err)
+	assert.Equal(t, SourceNuclino, adr.Source)
+	assert.Equal(t, "adr", adr.ItemID)
+
+	// Nuclino templates are cached per workspace
+	gets := client.gets
+	_, err = library.Find(context.Background(), "ws", "incident")
+	require.NoError(t, err)
+	assert.Equal(t, gets, client.gets)
+
+	_, err = library.Find(context.Background(), "ws", "retro")
+	assert.ErrorContains(t, err, "available: ADR")
+}
+
+func TestLibrary_Defaults(t *testing.T) {
+	library := NewLibrary(newFakeClient(), Config{UserID: "user-1"})
+
+	defaults := library.Defaults(context.Background(), "")
+	assert.Equal(t, "Ada Lovelace", defaults["User"])
+	assert.Equal(t, "ada@example.com", defaults["UserEmail"])
+	assert.NotEmpty(t, defaults["Date"])
+}
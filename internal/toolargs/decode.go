@@ -0,0 +1,234 @@
+package toolargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Decode fills the struct dst points to from MCP tool arguments. Absent
+// arguments take their default tag; unknown arguments, missing required
+// ones, values of the wrong type and values breaking a validate rule are
+// all reported together as Errors.
+func Decode(args map[string]interface{}, dst interface{}) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("toolargs: Decode needs a non-nil pointer, got %T", dst)
+	}
+	target = target.Elem()
+	fs, err := fields(target.Type())
+	if err != nil {
+		return err
+	}
+
+	var errs Errors
+	known := make(map[string]bool, len(fs))
+	for _, f := range fs {
+		known[f.Name] = true
+		fv := target.FieldByIndex(f.Index)
+
+		raw, present := args[f.Name]
+		if !present || raw == nil {
+			if f.Rules.Required {
+				errs = append(errs, &FieldError{Field: f.Name, Message: "is required"})
+				continue
+			}
+			if f.defaultText == "" {
+				continue
+			}
+			if err := setDefault(fv, f.defaultText); err != nil {
+				return fmt.Errorf("toolargs: default of %s: %w", f.Name, err)
+			}
+			continue
+		}
+
+		if err := assign(fv, raw, f.Name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if f.Rules.Required && isBlank(fv) {
+			errs = append(errs, &FieldError{Field: f.Name, Message: "must not be empty"})
+			continue
+		}
+		if err := f.Check(fv); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var unknown []string
+	for name := range args {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		errs = append(errs, &FieldError{Field: name, Message: "is not a known argument (known: " + knownList(fs) + ")"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func knownList(fs []field) string {
+	names := make([]string, len(fs))
+	for i, f := range fs {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// isBlank reports whether a required value is empty: a blank string or an
+// empty list
+func isBlank(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+func setDefault(v reflect.Value, text string) error {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		// Plain strings need no quotes in the tag
+		raw = text
+	}
+	if err := assign(v, raw, "default"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// assign converts a JSON-decoded value into v, reporting type mismatches
+// against name
+func assign(v reflect.Value, raw interface{}, name string) *FieldError {
+	if v.Kind() == reflect.Pointer {
+		if raw == nil {
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := assign(elem.Elem(), raw, name); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return typeError(name, "a string", raw)
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return typeError(name, "a boolean", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := number(raw)
+		if !ok || n != math.Trunc(n) {
+			return typeError(name, "an integer", raw)
+		}
+		if v.OverflowInt(int64(n)) {
+			return &FieldError{Field: name, Message: "is out of range"}
+		}
+		v.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := number(raw)
+		if !ok || n != math.Trunc(n) || n < 0 {
+			return typeError(name, "a non-negative integer", raw)
+		}
+		if v.OverflowUint(uint64(n)) {
+			return &FieldError{Field: name, Message: "is out of range"}
+		}
+		v.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, ok := number(raw)
+		if !ok {
+			return typeError(name, "a number", raw)
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		list, ok := raw.([]interface{})
+		if !ok {
+			return typeError(name, "an array", raw)
+		}
+		slice := reflect.MakeSlice(v.Type(), len(list), len(list))
+		for i, elem := range list {
+			if err := assign(slice.Index(i), elem, fmt.Sprintf("%s[%d]", name, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Interface:
+		if raw != nil {
+			v.Set(reflect.ValueOf(raw))
+		}
+	default:
+		// Maps and nested structs go through JSON
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return typeError(name, "an object", raw)
+		}
+		data, _ := json.Marshal(object)
+		if err := json.Unmarshal(data, v.Addr().Interface()); err != nil {
+			return &FieldError{Field: name, Message: "is not a valid object: " + err.Error()}
+		}
+	}
+	return nil
+}
+
+func number(raw interface{}) (float64, bool) {
+	switch n := raw.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func typeError(name, want string, raw interface{}) *FieldError {
+	return &FieldError{Field: name, Message: fmt.Sprintf("must be %s, got %s", want, jsonType(raw))}
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(raw interface{}) string {
+	switch raw.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, json.Number, int, int64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", raw)
+}
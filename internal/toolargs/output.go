@@ -0,0 +1,92 @@
+package toolargs
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// OutputSchema returns the JSON Schema of v's type as encoding/json
+// marshals it, for a tool's outputSchema. Struct fields follow their json
+// tags; slices, maps and pointers without omitempty may also be null.
+func OutputSchema(v interface{}) map[string]interface{} {
+	return valueSchema(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+// valueSchema describes t; seen guards against recursive types, which are
+// described as any value below the first level
+func valueSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		if seen[t] {
+			return map[string]interface{}{}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		return map[string]interface{}{"type": "object", "properties": structProperties(t, seen)}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// encoding/json sends []byte as base64
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return map[string]interface{}{"type": "array", "items": valueSchema(t.Elem(), seen)}
+	case t.Kind() == reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": valueSchema(t.Elem(), seen)}
+	}
+	return typeSchema(t)
+}
+
+// structProperties describes the marshalled fields of struct type t,
+// flattening embedded structs like encoding/json does
+func structProperties(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, options, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, value := range structProperties(embedded, seen) {
+					properties[key] = value
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		property := valueSchema(sf.Type, seen)
+		if nullable(sf.Type) && !strings.Contains(","+options+",", ",omitempty,") {
+			if typ, ok := property["type"].(string); ok {
+				property["type"] = []interface{}{typ, "null"}
+			}
+		}
+		properties[name] = property
+	}
+	return properties
+}
+
+// nullable reports whether encoding/json may send a value of t as null
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		return true
+	}
+	return false
+}
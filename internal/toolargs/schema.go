@@ -0,0 +1,114 @@
+package toolargs
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/validate"
+)
+
+// Schema returns the JSON Schema of the arguments struct v, or of the
+// struct v points to. It panics on invalid tags, which are programming
+// errors caught by any test listing the tool.
+func Schema(v interface{}) map[string]interface{} {
+	fs, err := fields(reflect.TypeOf(v))
+	if err != nil {
+		panic(err)
+	}
+
+	properties := make(map[string]interface{}, len(fs))
+	var required []string
+	for _, f := range fs {
+		property := typeSchema(f.Type)
+		if f.description != "" {
+			property["description"] = f.description
+		}
+		if f.defaultText != "" {
+			var value interface{}
+			if err := json.Unmarshal([]byte(f.defaultText), &value); err != nil {
+				value = f.defaultText
+			}
+			property["default"] = value
+		}
+		describe(f.Rules, property)
+		properties[f.Name] = property
+		if f.Rules.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// typeSchema maps a Go type to its JSON Schema type
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map, reflect.Struct:
+		return map[string]interface{}{"type": "object"}
+	}
+	// interface{} accepts any JSON value
+	return map[string]interface{}{}
+}
+
+// describe adds the schema keywords of rules r to property
+func describe(r validate.Rules, property map[string]interface{}) {
+	target := property
+	if items, ok := property["items"].(map[string]interface{}); ok && len(r.OneOf) > 0 {
+		// oneof on a list constrains its elements
+		target = items
+	}
+	if len(r.OneOf) > 0 {
+		enum := make([]interface{}, len(r.OneOf))
+		for i, value := range r.OneOf {
+			enum[i] = value
+			if target["type"] == "integer" || target["type"] == "number" {
+				var n float64
+				if err := json.Unmarshal([]byte(value), &n); err == nil {
+					enum[i] = n
+				}
+			}
+		}
+		target["enum"] = enum
+	}
+
+	minKey, maxKey := "minimum", "maximum"
+	switch property["type"] {
+	case "string":
+		minKey, maxKey = "minLength", "maxLength"
+	case "array":
+		minKey, maxKey = "minItems", "maxItems"
+	case "object":
+		minKey, maxKey = "minProperties", "maxProperties"
+	}
+	if r.Min != nil {
+		property[minKey] = *r.Min
+	}
+	if r.Max != nil {
+		property[maxKey] = *r.Max
+	}
+	if r.Required && property["type"] == "string" && r.Min == nil {
+		property["minLength"] = 1
+	}
+}
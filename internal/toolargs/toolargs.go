@@ -0,0 +1,56 @@
+// Package toolargs decodes MCP tool arguments into Go structs and derives
+// the tools' JSON Schema from the same structs, so a tool's schema and its
+// argument parsing cannot drift apart.
+//
+// Fields are described with struct tags:
+//
+//	type SearchArgs struct {
+//		Query string `json:"query" desc:"Search query text" validate:"required"`
+//		Limit int    `json:"limit" desc:"Maximum results" validate:"min=1,max=100" default:"50"`
+//		Sort  string `json:"sort" validate:"oneof=title updated"`
+//	}
+//
+// The json tag names the argument; fields without one, or tagged "-", are
+// not arguments. Anonymous struct fields are flattened, so option sets can
+// be shared between tools. The validate tag follows package validate:
+// required, min=N, max=N and oneof=a b c.
+//
+// OutputSchema describes a tool's result type the same way, for the
+// outputSchema of tools returning structured content.
+package toolargs
+
+import (
+	"reflect"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/validate"
+)
+
+// FieldError is a problem with one argument
+type FieldError = validate.FieldError
+
+// Errors lists every argument problem found
+type Errors = validate.Errors
+
+// field is an argument of an args struct
+type field struct {
+	validate.Field
+	description string
+	defaultText string
+}
+
+// fields returns the arguments of struct type t, in declaration order
+func fields(t reflect.Type) ([]field, error) {
+	fs, err := validate.Fields(t)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]field, len(fs))
+	for i, f := range fs {
+		result[i] = field{
+			Field:       f,
+			description: f.Tag.Get("desc"),
+			defaultText: f.Tag.Get("default"),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package toolargs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type paging struct {
+	Limit  int `json:"limit" desc:"Maximum results" validate:"min=1,max=100" default:"50"`
+	Offset int `json:"offset" validate:"min=0"`
+}
+
+type searchArgs struct {
+	Query  string                 `json:"query" desc:"Search text" validate:"required"`
+	Sort   string                 `json:"sort" validate:"oneof=title updated" default:"title"`
+	Fields []string               `json:"fields" validate:"oneof=id title"`
+	Exact  *bool                  `json:"exact"`
+	Extra  map[string]interface{} `json:"extra"`
+	paging
+	internal string
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema(searchArgs{})
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.Equal(t, []string{"query"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Len(t, properties, 7)
+	assert.Equal(t, map[string]interface{}{"type": "string", "description": "Search text", "minLength": 1}, properties["query"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"title", "updated"}, "default": "title"}, properties["sort"])
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string", "enum": []interface{}{"id", "title"}},
+	}, properties["fields"])
+	assert.Equal(t, map[string]interface{}{"type": "boolean"}, properties["exact"])
+	assert.Equal(t, map[string]interface{}{
+		"type": "integer", "description": "Maximum results", "default": float64(50), "minimum": float64(1), "maximum": float64(100),
+	}, properties["limit"])
+}
+
+func TestDecode(t *testing.T) {
+	var args searchArgs
+	require.NoError(t, Decode(map[string]interface{}{
+		"query":  "roadmap",
+		"fields": []interface{}{"id"},
+		"exact":  true,
+		"offset": float64(20),
+		"extra":  map[string]interface{}{"a": "b"},
+	}, &args))
+	assert.Equal(t, "roadmap", args.Query)
+	assert.Equal(t, "title", args.Sort, "default applied")
+	assert.Equal(t, 50, args.Limit, "default of an embedded field applied")
+	assert.Equal(t, 20, args.Offset)
+	assert.Equal(t, []string{"id"}, args.Fields)
+	require.NotNil(t, args.Exact)
+	assert.True(t, *args.Exact)
+	assert.Equal(t, "b", args.Extra["a"])
+}
+
+func TestDecode_Errors(t *testing.T) {
+	var args searchArgs
+	err := Decode(map[string]interface{}{
+		"query":  "  ",
+		"sort":   "random",
+		"fields": []interface{}{"id", "body"},
+		"limit":  2.5,
+		"offset": "10",
+		"colour": "blue",
+	}, &args)
+	require.Error(t, err)
+	errs, ok := err.(Errors)
+	require.True(t, ok)
+
+	messages := make(map[string]string)
+	for _, fieldErr := range errs {
+		messages[fieldErr.Field] = fieldErr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"query":     "must not be empty",
+		"sort":      "must be one of title, updated",
+		"fields[1]": "must be one of id, title",
+		"limit":     "must be an integer, got number",
+		"offset":    "must be an integer, got string",
+		"colour":    "is not a known argument (known: query, sort, fields, exact, extra, limit, offset)",
+	}, messages)
+
+	err = Decode(map[string]interface{}{"limit": float64(500)}, &args)
+	assert.EqualError(t, err, "invalid arguments: query is required; limit must be at most 100")
+}
+
+func TestOutputSchema(t *testing.T) {
+	type meta struct {
+		IDs []string `json:"ids"`
+	}
+	type result struct {
+		ID      string                 `json:"id"`
+		Created time.Time              `json:"createdAt"`
+		Tags    []string               `json:"tags,omitempty"`
+		Meta    meta                   `json:"meta"`
+		Fields  map[string]interface{} `json:"fields,omitempty"`
+		Next    *result                `json:"next,omitempty"`
+		secret  string
+	}
+
+	schema := OutputSchema(&result{})
+	assert.Equal(t, "object", schema["type"])
+	properties := schema["properties"].(map[string]interface{})
+	assert.Len(t, properties, 6)
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "date-time"}, properties["createdAt"])
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}, properties["tags"])
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"ids": map[string]interface{}{"type": []interface{}{"array", "null"}, "items": map[string]interface{}{"type": "string"}},
+		},
+	}, properties["meta"])
+	assert.Equal(t, map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{}}, properties["fields"])
+	assert.Equal(t, map[string]interface{}{}, properties["next"], "recursive types are not expanded")
+}
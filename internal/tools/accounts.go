@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// accountArg is the argument that selects the account of a tool call
+const accountArg = "account"
+
+// Account is a named Nuclino client, such as one per team API key
+type Account struct {
+	Name    string
+	Client  nuclino.Client
+	Options Options
+}
+
+// NewMultiAccountRegistry creates a registry serving several accounts. The
+// first account is the default. Each account gets its own registry, so
+// caches, link graphs, templates and bulk plans never cross accounts, and
+// every tool takes an optional account argument selecting one. With a
+// single account it is the same as NewRegistryWithOptions.
+func NewMultiAccountRegistry(accounts []Account) (*Registry, error) {
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no accounts configured")
+	}
+	if len(accounts) == 1 {
+		return NewRegistryWithOptions(accounts[0].Client, accounts[0].Options), nil
+	}
+
+	registries := make(map[string]*Registry, len(accounts))
+	names := make([]string, 0, len(accounts))
+	for i, account := range accounts {
+		if account.Name == "" {
+			return nil, fmt.Errorf("account %d has no name", i+1)
+		}
+		if _, ok := registries[account.Name]; ok {
+			return nil, fmt.Errorf("account %q is configured twice", account.Name)
+		}
+
+		options := account.Options
+		if i > 0 && options.BulkStoreDir == "" {
+			options.BulkStoreDir = filepath.Join(bulk.DefaultStoreDir(), "accounts", account.Name)
+		}
+		registries[account.Name] = NewRegistryWithOptions(account.Client, options)
+		names = append(names, account.Name)
+	}
+
+	registry := registries[names[0]]
+	registry.accounts = registries
+	registry.accountNames = names
+	registry.spanning = make(map[string]Tool)
+
+	// Only accounts that would serve the call themselves take part in it
+	var listing []string
+	for _, name := range names {
+		account := registries[name]
+		if _, ok := account.tools["nuclino_list_workspaces"]; ok && account.policy.permits("nuclino_list_workspaces") == nil {
+			listing = append(listing, name)
+		}
+	}
+	if len(listing) > 0 {
+		registry.spanning["nuclino_list_workspaces"] = &ListAccountWorkspacesTool{accounts: registries, names: listing}
+	}
+	return registry, nil
+}
+
+// Accounts returns the account names, default first; empty for a
+// single-account registry
+func (r *Registry) Accounts() []string {
+	return append([]string(nil), r.accountNames...)
+}
+
+// route picks the registry of the call's account and strips the account
+// argument. A nil registry with a tool means the tool spans all accounts.
+func (r *Registry) route(name string, args map[string]interface{}) (*Registry, Tool, map[string]interface{}, error) {
+	if len(r.accounts) == 0 {
+		return r, nil, args, nil
+	}
+
+	account, _ := args[accountArg].(string)
+	args = withoutArg(args, accountArg)
+
+	if account == "" {
+		if tool, ok := r.spanning[name]; ok {
+			return nil, tool, args, nil
+		}
+		account = r.accountNames[0]
+	}
+	target, ok := r.accounts[account]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("unknown account %q; available: %s", account, strings.Join(r.accountNames, ", "))
+	}
+	return target, nil, args, nil
+}
+
+// withoutArg returns args without name, copying rather than changing the
+// caller's map
+func withoutArg(args map[string]interface{}, name string) map[string]interface{} {
+	if _, ok := args[name]; !ok {
+		return args
+	}
+	stripped := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if key != name {
+			stripped[key] = value
+		}
+	}
+	return stripped
+}
+
+// accountProperty documents the account argument added to every tool
+func (r *Registry) accountProperty() map[string]interface{} {
+	property := StringProperty(fmt.Sprintf("Nuclino account to use (default: %s)", r.accountNames[0]))
+	property["enum"] = r.Accounts()
+	return property
+}
+
+// ListAccountWorkspacesTool lists the workspaces of every account whose
+// policy allows listing them, limited to each account's workspace allowlist
+type ListAccountWorkspacesTool struct {
+	accounts map[string]*Registry
+	names    []string
+}
+
+func (t *ListAccountWorkspacesTool) Name() string {
+	return "nuclino_list_workspaces"
+}
+
+func (t *ListAccountWorkspacesTool) Description() string {
+	return "List Nuclino workspaces. Without an account, lists the workspaces of every configured account, each tagged with its account; with an account, lists that account's workspaces with pagination support"
+}
+
+// InputSchema is the schema of nuclino_list_workspaces; without an account
+// the page applies to each account
+func (t *ListAccountWorkspacesTool) InputSchema() interface{} {
+	return SchemaOf[ListWorkspacesArgs]()
+}
+
+// accountWorkspace is a workspace tagged with its account
+type accountWorkspace struct {
+	Account string `json:"account"`
+	nuclino.Workspace
+}
+
+// accountSummary reports how listing one account went
+type accountSummary struct {
+	Name       string `json:"name"`
+	Workspaces int    `json:"workspaces"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (t *ListAccountWorkspacesTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *ListAccountWorkspacesTool) run(ctx context.Context, args *ListWorkspacesArgs) (*mcp.CallToolResult, error) {
+	// Accounts have separate rate limits, so they are listed concurrently
+	summaries := make([]accountSummary, len(t.names))
+	results := make([][]nuclino.Workspace, len(t.names))
+	var wg sync.WaitGroup
+	for i, name := range t.names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			summaries[i].Name = name
+			account := t.accounts[name]
+			response, err := account.client.ListWorkspaces(ctx, args.Limit, args.Offset)
+			if err != nil {
+				summaries[i].Error = err.Error()
+				return
+			}
+			for _, workspace := range response.Results {
+				if account.policy.allowsWorkspace(workspace.ID) {
+					results[i] = append(results[i], workspace)
+				}
+			}
+			summaries[i].Workspaces = len(results[i])
+		}(i, name)
+	}
+	wg.Wait()
+
+	workspaces := []accountWorkspace{}
+	for i, name := range t.names {
+		for _, workspace := range results[i] {
+			workspaces = append(workspaces, accountWorkspace{Account: name, Workspace: workspace})
+		}
+	}
+	return FormatResult(map[string]interface{}{
+		"accounts":   summaries,
+		"workspaces": workspaces,
+	})
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func TestMultiAccountRegistry(t *testing.T) {
+	work, client := new(MockClient), new(MockClient)
+	readOnly := DefaultOptions()
+	readOnly.ReadOnly = true
+	registry, err := NewMultiAccountRegistry([]Account{
+		{Name: "work", Client: work, Options: DefaultOptions()},
+		{Name: "client", Client: client, Options: readOnly},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work", "client"}, registry.Accounts())
+
+	for _, tool := range registry.ListTools() {
+		require.Contains(t, tool.InputSchema.Properties, "account", tool.Name)
+	}
+
+	// Calls go to the default account unless another is named
+	work.On("GetItem", mock.Anything, item1ID).Return(&nuclino.Item{ID: item1ID, Title: "Work item"}, nil)
+	client.On("GetItem", mock.Anything, item2ID).Return(&nuclino.Item{ID: item2ID, Title: "Client item"}, nil)
+
+	result, err := registry.CallTool("nuclino_get_item", map[string]interface{}{"item_id": item1ID})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Work item")
+	result, err = registry.CallTool("nuclino_get_item", map[string]interface{}{"item_id": item2ID, "account": "client"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Client item")
+
+	// Each account keeps its own options
+	result, err = registry.CallTool("nuclino_delete_item", map[string]interface{}{"item_id": item2ID, "account": "client"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "permission_denied")
+	_, err = registry.CallTool("nuclino_get_item", map[string]interface{}{"item_id": item1ID, "account": "other"})
+	assert.ErrorContains(t, err, `unknown account "other"`)
+
+	// Workspaces of every account are listed together
+	work.On("ListWorkspaces", mock.Anything, 50, 0).Return(&nuclino.WorkspacesResponse{
+		Results: []nuclino.Workspace{{ID: ws1ID, Name: "Engineering"}},
+	}, nil)
+	client.On("ListWorkspaces", mock.Anything, 50, 0).Return((*nuclino.WorkspacesResponse)(nil), nuclino.NewAPIError(401, "unauthorized"))
+
+	result, err = registry.CallTool("nuclino_list_workspaces", map[string]interface{}{})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"account":"work"`)
+	assert.Contains(t, text, `"name":"Engineering"`)
+	assert.Contains(t, text, "unauthorized")
+
+	work.AssertExpectations(t)
+	client.AssertExpectations(t)
+}
+
+func TestMultiAccountRegistry_ListWorkspacesFollowsPolicies(t *testing.T) {
+	work, client, docs := new(MockClient), new(MockClient), new(MockClient)
+	allowlisted := DefaultOptions()
+	allowlisted.Policy = Policy{Workspaces: []string{ws1ID}}
+	denied := DefaultOptions()
+	denied.Policy = Policy{Deny: []string{"nuclino_list_workspaces"}}
+	itemsOnly := DefaultOptions()
+	itemsOnly.Toolsets = []string{ToolsetItems}
+	registry, err := NewMultiAccountRegistry([]Account{
+		{Name: "work", Client: work, Options: allowlisted},
+		{Name: "client", Client: client, Options: denied},
+		{Name: "docs", Client: docs, Options: itemsOnly},
+	})
+	require.NoError(t, err)
+
+	// The allowlisted workspaces are fetched rather than listed
+	work.On("GetWorkspace", mock.Anything, ws1ID).Return(&nuclino.Workspace{ID: ws1ID, Name: "Engineering"}, nil)
+
+	result, err := registry.CallTool("nuclino_list_workspaces", map[string]interface{}{})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"name":"Engineering"`)
+	assert.NotContains(t, text, `"name":"client"`)
+	assert.NotContains(t, text, `"name":"docs"`)
+
+	work.AssertExpectations(t)
+	work.AssertNotCalled(t, "ListWorkspaces", mock.Anything, mock.Anything, mock.Anything)
+	client.AssertNotCalled(t, "ListWorkspaces", mock.Anything, mock.Anything, mock.Anything)
+	docs.AssertNotCalled(t, "ListWorkspaces", mock.Anything, mock.Anything, mock.Anything)
+}
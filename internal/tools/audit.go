@@ -0,0 +1,154 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+)
+
+// auditIDArgs are the arguments whose IDs are recorded as affected, next to
+// the item arguments checked by the policy
+var auditIDArgs = append([]string{"workspace_id", "file_id"}, itemArgs...)
+
+// recordCall appends a tool call to the audit log. args are the arguments
+// the tool ran with, references resolved to IDs.
+func (r *Registry) recordCall(ctx context.Context, name, account string, args map[string]interface{}, start time.Time, result *mcp.CallToolResult, err error) {
+	if r.audit == nil {
+		return
+	}
+
+	record := audit.Record{
+		Time:      start.UTC(),
+		Kind:      audit.KindTool,
+		Session:   audit.SessionFrom(ctx),
+		Tool:      name,
+		Args:      audit.Redact(args),
+		Outcome:   audit.OutcomeSuccess,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if len(r.accountNames) > 0 {
+		record.Account = account
+		if record.Account == "" {
+			record.Account = r.accountNames[0]
+		}
+	}
+
+	seen := make(map[string]bool)
+	affect := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			record.Affected = append(record.Affected, id)
+		}
+	}
+	for _, key := range auditIDArgs {
+		for _, id := range idList(args[key]) {
+			affect(id)
+		}
+	}
+
+	var body struct {
+		Error  string `json:"error"`
+		Status string `json:"status"`
+		ID     string `json:"id"`
+	}
+	if data := StructuredContent(result); data != nil {
+		_ = json.Unmarshal(data, &body)
+	} else if result != nil && len(result.Content) > 0 {
+		if text, ok := result.Content[0].(mcp.TextContent); ok {
+			_ = json.Unmarshal([]byte(text.Text), &body)
+		}
+	}
+	switch {
+	case err != nil:
+		record.Outcome = audit.OutcomeError
+		record.Error = err.Error()
+	case result == nil:
+		record.Outcome = audit.OutcomeError
+	case result.IsError && body.Error == "permission_denied":
+		record.Outcome = audit.OutcomeDenied
+	case result.IsError:
+		record.Outcome = audit.OutcomeError
+		if text, ok := result.Content[0].(mcp.TextContent); ok {
+			record.Error = text.Text
+		}
+	case body.Status == audit.OutcomeConfirm || body.Status == audit.OutcomeCancelled:
+		record.Outcome = body.Status
+	default:
+		// Created objects are returned with their ID
+		affect(body.ID)
+	}
+
+	r.audit.Log(record)
+}
+
+// AuditQueryTool searches the audit log
+type AuditQueryTool struct {
+	log *audit.Logger
+}
+
+func (t *AuditQueryTool) Name() string {
+	return "nuclino_audit_query"
+}
+
+func (t *AuditQueryTool) Description() string {
+	return "Search the audit log of tool calls and Nuclino API changes, newest first. Filter by time range, tool or API operation, affected item/collection/workspace ID and outcome"
+}
+
+// AuditQueryArgs are the arguments of nuclino_audit_query
+type AuditQueryArgs struct {
+	Since   string `json:"since" desc:"Start of the time range: RFC 3339, YYYY-MM-DD or a duration before now such as 24h or 7d"`
+	Until   string `json:"until" desc:"End of the time range, in the same formats as since"`
+	Tool    string `json:"tool" desc:"Tool name (e.g. nuclino_delete_item) or API operation (e.g. DeleteItem)"`
+	ItemID  string `json:"item_id" desc:"Only records that named or created this ID"`
+	Outcome string `json:"outcome" desc:"Only records with this outcome" validate:"oneof=success error denied confirmation_required cancelled"`
+	Kind    string `json:"kind" desc:"Only tool call records (tool) or API change records (api)" validate:"oneof=tool api"`
+	Limit   int    `json:"limit" desc:"Maximum number of records (default: 50, max: 500)" validate:"min=1,max=500" default:"50"`
+}
+
+func (t *AuditQueryTool) InputSchema() interface{} {
+	return SchemaOf[AuditQueryArgs]()
+}
+
+func (t *AuditQueryTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *AuditQueryTool) run(ctx context.Context, args *AuditQueryArgs) (*mcp.CallToolResult, error) {
+	now := time.Now()
+	filter := audit.Filter{
+		Tool:    args.Tool,
+		ItemID:  args.ItemID,
+		Outcome: args.Outcome,
+		Kind:    args.Kind,
+		Limit:   args.Limit,
+	}
+	var err error
+	if args.Since != "" {
+		if filter.Since, err = audit.ParseTime(args.Since, now); err != nil {
+			return FormatArgsError(toolargs.Errors{{Field: "since", Message: err.Error()}})
+		}
+	}
+	if args.Until != "" {
+		if filter.Until, err = audit.ParseTime(args.Until, now); err != nil {
+			return FormatArgsError(toolargs.Errors{{Field: "until", Message: err.Error()}})
+		}
+	}
+
+	records, err := t.log.Query(filter)
+	if err != nil {
+		return FormatError(err)
+	}
+	if records == nil {
+		records = []audit.Record{}
+	}
+	return FormatResult(map[string]interface{}{
+		"records": records,
+		"count":   len(records),
+		"path":    t.log.Path(),
+	})
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+	"github.com/lukasz/nuclino-mcp-server/internal/monitoring"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func TestRegistry_AuditLog(t *testing.T) {
+	logger, err := audit.NewLogger(audit.Config{Path: filepath.Join(t.TempDir(), "audit.jsonl")})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	mockClient := new(MockClient)
+	mockClient.On("CreateItem", mock.Anything, mock.Anything).Return(&nuclino.Item{ID: newItemID, Title: "Notes"}, nil)
+	mockClient.On("GetItem", mock.Anything, item1ID).Return(&nuclino.Item{ID: item1ID, Object: "item", Title: "Old"}, nil)
+	options := DefaultOptions()
+	options.Audit = logger
+	options.Policy = Policy{Deny: []string{"nuclino_get_team"}}
+	registry := NewRegistryWithOptions(mockClient, options)
+
+	mockClient.On("ListWorkspaces", mock.Anything, 100, 0).Return(&nuclino.WorkspacesResponse{Results: []nuclino.Workspace{{ID: ws1ID, Name: "Engineering"}}}, nil)
+
+	mustCall(t, registry, "nuclino_create_item", map[string]interface{}{"workspace_id": "Engineering", "title": "Notes"})
+	mustCall(t, registry, "nuclino_delete_item", map[string]interface{}{"item_id": item1ID})
+	mustCall(t, registry, "nuclino_get_team", map[string]interface{}{"team_id": "team-1"})
+
+	result := mustCall(t, registry, "nuclino_audit_query", map[string]interface{}{"since": "1h"})
+	require.False(t, result.IsError)
+	var body struct {
+		Records []audit.Record `json:"records"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &body))
+	require.Len(t, body.Records, 3)
+
+	byTool := make(map[string]audit.Record)
+	for _, record := range body.Records {
+		byTool[record.Tool] = record
+	}
+	assert.Equal(t, audit.OutcomeSuccess, byTool["nuclino_create_item"].Outcome)
+	assert.Equal(t, []string{ws1ID, newItemID}, byTool["nuclino_create_item"].Affected, "references are recorded as the IDs they resolved to")
+	assert.Equal(t, ws1ID, byTool["nuclino_create_item"].Args["workspace_id"])
+	assert.Equal(t, audit.OutcomeConfirm, byTool["nuclino_delete_item"].Outcome)
+	assert.Equal(t, audit.OutcomeDenied, byTool["nuclino_get_team"].Outcome)
+
+	result = mustCall(t, registry, "nuclino_audit_query", map[string]interface{}{"item_id": newItemID})
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &body))
+	require.Len(t, body.Records, 1)
+	assert.Equal(t, "nuclino_create_item", body.Records[0].Tool)
+
+	assert.False(t, toolNames(NewRegistry(mockClient))["nuclino_audit_query"], "the query tool needs an audit log")
+}
+
+func TestRegistry_Metrics(t *testing.T) {
+	mockClient := new(MockClient)
+	mockClient.On("GetItem", mock.Anything, item1ID).Return(&nuclino.Item{ID: item1ID, Object: "item", Title: "Notes"}, nil)
+	collector := monitoring.NewMetricsCollector()
+	options := DefaultOptions()
+	options.Metrics = collector
+	options.Policy = Policy{Deny: []string{"nuclino_get_team"}}
+	registry := NewRegistryWithOptions(mockClient, options)
+
+	mustCall(t, registry, "nuclino_get_item", map[string]interface{}{"item_id": item1ID})
+	mustCall(t, registry, "nuclino_get_team", map[string]interface{}{"team_id": "team-1"})
+
+	tools := collector.GetMetrics().Tools
+	assert.Equal(t, int64(1), tools["nuclino_get_item"].CallsSuccessful)
+	assert.Equal(t, int64(1), tools["nuclino_get_team"].CallsFailed, "denied calls count as failures")
+}
@@ -0,0 +1,286 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BulkPlanTool builds a bulk change plan without writing anything
+type BulkPlanTool struct {
+	client        nuclino.Client
+	plans         bulk.Store
+	moveSupported bool
+}
+
+func (t *BulkPlanTool) Name() string {
+	return "nuclino_bulk_plan"
+}
+
+func (t *BulkPlanTool) Description() string {
+	return "Plan a bulk change across Nuclino items without applying it. Select items by workspace, subtree, explicit IDs, text query, title regex or field value, then move, retitle by regex, find-and-replace in content, set a field or delete. Returns a plan ID to pass to nuclino_bulk_execute and a preview of each step."
+}
+
+// BulkPlanArgs are the arguments of nuclino_bulk_plan
+type BulkPlanArgs struct {
+	Operation    string  `json:"operation" desc:"Operation: 'move', 'retitle', 'replace', 'set_field' or 'delete'" validate:"required,oneof=move retitle replace set_field delete"`
+	WorkspaceID  string  `json:"workspace_id" desc:"Select items in this workspace"`
+	ParentID     string  `json:"parent_id" desc:"Select items under this collection, including nested collections"`
+	ItemIDs      string  `json:"item_ids" desc:"Comma-separated item IDs to select"`
+	Query        string  `json:"query" desc:"Only items whose title or content contains this text (case-insensitive)"`
+	TitleRegex   string  `json:"title_regex" desc:"Only items whose title matches this regular expression"`
+	FilterField  string  `json:"filter_field" desc:"Only items where this field equals filter_value"`
+	FilterValue  string  `json:"filter_value" desc:"Value filter_field must equal"`
+	Pattern      string  `json:"pattern" desc:"Text or regex to find (retitle always uses regex)"`
+	Replacement  string  `json:"replacement" desc:"Replacement text; $1 style groups work with regex"`
+	Regex        bool    `json:"regex" desc:"Treat pattern as a regular expression for replace (default: false)"`
+	SkipCode     bool    `json:"skip_code" desc:"Leave fenced code blocks untouched for replace (default: true)" default:"true"`
+	Field        string  `json:"field" desc:"Field to set for set_field"`
+	Value        *string `json:"value" desc:"Value for set_field; JSON such as [\"a\",\"b\"], 3 or true is decoded, anything else is used as text"`
+	TargetID     string  `json:"target_id" desc:"Target collection ID for move"`
+	PreviewLimit int     `json:"preview_limit" desc:"Maximum number of steps to preview (default: 20)" validate:"min=0" default:"20"`
+}
+
+func (t *BulkPlanTool) InputSchema() interface{} {
+	return SchemaOf[BulkPlanArgs]()
+}
+
+func (t *BulkPlanTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *BulkPlanTool) run(ctx context.Context, args *BulkPlanArgs) (*mcp.CallToolResult, error) {
+	if args.Operation == bulk.OpMove && !t.moveSupported {
+		return FormatError(fmt.Errorf("moving items is not supported by this Nuclino API"))
+	}
+
+	spec := bulk.OperationSpec{
+		Type:        args.Operation,
+		Pattern:     args.Pattern,
+		Replacement: args.Replacement,
+		Regex:       args.Regex,
+		SkipCode:    args.SkipCode,
+		Field:       args.Field,
+		TargetID:    args.TargetID,
+	}
+	if args.Value != nil {
+		spec.Value = parseFieldValue(*args.Value)
+	}
+
+	op, err := bulk.NewOperation(spec)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	selector := bulk.Selector{
+		WorkspaceID: args.WorkspaceID,
+		ParentID:    args.ParentID,
+		Query:       args.Query,
+		TitleRegex:  args.TitleRegex,
+		Field:       args.FilterField,
+		FieldValue:  args.FilterValue,
+	}
+	if args.ItemIDs != "" {
+		selector.ItemIDs = splitIDs(args.ItemIDs)
+	}
+
+	plan, err := bulk.NewPlanner(t.client, bulk.DefaultPlannerConfig()).Plan(ctx, selector, op)
+	if err != nil {
+		return FormatError(err)
+	}
+	if err := t.plans.Save(plan); err != nil {
+		return FormatError(err)
+	}
+
+	return FormatResult(map[string]interface{}{
+		"plan_id":       plan.ID,
+		"operation":     plan.Operation,
+		"items_scanned": plan.Scanned,
+		"steps":         len(plan.Steps),
+		"preview":       previewSteps(plan.Steps, args.PreviewLimit),
+		"next":          "Call nuclino_bulk_execute with this plan_id to apply the changes",
+	})
+}
+
+// BulkExecuteTool applies or resumes a stored plan
+type BulkExecuteTool struct {
+	plans    bulk.Store
+	executor *bulk.Executor
+}
+
+func (t *BulkExecuteTool) Name() string {
+	return "nuclino_bulk_execute"
+}
+
+func (t *BulkExecuteTool) Description() string {
+	return "Apply a plan from nuclino_bulk_plan. Steps run with bounded concurrency under the rate limit, items edited since planning are reported as conflicts, and progress is saved so calling again resumes an interrupted or partly failed run. In atomic mode any failure undoes the steps already applied."
+}
+
+// BulkExecuteArgs are the arguments of nuclino_bulk_execute
+type BulkExecuteArgs struct {
+	PlanID string `json:"plan_id" desc:"The plan ID returned by nuclino_bulk_plan" validate:"required"`
+	Atomic bool   `json:"atomic" desc:"Undo applied steps if any step fails (default: true)" default:"true"`
+}
+
+func (t *BulkExecuteTool) InputSchema() interface{} {
+	return SchemaOf[BulkExecuteArgs]()
+}
+
+func (t *BulkExecuteTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *BulkExecuteTool) run(ctx context.Context, args *BulkExecuteArgs) (*mcp.CallToolResult, error) {
+	plan, err := t.plans.Load(args.PlanID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	result, err := t.executor.Execute(ctx, plan, bulk.Options{Atomic: args.Atomic})
+	if err != nil {
+		return FormatError(err)
+	}
+	return FormatResult(bulkOutcome(result))
+}
+
+// BulkStatusTool reports the progress of stored plans
+type BulkStatusTool struct {
+	plans bulk.Store
+}
+
+func (t *BulkStatusTool) Name() string {
+	return "nuclino_bulk_status"
+}
+
+func (t *BulkStatusTool) Description() string {
+	return "Show the status and per-item outcomes of a bulk plan, or list recent plans when no plan ID is given"
+}
+
+// BulkStatusArgs are the arguments of nuclino_bulk_status
+type BulkStatusArgs struct {
+	PlanID string `json:"plan_id" desc:"The plan ID (optional)"`
+	Limit  int    `json:"limit" desc:"Maximum number of plans to list (default: 10)" validate:"min=1" default:"10"`
+}
+
+func (t *BulkStatusTool) InputSchema() interface{} {
+	return SchemaOf[BulkStatusArgs]()
+}
+
+func (t *BulkStatusTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *BulkStatusTool) run(ctx context.Context, args *BulkStatusArgs) (*mcp.CallToolResult, error) {
+	if args.PlanID != "" {
+		plan, err := t.plans.Load(args.PlanID)
+		if err != nil {
+			return FormatError(err)
+		}
+		return FormatResult(map[string]interface{}{
+			"plan_id":    plan.ID,
+			"status":     plan.Status,
+			"operation":  plan.Operation,
+			"selector":   plan.Selector,
+			"created_at": plan.CreatedAt,
+			"updated_at": plan.UpdatedAt,
+			"outcomes":   previewSteps(plan.Steps, len(plan.Steps)),
+		})
+	}
+
+	plans, err := t.plans.List()
+	if err != nil {
+		return FormatError(err)
+	}
+	if len(plans) > args.Limit {
+		plans = plans[:args.Limit]
+	}
+
+	summaries := make([]map[string]interface{}, 0, len(plans))
+	for _, plan := range plans {
+		summaries = append(summaries, map[string]interface{}{
+			"plan_id":    plan.ID,
+			"operation":  plan.Operation.Type,
+			"status":     plan.Status,
+			"steps":      len(plan.Steps),
+			"applied":    plan.Count(bulk.StepApplied),
+			"pending":    plan.Count(bulk.StepPending),
+			"created_at": plan.CreatedAt,
+		})
+	}
+	return FormatResult(map[string]interface{}{"plans": summaries})
+}
+
+// bulkOutcome reports the counts of a run with a summary of every step
+func bulkOutcome(result *bulk.Result) map[string]interface{} {
+	return map[string]interface{}{
+		"plan_id":     result.PlanID,
+		"status":      result.Status,
+		"total":       result.Total,
+		"applied":     result.Applied,
+		"failed":      result.Failed,
+		"conflicts":   result.Conflicts,
+		"compensated": result.Compensated,
+		"pending":     result.Pending,
+		"outcomes":    previewSteps(result.Steps, len(result.Steps)),
+	}
+}
+
+// previewSteps summarizes steps without their full before and after content
+func previewSteps(steps []*bulk.Step, limit int) []map[string]interface{} {
+	if limit > len(steps) {
+		limit = len(steps)
+	}
+
+	previews := make([]map[string]interface{}, 0, limit)
+	for _, step := range steps[:limit] {
+		preview := map[string]interface{}{
+			"item_id": step.ItemID,
+			"title":   step.Title,
+			"action":  step.Action,
+			"status":  step.Status,
+		}
+		if step.Matches > 0 {
+			preview["matches"] = step.Matches
+		}
+		if step.TargetID != "" {
+			preview["target_id"] = step.TargetID
+		}
+		if step.After != nil {
+			if step.After.Title != "" {
+				preview["new_title"] = step.After.Title
+			}
+			if len(step.After.Fields) > 0 {
+				preview["fields"] = step.After.Fields
+			}
+		}
+		if step.Error != "" {
+			preview["error"] = step.Error
+		}
+		previews = append(previews, preview)
+	}
+	return previews
+}
+
+// parseFieldValue decodes JSON values and falls back to plain text
+func parseFieldValue(value string) interface{} {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+		return decoded
+	}
+	return value
+}
+
+func splitIDs(list string) []string {
+	var ids []string
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
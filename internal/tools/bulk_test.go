@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func TestBulkPlanAndExecuteTools(t *testing.T) {
+	mockClient := new(MockClient)
+	draft := &nuclino.Item{ID: "item-1", Title: "Draft: Setup"}
+	final := &nuclino.Item{ID: "item-2", Title: "Release notes"}
+	mockClient.On("ListItems", mock.Anything, "workspace-1", 100, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{{ID: "item-1"}, {ID: "item-2"}},
+	}, nil)
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(draft, nil)
+	mockClient.On("GetItem", mock.Anything, "item-2").Return(final, nil)
+
+	plans := bulk.NewMemoryStore()
+	planTool := &BulkPlanTool{client: mockClient, plans: plans}
+
+	result, err := planTool.Execute(context.Background(), map[string]interface{}{
+		"operation":    "retitle",
+		"workspace_id": "workspace-1",
+		"pattern":      "^Draft: ",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var planned struct {
+		PlanID string `json:"plan_id"`
+		Steps  int    `json:"steps"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &planned))
+	assert.Equal(t, 1, planned.Steps)
+	mockClient.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+
+	mockClient.On("UpdateItem", mock.Anything, "item-1", mock.MatchedBy(func(req *nuclino.UpdateItemRequest) bool {
+		return req.Title != nil && *req.Title == "Setup"
+	})).Return(draft, nil)
+
+	executeTool := &BulkExecuteTool{plans: plans, executor: bulk.NewExecutor(mockClient, plans, bulk.ExecutorConfig{RequestsPerSecond: 1000})}
+	result, err = executeTool.Execute(context.Background(), map[string]interface{}{"plan_id": planned.PlanID})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"status":"completed"`)
+
+	statusTool := &BulkStatusTool{plans: plans}
+	result, err = statusTool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, planned.PlanID)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestBulkPlanTool_RejectsUnsupportedMove(t *testing.T) {
+	tool := &BulkPlanTool{client: new(MockClient), plans: bulk.NewMemoryStore()}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"operation":    "move",
+		"workspace_id": "workspace-1",
+		"target_id":    "collection-1",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestBulkOperationsTool_Execute_MoveReportsEveryItem(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &BulkOperationsTool{client: mockClient, executor: bulk.NewExecutor(mockClient, nil, bulk.ExecutorConfig{RequestsPerSecond: 1000, MaxRetries: 0})}
+
+	collection := &nuclino.Collection{ID: "source-collection", WorkspaceID: "workspace-123"}
+	mockClient.On("GetCollection", mock.Anything, "source-collection").Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, "workspace-123", listPageSize, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{
+			{ID: "item-1", CollectionID: "source-collection"},
+			{ID: "item-2", CollectionID: "source-collection"},
+			{ID: "item-3", CollectionID: "source-collection"},
+		},
+	}, nil)
+	mockClient.On("MoveItem", mock.Anything, "item-1", "target").Return(&nuclino.Item{ID: "item-1"}, nil)
+	mockClient.On("MoveItem", mock.Anything, "item-2", "target").Return((*nuclino.Item)(nil), nuclino.NewAPIError(403, "forbidden"))
+	mockClient.On("MoveItem", mock.Anything, "item-3", "target").Return(&nuclino.Item{ID: "item-3"}, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"operation":         "move",
+		"source_collection": "source-collection",
+		"target_collection": "target",
+		"dry_run":           false,
+		"atomic":            false,
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"applied":2`)
+	assert.Contains(t, text, `"failed":1`)
+	assert.Contains(t, text, "forbidden")
+	mockClient.AssertExpectations(t)
+}
+
+func TestBulkOperationsTool_Execute_AtomicUndoesMoves(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &BulkOperationsTool{client: mockClient, executor: bulk.NewExecutor(mockClient, nil, bulk.ExecutorConfig{Concurrency: 1, RequestsPerSecond: 1000, MaxRetries: 0})}
+
+	collection := &nuclino.Collection{ID: "source-collection", WorkspaceID: "workspace-123"}
+	mockClient.On("GetCollection", mock.Anything, "source-collection").Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, "workspace-123", listPageSize, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{
+			{ID: "item-1", CollectionID: "source-collection"},
+			{ID: "item-2", CollectionID: "source-collection"},
+			{ID: "item-3", CollectionID: "source-collection"},
+		},
+	}, nil)
+	mockClient.On("MoveItem", mock.Anything, "item-1", "target").Return(&nuclino.Item{ID: "item-1"}, nil).Once()
+	mockClient.On("MoveItem", mock.Anything, "item-2", "target").Return((*nuclino.Item)(nil), nuclino.NewAPIError(403, "forbidden"))
+	mockClient.On("MoveItem", mock.Anything, "item-1", "source-collection").Return(&nuclino.Item{ID: "item-1"}, nil).Once()
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"operation":         "move",
+		"source_collection": "source-collection",
+		"target_collection": "target",
+		"dry_run":           false,
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"status":"rolled_back"`)
+	assert.Contains(t, text, `"compensated":1`)
+	assert.Contains(t, text, "forbidden")
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "MoveItem", mock.Anything, "item-3", "target")
+}
+
+func TestBulkOperationsTool_Execute_UpdateTags(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &BulkOperationsTool{client: mockClient, executor: bulk.NewExecutor(mockClient, nil, bulk.ExecutorConfig{RequestsPerSecond: 1000})}
+
+	tagged := nuclino.Item{ID: "item-2", CollectionID: "source-collection", Fields: map[string]interface{}{"Tags": []interface{}{"ops", "guide"}}}
+	collection := &nuclino.Collection{ID: "source-collection", WorkspaceID: "workspace-123"}
+	mockClient.On("GetCollection", mock.Anything, "source-collection").Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, "workspace-123", listPageSize, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{{ID: "item-1", CollectionID: "source-collection"}, tagged},
+	}, nil)
+
+	args := map[string]interface{}{
+		"operation":         "update_tags",
+		"source_collection": "source-collection",
+		"tags":              "ops, guide",
+	}
+	result, err := tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"items_to_update":["item-1"]`)
+
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(&nuclino.Item{ID: "item-1"}, nil)
+	mockClient.On("UpdateItem", mock.Anything, "item-1", mock.MatchedBy(func(req *nuclino.UpdateItemRequest) bool {
+		return assert.ObjectsAreEqual([]interface{}{"ops", "guide"}, req.Fields["Tags"])
+	})).Return(&nuclino.Item{ID: "item-1"}, nil)
+
+	args["dry_run"] = false
+	result, err = tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"applied":1`)
+	mockClient.AssertExpectations(t)
+}
@@ -21,32 +21,22 @@ func (t *ListCollectionsTool) Description() string {
 	return "List all collections in a Nuclino workspace with pagination support"
 }
 
-func (t *ListCollectionsTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id": StringProperty("The ID of the workspace to list collections from"),
-		"limit":        IntProperty("Maximum number of collections to return (default: 50)"),
-		"offset":       IntProperty("Number of collections to skip for pagination (default: 0)"),
-	}, []string{"workspace_id"})
+// ListCollectionsArgs are the arguments of nuclino_list_collections
+type ListCollectionsArgs struct {
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to list collections from" validate:"required"`
+	pageArgs
 }
 
-func (t *ListCollectionsTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
-
-	limit := 50
-	offset := 0
-
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
-	}
+func (t *ListCollectionsTool) InputSchema() interface{} {
+	return SchemaOf[ListCollectionsArgs]()
+}
 
-	if o, ok := args["offset"].(float64); ok {
-		offset = int(o)
-	}
+func (t *ListCollectionsTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	collections, err := t.client.ListCollections(context.Background(), workspaceID, limit, offset)
+func (t *ListCollectionsTool) run(ctx context.Context, args *ListCollectionsArgs) (*mcp.CallToolResult, error) {
+	collections, err := t.client.ListCollections(ctx, args.WorkspaceID, args.Limit, args.Offset)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -67,19 +57,21 @@ func (t *GetCollectionTool) Description() string {
 	return "Get detailed information about a specific Nuclino collection"
 }
 
+// GetCollectionArgs are the arguments of nuclino_get_collection
+type GetCollectionArgs struct {
+	CollectionID string `json:"collection_id" desc:"The ID of the collection to retrieve" validate:"required"`
+}
+
 func (t *GetCollectionTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"collection_id": StringProperty("The ID of the collection to retrieve"),
-	}, []string{"collection_id"})
+	return SchemaOf[GetCollectionArgs]()
 }
 
-func (t *GetCollectionTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	collectionID, ok := args["collection_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("collection_id must be a string"))
-	}
+func (t *GetCollectionTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	collection, err := t.client.GetCollection(context.Background(), collectionID)
+func (t *GetCollectionTool) run(ctx context.Context, args *GetCollectionArgs) (*mcp.CallToolResult, error) {
+	collection, err := t.client.GetCollection(ctx, args.CollectionID)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -100,30 +92,29 @@ func (t *CreateCollectionTool) Description() string {
 	return "Create a new Nuclino collection within a workspace"
 }
 
-func (t *CreateCollectionTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"title":        StringProperty("The title of the new collection"),
-		"workspace_id": StringProperty("The ID of the workspace to create the collection in"),
-	}, []string{"title", "workspace_id"})
+// CreateCollectionArgs are the arguments of nuclino_create_collection
+type CreateCollectionArgs struct {
+	Title       string `json:"title" desc:"The title of the new collection" validate:"required"`
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to create the collection in" validate:"required"`
+	ParentID    string `json:"parent_id" desc:"Optional ID of a parent collection to nest the new collection in"`
 }
 
-func (t *CreateCollectionTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	title, ok := args["title"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("title must be a string"))
-	}
+func (t *CreateCollectionTool) InputSchema() interface{} {
+	return SchemaOf[CreateCollectionArgs]()
+}
 
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
+func (t *CreateCollectionTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
+func (t *CreateCollectionTool) run(ctx context.Context, args *CreateCollectionArgs) (*mcp.CallToolResult, error) {
 	req := &nuclino.CreateCollectionRequest{
-		Title:       title,
-		WorkspaceID: workspaceID,
+		Title:       args.Title,
+		WorkspaceID: args.WorkspaceID,
+		ParentID:    args.ParentID,
 	}
 
-	collection, err := t.client.CreateCollection(context.Background(), req)
+	collection, err := t.client.CreateCollection(ctx, req)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -144,29 +135,26 @@ func (t *UpdateCollectionTool) Description() string {
 	return "Update an existing Nuclino collection (currently supports title changes)"
 }
 
-func (t *UpdateCollectionTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"collection_id": StringProperty("The ID of the collection to update"),
-		"title":         StringProperty("The new title for the collection"),
-	}, []string{"collection_id", "title"})
+// UpdateCollectionArgs are the arguments of nuclino_update_collection
+type UpdateCollectionArgs struct {
+	CollectionID string `json:"collection_id" desc:"The ID of the collection to update" validate:"required"`
+	Title        string `json:"title" desc:"The new title for the collection" validate:"required"`
 }
 
-func (t *UpdateCollectionTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	collectionID, ok := args["collection_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("collection_id must be a string"))
-	}
+func (t *UpdateCollectionTool) InputSchema() interface{} {
+	return SchemaOf[UpdateCollectionArgs]()
+}
 
-	title, ok := args["title"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("title must be a string"))
-	}
+func (t *UpdateCollectionTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
+func (t *UpdateCollectionTool) run(ctx context.Context, args *UpdateCollectionArgs) (*mcp.CallToolResult, error) {
 	req := &nuclino.UpdateCollectionRequest{
-		Title: &title,
+		Title: &args.Title,
 	}
 
-	collection, err := t.client.UpdateCollection(context.Background(), collectionID, req)
+	collection, err := t.client.UpdateCollection(ctx, args.CollectionID, req)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -184,34 +172,60 @@ func (t *DeleteCollectionTool) Name() string {
 }
 
 func (t *DeleteCollectionTool) Description() string {
-	return "Delete a Nuclino collection. WARNING: This will also delete all items in the collection."
+	return "Delete a Nuclino collection. WARNING: This will also delete all items in the collection. The first call returns what would be deleted and a confirm_token; call again with the token to delete."
 }
 
-func (t *DeleteCollectionTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"collection_id": StringProperty("The ID of the collection to delete"),
-		"confirm":       BoolProperty("Confirmation that you want to delete the collection (must be true)"),
-	}, []string{"collection_id", "confirm"})
+// DeleteCollectionArgs are the arguments of nuclino_delete_collection
+type DeleteCollectionArgs struct {
+	CollectionID string `json:"collection_id" desc:"The ID of the collection to delete" validate:"required"`
 }
 
-func (t *DeleteCollectionTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	collectionID, ok := args["collection_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("collection_id must be a string"))
-	}
+func (t *DeleteCollectionTool) InputSchema() interface{} {
+	return SchemaOf[DeleteCollectionArgs]()
+}
 
-	confirm, ok := args["confirm"].(bool)
-	if !ok || !confirm {
-		return FormatError(fmt.Errorf("you must set confirm=true to delete a collection"))
-	}
+func (t *DeleteCollectionTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	err := t.client.DeleteCollection(context.Background(), collectionID)
+func (t *DeleteCollectionTool) run(ctx context.Context, args *DeleteCollectionArgs) (*mcp.CallToolResult, error) {
+	err := t.client.DeleteCollection(ctx, args.CollectionID)
 	if err != nil {
 		return FormatError(err)
 	}
 
 	return FormatResult(map[string]string{
 		"status":  "success",
-		"message": fmt.Sprintf("Collection %s has been deleted successfully", collectionID),
+		"message": fmt.Sprintf("Collection %s has been deleted successfully", args.CollectionID),
 	})
 }
+
+// listCollectionItems pages through the collection's workspace and keeps
+// the items that belong to the collection
+func listCollectionItems(ctx context.Context, client nuclino.Client, collection *nuclino.Collection) ([]nuclino.Item, error) {
+	items, err := nuclino.ListAllItems(ctx, client, collection.WorkspaceID, listPageSize)
+	if err != nil {
+		return nil, err
+	}
+	var filtered []nuclino.Item
+	for _, item := range items {
+		if inCollection(collection, item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// inCollection reports whether an item belongs to a collection, either
+// through the collection's childIds or the item's collectionId
+func inCollection(collection *nuclino.Collection, item nuclino.Item) bool {
+	if item.CollectionID != "" && item.CollectionID == collection.ID {
+		return true
+	}
+	for _, id := range collection.ChildIDs {
+		if id == item.ID {
+			return true
+		}
+	}
+	return false
+}
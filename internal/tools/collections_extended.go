@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/dedup"
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -22,68 +25,46 @@ func (t *GetCollectionOverviewTool) Description() string {
 	return "Get comprehensive overview of a Nuclino collection including item count, content statistics, and recent activity"
 }
 
-func (t *GetCollectionOverviewTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"collection_id":      StringProperty("The ID of the collection to analyze"),
-		"include_statistics": BoolProperty("Whether to include content statistics (default: true)"),
-		"include_recent":     BoolProperty("Whether to include recent items (default: true)"),
-		"recent_limit":       IntProperty("Number of recent items to include (default: 5)"),
-	}, []string{"collection_id"})
+// GetCollectionOverviewArgs are the arguments of nuclino_get_collection_overview
+type GetCollectionOverviewArgs struct {
+	CollectionID      string `json:"collection_id" desc:"The ID of the collection to analyze" validate:"required"`
+	IncludeStatistics bool   `json:"include_statistics" desc:"Whether to include content statistics (default: true)" default:"true"`
+	IncludeRecent     bool   `json:"include_recent" desc:"Whether to include recent items (default: true)" default:"true"`
+	RecentLimit       int    `json:"recent_limit" desc:"Number of recent items to include (default: 5)" validate:"min=0" default:"5"`
 }
 
-func (t *GetCollectionOverviewTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	collectionID, ok := args["collection_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("collection_id must be a string"))
-	}
-
-	includeStats := true
-	if include, ok := args["include_statistics"].(bool); ok {
-		includeStats = include
-	}
-
-	includeRecent := true
-	if include, ok := args["include_recent"].(bool); ok {
-		includeRecent = include
-	}
+func (t *GetCollectionOverviewTool) InputSchema() interface{} {
+	return SchemaOf[GetCollectionOverviewArgs]()
+}
 
-	recentLimit := 5
-	if limit, ok := args["recent_limit"].(float64); ok {
-		recentLimit = int(limit)
-	}
+func (t *GetCollectionOverviewTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
+func (t *GetCollectionOverviewTool) run(ctx context.Context, args *GetCollectionOverviewArgs) (*mcp.CallToolResult, error) {
 	// Get collection info
-	collection, err := t.client.GetCollection(context.Background(), collectionID)
+	collection, err := t.client.GetCollection(ctx, args.CollectionID)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	// Get all items in the workspace to filter by collection
-	items, err := t.client.ListItems(context.Background(), collection.WorkspaceID, 1000, 0)
+	collectionItems, err := listCollectionItems(ctx, t.client, collection)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	// Filter items by collection ID
-	var collectionItems []nuclino.Item
-	for _, item := range items.Results {
-		if item.CollectionID == collectionID {
-			collectionItems = append(collectionItems, item)
-		}
-	}
-
 	overview := map[string]interface{}{
 		"collection": collection,
 		"item_count": len(collectionItems),
 		"items":      collectionItems,
 	}
 
-	if includeStats && len(collectionItems) > 0 {
+	if args.IncludeStatistics && len(collectionItems) > 0 {
 		stats := calculateContentStats(collectionItems)
 		overview["statistics"] = stats
 	}
 
-	if includeRecent && len(collectionItems) > 0 {
+	if args.IncludeRecent && len(collectionItems) > 0 {
 		// Sort by updated time (most recent first) - simplified sorting by assuming newer IDs = more recent
 		sortedItems := make([]nuclino.Item, len(collectionItems))
 		copy(sortedItems, collectionItems)
@@ -93,7 +74,7 @@ func (t *GetCollectionOverviewTool) Execute(args map[string]interface{}) (*mcp.C
 			return sortedItems[i].ID > sortedItems[j].ID // Simplified - newer IDs first
 		})
 
-		limit := recentLimit
+		limit := args.RecentLimit
 		if limit > len(sortedItems) {
 			limit = len(sortedItems)
 		}
@@ -117,72 +98,50 @@ func (t *OrganizeCollectionTool) Description() string {
 	return "Analyze and provide organization suggestions for a Nuclino collection based on content patterns"
 }
 
-func (t *OrganizeCollectionTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"collection_id":     StringProperty("The ID of the collection to analyze"),
-		"suggest_tags":      BoolProperty("Whether to suggest content tags (default: true)"),
-		"find_duplicates":   BoolProperty("Whether to find potential duplicate items (default: true)"),
-		"analyze_structure": BoolProperty("Whether to analyze content structure (default: true)"),
-	}, []string{"collection_id"})
+// OrganizeCollectionArgs are the arguments of nuclino_organize_collection
+type OrganizeCollectionArgs struct {
+	CollectionID     string `json:"collection_id" desc:"The ID of the collection to analyze" validate:"required"`
+	SuggestTags      bool   `json:"suggest_tags" desc:"Whether to suggest content tags (default: true)" default:"true"`
+	FindDuplicates   bool   `json:"find_duplicates" desc:"Whether to find potential duplicate items (default: true)" default:"true"`
+	AnalyzeStructure bool   `json:"analyze_structure" desc:"Whether to analyze content structure (default: true)" default:"true"`
 }
 
-func (t *OrganizeCollectionTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	collectionID, ok := args["collection_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("collection_id must be a string"))
-	}
-
-	suggestTags := true
-	if suggest, ok := args["suggest_tags"].(bool); ok {
-		suggestTags = suggest
-	}
-
-	findDuplicates := true
-	if find, ok := args["find_duplicates"].(bool); ok {
-		findDuplicates = find
-	}
+func (t *OrganizeCollectionTool) InputSchema() interface{} {
+	return SchemaOf[OrganizeCollectionArgs]()
+}
 
-	analyzeStructure := true
-	if analyze, ok := args["analyze_structure"].(bool); ok {
-		analyzeStructure = analyze
-	}
+func (t *OrganizeCollectionTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
+func (t *OrganizeCollectionTool) run(ctx context.Context, args *OrganizeCollectionArgs) (*mcp.CallToolResult, error) {
 	// Get collection info
-	collection, err := t.client.GetCollection(context.Background(), collectionID)
+	collection, err := t.client.GetCollection(ctx, args.CollectionID)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	// Get collection items
-	items, err := t.client.ListItems(context.Background(), collection.WorkspaceID, 1000, 0)
+	collectionItems, err := listCollectionItems(ctx, t.client, collection)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	// Filter by collection
-	var collectionItems []nuclino.Item
-	for _, item := range items.Results {
-		if item.CollectionID == collectionID {
-			collectionItems = append(collectionItems, item)
-		}
-	}
-
 	organization := map[string]interface{}{
 		"collection":  collection,
 		"total_items": len(collectionItems),
 	}
 
-	if suggestTags && len(collectionItems) > 0 {
+	if args.SuggestTags && len(collectionItems) > 0 {
 		tags := suggestContentTags(collectionItems)
 		organization["suggested_tags"] = tags
 	}
 
-	if findDuplicates && len(collectionItems) > 1 {
+	if args.FindDuplicates && len(collectionItems) > 1 {
 		duplicates := findPotentialDuplicates(collectionItems)
 		organization["potential_duplicates"] = duplicates
 	}
 
-	if analyzeStructure && len(collectionItems) > 0 {
+	if args.AnalyzeStructure && len(collectionItems) > 0 {
 		structure := analyzeContentStructure(collectionItems)
 		organization["content_structure"] = structure
 	}
@@ -192,7 +151,8 @@ func (t *OrganizeCollectionTool) Execute(args map[string]interface{}) (*mcp.Call
 
 // BulkOperationsTool provides batch operations for collections
 type BulkOperationsTool struct {
-	client nuclino.Client
+	client   nuclino.Client
+	executor *bulk.Executor
 }
 
 func (t *BulkOperationsTool) Name() string {
@@ -203,53 +163,41 @@ func (t *BulkOperationsTool) Description() string {
 	return "Perform bulk operations on collection items like batch moving, updating, or organizing"
 }
 
-func (t *BulkOperationsTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"operation":         StringProperty("Operation type: 'move', 'update_tags', 'organize'"),
-		"source_collection": StringProperty("Source collection ID"),
-		"target_collection": StringProperty("Target collection ID (for move operations)"),
-		"filter_query":      StringProperty("Optional query to filter items for operation"),
-		"dry_run":           BoolProperty("Whether to perform a dry run (default: true)"),
-	}, []string{"operation", "source_collection"})
+// BulkOperationsArgs are the arguments of nuclino_bulk_collection_operations
+type BulkOperationsArgs struct {
+	Operation        string  `json:"operation" desc:"Operation type: 'move', 'update_tags', 'organize'" validate:"required,oneof=move update_tags organize"`
+	SourceCollection string  `json:"source_collection" desc:"Source collection ID" validate:"required"`
+	TargetCollection string  `json:"target_collection" desc:"Target collection ID (for move operations)"`
+	Tags             *string `json:"tags" desc:"Comma-separated tags to set on the Tags field (for update_tags operations)"`
+	FilterQuery      string  `json:"filter_query" desc:"Optional query to filter items for operation"`
+	DryRun           bool    `json:"dry_run" desc:"Whether to perform a dry run (default: true)" default:"true"`
+	Atomic           bool    `json:"atomic" desc:"Undo the items already changed if any item fails (default: true)" default:"true"`
 }
 
-func (t *BulkOperationsTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	operation, ok := args["operation"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("operation must be a string"))
-	}
+func (t *BulkOperationsTool) InputSchema() interface{} {
+	return SchemaOf[BulkOperationsArgs]()
+}
 
-	sourceCollection, ok := args["source_collection"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("source_collection must be a string"))
-	}
+func (t *BulkOperationsTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	dryRun := true
-	if dry, ok := args["dry_run"].(bool); ok {
-		dryRun = dry
-	}
+func (t *BulkOperationsTool) run(ctx context.Context, args *BulkOperationsArgs) (*mcp.CallToolResult, error) {
+	dryRun := args.DryRun
 
 	// Get source collection
-	collection, err := t.client.GetCollection(context.Background(), sourceCollection)
+	collection, err := t.client.GetCollection(ctx, args.SourceCollection)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	// Get items in collection
-	items, err := t.client.ListItems(context.Background(), collection.WorkspaceID, 1000, 0)
+	collectionItems, err := listCollectionItems(ctx, t.client, collection)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	var collectionItems []nuclino.Item
-	for _, item := range items.Results {
-		if item.CollectionID == sourceCollection {
-			collectionItems = append(collectionItems, item)
-		}
-	}
-
 	// Filter items if query provided
-	if filterQuery, ok := args["filter_query"].(string); ok && filterQuery != "" {
+	if filterQuery := args.FilterQuery; filterQuery != "" {
 		var filteredItems []nuclino.Item
 		for _, item := range collectionItems {
 			if containsIgnoreCase(item.Title, filterQuery) || containsIgnoreCase(item.Content, filterQuery) {
@@ -260,32 +208,43 @@ func (t *BulkOperationsTool) Execute(args map[string]interface{}) (*mcp.CallTool
 	}
 
 	result := map[string]interface{}{
-		"operation":   operation,
-		"source":      sourceCollection,
+		"operation":   args.Operation,
+		"source":      args.SourceCollection,
 		"items_found": len(collectionItems),
 		"dry_run":     dryRun,
 	}
 
-	switch operation {
+	switch args.Operation {
 	case "move":
-		targetCollection, ok := args["target_collection"].(string)
-		if !ok {
-			return FormatError(fmt.Errorf("target_collection required for move operation"))
+		targetCollection := args.TargetCollection
+		if targetCollection == "" {
+			return FormatArgsError(toolargs.Errors{{Field: "target_collection", Message: "is required for move operations"}})
 		}
 
 		result["target"] = targetCollection
 
 		if !dryRun {
+			op, err := bulk.NewOperation(bulk.OperationSpec{Type: bulk.OpMove, TargetID: targetCollection})
+			if err != nil {
+				return FormatError(err)
+			}
+			// The API leaves collectionId empty; record the source so the
+			// moves can be rolled back
+			for i := range collectionItems {
+				collectionItems[i].CollectionID = args.SourceCollection
+			}
+			outcome, err := t.apply(ctx, op, collectionItems, args.Atomic)
+			if err != nil {
+				return FormatError(err)
+			}
 			var movedItems []string
-			for _, item := range collectionItems {
-				_, err := t.client.MoveItem(context.Background(), item.ID, targetCollection)
-				if err != nil {
-					result["error"] = fmt.Sprintf("Failed to move item %s: %v", item.ID, err)
-					break
+			for _, step := range outcome.Steps {
+				if step.Status == bulk.StepApplied {
+					movedItems = append(movedItems, step.ItemID)
 				}
-				movedItems = append(movedItems, item.ID)
 			}
 			result["moved_items"] = movedItems
+			result["outcome"] = bulkOutcome(outcome)
 		} else {
 			var itemsToMove []string
 			for _, item := range collectionItems {
@@ -294,17 +253,73 @@ func (t *BulkOperationsTool) Execute(args map[string]interface{}) (*mcp.CallTool
 			result["items_to_move"] = itemsToMove
 		}
 
+	case "update_tags":
+		if args.Tags == nil {
+			return FormatArgsError(toolargs.Errors{{Field: "tags", Message: "is required for update_tags operations"}})
+		}
+		tags := []interface{}{}
+		for _, tag := range splitIDs(*args.Tags) {
+			tags = append(tags, tag)
+		}
+
+		op, err := bulk.NewOperation(bulk.OperationSpec{Type: bulk.OpSetField, Field: tagsField, Value: tags})
+		if err != nil {
+			return FormatError(err)
+		}
+		result["tags"] = tags
+
+		if !dryRun {
+			outcome, err := t.apply(ctx, op, collectionItems, args.Atomic)
+			if err != nil {
+				return FormatError(err)
+			}
+			result["outcome"] = bulkOutcome(outcome)
+		} else {
+			var itemsToUpdate []string
+			for i := range collectionItems {
+				if op.Apply(&collectionItems[i]) != nil {
+					itemsToUpdate = append(itemsToUpdate, collectionItems[i].ID)
+				}
+			}
+			result["items_to_update"] = itemsToUpdate
+		}
+
 	case "organize":
 		suggestions := generateOrganizationSuggestions(collectionItems)
 		result["suggestions"] = suggestions
-
-	default:
-		return FormatError(fmt.Errorf("unknown operation: %s", operation))
 	}
 
 	return FormatResult(result)
 }
 
+// tagsField is the item field update_tags writes
+const tagsField = "Tags"
+
+// apply runs an operation on the selected items through the bulk executor so
+// every item gets its own outcome instead of stopping at the first error. In
+// atomic mode a failure undoes the items already changed.
+func (t *BulkOperationsTool) apply(ctx context.Context, op bulk.Operation, items []nuclino.Item, atomic bool) (*bulk.Result, error) {
+	selected := make([]*nuclino.Item, len(items))
+	for i := range items {
+		selected[i] = &items[i]
+	}
+	plan := bulk.NewPlan(bulk.Selector{ItemIDs: itemIDs(items)}, op, selected)
+
+	executor := t.executor
+	if executor == nil {
+		executor = bulk.NewExecutor(t.client, nil, bulk.DefaultExecutorConfig())
+	}
+	return executor.Execute(ctx, plan, bulk.Options{Atomic: atomic})
+}
+
+func itemIDs(items []nuclino.Item) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
 // Helper functions for content analysis
 func calculateContentStats(items []nuclino.Item) map[string]interface{} {
 	totalChars := 0
@@ -370,14 +385,20 @@ func suggestContentTags(items []nuclino.Item) []string {
 }
 
 func findPotentialDuplicates(items []nuclino.Item) [][]string {
-	var duplicates [][]string
+	detector, err := dedup.NewDetector(dedup.DefaultConfig())
+	if err != nil {
+		return nil
+	}
 
-	for i := 0; i < len(items); i++ {
-		for j := i + 1; j < len(items); j++ {
-			similarity := calculateSimilarity(items[i].Title, items[j].Title)
-			if similarity > 0.7 { // 70% similarity threshold
-				duplicates = append(duplicates, []string{items[i].ID, items[j].ID})
-			}
+	docs := make([]dedup.Document, 0, len(items))
+	for _, item := range items {
+		docs = append(docs, dedup.Document{ID: item.ID, Title: item.Title, Content: item.Content})
+	}
+
+	duplicates := [][]string{}
+	for _, cluster := range detector.Find(docs) {
+		for _, pair := range cluster.Pairs {
+			duplicates = append(duplicates, []string{pair.A, pair.B})
 		}
 	}
 
@@ -461,31 +482,6 @@ func splitWords(text string) []string {
 	return words
 }
 
-func calculateSimilarity(str1, str2 string) float64 {
-	if str1 == str2 {
-		return 1.0
-	}
-
-	// Simple similarity based on common characters
-	common := 0
-	total := len(str1) + len(str2)
-
-	if total == 0 {
-		return 1.0
-	}
-
-	for _, char1 := range str1 {
-		for _, char2 := range str2 {
-			if char1 == char2 {
-				common++
-				break
-			}
-		}
-	}
-
-	return float64(common*2) / float64(total)
-}
-
 func min(a, b int) int {
 	if a < b {
 		return a
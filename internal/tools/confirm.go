@@ -0,0 +1,373 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+)
+
+// confirmArg carries the token of a previewed destructive call
+const confirmArg = "confirm_token"
+
+// DefaultConfirmTTL is how long a confirmation token stays valid
+const DefaultConfirmTTL = 5 * time.Minute
+
+// Preview limits: how many items are counted and how many titles listed
+const (
+	previewPageSize  = 100
+	previewMaxItems  = 5000
+	previewMaxTitles = 10
+)
+
+// Elicitor asks the user directly through the MCP client, for clients that
+// support elicitation
+type Elicitor interface {
+	// Confirm shows message and reports whether the user accepted; action
+	// describes what accepting does, for the confirmation field
+	Confirm(ctx context.Context, message, action string) (bool, error)
+}
+
+type elicitorKey struct{}
+
+// WithElicitor returns a context whose destructive tool calls are confirmed
+// by asking the user through e instead of with a token
+func WithElicitor(ctx context.Context, e Elicitor) context.Context {
+	return context.WithValue(ctx, elicitorKey{}, e)
+}
+
+// ElicitorFrom returns the Elicitor of ctx, or nil when the client cannot
+// be asked directly
+func ElicitorFrom(ctx context.Context) Elicitor {
+	e, _ := ctx.Value(elicitorKey{}).(Elicitor)
+	return e
+}
+
+// Impact describes what a destructive call would remove or overwrite
+type Impact struct {
+	Kind string `json:"kind"`
+	// Action is what happens to the affected content: delete, or overwrite
+	// for merged items replaced with a link to the kept item
+	Action      string   `json:"action"`
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Items       int      `json:"items"`
+	Collections int      `json:"collections"`
+	Titles      []string `json:"titles,omitempty"`
+	// Truncated is set when counting stopped at the preview limit
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Summary is a one-line description for prompts
+func (i *Impact) Summary() string {
+	switch i.Kind {
+	case "item":
+		return fmt.Sprintf("item %q (%s)", i.Title, i.ID)
+	case "merge":
+		return fmt.Sprintf("%d items merged into %q (%s): %s", i.Items+i.Collections, i.Title, i.ID, strings.Join(i.Titles, ", "))
+	}
+	more := ""
+	if i.Truncated {
+		more = " or more"
+	}
+	return fmt.Sprintf("%s %q (%s) with %d items and %d collections%s", i.Kind, i.Title, i.ID, i.Items, i.Collections, more)
+}
+
+// warning tells the user what happens to the affected content
+func (i *Impact) warning() string {
+	switch {
+	case i.Kind == "item":
+		return "The item is moved to the trash."
+	case i.Kind == "merge" && i.Action == actionDelete:
+		return "The merged items are moved to the trash."
+	case i.Kind == "merge":
+		return "Their content is replaced with a link to the kept item."
+	}
+	return "This cannot be undone."
+}
+
+// consent describes what accepting the confirmation does
+func (i *Impact) consent() string {
+	if i.Action == actionOverwrite {
+		return "Proceed and overwrite the merged items"
+	}
+	return "Proceed with the deletion"
+}
+
+// Impact actions
+const (
+	actionDelete    = "delete"
+	actionOverwrite = "overwrite"
+)
+
+// previewer finds what a destructive call would affect. A nil impact means
+// the call changes nothing, such as a dry run, and needs no confirmation.
+type previewer func(ctx context.Context, client nuclino.Client, args map[string]interface{}) (*Impact, error)
+
+// confirmedTools are the destructive tools that need a confirmed preview.
+// Bulk changes have their own plan step.
+var confirmedTools = map[string]previewer{
+	"nuclino_delete_item":       previewItem("item_id"),
+	"nuclino_delete_collection": previewItem("collection_id"),
+	"nuclino_delete_workspace":  previewWorkspace,
+	"nuclino_merge_duplicates":  previewMerge,
+}
+
+// confirmation is a pending destructive call
+type confirmation struct {
+	tool        string
+	fingerprint string
+	expires     time.Time
+}
+
+// confirmations holds short-lived single-use tokens
+type confirmations struct {
+	mu     sync.Mutex
+	tokens map[string]confirmation
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func newConfirmations(ttl time.Duration) *confirmations {
+	if ttl <= 0 {
+		ttl = DefaultConfirmTTL
+	}
+	return &confirmations{tokens: make(map[string]confirmation), ttl: ttl, now: time.Now}
+}
+
+// issue returns a token for the call, dropping expired ones
+func (c *confirmations) issue(tool, fingerprint string) (string, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for token, pending := range c.tokens {
+		if now.After(pending.expires) {
+			delete(c.tokens, token)
+		}
+	}
+
+	raw := make([]byte, 12)
+	_, _ = rand.Read(raw)
+	token := hex.EncodeToString(raw)
+	expires := now.Add(c.ttl)
+	c.tokens[token] = confirmation{tool: tool, fingerprint: fingerprint, expires: expires}
+	return token, expires
+}
+
+// redeem consumes a token issued for the same call
+func (c *confirmations) redeem(token, tool, fingerprint string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.tokens[token]
+	if !ok {
+		return fmt.Errorf("unknown or already used confirm_token; call %s without it for a new preview", tool)
+	}
+	if c.now().After(pending.expires) {
+		delete(c.tokens, token)
+		return fmt.Errorf("confirm_token expired; call %s without it for a new preview", tool)
+	}
+	if pending.tool != tool || pending.fingerprint != fingerprint {
+		return fmt.Errorf("confirm_token was issued for a different call; call %s without it for a new preview", tool)
+	}
+	delete(c.tokens, token)
+	return nil
+}
+
+// fingerprint identifies a call by its arguments, without the token
+func fingerprint(args map[string]interface{}) string {
+	// Map keys are marshalled in sorted order
+	data, _ := json.Marshal(withoutArg(args, confirmArg))
+	return string(data)
+}
+
+// confirm runs the confirmation step of a destructive call. It returns a
+// result to send back instead of running the tool, or nil when the call is
+// confirmed and may run.
+func (r *Registry) confirm(ctx context.Context, name string, preview previewer, args map[string]interface{}) *mcp.CallToolResult {
+	call := fingerprint(args)
+	if token, _ := args[confirmArg].(string); token != "" {
+		if err := r.pending.redeem(token, name, call); err != nil {
+			result, _ := FormatError(err)
+			return result
+		}
+		return nil
+	}
+
+	impact, err := preview(ctx, r.client, args)
+	if err != nil {
+		result, _ := FormatError(fmt.Errorf("failed to preview %s: %w", name, err))
+		return result
+	}
+	if impact == nil {
+		return nil
+	}
+
+	if elicitor := ElicitorFrom(ctx); elicitor != nil {
+		verb := strings.ToUpper(impact.Action[:1]) + impact.Action[1:]
+		accepted, err := elicitor.Confirm(ctx, fmt.Sprintf("%s %s? %s", verb, impact.Summary(), impact.warning()), impact.consent())
+		if err == nil {
+			if accepted {
+				return nil
+			}
+			result, _ := FormatResult(map[string]interface{}{
+				"status":   "cancelled",
+				"message":  "The user declined to " + impact.Action + " " + impact.Summary(),
+				"affected": impact,
+			})
+			return result
+		}
+		log.Warn().Err(err).Str("tool", name).Msg("Elicitation failed, falling back to a confirmation token")
+	}
+
+	token, expires := r.pending.issue(name, call)
+	result, _ := FormatResult(map[string]interface{}{
+		"status":        "confirmation_required",
+		"message":       fmt.Sprintf("This would %s %s. Show this to the user and, once they agree, call %s again with the same arguments and confirm_token.", impact.Action, impact.Summary(), name),
+		"affected":      impact,
+		"confirm_token": token,
+		"expires_at":    expires.UTC().Format(time.RFC3339),
+	})
+	return result
+}
+
+// previewItem previews deleting an item or collection named by arg,
+// counting the collection's descendants
+func previewItem(arg string) previewer {
+	return func(ctx context.Context, client nuclino.Client, args map[string]interface{}) (*Impact, error) {
+		id, _ := args[arg].(string)
+		if id == "" {
+			return nil, fmt.Errorf("%s must be a non-empty string", arg)
+		}
+		item, err := client.GetItem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		impact := &Impact{Kind: "item", Action: actionDelete, ID: item.ID, Title: item.Title}
+		if !item.IsCollection() {
+			return impact, nil
+		}
+		impact.Kind = "collection"
+
+		items, truncated, err := workspaceItems(ctx, client, item.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		impact.Truncated = truncated
+		byID := make(map[string]*nuclino.Item, len(items))
+		for i := range items {
+			byID[items[i].ID] = &items[i]
+		}
+		queue := append([]string(nil), item.ChildIDs...)
+		seen := map[string]bool{item.ID: true}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			child, ok := byID[id]
+			if !ok {
+				impact.Items++
+				continue
+			}
+			impact.count(child)
+			queue = append(queue, child.ChildIDs...)
+		}
+		return impact, nil
+	}
+}
+
+// previewWorkspace previews deleting a workspace, counting its content
+func previewWorkspace(ctx context.Context, client nuclino.Client, args map[string]interface{}) (*Impact, error) {
+	id, _ := args["workspace_id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("workspace_id must be a non-empty string")
+	}
+	workspace, err := client.GetWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, truncated, err := workspaceItems(ctx, client, id)
+	if err != nil {
+		return nil, err
+	}
+	impact := &Impact{Kind: "workspace", Action: actionDelete, ID: workspace.ID, Title: workspace.Name, Truncated: truncated}
+	for i := range items {
+		impact.count(&items[i])
+	}
+	return impact, nil
+}
+
+// previewMerge previews merging duplicates, listing the merged items that
+// would be deleted or overwritten with a link. Dry runs need no preview.
+func previewMerge(ctx context.Context, client nuclino.Client, args map[string]interface{}) (*Impact, error) {
+	var merge MergeDuplicatesArgs
+	if err := toolargs.Decode(withoutArg(args, confirmArg), &merge); err != nil {
+		// The tool rejects the same arguments without changing anything
+		return nil, nil
+	}
+	if merge.DryRun {
+		return nil, nil
+	}
+
+	keep, err := client.GetItem(ctx, merge.KeepID)
+	if err != nil {
+		return nil, err
+	}
+	impact := &Impact{Kind: "merge", Action: actionOverwrite, ID: keep.ID, Title: keep.Title}
+	if merge.DeleteMerged {
+		impact.Action = actionDelete
+	}
+	for _, id := range splitIDs(merge.MergeIDs) {
+		if id == merge.KeepID {
+			continue
+		}
+		item, err := client.GetItem(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		impact.count(item)
+	}
+	return impact, nil
+}
+
+func (i *Impact) count(item *nuclino.Item) {
+	if item.IsCollection() {
+		i.Collections++
+	} else {
+		i.Items++
+	}
+	if len(i.Titles) < previewMaxTitles {
+		i.Titles = append(i.Titles, strings.TrimSpace(item.Title))
+	}
+}
+
+// workspaceItems pages through ListItems up to the preview limit
+func workspaceItems(ctx context.Context, client nuclino.Client, workspaceID string) ([]nuclino.Item, bool, error) {
+	var items []nuclino.Item
+	truncated := false
+	err := nuclino.EachItemPage(ctx, client, workspaceID, previewPageSize, func(page []nuclino.Item) bool {
+		items = append(items, page...)
+		truncated = len(items) >= previewMaxItems
+		return !truncated
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list items: %w", err)
+	}
+	return items, truncated, nil
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+type confirmPreview struct {
+	Status       string `json:"status"`
+	Affected     Impact `json:"affected"`
+	ConfirmToken string `json:"confirm_token"`
+}
+
+func decodePreview(t *testing.T, result *mcp.CallToolResult) confirmPreview {
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	var preview confirmPreview
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview))
+	return preview
+}
+
+func confirmClient() *MockClient {
+	mockClient := new(MockClient)
+	mockClient.On("GetItem", mock.Anything, col1ID).Return(&nuclino.Item{
+		ID: col1ID, Object: "collection", WorkspaceID: ws1ID, Title: "Docs", ChildIDs: []string{item1ID, col2ID},
+	}, nil)
+	mockClient.On("ListItems", mock.Anything, ws1ID, previewPageSize, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{
+			{ID: col1ID, Object: "collection", Title: "Docs", ChildIDs: []string{item1ID, col2ID}},
+			{ID: item1ID, Object: "item", Title: "Intro"},
+			{ID: col2ID, Object: "collection", Title: "Guides", ChildIDs: []string{item2ID}},
+			{ID: item2ID, Object: "item", Title: "Setup"},
+			{ID: item3ID, Object: "item", Title: "Elsewhere"},
+		},
+	}, nil)
+	return mockClient
+}
+
+func TestConfirmation_TokenFlow(t *testing.T) {
+	mockClient := confirmClient()
+	registry := NewRegistry(mockClient)
+	args := map[string]interface{}{"collection_id": col1ID}
+
+	result, err := registry.CallTool("nuclino_delete_collection", args)
+	require.NoError(t, err)
+	preview := decodePreview(t, result)
+	assert.Equal(t, "confirmation_required", preview.Status)
+	assert.Equal(t, 2, preview.Affected.Items)
+	assert.Equal(t, 1, preview.Affected.Collections)
+	assert.Equal(t, []string{"Intro", "Guides", "Setup"}, preview.Affected.Titles)
+	require.NotEmpty(t, preview.ConfirmToken)
+	mockClient.AssertNotCalled(t, "DeleteCollection", mock.Anything, mock.Anything)
+
+	// A token only confirms the call it was issued for
+	result, err = registry.CallTool("nuclino_delete_collection", map[string]interface{}{"collection_id": col2ID, "confirm_token": preview.ConfirmToken})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	// Mismatched tokens are consumed, so preview again
+	preview = decodePreview(t, mustCall(t, registry, "nuclino_delete_collection", args))
+	mockClient.On("DeleteCollection", mock.Anything, col1ID).Return(nil).Once()
+	result = mustCall(t, registry, "nuclino_delete_collection", map[string]interface{}{"collection_id": col1ID, "confirm_token": preview.ConfirmToken})
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "deleted successfully")
+
+	// Tokens are single-use
+	result = mustCall(t, registry, "nuclino_delete_collection", map[string]interface{}{"collection_id": col1ID, "confirm_token": preview.ConfirmToken})
+	assert.True(t, result.IsError)
+	mockClient.AssertNumberOfCalls(t, "DeleteCollection", 1)
+}
+
+func TestConfirmation_Expiry(t *testing.T) {
+	mockClient := new(MockClient)
+	mockClient.On("GetItem", mock.Anything, item1ID).Return(&nuclino.Item{ID: item1ID, Object: "item", Title: "Old"}, nil)
+	registry := NewRegistry(mockClient)
+	now := time.Now()
+	registry.pending.now = func() time.Time { return now }
+
+	preview := decodePreview(t, mustCall(t, registry, "nuclino_delete_item", map[string]interface{}{"item_id": item1ID}))
+	assert.Equal(t, "item", preview.Affected.Kind)
+
+	now = now.Add(DefaultConfirmTTL + time.Second)
+	result := mustCall(t, registry, "nuclino_delete_item", map[string]interface{}{"item_id": item1ID, "confirm_token": preview.ConfirmToken})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "expired")
+	mockClient.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything)
+}
+
+type fakeElicitor struct {
+	accept   bool
+	messages []string
+	actions  []string
+}
+
+func (e *fakeElicitor) Confirm(ctx context.Context, message, action string) (bool, error) {
+	e.messages = append(e.messages, message)
+	e.actions = append(e.actions, action)
+	return e.accept, nil
+}
+
+func TestConfirmation_Elicitation(t *testing.T) {
+	mockClient := confirmClient()
+	mockClient.On("DeleteCollection", mock.Anything, col1ID).Return(nil)
+	registry := NewRegistry(mockClient)
+	args := map[string]interface{}{"collection_id": col1ID}
+
+	elicitor := &fakeElicitor{}
+	result, err := registry.CallToolContext(WithElicitor(context.Background(), elicitor), "nuclino_delete_collection", args)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "cancelled")
+	require.Len(t, elicitor.messages, 1)
+	assert.Contains(t, elicitor.messages[0], `collection "Docs" (`+col1ID+`) with 2 items and 1 collections`)
+	assert.Equal(t, "Proceed with the deletion", elicitor.actions[0])
+	mockClient.AssertNotCalled(t, "DeleteCollection", mock.Anything, mock.Anything)
+
+	elicitor.accept = true
+	result, err = registry.CallToolContext(WithElicitor(context.Background(), elicitor), "nuclino_delete_collection", args)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "deleted successfully")
+	mockClient.AssertNumberOfCalls(t, "DeleteCollection", 1)
+}
+
+func TestConfirmation_MergeDuplicates(t *testing.T) {
+	mockClient := new(MockClient)
+	mockClient.On("GetItem", mock.Anything, item1ID).Return(&nuclino.Item{ID: item1ID, Object: "item", Title: "Setup", Content: "Install Go."}, nil)
+	mockClient.On("GetItem", mock.Anything, item2ID).Return(&nuclino.Item{ID: item2ID, Object: "item", Title: "Setup (copy)", Content: "Install Go.\n\nRun make."}, nil)
+	mockClient.On("GetItem", mock.Anything, item3ID).Return(&nuclino.Item{ID: item3ID, Object: "item", Title: "Old setup", Content: "Install Go."}, nil)
+	registry := NewRegistry(mockClient)
+	args := map[string]interface{}{
+		"keep_id": item1ID, "merge_ids": item2ID + "," + item3ID, "delete_merged": true, "dry_run": false,
+	}
+
+	// Dry runs change nothing and need no confirmation
+	result := mustCall(t, registry, "nuclino_merge_duplicates", map[string]interface{}{"keep_id": item1ID, "merge_ids": item2ID})
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "merged_content")
+
+	preview := decodePreview(t, mustCall(t, registry, "nuclino_merge_duplicates", args))
+	assert.Equal(t, "confirmation_required", preview.Status)
+	assert.Equal(t, "merge", preview.Affected.Kind)
+	assert.Equal(t, "delete", preview.Affected.Action)
+	assert.Equal(t, 2, preview.Affected.Items)
+	assert.Equal(t, []string{"Setup (copy)", "Old setup"}, preview.Affected.Titles)
+	mockClient.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything)
+
+	mockClient.On("UpdateItem", mock.Anything, item1ID, mock.Anything).Return(&nuclino.Item{ID: item1ID}, nil).Once()
+	mockClient.On("DeleteItem", mock.Anything, item2ID).Return(nil).Once()
+	mockClient.On("DeleteItem", mock.Anything, item3ID).Return(nil).Once()
+	confirmed := map[string]interface{}{"confirm_token": preview.ConfirmToken}
+	for key, value := range args {
+		confirmed[key] = value
+	}
+	result = mustCall(t, registry, "nuclino_merge_duplicates", confirmed)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	mockClient.AssertNumberOfCalls(t, "DeleteItem", 2)
+
+	// Asking the user directly names what gets overwritten
+	delete(args, "delete_merged")
+	elicitor := &fakeElicitor{}
+	result, err := registry.CallToolContext(WithElicitor(context.Background(), elicitor), "nuclino_merge_duplicates", args)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "cancelled")
+	require.Len(t, elicitor.messages, 1)
+	assert.Contains(t, elicitor.messages[0], `Overwrite 2 items merged into "Setup" (`+item1ID+`): Setup (copy), Old setup?`)
+	assert.Equal(t, []string{"Proceed and overwrite the merged items"}, elicitor.actions)
+	mockClient.AssertNumberOfCalls(t, "UpdateItem", 1)
+}
+
+func mustCall(t *testing.T, registry *Registry, name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	result, err := registry.CallTool(name, args)
+	require.NoError(t, err)
+	return result
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/dedup"
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FindDuplicatesTool finds near-duplicate items across a workspace
+type FindDuplicatesTool struct {
+	graphs *linkgraph.Builder
+}
+
+func (t *FindDuplicatesTool) Name() string {
+	return "nuclino_find_duplicates"
+}
+
+func (t *FindDuplicatesTool) Description() string {
+	return "Find near-duplicate items across a Nuclino workspace by comparing their content. Returns clusters with similarity scores, overlapping passages and a merge suggestion for each cluster."
+}
+
+// FindDuplicatesArgs are the arguments of nuclino_find_duplicates. Threshold
+// and MinWords fall back to the dedup defaults when omitted.
+type FindDuplicatesArgs struct {
+	WorkspaceID string   `json:"workspace_id" desc:"The ID of the workspace to scan" validate:"required"`
+	Threshold   *float64 `json:"threshold" desc:"Minimum similarity between 0 and 1 (default: 0.5)" validate:"min=0,max=1"`
+	MinWords    *int     `json:"min_words" desc:"Skip items with fewer words (default: 10)" validate:"min=0"`
+	MaxClusters int      `json:"max_clusters" desc:"Maximum number of clusters to return (default: 20)" validate:"min=1" default:"20"`
+	Refresh     bool     `json:"refresh" desc:"Reload items instead of using cached content (default: false)"`
+}
+
+func (t *FindDuplicatesTool) InputSchema() interface{} {
+	return SchemaOf[FindDuplicatesArgs]()
+}
+
+func (t *FindDuplicatesTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *FindDuplicatesTool) run(ctx context.Context, args *FindDuplicatesArgs) (*mcp.CallToolResult, error) {
+	config := dedup.DefaultConfig()
+	if args.Threshold != nil {
+		config.Threshold = *args.Threshold
+	}
+	if args.MinWords != nil {
+		config.MinWords = *args.MinWords
+	}
+
+	detector, err := dedup.NewDetector(config)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	graph, err := t.graphs.Build(ctx, args.WorkspaceID, args.Refresh)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	docs := make([]dedup.Document, 0, len(graph.Items))
+	for _, item := range graph.Items {
+		docs = append(docs, itemDocument(item))
+	}
+
+	clusters := detector.Find(docs)
+	total := len(clusters)
+	if len(clusters) > args.MaxClusters {
+		clusters = clusters[:args.MaxClusters]
+	}
+
+	return FormatResult(map[string]interface{}{
+		"workspace_id":   args.WorkspaceID,
+		"items_scanned":  len(docs),
+		"total_clusters": total,
+		"clusters":       clusters,
+		"next_step":      "Review a cluster, then call nuclino_merge_duplicates with its keep_id and merge ids",
+	})
+}
+
+// MergeDuplicatesTool folds the unique content of duplicate items into one item
+type MergeDuplicatesTool struct {
+	client nuclino.Client
+	graphs *linkgraph.Builder
+}
+
+func (t *MergeDuplicatesTool) Name() string {
+	return "nuclino_merge_duplicates"
+}
+
+func (t *MergeDuplicatesTool) Description() string {
+	return "Merge duplicate Nuclino items into one: paragraphs missing from the kept item are appended to it, and merged items are replaced with a link to the kept item or deleted. Runs as a dry run unless dry_run is false."
+}
+
+// MergeDuplicatesArgs are the arguments of nuclino_merge_duplicates
+type MergeDuplicatesArgs struct {
+	KeepID       string `json:"keep_id" desc:"The ID of the item to keep" validate:"required"`
+	MergeIDs     string `json:"merge_ids" desc:"Comma-separated IDs of the items to merge into the kept item" validate:"required"`
+	DeleteMerged bool   `json:"delete_merged" desc:"Delete merged items instead of replacing their content with a link (default: false)"`
+	DryRun       bool   `json:"dry_run" desc:"Only show the merged content without changing anything (default: true)" default:"true"`
+}
+
+func (t *MergeDuplicatesTool) InputSchema() interface{} {
+	return SchemaOf[MergeDuplicatesArgs]()
+}
+
+func (t *MergeDuplicatesTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *MergeDuplicatesTool) run(ctx context.Context, args *MergeDuplicatesArgs) (*mcp.CallToolResult, error) {
+	var mergeIDs []string
+	for _, id := range strings.Split(args.MergeIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" && id != args.KeepID {
+			mergeIDs = append(mergeIDs, id)
+		}
+	}
+	if len(mergeIDs) == 0 {
+		return FormatArgsError(toolargs.Errors{{Field: "merge_ids", Message: "must name at least one item other than keep_id"}})
+	}
+
+	keep, err := t.client.GetItem(ctx, args.KeepID)
+	if err != nil {
+		return FormatError(err)
+	}
+	merged := make([]*nuclino.Item, 0, len(mergeIDs))
+	docs := make([]dedup.Document, 0, len(mergeIDs))
+	for _, id := range mergeIDs {
+		item, err := t.client.GetItem(ctx, id)
+		if err != nil {
+			return FormatError(err)
+		}
+		merged = append(merged, item)
+		docs = append(docs, itemDocument(item))
+	}
+
+	detector, err := dedup.NewDetector(dedup.DefaultConfig())
+	if err != nil {
+		return FormatError(err)
+	}
+	plan := detector.PlanMerge(itemDocument(keep), docs)
+
+	result := map[string]interface{}{
+		"keep_id":          keep.ID,
+		"keep_title":       keep.Title,
+		"added_paragraphs": plan.Added,
+		"merged_content":   plan.Content,
+		"dry_run":          args.DryRun,
+	}
+	if args.DryRun {
+		return FormatResult(result)
+	}
+
+	if _, err := t.client.UpdateItem(ctx, keep.ID, &nuclino.UpdateItemRequest{Content: &plan.Content}); err != nil {
+		return FormatError(fmt.Errorf("failed to update kept item: %w", err))
+	}
+
+	outcomes := make(map[string]string, len(merged))
+	for _, item := range merged {
+		if args.DeleteMerged {
+			if err := t.client.DeleteItem(ctx, item.ID); err != nil {
+				outcomes[item.ID] = "error: " + err.Error()
+				continue
+			}
+			outcomes[item.ID] = "deleted"
+			continue
+		}
+
+		pointer := fmt.Sprintf("This page was merged into [%s](%s).\n", keep.Title, keep.URL)
+		if _, err := t.client.UpdateItem(ctx, item.ID, &nuclino.UpdateItemRequest{Content: &pointer}); err != nil {
+			outcomes[item.ID] = "error: " + err.Error()
+			continue
+		}
+		outcomes[item.ID] = "replaced with link"
+	}
+
+	if keep.WorkspaceID != "" {
+		t.graphs.Invalidate(keep.WorkspaceID)
+	}
+
+	result["merged_items"] = outcomes
+	return FormatResult(result)
+}
+
+func itemDocument(item *nuclino.Item) dedup.Document {
+	return dedup.Document{ID: item.ID, Title: item.Title, Content: item.Content, ModifiedAt: item.ModifiedAt()}
+}
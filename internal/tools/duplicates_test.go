@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+const deployGuide = "Deployments run from the main branch through the release pipeline. " +
+	"Every deployment needs an approved change request and a green build before it starts."
+
+func TestFindDuplicatesTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	items := map[string]*nuclino.Item{
+		"item-1": {ID: "item-1", Title: "Deploying", Content: deployGuide},
+		"item-2": {ID: "item-2", Title: "Deploy guide", Content: deployGuide + " Rollbacks use the previous tag."},
+		"item-3": {ID: "item-3", Title: "Holidays", Content: "The office is closed on public holidays and between Christmas and New Year."},
+	}
+	mockClient.On("ListItems", mock.Anything, "workspace-1", 100, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{{ID: "item-1"}, {ID: "item-2"}, {ID: "item-3"}},
+	}, nil)
+	for id, item := range items {
+		mockClient.On("GetItem", mock.Anything, id).Return(item, nil)
+	}
+	tool := &FindDuplicatesTool{graphs: linkgraph.NewBuilder(mockClient, linkgraph.DefaultConfig())}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"workspace_id": "workspace-1"})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"total_clusters":1`)
+	assert.Contains(t, text, `"keep_id":"item-2"`)
+	assert.Contains(t, text, "approved change request")
+}
+
+func TestMergeDuplicatesTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	keep := &nuclino.Item{ID: "item-2", Title: "Deploy guide", WorkspaceID: "workspace-1", URL: "https://app.nuclino.com/t/w/deploy-item-2", Content: deployGuide}
+	dup := &nuclino.Item{ID: "item-1", Title: "Deploying", Content: deployGuide + "\n\nHotfixes skip the staging soak period."}
+	mockClient.On("GetItem", mock.Anything, "item-2").Return(keep, nil)
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(dup, nil)
+	tool := &MergeDuplicatesTool{client: mockClient, graphs: linkgraph.NewBuilder(mockClient, linkgraph.DefaultConfig())}
+
+	preview, err := tool.Execute(context.Background(), map[string]interface{}{"keep_id": "item-2", "merge_ids": "item-1"})
+	assert.NoError(t, err)
+	assert.Contains(t, preview.Content[0].(mcp.TextContent).Text, "Hotfixes skip")
+	mockClient.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+
+	mockClient.On("UpdateItem", mock.Anything, "item-2", mock.MatchedBy(func(req *nuclino.UpdateItemRequest) bool {
+		return strings.Contains(*req.Content, "Hotfixes skip")
+	})).Return(keep, nil)
+	mockClient.On("UpdateItem", mock.Anything, "item-1", mock.MatchedBy(func(req *nuclino.UpdateItemRequest) bool {
+		return strings.Contains(*req.Content, "merged into [Deploy guide]")
+	})).Return(dup, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"keep_id": "item-2", "merge_ids": "item-1", "dry_run": false})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "replaced with link")
+	mockClient.AssertExpectations(t)
+}
@@ -1,13 +1,18 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino/nuclinotest"
 )
 
 // Test ListItemsTool
@@ -17,19 +22,19 @@ func TestListItemsTool_Execute_Success(t *testing.T) {
 
 	expectedResponse := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Item 1", WorkspaceID: "workspace-123"},
-			{ID: "item-2", Title: "Item 2", WorkspaceID: "workspace-123"},
+			{ID: item1ID, Title: "Item 1", WorkspaceID: workspace123ID},
+			{ID: item2ID, Title: "Item 2", WorkspaceID: workspace123ID},
 		},
 		Total: 2, Limit: 50, Offset: 0,
 	}
 
-	mockClient.On("ListItems", mock.Anything, "workspace-123", 50, 0).Return(expectedResponse, nil)
+	mockClient.On("ListItems", mock.Anything, workspace123ID, 50, 0).Return(expectedResponse, nil)
 
 	args := map[string]interface{}{
-		"workspace_id": "workspace-123",
+		"workspace_id": workspace123ID,
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
@@ -46,7 +51,7 @@ func TestListItemsTool_Execute_InvalidArgs(t *testing.T) {
 		"workspace_id": 123, // Should be string
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.True(t, result.IsError)
@@ -65,28 +70,28 @@ func TestListCollectionItemsTool_Execute_Success(t *testing.T) {
 	tool := &ListCollectionItemsTool{client: mockClient}
 
 	collection := &nuclino.Collection{
-		ID:          "collection-123",
+		ID:          collection123ID,
 		Title:       "Test Collection",
-		WorkspaceID: "workspace-456",
+		WorkspaceID: workspace456ID,
 	}
 
 	workspaceItems := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Item 1", CollectionID: "collection-123", WorkspaceID: "workspace-456"},
-			{ID: "item-2", Title: "Item 2", CollectionID: "collection-456", WorkspaceID: "workspace-456"}, // Different collection
-			{ID: "item-3", Title: "Item 3", CollectionID: "collection-123", WorkspaceID: "workspace-456"},
+			{ID: item1ID, Title: "Item 1", CollectionID: collection123ID, WorkspaceID: workspace456ID},
+			{ID: item2ID, Title: "Item 2", CollectionID: collection456ID, WorkspaceID: workspace456ID}, // Different collection
+			{ID: item3ID, Title: "Item 3", CollectionID: collection123ID, WorkspaceID: workspace456ID},
 		},
 		Total: 3, Limit: 1000, Offset: 0,
 	}
 
-	mockClient.On("GetCollection", mock.Anything, "collection-123").Return(collection, nil)
-	mockClient.On("ListItems", mock.Anything, "workspace-456", 1000, 0).Return(workspaceItems, nil)
+	mockClient.On("GetCollection", mock.Anything, collection123ID).Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, workspace456ID, listPageSize, 0).Return(workspaceItems, nil)
 
 	args := map[string]interface{}{
-		"collection_id": "collection-123",
+		"collection_id": collection123ID,
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
@@ -95,6 +100,36 @@ func TestListCollectionItemsTool_Execute_Success(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestCollectionTools_PageThroughFakeAPI(t *testing.T) {
+	fake := nuclinotest.NewServer()
+	defer fake.Close()
+	workspaceID := fake.AddWorkspace(fake.AddTeam("Acme"), "Engineering")
+	guides := fake.AddCollection(workspaceID, "Guides")
+	for i := 0; i < listPageSize+5; i++ {
+		fake.AddItem(workspaceID, fmt.Sprintf("Note %d", i), "")
+	}
+	fake.AddItem(guides, "Setup", "# Setup")
+	fake.AddItem(guides, "Style", "# Style")
+	client := nuclino.NewClientFromConfig(nuclino.ClientConfig{APIKey: "key", BaseURL: fake.URL})
+
+	tools := []Tool{
+		&ListCollectionItemsTool{client: client},
+		&GetCollectionOverviewTool{client: client},
+		&OrganizeCollectionTool{client: client},
+	}
+	for _, tool := range tools {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"collection_id": guides})
+		require.NoError(t, err)
+		require.False(t, result.IsError, tool.Name()+": "+result.Content[0].(mcp.TextContent).Text)
+	}
+
+	result, err := tools[0].Execute(context.Background(), map[string]interface{}{"collection_id": guides})
+	require.NoError(t, err)
+	var listed ItemsResult
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &listed))
+	assert.Equal(t, 2, listed.Total)
+}
+
 // Test SearchItemsTool with collection filtering
 func TestSearchItemsTool_Execute_WithCollectionFilter(t *testing.T) {
 	mockClient := new(MockClient)
@@ -102,9 +137,9 @@ func TestSearchItemsTool_Execute_WithCollectionFilter(t *testing.T) {
 
 	searchResponse := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Test Item 1", CollectionID: "collection-123"},
-			{ID: "item-2", Title: "Test Item 2", CollectionID: "collection-456"},
-			{ID: "item-3", Title: "Test Item 3", CollectionID: "collection-123"},
+			{ID: item1ID, Title: "Test Item 1", CollectionID: collection123ID},
+			{ID: item2ID, Title: "Test Item 2", CollectionID: collection456ID},
+			{ID: item3ID, Title: "Test Item 3", CollectionID: collection123ID},
 		},
 		Total: 3, Limit: 50, Offset: 0,
 	}
@@ -112,17 +147,24 @@ func TestSearchItemsTool_Execute_WithCollectionFilter(t *testing.T) {
 	mockClient.On("SearchItems", mock.Anything, mock.MatchedBy(func(req *nuclino.SearchItemsRequest) bool {
 		return req.Query == "test" && req.Limit == 50
 	})).Return(searchResponse, nil)
+	mockClient.On("GetCollection", mock.Anything, collection123ID).Return(&nuclino.Collection{ID: collection123ID}, nil)
 
 	args := map[string]interface{}{
 		"query":         "test",
-		"collection_id": "collection-123", // Filter by this collection
+		"collection_id": collection123ID, // Filter by this collection
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
 
+	var filtered nuclino.ItemsResponse
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &filtered))
+	require.Len(t, filtered.Results, 2)
+	assert.Equal(t, item1ID, filtered.Results[0].ID)
+	assert.Equal(t, item3ID, filtered.Results[1].ID)
+
 	mockClient.AssertExpectations(t)
 }
 
@@ -132,27 +174,27 @@ func TestGetWorkspaceOverviewTool_Execute_Success(t *testing.T) {
 	tool := &GetWorkspaceOverviewTool{client: mockClient}
 
 	workspace := &nuclino.Workspace{
-		ID:     "workspace-123",
+		ID:     workspace123ID,
 		Name:   "Test Workspace",
 		TeamID: "team-456",
 	}
 
 	collections := &nuclino.CollectionsResponse{
 		Results: []nuclino.Collection{
-			{ID: "collection-1", Title: "Collection 1", WorkspaceID: "workspace-123"},
-			{ID: "collection-2", Title: "Collection 2", WorkspaceID: "workspace-123"},
+			{ID: collection1ID, Title: "Collection 1", WorkspaceID: workspace123ID},
+			{ID: collection2ID, Title: "Collection 2", WorkspaceID: workspace123ID},
 		},
 		Total: 2, Limit: 100, Offset: 0,
 	}
 
-	mockClient.On("GetWorkspace", mock.Anything, "workspace-123").Return(workspace, nil)
-	mockClient.On("ListCollections", mock.Anything, "workspace-123", 100, 0).Return(collections, nil)
+	mockClient.On("GetWorkspace", mock.Anything, workspace123ID).Return(workspace, nil)
+	mockClient.On("ListCollections", mock.Anything, workspace123ID, 100, 0).Return(collections, nil)
 
 	args := map[string]interface{}{
-		"workspace_id": "workspace-123",
+		"workspace_id": workspace123ID,
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
@@ -161,36 +203,70 @@ func TestGetWorkspaceOverviewTool_Execute_Success(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestGetWorkspaceOverviewTool_CountsEveryPage(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &GetWorkspaceOverviewTool{client: mockClient}
+
+	page := func(from, count int) *nuclino.ItemsResponse {
+		response := &nuclino.ItemsResponse{}
+		for i := from; i < from+count; i++ {
+			response.Results = append(response.Results, nuclino.Item{ID: fmt.Sprintf("item-%d", i), CollectionID: collection1ID})
+		}
+		return response
+	}
+	mockClient.On("GetWorkspace", mock.Anything, workspace123ID).Return(&nuclino.Workspace{ID: workspace123ID}, nil)
+	mockClient.On("ListCollections", mock.Anything, workspace123ID, 100, 0).Return(&nuclino.CollectionsResponse{}, nil)
+	mockClient.On("ListItems", mock.Anything, workspace123ID, listPageSize, 0).Return(page(0, listPageSize), nil)
+	mockClient.On("ListItems", mock.Anything, workspace123ID, listPageSize, listPageSize).Return(page(listPageSize, 30), nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"workspace_id": workspace123ID, "include_items": true,
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var overview struct {
+		ItemsSummary struct {
+			TotalItems         int            `json:"total_items"`
+			ItemsPerCollection map[string]int `json:"items_per_collection"`
+		} `json:"items_summary"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &overview))
+	assert.Equal(t, listPageSize+30, overview.ItemsSummary.TotalItems)
+	assert.Equal(t, listPageSize+30, overview.ItemsSummary.ItemsPerCollection[collection1ID])
+	mockClient.AssertExpectations(t)
+}
+
 // Test GetCollectionOverviewTool
 func TestGetCollectionOverviewTool_Execute_Success(t *testing.T) {
 	mockClient := new(MockClient)
 	tool := &GetCollectionOverviewTool{client: mockClient}
 
 	collection := &nuclino.Collection{
-		ID:          "collection-123",
+		ID:          collection123ID,
 		Title:       "Test Collection",
-		WorkspaceID: "workspace-456",
+		WorkspaceID: workspace456ID,
 	}
 
 	items := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Item 1", CollectionID: "collection-123", Content: "Some content"},
-			{ID: "item-2", Title: "Item 2", CollectionID: "collection-123", Content: "More content"},
-			{ID: "item-3", Title: "Item 3", CollectionID: "collection-456", Content: "Different collection"},
+			{ID: item1ID, Title: "Item 1", CollectionID: collection123ID, Content: "Some content"},
+			{ID: item2ID, Title: "Item 2", CollectionID: collection123ID, Content: "More content"},
+			{ID: item3ID, Title: "Item 3", CollectionID: collection456ID, Content: "Different collection"},
 		},
 		Total: 3, Limit: 1000, Offset: 0,
 	}
 
-	mockClient.On("GetCollection", mock.Anything, "collection-123").Return(collection, nil)
-	mockClient.On("ListItems", mock.Anything, "workspace-456", 1000, 0).Return(items, nil)
+	mockClient.On("GetCollection", mock.Anything, collection123ID).Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, workspace456ID, listPageSize, 0).Return(items, nil)
 
 	args := map[string]interface{}{
-		"collection_id":      "collection-123",
+		"collection_id":      collection123ID,
 		"include_statistics": true,
 		"include_recent":     true,
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
@@ -205,30 +281,30 @@ func TestBulkOperationsTool_Execute_DryRun(t *testing.T) {
 	tool := &BulkOperationsTool{client: mockClient}
 
 	collection := &nuclino.Collection{
-		ID:          "source-collection",
+		ID:          sourceCollectionID,
 		Title:       "Source Collection",
-		WorkspaceID: "workspace-123",
+		WorkspaceID: workspace123ID,
 	}
 
 	items := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Item 1", CollectionID: "source-collection"},
-			{ID: "item-2", Title: "Item 2", CollectionID: "source-collection"},
+			{ID: item1ID, Title: "Item 1", CollectionID: sourceCollectionID},
+			{ID: item2ID, Title: "Item 2", CollectionID: sourceCollectionID},
 		},
 		Total: 2, Limit: 1000, Offset: 0,
 	}
 
-	mockClient.On("GetCollection", mock.Anything, "source-collection").Return(collection, nil)
-	mockClient.On("ListItems", mock.Anything, "workspace-123", 1000, 0).Return(items, nil)
+	mockClient.On("GetCollection", mock.Anything, sourceCollectionID).Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, workspace123ID, listPageSize, 0).Return(items, nil)
 
 	args := map[string]interface{}{
 		"operation":         "move",
-		"source_collection": "source-collection",
-		"target_collection": "target-collection",
+		"source_collection": sourceCollectionID,
+		"target_collection": targetCollectionID,
 		"dry_run":           true,
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
@@ -288,3 +364,80 @@ func TestRegistry_WithExtendedTools(t *testing.T) {
 		assert.True(t, toolNames[toolName], "Should contain extended tool: %s", toolName)
 	}
 }
+
+func TestRegistry_WithoutCollectionCapability(t *testing.T) {
+	registry := NewRegistryWithCapabilities(new(MockClient), nuclino.Capabilities{})
+
+	toolNames := make(map[string]bool)
+	for _, tool := range registry.ListTools() {
+		toolNames[tool.Name] = true
+	}
+
+	assert.True(t, toolNames["nuclino_list_items"])
+	assert.False(t, toolNames["nuclino_list_collections"])
+	assert.False(t, toolNames["nuclino_bulk_collection_operations"])
+	assert.False(t, toolNames["nuclino_move_item"])
+
+	// Collection tools that move items also need the move endpoint
+	registry = NewRegistryWithCapabilities(new(MockClient), nuclino.Capabilities{Collections: true})
+	toolNames = make(map[string]bool)
+	for _, tool := range registry.ListTools() {
+		toolNames[tool.Name] = true
+	}
+	assert.True(t, toolNames["nuclino_list_collections"])
+	assert.False(t, toolNames["nuclino_bulk_collection_operations"])
+}
+
+func TestRegistry_ToolsetsAndReadOnly(t *testing.T) {
+	// Every registered tool belongs to a toolset
+	for _, tool := range NewRegistry(new(MockClient)).ListTools() {
+		assert.NotEmpty(t, ToolsetOf(tool.Name), "tool %s has no toolset", tool.Name)
+	}
+
+	options := DefaultOptions()
+	options.Toolsets = []string{ToolsetItems, ToolsetWorkspaces}
+	options.ReadOnly = true
+	registry := NewRegistryWithOptions(new(MockClient), options)
+
+	toolNames := make(map[string]bool)
+	for _, tool := range registry.ListTools() {
+		toolNames[tool.Name] = true
+		assert.Equal(t, CategoryRead, CategoryOf(tool.Name))
+	}
+	assert.True(t, toolNames["nuclino_get_item"])
+	assert.True(t, toolNames["nuclino_list_workspaces"])
+	assert.False(t, toolNames["nuclino_update_item"])
+	assert.False(t, toolNames["nuclino_delete_workspace"])
+	assert.False(t, toolNames["nuclino_list_files"])
+
+	result, err := registry.CallTool("nuclino_delete_item", map[string]interface{}{"item_id": item1ID})
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"reason": "read_only"`)
+
+	_, err = registry.CallTool("nuclino_list_files", map[string]interface{}{})
+	assert.ErrorContains(t, err, "tool not found")
+
+	assert.NoError(t, ValidateToolsets([]string{"items", "files"}))
+	assert.ErrorContains(t, ValidateToolsets([]string{"item"}), "unknown toolset")
+}
+
+func TestListCollectionItemsTool_Execute_ChildIDs(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &ListCollectionItemsTool{client: mockClient}
+
+	collection := &nuclino.Collection{ID: collection1ID, WorkspaceID: workspace1ID, ChildIDs: []string{item2ID}}
+	items := &nuclino.ItemsResponse{
+		Results: []nuclino.Item{{ID: item1ID, Title: "Elsewhere"}, {ID: item2ID, Title: "Child"}},
+	}
+	mockClient.On("GetCollection", mock.Anything, collection1ID).Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, workspace1ID, listPageSize, 0).Return(items, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"collection_id": collection1ID})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "Child")
+	assert.NotContains(t, text, "Elsewhere")
+}
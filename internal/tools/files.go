@@ -1,10 +1,20 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/lukasz/nuclino-mcp-server/internal/extract"
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -21,32 +31,22 @@ func (t *ListFilesTool) Description() string {
 	return "List all files in a Nuclino workspace with pagination support"
 }
 
-func (t *ListFilesTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id": StringProperty("The ID of the workspace to list files from"),
-		"limit":        IntProperty("Maximum number of files to return (default: 50)"),
-		"offset":       IntProperty("Number of files to skip for pagination (default: 0)"),
-	}, []string{"workspace_id"})
+// ListFilesArgs are the arguments of nuclino_list_files
+type ListFilesArgs struct {
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to list files from" validate:"required"`
+	pageArgs
 }
 
-func (t *ListFilesTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
-
-	limit := 50
-	offset := 0
-
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
-	}
+func (t *ListFilesTool) InputSchema() interface{} {
+	return SchemaOf[ListFilesArgs]()
+}
 
-	if o, ok := args["offset"].(float64); ok {
-		offset = int(o)
-	}
+func (t *ListFilesTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	files, err := t.client.ListFiles(context.Background(), workspaceID, limit, offset)
+func (t *ListFilesTool) run(ctx context.Context, args *ListFilesArgs) (*mcp.CallToolResult, error) {
+	files, err := t.client.ListFiles(ctx, args.WorkspaceID, args.Limit, args.Offset)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -67,22 +67,290 @@ func (t *GetFileTool) Description() string {
 	return "Get metadata information about a specific Nuclino file"
 }
 
+// GetFileArgs are the arguments of nuclino_get_file
+type GetFileArgs struct {
+	FileID string `json:"file_id" desc:"The ID of the file to retrieve" validate:"required"`
+}
+
 func (t *GetFileTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"file_id": StringProperty("The ID of the file to retrieve"),
-	}, []string{"file_id"})
+	return SchemaOf[GetFileArgs]()
 }
 
-func (t *GetFileTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	fileID, ok := args["file_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("file_id must be a string"))
-	}
+func (t *GetFileTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	file, err := t.client.GetFile(context.Background(), fileID)
+func (t *GetFileTool) run(ctx context.Context, args *GetFileArgs) (*mcp.CallToolResult, error) {
+	file, err := t.client.GetFile(ctx, args.FileID)
 	if err != nil {
 		return FormatError(err)
 	}
 
 	return FormatResult(file)
 }
+
+// maxInlineFileSize bounds how much file data a single tool call moves through
+// the MCP connection, since it is base64 encoded in the JSON-RPC message
+const maxInlineFileSize = 10 * 1024 * 1024
+
+// UploadFileTool implements uploading a file from base64 data or a local path
+type UploadFileTool struct {
+	client nuclino.Client
+	// root is the directory local_path files must lie in; empty disables
+	// local_path
+	root string
+}
+
+func (t *UploadFileTool) Name() string {
+	return "nuclino_upload_file"
+}
+
+func (t *UploadFileTool) Description() string {
+	return "Upload a file to a Nuclino workspace. Provide either base64 encoded content or a path to a local file. Over stdio a request is limited to 16 MiB, so content_base64 fits files up to about 12 MB; use local_path for larger files."
+}
+
+// UploadFileArgs are the arguments of nuclino_upload_file
+type UploadFileArgs struct {
+	WorkspaceID   string `json:"workspace_id" desc:"The ID of the workspace to upload the file to" validate:"required"`
+	Filename      string `json:"filename" desc:"File name to store in Nuclino (defaults to the base name of local_path)"`
+	ContentBase64 string `json:"content_base64" desc:"Base64 encoded file content"`
+	LocalPath     string `json:"local_path" desc:"Path to a local file under the server's upload root to upload instead of content_base64; relative paths start at the root. Only available when an upload root is configured."`
+}
+
+func (t *UploadFileTool) InputSchema() interface{} {
+	return SchemaOf[UploadFileArgs]()
+}
+
+func (t *UploadFileTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *UploadFileTool) run(ctx context.Context, args *UploadFileArgs) (*mcp.CallToolResult, error) {
+	filename := args.Filename
+
+	if (args.ContentBase64 == "") == (args.LocalPath == "") {
+		return FormatError(fmt.Errorf("exactly one of content_base64 or local_path must be provided"))
+	}
+
+	if args.ContentBase64 != "" {
+		if filename == "" {
+			return FormatError(fmt.Errorf("filename is required when uploading content_base64"))
+		}
+		data, err := base64.StdEncoding.DecodeString(args.ContentBase64)
+		if err != nil {
+			return FormatError(fmt.Errorf("content_base64 is not valid base64: %w", err))
+		}
+		file, err := t.client.UploadFile(ctx, args.WorkspaceID, filename, data)
+		if err != nil {
+			return FormatError(err)
+		}
+		return FormatResult(file)
+	}
+
+	if t.root == "" {
+		return FormatArgsError(toolargs.Errors{{Field: "local_path", Message: "is disabled; the server has no upload root configured"}})
+	}
+	f, err := openUnder(t.root, args.LocalPath)
+	if err != nil {
+		return FormatError(fmt.Errorf("cannot read local_path: %w", err))
+	}
+	defer f.Close()
+	if filename == "" {
+		filename = filepath.Base(args.LocalPath)
+	}
+
+	file, err := t.client.UploadFileFrom(ctx, args.WorkspaceID, filename, f)
+	if err != nil {
+		return FormatError(err)
+	}
+	return FormatResult(file)
+}
+
+// openUnder opens a regular file at path, relative to root unless absolute,
+// refusing paths that lead outside root once symlinks are resolved
+func openUnder(root, path string) (*os.File, error) {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is outside the upload root", path)
+	}
+
+	f, err := os.Open(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := f.Stat(); err != nil || !info.Mode().IsRegular() {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+	return f, nil
+}
+
+// DownloadFileTool implements downloading file content as MCP binary content
+type DownloadFileTool struct {
+	client nuclino.Client
+}
+
+func (t *DownloadFileTool) Name() string {
+	return "nuclino_download_file"
+}
+
+func (t *DownloadFileTool) Description() string {
+	return "Download a Nuclino file. Images are returned as image content, other files as an embedded binary resource."
+}
+
+// DownloadFileArgs are the arguments of nuclino_download_file
+type DownloadFileArgs struct {
+	FileID string `json:"file_id" desc:"The ID of the file to download" validate:"required"`
+}
+
+func (t *DownloadFileTool) InputSchema() interface{} {
+	return SchemaOf[DownloadFileArgs]()
+}
+
+func (t *DownloadFileTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *DownloadFileTool) run(ctx context.Context, args *DownloadFileArgs) (*mcp.CallToolResult, error) {
+	fileID := args.FileID
+
+	file, err := t.client.GetFile(ctx, fileID)
+	if err != nil {
+		return FormatError(err)
+	}
+	if file.Size > maxInlineFileSize {
+		return FormatError(fmt.Errorf("file is %d bytes, larger than the %d byte download limit", file.Size, maxInlineFileSize))
+	}
+
+	var buf bytes.Buffer
+	limited := &limitedWriter{w: &buf, remaining: maxInlineFileSize}
+	if _, err := t.client.DownloadFileTo(ctx, file, limited); err != nil {
+		return FormatError(err)
+	}
+
+	data := buf.Bytes()
+	mimeType := detectMimeType(file, data)
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	summary := mcp.TextContent{
+		Type: "text",
+		Text: fmt.Sprintf("File %s (%s, %s, %d bytes)", file.DisplayName(), fileID, mimeType, len(data)),
+	}
+
+	var payload interface{}
+	if strings.HasPrefix(mimeType, "image/") {
+		payload = mcp.ImageContent{
+			Type:     "image",
+			Data:     encoded,
+			MimeType: mimeType,
+		}
+	} else {
+		payload = mcp.EmbeddedResource{
+			Type: "resource",
+			Resource: mcp.BlobResourceContents{
+				Uri:      "nuclino://files/" + fileID,
+				MimeType: mimeType,
+				Blob:     encoded,
+			},
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []interface{}{summary, payload},
+	}, nil
+}
+
+// detectMimeType prefers the type reported by the API, then the file
+// extension, and finally sniffs the content
+func detectMimeType(file *nuclino.File, data []byte) string {
+	if file.MimeType != "" {
+		return file.MimeType
+	}
+	if byExt := mime.TypeByExtension(filepath.Ext(file.DisplayName())); byExt != "" {
+		return byExt
+	}
+	return http.DetectContentType(data)
+}
+
+// limitedWriter fails once more than the allowed number of bytes is written
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		return 0, fmt.Errorf("file exceeds the %d byte download limit", maxInlineFileSize)
+	}
+	l.remaining -= int64(len(p))
+	return l.w.Write(p)
+}
+
+// ReadFileTextTool implements reading the extracted text of an attached file
+type ReadFileTextTool struct {
+	extractor *extract.Service
+}
+
+func (t *ReadFileTextTool) Name() string {
+	return "nuclino_read_file_text"
+}
+
+func (t *ReadFileTextTool) Description() string {
+	return "Extract plain text from an attached Nuclino file (PDF, DOCX, XLSX, CSV, HTML or plain text). Long text can be paged with offset and max_chars."
+}
+
+// ReadFileTextArgs are the arguments of nuclino_read_file_text
+type ReadFileTextArgs struct {
+	FileID   string `json:"file_id" desc:"The ID of the file to read" validate:"required"`
+	Offset   int    `json:"offset" desc:"Character offset to start reading from (default: 0)" validate:"min=0"`
+	MaxChars int    `json:"max_chars" desc:"Maximum number of characters to return (default: 20000)" validate:"min=1" default:"20000"`
+}
+
+func (t *ReadFileTextTool) InputSchema() interface{} {
+	return SchemaOf[ReadFileTextArgs]()
+}
+
+func (t *ReadFileTextTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *ReadFileTextTool) run(ctx context.Context, args *ReadFileTextArgs) (*mcp.CallToolResult, error) {
+	result, err := t.extractor.ExtractFile(ctx, args.FileID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	text := []rune(result.Text)
+	total := len(text)
+	offset := args.Offset
+	if offset > total {
+		offset = total
+	}
+	end := offset + args.MaxChars
+	if end > total {
+		end = total
+	}
+
+	return FormatResult(map[string]interface{}{
+		"file_id":     result.FileID,
+		"file_name":   result.FileName,
+		"format":      result.Format,
+		"total_chars": total,
+		"offset":      offset,
+		"text":        string(text[offset:end]),
+		"truncated":   end < total,
+	})
+}
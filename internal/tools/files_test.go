@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/extract"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino/nuclinotest"
+)
+
+func mockDownload(mockClient *MockClient, fileID string, data []byte) {
+	mockClient.On("DownloadFileTo", mock.Anything, fileID, mock.Anything).
+		Run(func(args mock.Arguments) {
+			_, _ = args.Get(2).(io.Writer).Write(data)
+		}).
+		Return(int64(len(data)), nil)
+}
+
+func TestUploadFileTool_Execute_Base64(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &UploadFileTool{client: mockClient}
+
+	uploaded := &nuclino.File{ID: "file-1", Name: "notes.txt"}
+	mockClient.On("UploadFile", mock.Anything, "workspace-1", "notes.txt", []byte("hello")).Return(uploaded, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"workspace_id":   "workspace-1",
+		"filename":       "notes.txt",
+		"content_base64": base64.StdEncoding.EncodeToString([]byte("hello")),
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUploadFileTool_Execute_LocalPath(t *testing.T) {
+	mockClient := new(MockClient)
+	root := t.TempDir()
+	tool := &UploadFileTool{client: mockClient, root: root}
+
+	path := filepath.Join(root, "report.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("a,b\n1,2\n"), 0o600))
+
+	uploaded := &nuclino.File{ID: "file-2", Name: "report.csv"}
+	mockClient.On("UploadFileFrom", mock.Anything, "workspace-1", "report.csv", mock.Anything).Return(uploaded, nil)
+
+	for _, localPath := range []string{path, "report.csv"} {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"workspace_id": "workspace-1",
+			"local_path":   localPath,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsError, localPath)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+func TestUploadFileTool_Execute_LocalPathOutsideRoot(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	secret := filepath.Join(outside, "id_rsa")
+	assert.NoError(t, os.WriteFile(secret, []byte("key"), 0o600))
+	assert.NoError(t, os.Symlink(secret, filepath.Join(root, "link")))
+	assert.NoError(t, os.Symlink(outside, filepath.Join(root, "dir")))
+
+	call := func(tool *UploadFileTool, localPath string) string {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"workspace_id": "workspace-1",
+			"local_path":   localPath,
+		})
+		assert.NoError(t, err)
+		assert.True(t, result.IsError, localPath)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	// The mock client fails the test on any upload
+	assert.Contains(t, call(&UploadFileTool{client: new(MockClient)}, secret), "no upload root")
+	tool := &UploadFileTool{client: new(MockClient), root: root}
+	for _, localPath := range []string{secret, "../" + filepath.Base(outside) + "/id_rsa", "link", "dir/id_rsa"} {
+		assert.Contains(t, call(tool, localPath), "outside the upload root", localPath)
+	}
+}
+
+func TestUploadFileTool_Execute_RequiresSingleSource(t *testing.T) {
+	tool := &UploadFileTool{client: new(MockClient)}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"workspace_id": "workspace-1"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestDownloadFileTool_Execute_Image(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &DownloadFileTool{client: mockClient}
+
+	png := []byte("\x89PNG\r\n\x1a\nrest-of-image")
+	mockClient.On("GetFile", mock.Anything, "file-img").Return(&nuclino.File{ID: "file-img", Name: "diagram.png"}, nil)
+	mockDownload(mockClient, "file-img", png)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"file_id": "file-img"})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Len(t, result.Content, 2)
+
+	image, ok := result.Content[1].(mcp.ImageContent)
+	assert.True(t, ok, "expected image content")
+	assert.Equal(t, "image/png", image.MimeType)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(png), image.Data)
+}
+
+func TestDownloadFileTool_Execute_Blob(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &DownloadFileTool{client: mockClient}
+
+	mockClient.On("GetFile", mock.Anything, "file-pdf").
+		Return(&nuclino.File{ID: "file-pdf", Name: "spec.pdf", MimeType: "application/pdf"}, nil)
+	mockDownload(mockClient, "file-pdf", []byte("%PDF-1.4"))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"file_id": "file-pdf"})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	resource, ok := result.Content[1].(mcp.EmbeddedResource)
+	assert.True(t, ok, "expected embedded resource")
+	blob, ok := resource.Resource.(mcp.BlobResourceContents)
+	assert.True(t, ok, "expected blob contents")
+	assert.Equal(t, "application/pdf", blob.MimeType)
+	assert.Equal(t, "nuclino://files/file-pdf", blob.Uri)
+}
+
+func TestSearchWorkspaceContentTool_SearchAttachments(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &SearchWorkspaceContentTool{client: mockClient, extractor: extract.NewService(mockClient, extract.DefaultConfig())}
+
+	mockClient.On("SearchItems", mock.Anything, mock.Anything).Return(&nuclino.ItemsResponse{}, nil)
+	mockClient.On("ListItems", mock.Anything, "workspace-1", listPageSize, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{
+			{ID: "item-1", Title: "Specs", ContentMeta: nuclino.ContentMeta{FileIDs: []string{"file-1"}}},
+			{ID: "item-2", Title: "Empty"},
+		},
+	}, nil)
+	mockClient.On("GetFile", mock.Anything, "file-1").
+		Return(&nuclino.File{ID: "file-1", Name: "limits.txt", MimeType: "text/plain"}, nil)
+	mockDownload(mockClient, "file-1", []byte("The upload quota is 500 requests per minute."))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"workspace_id":       "workspace-1",
+		"query":              "QUOTA",
+		"search_attachments": true,
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"file_id":"file-1"`)
+	assert.Contains(t, text, "upload quota is 500")
+	assert.Contains(t, text, `"total_found":1`)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSearchWorkspaceContentTool_SkipsFilesOverTheBudget(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &SearchWorkspaceContentTool{client: mockClient, extractor: extract.NewService(mockClient, extract.DefaultConfig())}
+
+	fileIDs := make([]string, attachmentSearchMaxFiles+2)
+	for i := range fileIDs {
+		fileIDs[i] = fmt.Sprintf("file-%d", i)
+		mockClient.On("GetFile", mock.Anything, fileIDs[i]).
+			Return(&nuclino.File{ID: fileIDs[i], Name: "notes.txt", MimeType: "text/plain"}, nil)
+		mockDownload(mockClient, fileIDs[i], []byte("quota notes"))
+	}
+	mockClient.On("SearchItems", mock.Anything, mock.Anything).Return(&nuclino.ItemsResponse{}, nil)
+	mockClient.On("ListItems", mock.Anything, "workspace-1", listPageSize, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{{ID: "item-1", Title: "Specs", ContentMeta: nuclino.ContentMeta{FileIDs: fileIDs}}},
+	}, nil)
+
+	matches, failures, err := tool.searchAttachments(context.Background(), "workspace-1", "quota")
+	require.NoError(t, err)
+	assert.Len(t, matches, attachmentSearchMaxFiles)
+	assert.Equal(t, []string{
+		fmt.Sprintf("file-%d: skipped, a search reads at most %d files", attachmentSearchMaxFiles, attachmentSearchMaxFiles),
+		fmt.Sprintf("file-%d: skipped, a search reads at most %d files", attachmentSearchMaxFiles+1, attachmentSearchMaxFiles),
+	}, failures)
+	mockClient.AssertNumberOfCalls(t, "DownloadFileTo", attachmentSearchMaxFiles)
+}
+
+func TestDownloadFileTool_FetchesMetadataOnce(t *testing.T) {
+	fake := nuclinotest.NewServer()
+	defer fake.Close()
+	workspaceID := fake.AddWorkspace(fake.AddTeam("Acme"), "Docs")
+	fileID := fake.AddFile(fake.AddItem(workspaceID, "Report", ""), "report.txt", []byte("quarterly numbers"))
+	tool := &DownloadFileTool{client: nuclino.NewClientFromConfig(nuclino.ClientConfig{APIKey: nuclinotest.DemoAPIKey, BaseURL: fake.URL})}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"file_id": fileID})
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	metadata := 0
+	for _, request := range fake.Requests() {
+		if request == "GET /v0/files/"+fileID {
+			metadata++
+		}
+	}
+	assert.Equal(t, 1, metadata)
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FindReplaceTool replaces text across a workspace or subtree. The first call
+// returns a diff preview and a plan token; the change is only written when the
+// token is passed back.
+type FindReplaceTool struct {
+	client   nuclino.Client
+	plans    bulk.Store
+	executor *bulk.Executor
+}
+
+func (t *FindReplaceTool) Name() string {
+	return "nuclino_find_replace"
+}
+
+func (t *FindReplaceTool) Description() string {
+	return "Find and replace text in the content of every item in a workspace or below a collection. Without plan_token it only returns a per-item diff preview and a plan token; call again with plan_token to write the changes. Applied changes are recorded and can be undone with plan_token and revert: true."
+}
+
+// FindReplaceArgs are the arguments of nuclino_find_replace
+type FindReplaceArgs struct {
+	Find         string `json:"find" desc:"Text or regular expression to find"`
+	Replace      string `json:"replace" desc:"Replacement text; $1 style groups work with regex (default: empty)"`
+	WorkspaceID  string `json:"workspace_id" desc:"Search every item in this workspace"`
+	ParentID     string `json:"parent_id" desc:"Search only items below this collection"`
+	Regex        bool   `json:"regex" desc:"Treat find as a regular expression (default: false)"`
+	IgnoreCase   bool   `json:"ignore_case" desc:"Match case-insensitively (default: false)"`
+	SkipCode     bool   `json:"skip_code" desc:"Leave fenced code blocks untouched (default: true)" default:"true"`
+	PlanToken    string `json:"plan_token" desc:"Token from a preview; applies that exact change"`
+	Revert       bool   `json:"revert" desc:"With plan_token, undo the applied changes (default: false)"`
+	Atomic       bool   `json:"atomic" desc:"With plan_token, undo the changes already written if any item fails (default: true)" default:"true"`
+	PreviewLimit int    `json:"preview_limit" desc:"Maximum number of items to show diffs for (default: 10)" validate:"min=0" default:"10"`
+	ContextLines int    `json:"context_lines" desc:"Unchanged lines shown around each change (default: 1)" validate:"min=0" default:"1"`
+}
+
+func (t *FindReplaceTool) InputSchema() interface{} {
+	return SchemaOf[FindReplaceArgs]()
+}
+
+func (t *FindReplaceTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *FindReplaceTool) run(ctx context.Context, args *FindReplaceArgs) (*mcp.CallToolResult, error) {
+	if args.PlanToken != "" {
+		return t.apply(ctx, args.PlanToken, args.Revert, args.Atomic)
+	}
+
+	if args.Find == "" {
+		return FormatArgsError(toolargs.Errors{{Field: "find", Message: "is required without plan_token"}})
+	}
+	if args.WorkspaceID == "" && args.ParentID == "" {
+		return FormatArgsError(toolargs.Errors{{Field: "workspace_id", Message: "or parent_id is required"}})
+	}
+
+	op, err := bulk.NewOperation(bulk.OperationSpec{
+		Type:        bulk.OpReplace,
+		Pattern:     args.Find,
+		Replacement: args.Replace,
+		Regex:       args.Regex,
+		IgnoreCase:  args.IgnoreCase,
+		SkipCode:    args.SkipCode,
+	})
+	if err != nil {
+		return FormatError(err)
+	}
+
+	selector := bulk.Selector{WorkspaceID: args.WorkspaceID, ParentID: args.ParentID}
+	plan, err := bulk.NewPlanner(t.client, bulk.DefaultPlannerConfig()).Plan(ctx, selector, op)
+	if err != nil {
+		return FormatError(err)
+	}
+	plan.Description = fmt.Sprintf("find %q replace %q", args.Find, args.Replace)
+
+	matches := 0
+	previews := make([]map[string]interface{}, 0, min(args.PreviewLimit, len(plan.Steps)))
+	for i, step := range plan.Steps {
+		matches += step.Matches
+		if i >= args.PreviewLimit {
+			continue
+		}
+		previews = append(previews, map[string]interface{}{
+			"item_id": step.ItemID,
+			"title":   step.Title,
+			"matches": step.Matches,
+			"diff":    bulk.UnifiedDiff(step.Before.Content, step.After.Content, args.ContextLines),
+		})
+	}
+
+	result := map[string]interface{}{
+		"items_scanned": plan.Scanned,
+		"items_changed": len(plan.Steps),
+		"total_matches": matches,
+		"preview":       previews,
+	}
+	if len(plan.Steps) == 0 {
+		return FormatResult(result)
+	}
+
+	if err := t.plans.Save(plan); err != nil {
+		return FormatError(err)
+	}
+	result["plan_token"] = plan.ID
+	result["next"] = "Call nuclino_find_replace with this plan_token to write the changes"
+	return FormatResult(result)
+}
+
+// apply writes or reverts a previewed find and replace. In atomic mode a
+// failed item undoes the items already written.
+func (t *FindReplaceTool) apply(ctx context.Context, token string, revert, atomic bool) (*mcp.CallToolResult, error) {
+	plan, err := t.plans.Load(token)
+	if err != nil {
+		return FormatError(err)
+	}
+	if plan.Operation.Type != bulk.OpReplace {
+		return FormatError(fmt.Errorf("plan %s is not a find and replace", token))
+	}
+
+	var result *bulk.Result
+	if revert {
+		result, err = t.executor.Revert(ctx, plan)
+	} else {
+		result, err = t.executor.Execute(ctx, plan, bulk.Options{Atomic: atomic})
+	}
+	if err != nil {
+		return FormatError(err)
+	}
+	return FormatResult(bulkOutcome(result))
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func TestFindReplaceTool_PreviewApplyRevert(t *testing.T) {
+	mockClient := new(MockClient)
+	original := "Call https://old.example.com/api.\n```\ncurl https://old.example.com\n```\n"
+	replaced := "Call https://example.com/api.\n```\ncurl https://old.example.com\n```\n"
+	item := &nuclino.Item{ID: "item-1", Title: "API", Content: original}
+	mockClient.On("ListItems", mock.Anything, "workspace-1", 100, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{{ID: "item-1"}, {ID: "item-2"}},
+	}, nil)
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(item, nil).Once()
+	mockClient.On("GetItem", mock.Anything, "item-2").Return(&nuclino.Item{ID: "item-2", Content: "Unrelated"}, nil)
+
+	plans := bulk.NewMemoryStore()
+	tool := &FindReplaceTool{
+		client:   mockClient,
+		plans:    plans,
+		executor: bulk.NewExecutor(mockClient, plans, bulk.ExecutorConfig{RequestsPerSecond: 1000}),
+	}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"find":         "old.example.com",
+		"replace":      "example.com",
+		"workspace_id": "workspace-1",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var preview struct {
+		PlanToken    string `json:"plan_token"`
+		ItemsChanged int    `json:"items_changed"`
+		TotalMatches int    `json:"total_matches"`
+		Preview      []struct {
+			Diff string `json:"diff"`
+		} `json:"preview"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview))
+	assert.Equal(t, 1, preview.ItemsChanged)
+	assert.Equal(t, 1, preview.TotalMatches)
+	assert.Contains(t, preview.Preview[0].Diff, "-Call https://old.example.com/api.\n+Call https://example.com/api.")
+	mockClient.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+
+	// Apply with the token
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(item, nil).Once()
+	mockClient.On("UpdateItem", mock.Anything, "item-1", mock.MatchedBy(func(req *nuclino.UpdateItemRequest) bool {
+		return req.Content != nil && *req.Content == replaced
+	})).Return(item, nil).Once()
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"plan_token": preview.PlanToken})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"applied":1`)
+
+	// Revert restores the recorded content
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(&nuclino.Item{ID: "item-1", Content: replaced}, nil).Once()
+	mockClient.On("UpdateItem", mock.Anything, "item-1", mock.MatchedBy(func(req *nuclino.UpdateItemRequest) bool {
+		return req.Content != nil && *req.Content == original
+	})).Return(item, nil).Once()
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"plan_token": preview.PlanToken, "revert": true})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"status":"rolled_back"`)
+	mockClient.AssertExpectations(t)
+}
+
+func TestFindReplaceTool_AtomicUndoesWrittenItems(t *testing.T) {
+	mockClient := new(MockClient)
+	first := &nuclino.Item{ID: "item-1", Content: "old name"}
+	second := &nuclino.Item{ID: "item-2", Content: "the old name"}
+	mockClient.On("ListItems", mock.Anything, "workspace-1", 100, 0).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{{ID: "item-1"}, {ID: "item-2"}},
+	}, nil)
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(first, nil).Twice()
+	mockClient.On("GetItem", mock.Anything, "item-2").Return(second, nil).Twice()
+
+	plans := bulk.NewMemoryStore()
+	tool := &FindReplaceTool{
+		client:   mockClient,
+		plans:    plans,
+		executor: bulk.NewExecutor(mockClient, plans, bulk.ExecutorConfig{Concurrency: 1, RequestsPerSecond: 1000}),
+	}
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"find": "old", "replace": "new", "workspace_id": "workspace-1",
+	})
+	require.NoError(t, err)
+	var preview struct {
+		PlanToken string `json:"plan_token"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview))
+
+	written := &nuclino.Item{ID: "item-1", Content: "new name"}
+	contentIs := func(content string) interface{} {
+		return mock.MatchedBy(func(req *nuclino.UpdateItemRequest) bool { return req.Content != nil && *req.Content == content })
+	}
+	mockClient.On("UpdateItem", mock.Anything, "item-1", contentIs("new name")).Return(written, nil).Once()
+	mockClient.On("UpdateItem", mock.Anything, "item-2", contentIs("the new name")).Return((*nuclino.Item)(nil), nuclino.NewAPIError(403, "forbidden"))
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(written, nil).Once()
+	mockClient.On("UpdateItem", mock.Anything, "item-1", contentIs("old name")).Return(first, nil).Once()
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"plan_token": preview.PlanToken})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"status":"rolled_back"`)
+	assert.Contains(t, text, `"compensated":1`)
+	mockClient.AssertExpectations(t)
+}
+
+func TestFindReplaceTool_RequiresScope(t *testing.T) {
+	tool := &FindReplaceTool{client: new(MockClient), plans: bulk.NewMemoryStore()}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"find": "x"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"find": "(", "regex": true, "workspace_id": "w"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
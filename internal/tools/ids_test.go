@@ -0,0 +1,31 @@
+package tools
+
+// Fixture IDs are UUIDs like the API's, so that the registry passes them
+// through instead of resolving them as references
+const (
+	item1ID            = "00000000-0000-4000-8000-000000000001"
+	item2ID            = "00000000-0000-4000-8000-000000000002"
+	item3ID            = "00000000-0000-4000-8000-000000000003"
+	item4ID            = "00000000-0000-4000-8000-000000000004"
+	item5ID            = "00000000-0000-4000-8000-000000000005"
+	inItemID           = "00000000-0000-4000-8000-000000000006"
+	outItemID          = "00000000-0000-4000-8000-000000000007"
+	newItemID          = "00000000-0000-4000-8000-000000000008"
+	workspace1ID       = "00000000-0000-4000-8000-000000000009"
+	workspace123ID     = "00000000-0000-4000-8000-00000000000a"
+	workspace456ID     = "00000000-0000-4000-8000-00000000000b"
+	ws1ID              = "00000000-0000-4000-8000-00000000000c"
+	ws123ID            = "00000000-0000-4000-8000-00000000000d"
+	allowedWorkspaceID = "00000000-0000-4000-8000-00000000000e"
+	otherWorkspaceID   = "00000000-0000-4000-8000-00000000000f"
+	collection1ID      = "00000000-0000-4000-8000-000000000010"
+	collection2ID      = "00000000-0000-4000-8000-000000000011"
+	collection123ID    = "00000000-0000-4000-8000-000000000012"
+	collection456ID    = "00000000-0000-4000-8000-000000000013"
+	col1ID             = "00000000-0000-4000-8000-000000000014"
+	col2ID             = "00000000-0000-4000-8000-000000000015"
+	devCollectionID    = "00000000-0000-4000-8000-000000000016"
+	docsCollectionID   = "00000000-0000-4000-8000-000000000017"
+	sourceCollectionID = "00000000-0000-4000-8000-000000000018"
+	targetCollectionID = "00000000-0000-4000-8000-000000000019"
+)
@@ -18,35 +18,35 @@ func TestWorkspaceAndCollectionIntegration(t *testing.T) {
 	registry := NewRegistry(mockClient)
 
 	workspace := &nuclino.Workspace{
-		ID:     "workspace-123",
+		ID:     workspace123ID,
 		Name:   "Test Workspace",
 		TeamID: "team-456",
 	}
 
 	collections := &nuclino.CollectionsResponse{
 		Results: []nuclino.Collection{
-			{ID: "collection-1", Title: "Collection 1", WorkspaceID: "workspace-123"},
-			{ID: "collection-2", Title: "Collection 2", WorkspaceID: "workspace-123"},
+			{ID: collection1ID, Title: "Collection 1", WorkspaceID: workspace123ID},
+			{ID: collection2ID, Title: "Collection 2", WorkspaceID: workspace123ID},
 		},
 		Total: 2, Limit: 100, Offset: 0,
 	}
 
 	items := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Item 1", CollectionID: "collection-1", WorkspaceID: "workspace-123", Content: "First item content"},
-			{ID: "item-2", Title: "Item 2", CollectionID: "collection-1", WorkspaceID: "workspace-123", Content: "Second item content"},
-			{ID: "item-3", Title: "Item 3", CollectionID: "collection-2", WorkspaceID: "workspace-123", Content: "Third item content"},
+			{ID: item1ID, Title: "Item 1", CollectionID: collection1ID, WorkspaceID: workspace123ID, Content: "First item content"},
+			{ID: item2ID, Title: "Item 2", CollectionID: collection1ID, WorkspaceID: workspace123ID, Content: "Second item content"},
+			{ID: item3ID, Title: "Item 3", CollectionID: collection2ID, WorkspaceID: workspace123ID, Content: "Third item content"},
 		},
 		Total: 3, Limit: 1000, Offset: 0,
 	}
 
-	mockClient.On("GetWorkspace", mock.Anything, "workspace-123").Return(workspace, nil)
-	mockClient.On("ListCollections", mock.Anything, "workspace-123", 100, 0).Return(collections, nil)
-	mockClient.On("ListItems", mock.Anything, "workspace-123", 1000, 0).Return(items, nil)
+	mockClient.On("GetWorkspace", mock.Anything, workspace123ID).Return(workspace, nil)
+	mockClient.On("ListCollections", mock.Anything, workspace123ID, 100, 0).Return(collections, nil)
+	mockClient.On("ListItems", mock.Anything, workspace123ID, listPageSize, 0).Return(items, nil)
 
 	// Test workspace overview with items
 	overviewArgs := map[string]interface{}{
-		"workspace_id":   "workspace-123",
+		"workspace_id":   workspace123ID,
 		"include_items":  true,
 		"include_recent": true,
 		"recent_limit":   2.0,
@@ -66,28 +66,28 @@ func TestCollectionOrganizationWorkflow(t *testing.T) {
 	registry := NewRegistry(mockClient)
 
 	collection := &nuclino.Collection{
-		ID:          "collection-123",
+		ID:          collection123ID,
 		Title:       "Test Collection",
-		WorkspaceID: "workspace-456",
+		WorkspaceID: workspace456ID,
 	}
 
 	items := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Project Alpha", CollectionID: "collection-123", Content: "Alpha project documentation"},
-			{ID: "item-2", Title: "Project Beta", CollectionID: "collection-123", Content: "Beta project notes"},
-			{ID: "item-3", Title: "Meeting Notes", CollectionID: "collection-123", Content: "Weekly team meeting"},
-			{ID: "item-4", Title: "Alpha Update", CollectionID: "collection-123", Content: "Project Alpha progress update"},
-			{ID: "item-5", Title: "", CollectionID: "collection-123", Content: ""}, // Empty item
+			{ID: item1ID, Title: "Project Alpha", CollectionID: collection123ID, Content: "Alpha project documentation"},
+			{ID: item2ID, Title: "Project Beta", CollectionID: collection123ID, Content: "Beta project notes"},
+			{ID: item3ID, Title: "Meeting Notes", CollectionID: collection123ID, Content: "Weekly team meeting"},
+			{ID: item4ID, Title: "Alpha Update", CollectionID: collection123ID, Content: "Project Alpha progress update"},
+			{ID: item5ID, Title: "", CollectionID: collection123ID, Content: ""}, // Empty item
 		},
 		Total: 5, Limit: 1000, Offset: 0,
 	}
 
-	mockClient.On("GetCollection", mock.Anything, "collection-123").Return(collection, nil)
-	mockClient.On("ListItems", mock.Anything, "workspace-456", 1000, 0).Return(items, nil)
+	mockClient.On("GetCollection", mock.Anything, collection123ID).Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, workspace456ID, listPageSize, 0).Return(items, nil)
 
 	// Test collection overview
 	overviewArgs := map[string]interface{}{
-		"collection_id":      "collection-123",
+		"collection_id":      collection123ID,
 		"include_statistics": true,
 		"include_recent":     true,
 		"recent_limit":       3.0,
@@ -100,7 +100,7 @@ func TestCollectionOrganizationWorkflow(t *testing.T) {
 
 	// Test organization suggestions
 	organizeArgs := map[string]interface{}{
-		"collection_id":     "collection-123",
+		"collection_id":     collection123ID,
 		"suggest_tags":      true,
 		"find_duplicates":   true,
 		"analyze_structure": true,
@@ -120,34 +120,35 @@ func TestSearchAndFilterWorkflow(t *testing.T) {
 
 	searchResponse1 := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "API Documentation", CollectionID: "collection-dev", WorkspaceID: "workspace-123", Content: "REST API docs"},
-			{ID: "item-2", Title: "User Guide", CollectionID: "collection-docs", WorkspaceID: "workspace-123", Content: "User documentation"},
-			{ID: "item-3", Title: "API Testing", CollectionID: "collection-dev", WorkspaceID: "workspace-123", Content: "API test cases"},
+			{ID: item1ID, Title: "API Documentation", CollectionID: devCollectionID, WorkspaceID: workspace123ID, Content: "REST API docs"},
+			{ID: item2ID, Title: "User Guide", CollectionID: docsCollectionID, WorkspaceID: workspace123ID, Content: "User documentation"},
+			{ID: item3ID, Title: "API Testing", CollectionID: devCollectionID, WorkspaceID: workspace123ID, Content: "API test cases"},
 		},
 		Total: 3, Limit: 100, Offset: 0,
 	}
 
 	searchResponse2 := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "API Documentation", CollectionID: "collection-dev", WorkspaceID: "workspace-123", Content: "REST API docs"},
-			{ID: "item-3", Title: "API Testing", CollectionID: "collection-dev", WorkspaceID: "workspace-123", Content: "API test cases"},
+			{ID: item1ID, Title: "API Documentation", CollectionID: devCollectionID, WorkspaceID: workspace123ID, Content: "REST API docs"},
+			{ID: item3ID, Title: "API Testing", CollectionID: devCollectionID, WorkspaceID: workspace123ID, Content: "API test cases"},
 		},
 		Total: 2, Limit: 20, Offset: 0,
 	}
 
 	// Mock for workspace content search
 	mockClient.On("SearchItems", mock.Anything, mock.MatchedBy(func(req *nuclino.SearchItemsRequest) bool {
-		return req.Query == "API" && req.WorkspaceID == "workspace-123" && req.Limit == 100
+		return req.Query == "API" && req.WorkspaceID == workspace123ID && req.Limit == 100
 	})).Return(searchResponse1, nil).Once()
 
 	// Mock for collection filtered search
 	mockClient.On("SearchItems", mock.Anything, mock.MatchedBy(func(req *nuclino.SearchItemsRequest) bool {
 		return req.Query == "API" && req.Limit == 10
 	})).Return(searchResponse2, nil).Once()
+	mockClient.On("GetCollection", mock.Anything, devCollectionID).Return(&nuclino.Collection{ID: devCollectionID}, nil).Once()
 
 	// Test workspace content search
 	searchArgs := map[string]interface{}{
-		"workspace_id":        "workspace-123",
+		"workspace_id":        workspace123ID,
 		"query":               "API",
 		"search_titles":       true,
 		"search_content":      true,
@@ -163,7 +164,7 @@ func TestSearchAndFilterWorkflow(t *testing.T) {
 	// Test search with collection filtering
 	filterArgs := map[string]interface{}{
 		"query":         "API",
-		"collection_id": "collection-dev",
+		"collection_id": devCollectionID,
 		"limit":         10.0,
 	}
 
@@ -180,28 +181,28 @@ func TestBulkOperationsWorkflow(t *testing.T) {
 	registry := NewRegistry(mockClient)
 
 	sourceCollection := &nuclino.Collection{
-		ID:          "source-collection",
+		ID:          sourceCollectionID,
 		Title:       "Source Collection",
-		WorkspaceID: "workspace-123",
+		WorkspaceID: workspace123ID,
 	}
 
 	items := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Old Document 1", CollectionID: "source-collection", Content: "Legacy content"},
-			{ID: "item-2", Title: "Old Document 2", CollectionID: "source-collection", Content: "More legacy content"},
-			{ID: "item-3", Title: "Current Doc", CollectionID: "source-collection", Content: "Current content"},
+			{ID: item1ID, Title: "Old Document 1", CollectionID: sourceCollectionID, Content: "Legacy content"},
+			{ID: item2ID, Title: "Old Document 2", CollectionID: sourceCollectionID, Content: "More legacy content"},
+			{ID: item3ID, Title: "Current Doc", CollectionID: sourceCollectionID, Content: "Current content"},
 		},
 		Total: 3, Limit: 1000, Offset: 0,
 	}
 
-	mockClient.On("GetCollection", mock.Anything, "source-collection").Return(sourceCollection, nil)
-	mockClient.On("ListItems", mock.Anything, "workspace-123", 1000, 0).Return(items, nil)
+	mockClient.On("GetCollection", mock.Anything, sourceCollectionID).Return(sourceCollection, nil)
+	mockClient.On("ListItems", mock.Anything, workspace123ID, listPageSize, 0).Return(items, nil)
 
 	// Test dry run bulk move operation
 	dryRunArgs := map[string]interface{}{
 		"operation":         "move",
-		"source_collection": "source-collection",
-		"target_collection": "target-collection",
+		"source_collection": sourceCollectionID,
+		"target_collection": targetCollectionID,
 		"filter_query":      "Old",
 		"dry_run":           true,
 	}
@@ -214,7 +215,7 @@ func TestBulkOperationsWorkflow(t *testing.T) {
 	// Test organize operation
 	organizeArgs := map[string]interface{}{
 		"operation":         "organize",
-		"source_collection": "source-collection",
+		"source_collection": sourceCollectionID,
 		"dry_run":           true,
 	}
 
@@ -231,27 +232,27 @@ func TestListingToolsIntegration(t *testing.T) {
 	registry := NewRegistry(mockClient)
 
 	collection := &nuclino.Collection{
-		ID:          "collection-123",
+		ID:          collection123ID,
 		Title:       "Test Collection",
-		WorkspaceID: "workspace-456",
+		WorkspaceID: workspace456ID,
 	}
 
 	workspaceItems := &nuclino.ItemsResponse{
 		Results: []nuclino.Item{
-			{ID: "item-1", Title: "Item 1", CollectionID: "collection-123", WorkspaceID: "workspace-456"},
-			{ID: "item-2", Title: "Item 2", CollectionID: "collection-456", WorkspaceID: "workspace-456"}, // Different collection
-			{ID: "item-3", Title: "Item 3", CollectionID: "collection-123", WorkspaceID: "workspace-456"},
+			{ID: item1ID, Title: "Item 1", CollectionID: collection123ID, WorkspaceID: workspace456ID},
+			{ID: item2ID, Title: "Item 2", CollectionID: collection456ID, WorkspaceID: workspace456ID}, // Different collection
+			{ID: item3ID, Title: "Item 3", CollectionID: collection123ID, WorkspaceID: workspace456ID},
 		},
 		Total: 3, Limit: 50, Offset: 0,
 	}
 
-	mockClient.On("ListItems", mock.Anything, "workspace-456", 50, 0).Return(workspaceItems, nil)
-	mockClient.On("GetCollection", mock.Anything, "collection-123").Return(collection, nil)
-	mockClient.On("ListItems", mock.Anything, "workspace-456", 1000, 0).Return(workspaceItems, nil)
+	mockClient.On("ListItems", mock.Anything, workspace456ID, 50, 0).Return(workspaceItems, nil)
+	mockClient.On("GetCollection", mock.Anything, collection123ID).Return(collection, nil)
+	mockClient.On("ListItems", mock.Anything, workspace456ID, listPageSize, 0).Return(workspaceItems, nil)
 
 	// Test workspace listing
 	workspaceArgs := map[string]interface{}{
-		"workspace_id": "workspace-456",
+		"workspace_id": workspace456ID,
 		"limit":        50.0,
 		"offset":       0.0,
 	}
@@ -263,7 +264,7 @@ func TestListingToolsIntegration(t *testing.T) {
 
 	// Test collection-specific listing
 	collectionArgs := map[string]interface{}{
-		"collection_id": "collection-123",
+		"collection_id": collection123ID,
 		"limit":         50.0,
 		"offset":        0.0,
 	}
@@ -298,8 +299,8 @@ func TestErrorHandlingInExtendedTools(t *testing.T) {
 		},
 		{
 			"nuclino_search_workspace_content",
-			map[string]interface{}{"workspace_id": "ws-123"},
-			"query must be a string",
+			map[string]interface{}{"workspace_id": ws123ID},
+			"query is required",
 		},
 		{
 			"nuclino_bulk_collection_operations",
@@ -5,9 +5,16 @@ import (
 	"fmt"
 
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// pageArgs are the pagination arguments of list tools
+type pageArgs struct {
+	Limit  int `json:"limit" desc:"Maximum number of results to return (default: 50)" validate:"min=1,max=100" default:"50"`
+	Offset int `json:"offset" desc:"Number of results to skip for pagination (default: 0)" validate:"min=0"`
+}
+
 // GetItemTool implements getting a single item by ID
 type GetItemTool struct {
 	client nuclino.Client
@@ -21,24 +28,39 @@ func (t *GetItemTool) Description() string {
 	return "Get a Nuclino item by ID with full content in Markdown format"
 }
 
+// GetItemArgs are the arguments of nuclino_get_item
+type GetItemArgs struct {
+	ItemID string `json:"item_id" desc:"The ID of the item to retrieve" validate:"required"`
+	outputArgs
+}
+
 func (t *GetItemTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"item_id": StringProperty("The ID of the item to retrieve"),
-	}, []string{"item_id"})
+	return SchemaOf[GetItemArgs]()
 }
 
-func (t *GetItemTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	itemID, ok := args["item_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("item_id must be a string"))
+func (t *GetItemTool) OutputSchema() interface{} {
+	return OutputSchemaOf[ItemResult]()
+}
+
+func (t *GetItemTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *GetItemTool) run(ctx context.Context, args *GetItemArgs) (*mcp.CallToolResult, error) {
+	from, err := args.decodeCursor(t.Name())
+	if err != nil {
+		return FormatArgsError(err)
+	}
+	if from != nil && from.ItemID != args.ItemID {
+		return FormatArgsError(toolargs.Errors{{Field: "cursor", Message: "belongs to item " + from.ItemID}})
 	}
 
-	item, err := t.client.GetItem(context.Background(), itemID)
+	item, err := t.client.GetItem(ctx, args.ItemID)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	return FormatResult(item)
+	return args.formatItem(t.Name(), item, from)
 }
 
 // SearchItemsTool implements searching items with filters
@@ -54,43 +76,63 @@ func (t *SearchItemsTool) Description() string {
 	return "Search Nuclino items with query and optional filters. Returns items with full content."
 }
 
+// SearchItemsArgs are the arguments of nuclino_search_items
+type SearchItemsArgs struct {
+	Query        string `json:"query" desc:"Search query text"`
+	WorkspaceID  string `json:"workspace_id" desc:"Optional workspace ID to limit search scope"`
+	CollectionID string `json:"collection_id" desc:"Optional collection ID; only its direct children are returned"`
+	pageArgs
+	outputArgs
+}
+
 func (t *SearchItemsTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"query":        StringProperty("Search query text"),
-		"workspace_id": StringProperty("Optional workspace ID to limit search scope"),
-		"limit":        IntProperty("Maximum number of items to return (default: 50)"),
-		"offset":       IntProperty("Number of items to skip for pagination (default: 0)"),
-	}, []string{})
+	return SchemaOf[SearchItemsArgs]()
 }
 
-func (t *SearchItemsTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	req := &nuclino.SearchItemsRequest{}
+func (t *SearchItemsTool) OutputSchema() interface{} {
+	return OutputSchemaOf[ItemsResult]()
+}
 
-	if query, ok := args["query"].(string); ok {
-		req.Query = query
-	}
+func (t *SearchItemsTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	if workspaceID, ok := args["workspace_id"].(string); ok {
-		req.WorkspaceID = workspaceID
+func (t *SearchItemsTool) run(ctx context.Context, args *SearchItemsArgs) (*mcp.CallToolResult, error) {
+	from, err := args.decodeCursor(t.Name())
+	if err != nil {
+		return FormatArgsError(err)
 	}
-
-	if limit, ok := args["limit"].(float64); ok {
-		req.Limit = int(limit)
-	} else {
-		req.Limit = 50
+	req := &nuclino.SearchItemsRequest{
+		Query:       args.Query,
+		WorkspaceID: args.WorkspaceID,
+		Limit:       args.Limit,
+		Offset:      args.Offset,
 	}
-
-	if offset, ok := args["offset"].(float64); ok {
-		req.Offset = int(offset)
+	if from != nil {
+		req.Offset, req.Limit = from.Offset, from.Limit
 	}
 
 	// Get search results
-	items, err := t.client.SearchItems(context.Background(), req)
+	items, err := t.client.SearchItems(ctx, req)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	return FormatResult(items)
+	if args.CollectionID != "" {
+		collection, err := t.client.GetCollection(ctx, args.CollectionID)
+		if err != nil {
+			return FormatError(err)
+		}
+		filtered := []nuclino.Item{}
+		for _, item := range items.Results {
+			if inCollection(collection, item) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = &nuclino.ItemsResponse{Results: filtered, Total: len(filtered), Limit: items.Limit, Offset: items.Offset}
+	}
+
+	return args.formatItems(t.Name(), items, req.Offset, req.Limit, from)
 }
 
 // CreateItemTool implements creating new items
@@ -106,39 +148,35 @@ func (t *CreateItemTool) Description() string {
 	return "Create a new Nuclino item with title, content (in Markdown), and workspace ID. Optionally specify a parent item ID."
 }
 
-func (t *CreateItemTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"title":        StringProperty("The title of the item"),
-		"content":      StringProperty("The content of the item in Markdown format"),
-		"workspace_id": StringProperty("The ID of the workspace to create the item in"),
-		"parent_id":    StringProperty("Optional: The ID of the parent item (for nested structure)"),
-	}, []string{"title", "workspace_id"})
+// CreateItemArgs are the arguments of nuclino_create_item
+type CreateItemArgs struct {
+	Title       string `json:"title" desc:"The title of the item" validate:"required"`
+	Content     string `json:"content" desc:"The content of the item in Markdown format"`
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to create the item in" validate:"required"`
+	ParentID    string `json:"parent_id" desc:"Optional: The ID of the parent item (for nested structure)"`
 }
 
-func (t *CreateItemTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	req := &nuclino.CreateItemRequest{}
-
-	title, ok := args["title"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("title must be a string"))
-	}
-	req.Title = title
+func (t *CreateItemTool) InputSchema() interface{} {
+	return SchemaOf[CreateItemArgs]()
+}
 
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
-	req.WorkspaceID = workspaceID
+func (t *CreateItemTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.Item]()
+}
 
-	if content, ok := args["content"].(string); ok {
-		req.Content = content
-	}
+func (t *CreateItemTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	if parentID, ok := args["parent_id"].(string); ok && parentID != "" {
-		req.ParentID = parentID
+func (t *CreateItemTool) run(ctx context.Context, args *CreateItemArgs) (*mcp.CallToolResult, error) {
+	req := &nuclino.CreateItemRequest{
+		Title:       args.Title,
+		Content:     args.Content,
+		WorkspaceID: args.WorkspaceID,
+		ParentID:    args.ParentID,
 	}
 
-	item, err := t.client.CreateItem(context.Background(), req)
+	item, err := t.client.CreateItem(ctx, req)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -159,31 +197,32 @@ func (t *UpdateItemTool) Description() string {
 	return "Update an existing Nuclino item. You can update title and content (Markdown format)"
 }
 
-func (t *UpdateItemTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"item_id": StringProperty("The ID of the item to update"),
-		"title":   StringProperty("New title for the item (optional)"),
-		"content": StringProperty("New content for the item in Markdown format (optional)"),
-	}, []string{"item_id"})
+// UpdateItemArgs are the arguments of nuclino_update_item
+type UpdateItemArgs struct {
+	ItemID  string  `json:"item_id" desc:"The ID of the item to update" validate:"required"`
+	Title   *string `json:"title" desc:"New title for the item (optional)"`
+	Content *string `json:"content" desc:"New content for the item in Markdown format (optional)"`
 }
 
-func (t *UpdateItemTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	itemID, ok := args["item_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("item_id must be a string"))
-	}
+func (t *UpdateItemTool) InputSchema() interface{} {
+	return SchemaOf[UpdateItemArgs]()
+}
 
-	req := &nuclino.UpdateItemRequest{}
+func (t *UpdateItemTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.Item]()
+}
 
-	if title, ok := args["title"].(string); ok {
-		req.Title = &title
-	}
+func (t *UpdateItemTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	if content, ok := args["content"].(string); ok {
-		req.Content = &content
+func (t *UpdateItemTool) run(ctx context.Context, args *UpdateItemArgs) (*mcp.CallToolResult, error) {
+	req := &nuclino.UpdateItemRequest{
+		Title:   args.Title,
+		Content: args.Content,
 	}
 
-	item, err := t.client.UpdateItem(context.Background(), itemID, req)
+	item, err := t.client.UpdateItem(ctx, args.ItemID, req)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -201,29 +240,31 @@ func (t *DeleteItemTool) Name() string {
 }
 
 func (t *DeleteItemTool) Description() string {
-	return "Delete a Nuclino item (moves to trash). This is a soft delete operation."
+	return "Delete a Nuclino item (moves to trash). This is a soft delete operation. The first call returns the item and a confirm_token; call again with the token to delete."
+}
+
+// DeleteItemArgs are the arguments of nuclino_delete_item
+type DeleteItemArgs struct {
+	ItemID string `json:"item_id" desc:"The ID of the item to delete" validate:"required"`
 }
 
 func (t *DeleteItemTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"item_id": StringProperty("The ID of the item to delete"),
-	}, []string{"item_id"})
+	return SchemaOf[DeleteItemArgs]()
 }
 
-func (t *DeleteItemTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	itemID, ok := args["item_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("item_id must be a string"))
-	}
+func (t *DeleteItemTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	err := t.client.DeleteItem(context.Background(), itemID)
+func (t *DeleteItemTool) run(ctx context.Context, args *DeleteItemArgs) (*mcp.CallToolResult, error) {
+	err := t.client.DeleteItem(ctx, args.ItemID)
 	if err != nil {
 		return FormatError(err)
 	}
 
 	return FormatResult(map[string]string{
 		"status":  "success",
-		"message": fmt.Sprintf("Item %s has been deleted successfully", itemID),
+		"message": fmt.Sprintf("Item %s has been deleted successfully", args.ItemID),
 	})
 }
 
@@ -240,25 +281,26 @@ func (t *MoveItemTool) Description() string {
 	return "Move a Nuclino item from one collection to another"
 }
 
+// MoveItemArgs are the arguments of nuclino_move_item
+type MoveItemArgs struct {
+	ItemID       string `json:"item_id" desc:"The ID of the item to move" validate:"required"`
+	CollectionID string `json:"collection_id" desc:"The ID of the destination collection" validate:"required"`
+}
+
 func (t *MoveItemTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"item_id":       StringProperty("The ID of the item to move"),
-		"collection_id": StringProperty("The ID of the destination collection"),
-	}, []string{"item_id", "collection_id"})
+	return SchemaOf[MoveItemArgs]()
 }
 
-func (t *MoveItemTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	itemID, ok := args["item_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("item_id must be a string"))
-	}
+func (t *MoveItemTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.Item]()
+}
 
-	collectionID, ok := args["collection_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("collection_id must be a string"))
-	}
+func (t *MoveItemTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	item, err := t.client.MoveItem(context.Background(), itemID, collectionID)
+func (t *MoveItemTool) run(ctx context.Context, args *MoveItemArgs) (*mcp.CallToolResult, error) {
+	item, err := t.client.MoveItem(ctx, args.ItemID, args.CollectionID)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -279,37 +321,41 @@ func (t *ListItemsTool) Description() string {
 	return "List all items in a Nuclino workspace with pagination support"
 }
 
+// ListItemsArgs are the arguments of nuclino_list_items
+type ListItemsArgs struct {
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to list items from" validate:"required"`
+	pageArgs
+	outputArgs
+}
+
 func (t *ListItemsTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id": StringProperty("The ID of the workspace to list items from"),
-		"limit":        IntProperty("Maximum number of items to return (default: 50)"),
-		"offset":       IntProperty("Number of items to skip for pagination (default: 0)"),
-	}, []string{"workspace_id"})
+	return SchemaOf[ListItemsArgs]()
 }
 
-func (t *ListItemsTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
+func (t *ListItemsTool) OutputSchema() interface{} {
+	return OutputSchemaOf[ItemsResult]()
+}
 
-	limit := 50
-	offset := 0
+func (t *ListItemsTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
+func (t *ListItemsTool) run(ctx context.Context, args *ListItemsArgs) (*mcp.CallToolResult, error) {
+	from, err := args.decodeCursor(t.Name())
+	if err != nil {
+		return FormatArgsError(err)
 	}
-
-	if o, ok := args["offset"].(float64); ok {
-		offset = int(o)
+	limit, offset := args.Limit, args.Offset
+	if from != nil {
+		limit, offset = from.Limit, from.Offset
 	}
 
-	items, err := t.client.ListItems(context.Background(), workspaceID, limit, offset)
+	items, err := t.client.ListItems(ctx, args.WorkspaceID, limit, offset)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	return FormatResult(items)
+	return args.formatItems(t.Name(), items, offset, limit, from)
 }
 
 // ListCollectionItemsTool implements listing items in a specific collection
@@ -325,51 +371,47 @@ func (t *ListCollectionItemsTool) Description() string {
 	return "List all items in a specific Nuclino collection with pagination support"
 }
 
+// ListCollectionItemsArgs are the arguments of
+// nuclino_list_collection_items
+type ListCollectionItemsArgs struct {
+	CollectionID string `json:"collection_id" desc:"The ID of the collection to list items from" validate:"required"`
+	pageArgs
+	outputArgs
+}
+
 func (t *ListCollectionItemsTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"collection_id": StringProperty("The ID of the collection to list items from"),
-		"limit":         IntProperty("Maximum number of items to return (default: 50)"),
-		"offset":        IntProperty("Number of items to skip for pagination (default: 0)"),
-	}, []string{"collection_id"})
+	return SchemaOf[ListCollectionItemsArgs]()
 }
 
-func (t *ListCollectionItemsTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	collectionID, ok := args["collection_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("collection_id must be a string"))
-	}
+func (t *ListCollectionItemsTool) OutputSchema() interface{} {
+	return OutputSchemaOf[ItemsResult]()
+}
 
-	limit := 50
-	offset := 0
+func (t *ListCollectionItemsTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
+func (t *ListCollectionItemsTool) run(ctx context.Context, args *ListCollectionItemsArgs) (*mcp.CallToolResult, error) {
+	from, err := args.decodeCursor(t.Name())
+	if err != nil {
+		return FormatArgsError(err)
 	}
-
-	if o, ok := args["offset"].(float64); ok {
-		offset = int(o)
+	limit, offset := args.Limit, args.Offset
+	if from != nil {
+		limit, offset = from.Limit, from.Offset
 	}
 
 	// Get collection info first to find the workspace
-	collection, err := t.client.GetCollection(context.Background(), collectionID)
+	collection, err := t.client.GetCollection(ctx, args.CollectionID)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	// Get all items in the workspace and filter by collection
-	items, err := t.client.ListItems(context.Background(), collection.WorkspaceID, 1000, 0) // Get more to filter
+	filteredItems, err := listCollectionItems(ctx, t.client, collection)
 	if err != nil {
 		return FormatError(err)
 	}
 
-	// Filter items by collection ID
-	var filteredItems []nuclino.Item
-	for _, item := range items.Results {
-		if item.CollectionID == collectionID {
-			filteredItems = append(filteredItems, item)
-		}
-	}
-
 	// Apply pagination to filtered results
 	total := len(filteredItems)
 	start := offset
@@ -394,5 +436,5 @@ func (t *ListCollectionItemsTool) Execute(args map[string]interface{}) (*mcp.Cal
 		Offset:  offset,
 	}
 
-	return FormatResult(response)
+	return args.formatItems(t.Name(), response, offset, limit, from)
 }
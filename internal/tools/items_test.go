@@ -2,12 +2,16 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
 )
@@ -137,11 +141,21 @@ func (m *MockClient) UploadFile(ctx context.Context, workspaceID, filename strin
 	return args.Get(0).(*nuclino.File), args.Error(1)
 }
 
+func (m *MockClient) UploadFileFrom(ctx context.Context, workspaceID, filename string, r io.Reader) (*nuclino.File, error) {
+	args := m.Called(ctx, workspaceID, filename, r)
+	return args.Get(0).(*nuclino.File), args.Error(1)
+}
+
 func (m *MockClient) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
 	args := m.Called(ctx, fileID)
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (m *MockClient) DownloadFileTo(ctx context.Context, file *nuclino.File, w io.Writer) (int64, error) {
+	args := m.Called(ctx, file.ID, w)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // Test GetItemTool
 func TestGetItemTool_Execute_Success(t *testing.T) {
 	mockClient := new(MockClient)
@@ -159,7 +173,7 @@ func TestGetItemTool_Execute_Success(t *testing.T) {
 		"item_id": "item-123",
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
@@ -183,7 +197,7 @@ func TestGetItemTool_Execute_InvalidArgs(t *testing.T) {
 		"item_id": 123, // Should be string, not int
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.True(t, result.IsError)
@@ -196,6 +210,87 @@ func TestGetItemTool_Execute_InvalidArgs(t *testing.T) {
 	}
 }
 
+func TestSearchItemsTool_Execute_ArgumentErrors(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &SearchItemsTool{client: mockClient}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"query":   "roadmap",
+		"limit":   float64(500),
+		"sort_by": "title",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	var body struct {
+		Error  string `json:"error"`
+		Fields []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &body))
+	assert.Equal(t, "invalid_arguments", body.Error)
+	require.Len(t, body.Fields, 2)
+	assert.Equal(t, "limit", body.Fields[0].Field)
+	assert.Equal(t, "must be at most 100", body.Fields[0].Message)
+	assert.Equal(t, "sort_by", body.Fields[1].Field)
+	assert.Contains(t, body.Fields[1].Message, "is not a known argument")
+
+	// Nothing reaches the API
+	mockClient.AssertNotCalled(t, "SearchItems", mock.Anything, mock.Anything)
+}
+
+func TestTypedToolSchemas(t *testing.T) {
+	schema := (&CreateItemTool{}).InputSchema().(map[string]interface{})
+	assert.Equal(t, []string{"title", "workspace_id"}, schema["required"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties := (&ListItemsTool{}).InputSchema().(map[string]interface{})["properties"].(map[string]interface{})
+	limit := properties["limit"].(map[string]interface{})
+	assert.Equal(t, "integer", limit["type"])
+	assert.Equal(t, float64(50), limit["default"])
+	assert.Equal(t, float64(100), limit["maximum"])
+}
+
+func TestTypedToolSchemas_EveryTool(t *testing.T) {
+	registry := NewRegistryWithCapabilities(new(MockClient), nuclino.Capabilities{Collections: true, MoveItems: true})
+	for _, definition := range registry.Definitions() {
+		assert.Equal(t, false, definition.InputSchema["additionalProperties"], "%s takes typed arguments", definition.Name)
+	}
+}
+
+func TestFormatResult_Structured(t *testing.T) {
+	updated := time.Date(2025, 3, 4, 9, 30, 0, 0, time.UTC)
+	result, err := FormatResult(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{
+			{ID: "item-1", Title: "Roadmap", LastUpdatedAt: updated, Highlight: "Q2 <mark>roadmap</mark>\n goals"},
+			{ID: "col-1", Object: "collection", Title: "Plans"},
+		},
+		Total: 7, Offset: 5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2 of 7 items from offset 5\n"+
+		"- Roadmap [item-1] updated 2025-03-04 09:30\n"+
+		"  Q2 <mark>roadmap</mark> goals\n"+
+		"- Plans/ [col-1]", result.Content[0].(mcp.TextContent).Text)
+
+	var structured nuclino.ItemsResponse
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &structured))
+	assert.Equal(t, 7, structured.Total)
+	assert.Equal(t, "Roadmap", structured.Results[0].Title)
+
+	// Values other than objects are text only
+	result, err = FormatResult([]string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, `["a"]`, result.Content[0].(mcp.TextContent).Text)
+	assert.Nil(t, StructuredContent(result))
+
+	schema := (&GetItemTool{}).OutputSchema().(map[string]interface{})
+	assert.Contains(t, schema["properties"], "content")
+}
+
 func TestGetItemTool_Execute_APIError(t *testing.T) {
 	mockClient := new(MockClient)
 	tool := &GetItemTool{client: mockClient}
@@ -206,7 +301,7 @@ func TestGetItemTool_Execute_APIError(t *testing.T) {
 		"item_id": "nonexistent",
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.True(t, result.IsError)
@@ -250,7 +345,7 @@ func TestSearchItemsTool_Execute_Success(t *testing.T) {
 		"query": "test query",
 	}
 
-	result, err := tool.Execute(args)
+	result, err := tool.Execute(context.Background(), args)
 
 	assert.NoError(t, err)
 	assert.False(t, result.IsError)
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// GetBacklinksTool lists the items linking to an item and the item's own links
+type GetBacklinksTool struct {
+	client nuclino.Client
+	graphs *linkgraph.Builder
+}
+
+func (t *GetBacklinksTool) Name() string {
+	return "nuclino_get_backlinks"
+}
+
+func (t *GetBacklinksTool) Description() string {
+	return "Get the items linking to a Nuclino item (backlinks), the items it links to, and any of its links that are broken"
+}
+
+// GetBacklinksArgs are the arguments of nuclino_get_backlinks
+type GetBacklinksArgs struct {
+	ItemID      string `json:"item_id" desc:"The ID of the item" validate:"required"`
+	WorkspaceID string `json:"workspace_id" desc:"Workspace of the item (looked up from the item when omitted)"`
+	Refresh     bool   `json:"refresh" desc:"Rebuild the link graph instead of using the cached one (default: false)"`
+}
+
+func (t *GetBacklinksTool) InputSchema() interface{} {
+	return SchemaOf[GetBacklinksArgs]()
+}
+
+func (t *GetBacklinksTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *GetBacklinksTool) run(ctx context.Context, args *GetBacklinksArgs) (*mcp.CallToolResult, error) {
+	itemID, workspaceID := args.ItemID, args.WorkspaceID
+	if workspaceID == "" {
+		item, err := t.client.GetItem(ctx, itemID)
+		if err != nil {
+			return FormatError(err)
+		}
+		workspaceID = item.WorkspaceID
+	}
+
+	graph, err := t.graphs.Build(ctx, workspaceID, args.Refresh)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	node, ok := graph.Node(itemID)
+	if !ok {
+		return FormatError(fmt.Errorf("item %s not found in workspace %s", itemID, workspaceID))
+	}
+
+	return FormatResult(map[string]interface{}{
+		"item":         linkSummary(graph, node.ID),
+		"backlinks":    linkSummaries(graph, node.Backlinks),
+		"outgoing":     linkSummaries(graph, node.Outgoing),
+		"external":     node.External,
+		"broken_links": graph.BrokenFrom(node.ID),
+		"graph_built":  graph.BuiltAt,
+	})
+}
+
+// LinkHealthReportTool reports orphan items and broken links in a workspace
+type LinkHealthReportTool struct {
+	graphs *linkgraph.Builder
+}
+
+func (t *LinkHealthReportTool) Name() string {
+	return "nuclino_link_health_report"
+}
+
+func (t *LinkHealthReportTool) Description() string {
+	return "Analyze the links between items of a Nuclino workspace: orphan items nothing links to, links to deleted or inaccessible items, and the most linked items"
+}
+
+// LinkHealthReportArgs are the arguments of nuclino_link_health_report
+type LinkHealthReportArgs struct {
+	WorkspaceID    string `json:"workspace_id" desc:"The ID of the workspace to analyze" validate:"required"`
+	IncludeOrphans bool   `json:"include_orphans" desc:"Whether to list orphan items (default: true)" default:"true"`
+	TopLinked      int    `json:"top_linked" desc:"Number of most linked items to include (default: 10)" validate:"min=0" default:"10"`
+	Refresh        bool   `json:"refresh" desc:"Rebuild the link graph instead of using the cached one (default: false)"`
+}
+
+func (t *LinkHealthReportTool) InputSchema() interface{} {
+	return SchemaOf[LinkHealthReportArgs]()
+}
+
+func (t *LinkHealthReportTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *LinkHealthReportTool) run(ctx context.Context, args *LinkHealthReportArgs) (*mcp.CallToolResult, error) {
+	workspaceID := args.WorkspaceID
+	graph, err := t.graphs.Build(ctx, workspaceID, args.Refresh)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	totalLinks := 0
+	for _, node := range graph.Nodes {
+		totalLinks += len(node.Outgoing)
+	}
+	orphans := graph.Orphans()
+
+	report := map[string]interface{}{
+		"workspace_id": workspaceID,
+		"graph_built":  graph.BuiltAt,
+		"summary": map[string]interface{}{
+			"items":        len(graph.Nodes),
+			"links":        totalLinks,
+			"orphans":      len(orphans),
+			"broken_links": len(graph.Broken),
+		},
+		"broken_links": graph.Broken,
+	}
+
+	if args.IncludeOrphans {
+		orphanList := make([]map[string]interface{}, 0, len(orphans))
+		for _, node := range orphans {
+			orphanList = append(orphanList, linkSummary(graph, node.ID))
+		}
+		report["orphans"] = orphanList
+	}
+
+	if args.TopLinked > 0 {
+		mostLinked := make([]map[string]interface{}, 0, args.TopLinked)
+		for _, node := range graph.MostLinked(args.TopLinked) {
+			summary := linkSummary(graph, node.ID)
+			summary["backlinks"] = len(node.Backlinks)
+			mostLinked = append(mostLinked, summary)
+		}
+		report["most_linked"] = mostLinked
+	}
+
+	return FormatResult(report)
+}
+
+func linkSummary(graph *linkgraph.Graph, id string) map[string]interface{} {
+	summary := map[string]interface{}{"id": id}
+	if node, ok := graph.Node(id); ok {
+		summary["title"] = node.Title
+		if node.URL != "" {
+			summary["url"] = node.URL
+		}
+	}
+	return summary
+}
+
+func linkSummaries(graph *linkgraph.Graph, ids []string) []map[string]interface{} {
+	summaries := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		summaries = append(summaries, linkSummary(graph, id))
+	}
+	return summaries
+}
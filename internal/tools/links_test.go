@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+const (
+	linkedHomeID  = "aaaaaaaa-0000-0000-0000-000000000001"
+	linkedGuideID = "aaaaaaaa-0000-0000-0000-000000000002"
+	linkedGoneID  = "aaaaaaaa-0000-0000-0000-000000000003"
+)
+
+func mockLinkedWorkspace(mockClient *MockClient) {
+	home := &nuclino.Item{ID: linkedHomeID, Title: "Home", WorkspaceID: "workspace-1",
+		Content: "[Guide](https://app.nuclino.com/T/W/Guide-" + linkedGuideID + ")"}
+	guide := &nuclino.Item{ID: linkedGuideID, Title: "Guide", WorkspaceID: "workspace-1",
+		Content: "[Gone](https://app.nuclino.com/T/W/Gone-" + linkedGoneID + ")"}
+
+	mockClient.On("ListItems", mock.Anything, "workspace-1", 100, 0).
+		Return(&nuclino.ItemsResponse{Results: []nuclino.Item{{ID: linkedHomeID}, {ID: linkedGuideID}}}, nil)
+	mockClient.On("GetItem", mock.Anything, linkedHomeID).Return(home, nil)
+	mockClient.On("GetItem", mock.Anything, linkedGuideID).Return(guide, nil)
+	mockClient.On("GetItem", mock.Anything, linkedGoneID).Return((*nuclino.Item)(nil), nuclino.NewAPIError(404, "not found"))
+}
+
+func TestGetBacklinksTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	mockLinkedWorkspace(mockClient)
+	tool := &GetBacklinksTool{client: mockClient, graphs: linkgraph.NewBuilder(mockClient, linkgraph.DefaultConfig())}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"item_id": linkedGuideID})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"title":"Home"`)
+	assert.Contains(t, text, `"reason":"not_found"`)
+}
+
+func TestLinkHealthReportTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	mockLinkedWorkspace(mockClient)
+	tool := &LinkHealthReportTool{graphs: linkgraph.NewBuilder(mockClient, linkgraph.DefaultConfig())}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"workspace_id": "workspace-1"})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"orphans":1`)
+	assert.Contains(t, text, `"broken_links":1`)
+	assert.Contains(t, text, linkedGoneID)
+}
+
+func TestLintWorkspaceTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	mockLinkedWorkspace(mockClient)
+	tool := &LintWorkspaceTool{graphs: linkgraph.NewBuilder(mockClient, linkgraph.DefaultConfig())}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"workspace_id": "workspace-1",
+		"item_id":      linkedGuideID,
+		"min_severity": "error",
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"rule":"broken-link"`)
+	assert.Contains(t, text, `"severity":"error"`)
+	assert.Contains(t, text, `"info":0`)
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
+	"github.com/lukasz/nuclino-mcp-server/internal/lint"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// LintWorkspaceTool checks workspace items against the content quality rules
+type LintWorkspaceTool struct {
+	graphs *linkgraph.Builder
+}
+
+func (t *LintWorkspaceTool) Name() string {
+	return "nuclino_lint_workspace"
+}
+
+func (t *LintWorkspaceTool) Description() string {
+	return "Check the content quality of Nuclino items: missing H1, duplicated titles, empty sections, inconsistent heading levels, broken internal links, TODO markers, oversized and stale items. Issues are reported per item with severity."
+}
+
+// LintWorkspaceArgs are the arguments of nuclino_lint_workspace. StaleDays
+// and MaxChars fall back to the lint defaults when omitted.
+type LintWorkspaceArgs struct {
+	WorkspaceID  string `json:"workspace_id" desc:"The ID of the workspace to lint" validate:"required"`
+	ItemID       string `json:"item_id" desc:"Only report issues for this item (optional)"`
+	StaleDays    *int   `json:"stale_days" desc:"Flag items not updated for this many days, 0 to disable (default: 180)" validate:"min=0"`
+	MaxChars     *int   `json:"max_chars" desc:"Flag items longer than this many characters, 0 to disable (default: 50000)" validate:"min=0"`
+	DisableRules string `json:"disable_rules" desc:"Comma-separated rule names to skip (missing-h1, duplicate-title, empty-section, heading-levels, broken-link, todo-marker, oversized, stale)"`
+	MinSeverity  string `json:"min_severity" desc:"Lowest severity to report: info, warning or error (default: info)"`
+	Refresh      bool   `json:"refresh" desc:"Reload items instead of using cached content (default: false)"`
+}
+
+func (t *LintWorkspaceTool) InputSchema() interface{} {
+	return SchemaOf[LintWorkspaceArgs]()
+}
+
+func (t *LintWorkspaceTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *LintWorkspaceTool) run(ctx context.Context, args *LintWorkspaceArgs) (*mcp.CallToolResult, error) {
+	config := lint.DefaultConfig()
+	if args.StaleDays != nil {
+		config.StaleDays = *args.StaleDays
+	}
+	if args.MaxChars != nil {
+		config.MaxChars = *args.MaxChars
+	}
+	for _, name := range strings.Split(args.DisableRules, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			config.Disabled = append(config.Disabled, name)
+		}
+	}
+	if args.MinSeverity != "" {
+		severity, err := lint.ParseSeverity(args.MinSeverity)
+		if err != nil {
+			return FormatArgsError(toolargs.Errors{{Field: "min_severity", Message: err.Error()}})
+		}
+		config.MinSeverity = severity
+	}
+
+	graph, err := t.graphs.Build(ctx, args.WorkspaceID, args.Refresh)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	var itemIDs []string
+	if args.ItemID != "" {
+		if _, exists := graph.Items[args.ItemID]; !exists {
+			return FormatError(fmt.Errorf("item %s not found in workspace %s", args.ItemID, args.WorkspaceID))
+		}
+		itemIDs = append(itemIDs, args.ItemID)
+	}
+
+	return FormatResult(lint.NewLinter(config).LintGraph(graph, itemIDs...))
+}
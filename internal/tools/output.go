@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+)
+
+// OutputSchemer is implemented by tools that declare the schema of their
+// structured content. Every successful result of such a tool must match it.
+type OutputSchemer interface {
+	OutputSchema() interface{}
+}
+
+// OutputSchemaOf returns the output schema generated from the result type R
+func OutputSchemaOf[R any]() interface{} {
+	var result R
+	return toolargs.OutputSchema(&result)
+}
+
+// ToolDefinition is a tools/list entry. Unlike mcp.Tool, which predates
+// output schemas, it carries the full input schema, including required and
+// additionalProperties, and the tool's output schema.
+type ToolDefinition struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"inputSchema"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+}
+
+// CallToolResult is a tools/call result as sent to the client: an
+// mcp.CallToolResult plus the structuredContent it predates
+type CallToolResult struct {
+	Meta              map[string]interface{} `json:"_meta,omitempty"`
+	Content           []interface{}          `json:"content"`
+	StructuredContent json.RawMessage        `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+}
+
+// structuredKey holds a result's structured content in its _meta until
+// WireResult moves it to structuredContent
+const structuredKey = "nuclino/structuredContent"
+
+// withStructuredContent attaches the JSON object data to result
+func withStructuredContent(result *mcp.CallToolResult, data json.RawMessage) *mcp.CallToolResult {
+	if result.Meta == nil {
+		result.Meta = make(mcp.CallToolResultMeta)
+	}
+	result.Meta[structuredKey] = data
+	return result
+}
+
+// StructuredContent returns the structured content of result, or nil for
+// results with only text, such as errors
+func StructuredContent(result *mcp.CallToolResult) json.RawMessage {
+	if result == nil {
+		return nil
+	}
+	data, _ := result.Meta[structuredKey].(json.RawMessage)
+	return data
+}
+
+// WireResult converts result for sending, moving its structured content
+// out of _meta
+func WireResult(result *mcp.CallToolResult) *CallToolResult {
+	wire := &CallToolResult{
+		Content:           result.Content,
+		StructuredContent: StructuredContent(result),
+		IsError:           result.IsError,
+	}
+	for key, value := range result.Meta {
+		if key == structuredKey {
+			continue
+		}
+		if wire.Meta == nil {
+			wire.Meta = make(map[string]interface{})
+		}
+		wire.Meta[key] = value
+	}
+	if wire.Content == nil {
+		wire.Content = []interface{}{}
+	}
+	return wire
+}
+
+// isObject reports whether data is a JSON object; structuredContent must be
+// one
+func isObject(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
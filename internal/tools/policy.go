@@ -0,0 +1,352 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// Permission denial reasons
+const (
+	ReasonDenied    = "denied_by_policy"
+	ReasonNotAllow  = "not_allowed_by_policy"
+	ReasonCategory  = "category_not_allowed"
+	ReasonReadOnly  = "read_only"
+	ReasonWorkspace = "workspace_not_allowed"
+)
+
+// Policy decides which tools are listed and which calls may run. The zero
+// value allows everything.
+type Policy struct {
+	// Allow lists tool name patterns (path.Match syntax, e.g. "nuclino_get_*");
+	// when set, other tools are hidden
+	Allow []string
+	// Deny lists tool name patterns that are always hidden; deny wins over
+	// allow
+	Deny []string
+	// Categories limits tools to these categories (read, write,
+	// destructive); empty allows every category
+	Categories []string
+	// Workspaces limits calls to these workspace IDs. Items, collections
+	// and files are checked through their workspace; calls that change
+	// content without naming a target are refused, and reads without one,
+	// such as a search across workspaces, only see allowed workspaces.
+	Workspaces []string
+	// ReadOnly only allows read tools, whatever Categories says
+	ReadOnly bool
+}
+
+// Validate checks the patterns and category names
+func (p Policy) Validate() error {
+	for _, pattern := range append(append([]string(nil), p.Allow...), p.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	for _, category := range p.Categories {
+		switch category {
+		case CategoryRead, CategoryWrite, CategoryDestructive:
+		default:
+			return fmt.Errorf("unknown tool category %q (use %s, %s or %s)", category, CategoryRead, CategoryWrite, CategoryDestructive)
+		}
+	}
+	return nil
+}
+
+// PermissionError reports a tool call blocked by the policy
+type PermissionError struct {
+	Tool      string `json:"tool"`
+	Reason    string `json:"reason"`
+	Category  string `json:"category,omitempty"`
+	Workspace string `json:"workspace_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("permission denied for %s: %s", e.Tool, e.Message)
+}
+
+// FormatPermissionError formats a permission error as a structured MCP
+// error result, so clients can tell policy denials from API failures
+func FormatPermissionError(err *PermissionError) (*mcp.CallToolResult, error) {
+	body, _ := json.MarshalIndent(struct {
+		Error string `json:"error"`
+		*PermissionError
+	}{"permission_denied", err}, "", "  ")
+	return &mcp.CallToolResult{
+		Content: []interface{}{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(body),
+			},
+		},
+		IsError: true,
+	}, nil
+}
+
+// permits reports whether a tool may be listed and called at all; the
+// workspace allowlist is checked per call by authorize
+func (p Policy) permits(name string) *PermissionError {
+	category := CategoryOf(name)
+	deny := func(reason, format string, a ...interface{}) *PermissionError {
+		return &PermissionError{Tool: name, Reason: reason, Category: category, Message: fmt.Sprintf(format, a...)}
+	}
+
+	for _, pattern := range p.Deny {
+		if matched, _ := path.Match(pattern, name); matched {
+			return deny(ReasonDenied, "the tool matches the deny pattern %q", pattern)
+		}
+	}
+	if len(p.Allow) > 0 {
+		allowed := false
+		for _, pattern := range p.Allow {
+			if matched, _ := path.Match(pattern, name); matched {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return deny(ReasonNotAllow, "the tool matches no allow pattern")
+		}
+	}
+	if p.ReadOnly && category != CategoryRead {
+		return deny(ReasonReadOnly, "the server is in read-only mode and this tool is %s", category)
+	}
+	if len(p.Categories) > 0 && !containsString(p.Categories, category) {
+		return deny(ReasonCategory, "%s tools are not allowed; allowed categories: %s", category, strings.Join(p.Categories, ", "))
+	}
+	return nil
+}
+
+// itemArgs are the arguments naming items or collections, checked against
+// the workspace allowlist through the item's workspace
+var itemArgs = []string{
+	"item_id", "collection_id", "parent_id", "target_id", "keep_id", "item_ids", "merge_ids",
+	"source_collection", "target_collection",
+}
+
+// authorize checks a call against the policy, looking up the workspace of
+// the items it names, directly or through a saved plan, when a
+// workspace allowlist is set
+func (p Policy) authorize(ctx context.Context, client nuclino.Client, plans bulk.Store, name string, args map[string]interface{}) *PermissionError {
+	if err := p.permits(name); err != nil {
+		return err
+	}
+	if len(p.Workspaces) == 0 {
+		return nil
+	}
+
+	category := CategoryOf(name)
+	deny := func(workspaceID, format string, a ...interface{}) *PermissionError {
+		return &PermissionError{Tool: name, Reason: ReasonWorkspace, Category: category, Workspace: workspaceID, Message: fmt.Sprintf(format, a...)}
+	}
+	check := func(workspaceID string) *PermissionError {
+		if !p.allowsWorkspace(workspaceID) {
+			return deny(workspaceID, "workspace %s is not in the workspace allowlist", workspaceID)
+		}
+		return nil
+	}
+
+	// A saved plan, from nuclino_bulk_plan or a find and replace preview,
+	// names its items in its steps and selector rather than in the
+	// arguments, and a workspace_id passed along with it is not what gets
+	// written
+	planID, _ := args["plan_id"].(string)
+	if token, _ := args["plan_token"].(string); token != "" {
+		planID = token
+	}
+	usesPlan := planID != "" && plans != nil
+
+	targeted := false
+	if workspaceID, _ := args["workspace_id"].(string); workspaceID != "" && !usesPlan {
+		targeted = true
+		if err := check(workspaceID); err != nil {
+			return err
+		}
+	}
+
+	checkItem := func(key, id string) *PermissionError {
+		item, err := client.GetItem(ctx, id)
+		if err != nil {
+			return deny("", "cannot check the workspace of %s %s: %v", key, id, err)
+		}
+		return check(item.WorkspaceID)
+	}
+	for _, key := range itemArgs {
+		for _, id := range idList(args[key]) {
+			targeted = true
+			if err := checkItem(key, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	if usesPlan {
+		targeted = true
+		plan, err := plans.Load(planID)
+		if err != nil {
+			return deny("", "cannot check the workspaces of plan %s: %v", planID, err)
+		}
+		if plan.Selector.WorkspaceID != "" {
+			if err := check(plan.Selector.WorkspaceID); err != nil {
+				return err
+			}
+		}
+		if plan.Selector.ParentID != "" {
+			if err := checkItem("parent_id", plan.Selector.ParentID); err != nil {
+				return err
+			}
+		}
+		for _, step := range plan.Steps {
+			if err := checkItem("item_id", step.ItemID); err != nil {
+				return err
+			}
+			if step.TargetID != "" {
+				if err := checkItem("target_id", step.TargetID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if fileID, _ := args["file_id"].(string); fileID != "" {
+		targeted = true
+		file, err := client.GetFile(ctx, fileID)
+		if err != nil || file.ItemID == "" {
+			return deny("", "cannot check the workspace of file %s", fileID)
+		}
+		item, err := client.GetItem(ctx, file.ItemID)
+		if err != nil {
+			return deny("", "cannot check the workspace of file %s: %v", fileID, err)
+		}
+		if err := check(item.WorkspaceID); err != nil {
+			return err
+		}
+	}
+
+	if !targeted && category != CategoryRead {
+		return deny("", "the call names no workspace, item or collection to check against the workspace allowlist")
+	}
+	return nil
+}
+
+// allowsWorkspace reports whether the workspace allowlist admits a workspace
+func (p Policy) allowsWorkspace(workspaceID string) bool {
+	return len(p.Workspaces) == 0 || containsString(p.Workspaces, workspaceID)
+}
+
+// idList reads an ID argument given as a string, a comma-separated string
+// or an array
+func idList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return splitIDs(v)
+	case []interface{}:
+		var ids []string
+		for _, id := range v {
+			if s, ok := id.(string); ok && s != "" {
+				ids = append(ids, s)
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, entry := range list {
+		if entry == value {
+			return true
+		}
+	}
+	return false
+}
+
+// workspaceClient limits what a client lists and searches to the workspace
+// allowlist, so reads that name no workspace only return allowed content.
+// Calls that name items are checked by Policy.authorize instead.
+type workspaceClient struct {
+	nuclino.Client
+	policy Policy
+}
+
+// check refuses to list a workspace outside the allowlist
+func (c *workspaceClient) check(workspaceID string) error {
+	if !c.policy.allowsWorkspace(workspaceID) {
+		return fmt.Errorf("workspace %s is not in the workspace allowlist", workspaceID)
+	}
+	return nil
+}
+
+// ListWorkspaces pages through the allowed workspaces. The allowlist is
+// short, so its workspaces are fetched directly rather than filtered out of
+// the account's pages, which would leave short pages mid-listing.
+func (c *workspaceClient) ListWorkspaces(ctx context.Context, limit, offset int) (*nuclino.WorkspacesResponse, error) {
+	allowed := c.policy.Workspaces
+	response := &nuclino.WorkspacesResponse{Results: []nuclino.Workspace{}, Total: len(allowed), Limit: limit, Offset: offset}
+	if limit <= 0 {
+		limit = len(allowed)
+	}
+	for _, workspaceID := range allowed[min(offset, len(allowed)):min(offset+limit, len(allowed))] {
+		workspace, err := c.Client.GetWorkspace(ctx, workspaceID)
+		if nuclino.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		response.Results = append(response.Results, *workspace)
+	}
+	return response, nil
+}
+
+// SearchItems drops results from workspaces outside the allowlist
+func (c *workspaceClient) SearchItems(ctx context.Context, req *nuclino.SearchItemsRequest) (*nuclino.ItemsResponse, error) {
+	if req.WorkspaceID != "" {
+		if err := c.check(req.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+	response, err := c.Client.SearchItems(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := *response
+	filtered.Results = []nuclino.Item{}
+	for _, item := range response.Results {
+		if c.policy.allowsWorkspace(item.WorkspaceID) {
+			filtered.Results = append(filtered.Results, item)
+		}
+	}
+	filtered.Total -= len(response.Results) - len(filtered.Results)
+	return &filtered, nil
+}
+
+func (c *workspaceClient) ListItems(ctx context.Context, workspaceID string, limit, offset int) (*nuclino.ItemsResponse, error) {
+	if err := c.check(workspaceID); err != nil {
+		return nil, err
+	}
+	return c.Client.ListItems(ctx, workspaceID, limit, offset)
+}
+
+func (c *workspaceClient) ListCollections(ctx context.Context, workspaceID string, limit, offset int) (*nuclino.CollectionsResponse, error) {
+	if err := c.check(workspaceID); err != nil {
+		return nil, err
+	}
+	return c.Client.ListCollections(ctx, workspaceID, limit, offset)
+}
+
+func (c *workspaceClient) ListFiles(ctx context.Context, workspaceID string, limit, offset int) (*nuclino.FilesResponse, error) {
+	if err := c.check(workspaceID); err != nil {
+		return nil, err
+	}
+	return c.Client.ListFiles(ctx, workspaceID, limit, offset)
+}
@@ -0,0 +1,254 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func toolNames(registry *Registry) map[string]bool {
+	names := make(map[string]bool)
+	for _, tool := range registry.ListTools() {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+func permissionReason(t *testing.T, result *mcp.CallToolResult) string {
+	require.True(t, result.IsError)
+	var body struct {
+		Error  string `json:"error"`
+		Reason string `json:"reason"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &body))
+	assert.Equal(t, "permission_denied", body.Error)
+	return body.Reason
+}
+
+func TestPolicy_Patterns(t *testing.T) {
+	options := DefaultOptions()
+	options.Policy = Policy{
+		Allow: []string{"nuclino_get_*", "nuclino_list_*", "nuclino_update_item"},
+		Deny:  []string{"nuclino_get_team"},
+	}
+	registry := NewRegistryWithOptions(new(MockClient), options)
+
+	names := toolNames(registry)
+	assert.True(t, names["nuclino_get_item"])
+	assert.True(t, names["nuclino_update_item"])
+	assert.False(t, names["nuclino_get_team"])
+	assert.False(t, names["nuclino_create_item"])
+
+	result, err := registry.CallTool("nuclino_get_team", map[string]interface{}{"team_id": "team-1"})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonDenied, permissionReason(t, result))
+
+	result, err = registry.CallTool("nuclino_create_item", map[string]interface{}{"workspace_id": ws1ID})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonNotAllow, permissionReason(t, result))
+}
+
+func TestPolicy_Categories(t *testing.T) {
+	options := DefaultOptions()
+	options.Policy = Policy{Categories: []string{CategoryRead, CategoryWrite}}
+	registry := NewRegistryWithOptions(new(MockClient), options)
+
+	names := toolNames(registry)
+	assert.True(t, names["nuclino_update_item"])
+	assert.False(t, names["nuclino_delete_workspace"])
+
+	result, err := registry.CallTool("nuclino_delete_workspace", map[string]interface{}{"workspace_id": ws1ID})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonCategory, permissionReason(t, result))
+
+	assert.Error(t, Policy{Categories: []string{"admin"}}.Validate())
+	assert.Error(t, Policy{Deny: []string{"nuclino_["}}.Validate())
+}
+
+func TestPolicy_WorkspaceAllowlist(t *testing.T) {
+	mockClient := new(MockClient)
+	options := DefaultOptions()
+	options.Policy = Policy{Workspaces: []string{allowedWorkspaceID}}
+	registry := NewRegistryWithOptions(mockClient, options)
+
+	mockClient.On("GetItem", mock.Anything, inItemID).Return(&nuclino.Item{ID: inItemID, WorkspaceID: allowedWorkspaceID, Title: "Inside"}, nil)
+	mockClient.On("GetItem", mock.Anything, outItemID).Return(&nuclino.Item{ID: outItemID, WorkspaceID: otherWorkspaceID}, nil)
+
+	result, err := registry.CallTool("nuclino_get_item", map[string]interface{}{"item_id": inItemID})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Inside")
+
+	result, err = registry.CallTool("nuclino_delete_item", map[string]interface{}{"item_id": outItemID})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonWorkspace, permissionReason(t, result))
+
+	result, err = registry.CallTool("nuclino_create_workspace", map[string]interface{}{"name": "New", "team_id": "team-1"})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonWorkspace, permissionReason(t, result))
+
+	mockClient.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything)
+}
+
+func TestPolicy_WorkspaceAllowlistChecksBulkPlans(t *testing.T) {
+	mockClient := new(MockClient)
+	options := DefaultOptions()
+	options.Policy = Policy{Workspaces: []string{allowedWorkspaceID}}
+	options.BulkStoreDir = t.TempDir()
+	registry := NewRegistryWithOptions(mockClient, options)
+
+	inside := &nuclino.Item{ID: inItemID, WorkspaceID: allowedWorkspaceID, Title: "Draft: Inside"}
+	mockClient.On("GetItem", mock.Anything, inItemID).Return(inside, nil)
+	mockClient.On("GetItem", mock.Anything, outItemID).Return(&nuclino.Item{ID: outItemID, WorkspaceID: otherWorkspaceID, Title: "Draft: Outside"}, nil)
+	mockClient.On("UpdateItem", mock.Anything, inItemID, mock.Anything).Return(inside, nil)
+
+	op, err := bulk.NewOperation(bulk.OperationSpec{Type: bulk.OpRetitle, Pattern: "^Draft: "})
+	require.NoError(t, err)
+	plan := func(items ...*nuclino.Item) string {
+		p := bulk.NewPlan(bulk.Selector{ItemIDs: []string{inItemID, outItemID}}, op, items)
+		require.NoError(t, registry.bulkPlans.Save(p))
+		return p.ID
+	}
+
+	result, err := registry.CallTool("nuclino_bulk_execute", map[string]interface{}{"plan_id": plan(inside)})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	mockClient.AssertNumberOfCalls(t, "UpdateItem", 1)
+
+	result, err = registry.CallTool("nuclino_bulk_execute", map[string]interface{}{
+		"plan_id": plan(&nuclino.Item{ID: inItemID, Title: "Draft: Inside"}, &nuclino.Item{ID: outItemID, Title: "Draft: Outside"}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonWorkspace, permissionReason(t, result))
+	mockClient.AssertNumberOfCalls(t, "UpdateItem", 1)
+}
+
+func TestPolicy_WorkspaceAllowlistChecksFindReplacePlans(t *testing.T) {
+	mockClient := new(MockClient)
+	storeDir := t.TempDir()
+	registry := func(workspaceID string) *Registry {
+		options := DefaultOptions()
+		options.Policy = Policy{Workspaces: []string{workspaceID}}
+		options.BulkStoreDir = storeDir
+		return NewRegistryWithOptions(mockClient, options)
+	}
+	engineering, product := registry(allowedWorkspaceID), registry(otherWorkspaceID)
+
+	inside := &nuclino.Item{ID: inItemID, WorkspaceID: allowedWorkspaceID, Content: "old name"}
+	outside := &nuclino.Item{ID: outItemID, WorkspaceID: otherWorkspaceID, Content: "old name"}
+	mockClient.On("GetItem", mock.Anything, inItemID).Return(inside, nil)
+	mockClient.On("GetItem", mock.Anything, outItemID).Return(outside, nil)
+	mockClient.On("UpdateItem", mock.Anything, inItemID, mock.Anything).Return(inside, nil)
+
+	op, err := bulk.NewOperation(bulk.OperationSpec{Type: bulk.OpReplace, Pattern: "old", Replacement: "new"})
+	require.NoError(t, err)
+	plan := func(workspaceID string, item *nuclino.Item) string {
+		p := bulk.NewPlan(bulk.Selector{WorkspaceID: workspaceID}, op, []*nuclino.Item{item})
+		require.NoError(t, engineering.bulkPlans.Save(p))
+		return p.ID
+	}
+
+	// A token alone is checked through its plan
+	result, err := engineering.CallTool("nuclino_find_replace", map[string]interface{}{"plan_token": plan(allowedWorkspaceID, inside)})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	mockClient.AssertNumberOfCalls(t, "UpdateItem", 1)
+
+	// A plan from another workspace stays out of reach whatever workspace_id says
+	token := plan(otherWorkspaceID, outside)
+	result, err = engineering.CallTool("nuclino_find_replace", map[string]interface{}{
+		"plan_token":   token,
+		"workspace_id": allowedWorkspaceID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonWorkspace, permissionReason(t, result))
+
+	mockClient.AssertNumberOfCalls(t, "UpdateItem", 1)
+
+	// The registry that allows the plan's workspace applies it
+	mockClient.On("UpdateItem", mock.Anything, outItemID, mock.Anything).Return(outside, nil)
+	result, err = product.CallTool("nuclino_find_replace", map[string]interface{}{"plan_token": token})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	mockClient.AssertCalled(t, "UpdateItem", mock.Anything, outItemID, mock.Anything)
+}
+
+func TestPolicy_WorkspaceAllowlistFiltersUntargetedReads(t *testing.T) {
+	mockClient := new(MockClient)
+	options := DefaultOptions()
+	options.Policy = Policy{Workspaces: []string{allowedWorkspaceID}}
+	registry := NewRegistryWithOptions(mockClient, options)
+
+	// A search without workspace_id only returns items of allowed workspaces
+	mockClient.On("SearchItems", mock.Anything, mock.Anything).Return(&nuclino.ItemsResponse{
+		Results: []nuclino.Item{
+			{ID: inItemID, WorkspaceID: allowedWorkspaceID, Title: "Inside"},
+			{ID: outItemID, WorkspaceID: otherWorkspaceID, Title: "Outside"},
+		},
+		Total: 2,
+	}, nil)
+	result, err := registry.CallTool("nuclino_search_items", map[string]interface{}{"query": "side"})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var found ItemsResult
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &found))
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, inItemID, found.Results[0].ID)
+	assert.Equal(t, 1, found.Total)
+	assert.NotContains(t, result.Content[0].(mcp.TextContent).Text, "Outside")
+
+	// Listing workspaces only shows the allowed ones
+	mockClient.On("GetWorkspace", mock.Anything, allowedWorkspaceID).Return(&nuclino.Workspace{ID: allowedWorkspaceID, Name: "Allowed"}, nil)
+	result, err = registry.CallTool("nuclino_list_workspaces", map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var workspaces nuclino.WorkspacesResponse
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &workspaces))
+	require.Len(t, workspaces.Results, 1)
+	assert.Equal(t, allowedWorkspaceID, workspaces.Results[0].ID)
+
+	// Naming another workspace is still refused
+	result, err = registry.CallTool("nuclino_search_items", map[string]interface{}{"query": "side", "workspace_id": otherWorkspaceID})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonWorkspace, permissionReason(t, result))
+
+	mockClient.AssertNotCalled(t, "ListWorkspaces", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPolicy_ReferencesFollowThePolicy(t *testing.T) {
+	mockClient := new(MockClient)
+	options := DefaultOptions()
+	options.Policy = Policy{Workspaces: []string{allowedWorkspaceID}, Deny: []string{"nuclino_delete_item"}}
+	registry := NewRegistryWithOptions(mockClient, options)
+
+	// A denied tool is refused before its references are looked up, which
+	// would call the unmocked ListWorkspaces
+	result, err := registry.CallTool("nuclino_delete_item", map[string]interface{}{"item_id": "Secret plans"})
+	require.NoError(t, err)
+	assert.Equal(t, ReasonDenied, permissionReason(t, result))
+
+	// Names only resolve to allowed workspaces, which are the only candidates
+	mockClient.On("GetWorkspace", mock.Anything, allowedWorkspaceID).Return(&nuclino.Workspace{ID: allowedWorkspaceID, Name: "Allowed"}, nil)
+	result, err = registry.CallTool("nuclino_list_items", map[string]interface{}{"workspace_id": "Other"})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	var body struct {
+		Reason     string `json:"reason"`
+		Candidates []struct {
+			ID string `json:"id"`
+		} `json:"candidates"`
+	}
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &body))
+	assert.Equal(t, "not_found", body.Reason)
+	require.Len(t, body.Candidates, 1)
+	assert.Equal(t, allowedWorkspaceID, body.Candidates[0].ID)
+
+	mockClient.AssertNotCalled(t, "ListWorkspaces", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,297 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/outline"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+)
+
+// ReadItemTool pages through the content of a long item
+type ReadItemTool struct {
+	client nuclino.Client
+}
+
+// ReadItemArgs are the arguments of nuclino_read_item
+type ReadItemArgs struct {
+	ItemID  string `json:"item_id" desc:"The ID of the item to read" validate:"required"`
+	Offset  int    `json:"offset" desc:"Character offset in the content, or in the section, to start reading at (default: 0)" validate:"min=0"`
+	Section string `json:"section" desc:"Only read this section: an anchor from nuclino_get_outline, or the heading text"`
+	budgetArgs
+}
+
+// ItemContent is a page of an item's content
+type ItemContent struct {
+	ItemID  string `json:"item_id"`
+	Title   string `json:"title"`
+	Section string `json:"section,omitempty"`
+	Anchor  string `json:"anchor,omitempty"`
+	// Offset is the character offset of content in the item or section
+	Offset int `json:"offset"`
+	// TotalChars is the length of the item or section in characters
+	TotalChars   int           `json:"total_chars"`
+	Content      string        `json:"content"`
+	Continuation *Continuation `json:"continuation,omitempty"`
+}
+
+func (t *ReadItemTool) Name() string {
+	return "nuclino_read_item"
+}
+
+func (t *ReadItemTool) Description() string {
+	return "Read the content of a Nuclino item in pages, from a character offset or only the section under a heading, cut at paragraph boundaries to max_chars. Follow continuation.next_cursor to read on"
+}
+
+func (t *ReadItemTool) InputSchema() interface{} {
+	return SchemaOf[ReadItemArgs]()
+}
+
+func (t *ReadItemTool) OutputSchema() interface{} {
+	return OutputSchemaOf[ItemContent]()
+}
+
+func (t *ReadItemTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *ReadItemTool) run(ctx context.Context, args *ReadItemArgs) (*mcp.CallToolResult, error) {
+	from, err := args.decodeCursor(t.Name())
+	if err != nil {
+		return FormatArgsError(err)
+	}
+	offset, section := args.Offset, args.Section
+	if from != nil {
+		if from.ItemID != args.ItemID {
+			return FormatArgsError(toolargs.Errors{{Field: "cursor", Message: "belongs to item " + from.ItemID}})
+		}
+		offset, section = from.Offset, from.Section
+	}
+
+	item, err := t.client.GetItem(ctx, args.ItemID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	content, title := item.Content, ""
+	if section != "" {
+		structure := outline.Parse(content)
+		found, ok := structure.Find(section)
+		if !ok {
+			return FormatError(noSectionError(item.ID, section, structure))
+		}
+		content, section, title = found.Text(content), found.Anchor, found.Title
+	}
+
+	runes := []rune(content)
+	offset = min(offset, len(runes))
+	rest := runes[offset:]
+	page := &ItemContent{
+		ItemID:     item.ID,
+		Title:      item.Title,
+		Section:    title,
+		Anchor:     section,
+		Offset:     offset,
+		TotalChars: len(runes),
+	}
+	size, _ := json.Marshal(page)
+	keep := cutPage(rest, args.MaxChars-len(size)-continuationReserve)
+	page.Content = string(rest[:keep])
+	if keep < len(rest) {
+		page.Continuation = &Continuation{
+			NextCursor: cursor{Tool: t.Name(), ItemID: item.ID, Section: section, Offset: offset + keep}.encode(),
+			Remaining:  len(rest) - keep,
+		}
+	}
+	return FormatResult(page)
+}
+
+// noSectionError reports the sections of an item without the one asked for
+func noSectionError(itemID, section string, structure *outline.Outline) error {
+	anchors := "none"
+	if len(structure.Sections) > 0 {
+		anchors = strings.Join(structure.Anchors(), ", ")
+	}
+	return fmt.Errorf("no section %q in item %s; sections: %s", section, itemID, anchors)
+}
+
+// GetOutlineTool returns the heading structure of an item
+type GetOutlineTool struct {
+	client nuclino.Client
+}
+
+// GetOutlineArgs are the arguments of nuclino_get_outline
+type GetOutlineArgs struct {
+	ItemID string `json:"item_id" desc:"The ID of the item to outline" validate:"required"`
+}
+
+// ItemOutline is the outline of an item
+type ItemOutline struct {
+	ItemID string `json:"item_id"`
+	Title  string `json:"title"`
+	outline.Outline
+}
+
+func (t *GetOutlineTool) Name() string {
+	return "nuclino_get_outline"
+}
+
+func (t *GetOutlineTool) Description() string {
+	return "Get the outline of a Nuclino item: its headings with levels, anchors and word counts, without the content. Read sections by anchor with nuclino_read_sections"
+}
+
+func (t *GetOutlineTool) InputSchema() interface{} {
+	return SchemaOf[GetOutlineArgs]()
+}
+
+func (t *GetOutlineTool) OutputSchema() interface{} {
+	return OutputSchemaOf[ItemOutline]()
+}
+
+func (t *GetOutlineTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *GetOutlineTool) run(ctx context.Context, args *GetOutlineArgs) (*mcp.CallToolResult, error) {
+	item, err := t.client.GetItem(ctx, args.ItemID)
+	if err != nil {
+		return FormatError(err)
+	}
+	return FormatResult(&ItemOutline{ItemID: item.ID, Title: item.Title, Outline: *outline.Parse(item.Content)})
+}
+
+// ReadSectionsTool returns sections of an item by anchor
+type ReadSectionsTool struct {
+	client nuclino.Client
+}
+
+// ReadSectionsArgs are the arguments of nuclino_read_sections
+type ReadSectionsArgs struct {
+	ItemID  string   `json:"item_id" desc:"The ID of the item" validate:"required"`
+	Anchors []string `json:"anchors" desc:"Anchors of the sections to read, from nuclino_get_outline; heading texts work too" validate:"required"`
+	budgetArgs
+}
+
+// SectionContent is the content of a section, heading line and
+// subsections included
+type SectionContent struct {
+	Anchor string `json:"anchor"`
+	Title  string `json:"title"`
+	Level  int    `json:"level"`
+	// Offset is the character offset of content in the section when reading
+	// on from a cursor
+	Offset  int    `json:"offset,omitempty"`
+	Content string `json:"content"`
+}
+
+// ItemSections are sections of an item
+type ItemSections struct {
+	ItemID       string           `json:"item_id"`
+	Title        string           `json:"title"`
+	Sections     []SectionContent `json:"sections"`
+	Continuation *Continuation    `json:"continuation,omitempty"`
+}
+
+func (t *ReadSectionsTool) Name() string {
+	return "nuclino_read_sections"
+}
+
+func (t *ReadSectionsTool) Description() string {
+	return "Read one or more sections of a Nuclino item by anchor, as listed by nuclino_get_outline, instead of the whole item. Output is cut to max_chars; follow continuation.next_cursor to read on"
+}
+
+func (t *ReadSectionsTool) InputSchema() interface{} {
+	return SchemaOf[ReadSectionsArgs]()
+}
+
+func (t *ReadSectionsTool) OutputSchema() interface{} {
+	return OutputSchemaOf[ItemSections]()
+}
+
+func (t *ReadSectionsTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *ReadSectionsTool) run(ctx context.Context, args *ReadSectionsArgs) (*mcp.CallToolResult, error) {
+	from, err := args.decodeCursor(t.Name())
+	if err != nil {
+		return FormatArgsError(err)
+	}
+	if from != nil && from.ItemID != args.ItemID {
+		return FormatArgsError(toolargs.Errors{{Field: "cursor", Message: "belongs to item " + from.ItemID}})
+	}
+
+	item, err := t.client.GetItem(ctx, args.ItemID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	structure := outline.Parse(item.Content)
+	var sections []*outline.Section
+	var missing []string
+	for _, anchor := range args.Anchors {
+		if section, ok := structure.Find(anchor); ok {
+			sections = append(sections, section)
+		} else {
+			missing = append(missing, anchor)
+		}
+	}
+	if len(missing) > 0 {
+		return FormatError(noSectionError(item.ID, strings.Join(missing, ", "), structure))
+	}
+
+	// A cursor resumes at a section of the list, part way through its
+	// content
+	skip, offset := 0, 0
+	if from != nil {
+		skip, offset = min(from.Skip, len(sections)), from.Offset
+	}
+
+	result := &ItemSections{ItemID: item.ID, Title: item.Title, Sections: []SectionContent{}}
+	size, _ := json.Marshal(result)
+	used := len(size) + continuationReserve
+	for i := skip; i < len(sections); i++ {
+		section := sections[i]
+		content := []rune(section.Text(item.Content))
+		start := 0
+		if i == skip {
+			start = min(offset, len(content))
+		}
+		part := SectionContent{Anchor: section.Anchor, Title: section.Title, Level: section.Level, Offset: start}
+		size, _ := json.Marshal(part)
+		room := args.MaxChars - used - len(size)
+		rest := content[start:]
+		keep := len(rest)
+		if keep > room {
+			if len(result.Sections) > 0 && room < len(rest)/2 {
+				// Leave the section whole for the next call rather than
+				// splitting it this early
+				keep = 0
+			} else {
+				keep = cutPage(rest, room)
+			}
+		}
+		if keep > 0 || len(rest) == 0 {
+			part.Content = string(rest[:keep])
+			result.Sections = append(result.Sections, part)
+			used += len(size) + keep + 1
+		}
+		if keep < len(rest) {
+			remaining := len(rest) - keep
+			for _, later := range sections[i+1:] {
+				remaining += len([]rune(later.Text(item.Content)))
+			}
+			result.Continuation = &Continuation{
+				NextCursor: cursor{Tool: t.Name(), ItemID: item.ID, Skip: i, Offset: start + keep}.encode(),
+				Remaining:  remaining,
+			}
+			break
+		}
+	}
+	return FormatResult(result)
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func TestReadItemTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &ReadItemTool{client: mockClient}
+	content := "# Guide\n\nIntro.\n\n## Setup\n\n" + longContent(6) + "\n\n```\n# not a heading\n```\n\n## Usage\n\nRun it."
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(&nuclino.Item{ID: "item-1", Title: "Guide", Content: content}, nil)
+
+	var read strings.Builder
+	args := map[string]interface{}{"item_id": "item-1", "section": "setup", "max_chars": float64(800)}
+	for calls := 0; ; calls++ {
+		require.Less(t, calls, 20)
+		result, err := tool.Execute(context.Background(), args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+		var page ItemContent
+		require.NoError(t, json.Unmarshal(StructuredContent(result), &page))
+		assert.Equal(t, "Setup", page.Section)
+		assert.Equal(t, len([]rune(read.String())), page.Offset)
+		read.WriteString(page.Content)
+		if page.Continuation == nil {
+			break
+		}
+		args["cursor"] = page.Continuation.NextCursor
+	}
+	section := read.String()
+	assert.True(t, strings.HasPrefix(section, "## Setup\n\n"))
+	assert.Contains(t, section, "# not a heading", "fenced code is not a heading")
+	assert.NotContains(t, section, "## Usage")
+
+	// Reading from an offset
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"item_id": "item-1", "offset": float64(len(content) - 7)})
+	require.NoError(t, err)
+	assert.Equal(t, "Item \"Guide\" [item-1], characters "+fmt.Sprint(len(content)-7)+"-"+fmt.Sprint(len(content))+" of "+fmt.Sprint(len(content))+"\n\nRun it.",
+		result.Content[0].(mcp.TextContent).Text)
+
+	// A missing section lists the headings
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"item_id": "item-1", "section": "Install"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "sections: guide, setup, usage")
+}
+
+const designDoc = "Draft.\n\n# Payments\n\nWhy.\n\n## Goals\n\nFewer failed charges.\n\n## Design\n\nThe plan.\n\n### Retries\n\nBack off.\n\n## Design\n\nAlternative."
+
+func TestGetOutlineTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &GetOutlineTool{client: mockClient}
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(&nuclino.Item{ID: "item-1", Title: "Payments", Content: designDoc}, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"item_id": "item-1"})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "Outline of \"Payments\" [item-1], 10 words\n"+
+		"(1 word before the first heading)\n"+
+		"- Payments [#payments] 1 word, 9 with subsections\n"+
+		"  - Goals [#goals] 3 words\n"+
+		"  - Design [#design] 2 words, 4 with subsections\n"+
+		"    - Retries [#retries] 2 words\n"+
+		"  - Design [#design-1] 1 word", result.Content[0].(mcp.TextContent).Text)
+
+	var structured ItemOutline
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &structured))
+	require.Len(t, structured.Sections, 5)
+	assert.Equal(t, "design-1", structured.Sections[4].Anchor)
+	assert.Equal(t, 2, structured.Sections[4].Level)
+}
+
+func TestReadSectionsTool_Execute(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &ReadSectionsTool{client: mockClient}
+	content := designDoc + "\n\n## Appendix\n\n" + longContent(8)
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(&nuclino.Item{ID: "item-1", Title: "Payments", Content: content}, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"item_id": "item-1", "anchors": []interface{}{"design", "Goals"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	var sections ItemSections
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &sections))
+	require.Len(t, sections.Sections, 2)
+	assert.Equal(t, "## Design\n\nThe plan.\n\n### Retries\n\nBack off.", sections.Sections[0].Content)
+	assert.Equal(t, "goals", sections.Sections[1].Anchor)
+	assert.Nil(t, sections.Continuation)
+
+	// Sections over budget continue through the cursor
+	var read strings.Builder
+	args := map[string]interface{}{"item_id": "item-1", "anchors": []interface{}{"goals", "appendix"}, "max_chars": float64(700)}
+	for calls := 0; ; calls++ {
+		require.Less(t, calls, 20)
+		result, err := tool.Execute(context.Background(), args)
+		require.NoError(t, err)
+		var page ItemSections
+		require.NoError(t, json.Unmarshal(StructuredContent(result), &page))
+		for _, section := range page.Sections {
+			read.WriteString(section.Content)
+		}
+		if page.Continuation == nil {
+			break
+		}
+		args["cursor"] = page.Continuation.NextCursor
+	}
+	assert.Equal(t, "## Goals\n\nFewer failed charges."+"## Appendix\n\n"+longContent(8), read.String())
+
+	// Unknown anchors list the sections
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"item_id": "item-1", "anchors": []interface{}{"rollout"}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "sections: payments, goals, design, retries, design-1, appendix")
+}
+
+func TestReadingTools_RejectNegativeCursors(t *testing.T) {
+	mockClient := new(MockClient)
+	assertCursorRejected(t, &ReadItemTool{client: mockClient}, map[string]interface{}{"item_id": item1ID})
+	assertCursorRejected(t, &ReadSectionsTool{client: mockClient}, map[string]interface{}{"item_id": item1ID, "anchors": []interface{}{"design"}})
+}
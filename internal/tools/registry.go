@@ -1,17 +1,92 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"time"
 
+	"github.com/lukasz/nuclino-mcp-server/internal/audit"
+	"github.com/lukasz/nuclino-mcp-server/internal/bulk"
+	"github.com/lukasz/nuclino-mcp-server/internal/extract"
+	"github.com/lukasz/nuclino-mcp-server/internal/linkgraph"
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/resolve"
+	"github.com/lukasz/nuclino-mcp-server/internal/templates"
+	"github.com/lukasz/nuclino-mcp-server/internal/tracing"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Registry manages all available MCP tools
 type Registry struct {
-	tools  map[string]Tool
-	client nuclino.Client
+	tools        map[string]Tool
+	client       nuclino.Client
+	capabilities nuclino.Capabilities
+	extractor    *extract.Service
+	graphs       *linkgraph.Builder
+	resolver     *resolve.Resolver
+	bulkPlans    bulk.Store
+	bulkExecutor *bulk.Executor
+	library      *templates.Library
+	uploadRoot   string
+	toolsets     map[string]bool
+	policy       Policy
+	pending      *confirmations
+	audit        *audit.Logger
+	metrics      ToolRecorder
+
+	// Multi-account registries route calls to one registry per account;
+	// spanning tools serve calls without an account from all of them
+	accounts     map[string]*Registry
+	accountNames []string
+	spanning     map[string]Tool
+}
+
+// Options configure which tools a registry exposes
+type Options struct {
+	// Capabilities are the optional API features, as found by
+	// nuclino.ProbeCapabilities
+	Capabilities nuclino.Capabilities
+	// Toolsets limits the registered tools to these toolsets; empty
+	// registers every toolset
+	Toolsets []string
+	// ReadOnly hides and blocks every tool that writes or deletes content
+	ReadOnly bool
+	// Policy hides and blocks tools by name, category and workspace
+	Policy Policy
+	// Templates configures the template library
+	Templates templates.Config
+	// BulkStoreDir is where bulk change plans are saved (default:
+	// bulk.DefaultStoreDir)
+	BulkStoreDir string
+	// UploadRoot is the directory nuclino_upload_file may read local_path
+	// files from; empty disables local_path
+	UploadRoot string
+	// ConfirmTTL is how long the confirm_token of a destructive call
+	// preview stays valid (default: DefaultConfirmTTL)
+	ConfirmTTL time.Duration
+	// Audit records every tool call; nil disables auditing
+	Audit *audit.Logger
+	// Metrics records the outcome and latency of every tool call
+	Metrics ToolRecorder
+}
+
+// ToolRecorder receives the outcome of every tool call;
+// monitoring.MetricsCollector implements it
+type ToolRecorder interface {
+	RecordToolCall(toolName string, success bool, latency time.Duration)
+}
+
+// DefaultOptions registers every tool
+func DefaultOptions() Options {
+	return Options{
+		Capabilities: nuclino.AllCapabilities(),
+		Templates:    templates.DefaultConfig(),
+	}
 }
 
 // Tool interface defines what each MCP tool must implement
@@ -19,15 +94,61 @@ type Tool interface {
 	Name() string
 	Description() string
 	InputSchema() interface{}
-	Execute(args map[string]interface{}) (*mcp.CallToolResult, error)
+	// Execute runs the tool; ctx carries the MCP request's deadline,
+	// trace and session down to the API calls
+	Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error)
 }
 
-// NewRegistry creates a new tools registry
+// NewRegistry creates a new tools registry with every tool registered
 func NewRegistry(client nuclino.Client) *Registry {
+	return NewRegistryWithCapabilities(client, nuclino.AllCapabilities())
+}
+
+// NewRegistryWithCapabilities creates a tools registry that only registers
+// tools whose API features are available, as found by
+// nuclino.ProbeCapabilities
+func NewRegistryWithCapabilities(client nuclino.Client, capabilities nuclino.Capabilities) *Registry {
+	options := DefaultOptions()
+	options.Capabilities = capabilities
+	return NewRegistryWithOptions(client, options)
+}
+
+// NewRegistryWithOptions creates a tools registry limited by options
+func NewRegistryWithOptions(client nuclino.Client, options Options) *Registry {
+	storeDir := options.BulkStoreDir
+	if storeDir == "" {
+		storeDir = bulk.DefaultStoreDir()
+	}
+	if len(options.Policy.Workspaces) > 0 {
+		// Every tool, the resolver and the link graphs only see the allowed
+		// workspaces
+		client = &workspaceClient{Client: client, policy: options.Policy}
+	}
 	registry := &Registry{
-		tools:  make(map[string]Tool),
-		client: client,
+		tools:        make(map[string]Tool),
+		client:       client,
+		capabilities: options.Capabilities,
+		extractor:    extract.NewService(client, extract.DefaultConfig()),
+		graphs:       linkgraph.NewBuilder(client, linkgraph.DefaultConfig()),
+		resolver:     resolve.NewResolver(client, resolve.DefaultConfig()),
+		bulkPlans:    bulk.NewFileStore(storeDir),
+		library:      templates.NewLibrary(client, options.Templates),
+		uploadRoot:   options.UploadRoot,
+		policy:       options.Policy,
+		pending:      newConfirmations(options.ConfirmTTL),
+		audit:        options.Audit,
+		metrics:      options.Metrics,
+	}
+	if options.ReadOnly {
+		registry.policy.ReadOnly = true
 	}
+	if len(options.Toolsets) > 0 {
+		registry.toolsets = make(map[string]bool, len(options.Toolsets))
+		for _, toolset := range options.Toolsets {
+			registry.toolsets[toolset] = true
+		}
+	}
+	registry.bulkExecutor = bulk.NewExecutor(client, registry.bulkPlans, bulk.DefaultExecutorConfig())
 
 	// Register all tools
 	registry.registerBasicTools()
@@ -38,17 +159,19 @@ func NewRegistry(client nuclino.Client) *Registry {
 func (r *Registry) registerBasicTools() {
 	// Register item tools
 	r.registerTool(&GetItemTool{client: r.client})
+	r.registerTool(&ReadItemTool{client: r.client})
+	r.registerTool(&GetOutlineTool{client: r.client})
+	r.registerTool(&ReadSectionsTool{client: r.client})
 	r.registerTool(&SearchItemsTool{client: r.client})
 	r.registerTool(&CreateItemTool{client: r.client})
 	r.registerTool(&UpdateItemTool{client: r.client})
 	r.registerTool(&DeleteItemTool{client: r.client})
-	// Temporarily disabled: MoveItemTool (requires collection_id which may not exist)
-	// r.registerTool(&MoveItemTool{client: r.client})
+	if r.capabilities.MoveItems {
+		r.registerTool(&MoveItemTool{client: r.client})
+	}
 
 	// Register extended item tools
 	r.registerTool(&ListItemsTool{client: r.client})
-	// Temporarily disabled: ListCollectionItemsTool (collections may not exist in API)
-	// r.registerTool(&ListCollectionItemsTool{client: r.client})
 
 	// Register workspace tools
 	r.registerTool(&ListWorkspacesTool{client: r.client})
@@ -59,19 +182,39 @@ func (r *Registry) registerBasicTools() {
 
 	// Register extended workspace tools
 	r.registerTool(&GetWorkspaceOverviewTool{client: r.client})
-	r.registerTool(&SearchWorkspaceContentTool{client: r.client})
+	r.registerTool(&SearchWorkspaceContentTool{client: r.client, extractor: r.extractor})
 
-	// Temporarily disabled: Collection tools (collections may not exist in Nuclino API)
-	// r.registerTool(&ListCollectionsTool{client: r.client})
-	// r.registerTool(&GetCollectionTool{client: r.client})
-	// r.registerTool(&CreateCollectionTool{client: r.client})
-	// r.registerTool(&UpdateCollectionTool{client: r.client})
-	// r.registerTool(&DeleteCollectionTool{client: r.client})
+	// Register link graph tools
+	r.registerTool(&GetBacklinksTool{client: r.client, graphs: r.graphs})
+	r.registerTool(&LinkHealthReportTool{graphs: r.graphs})
+	r.registerTool(&LintWorkspaceTool{graphs: r.graphs})
+	r.registerTool(&FindDuplicatesTool{graphs: r.graphs})
+	r.registerTool(&MergeDuplicatesTool{client: r.client, graphs: r.graphs})
 
-	// Temporarily disabled: Extended collection tools
-	// r.registerTool(&GetCollectionOverviewTool{client: r.client})
-	// r.registerTool(&OrganizeCollectionTool{client: r.client})
-	// r.registerTool(&BulkOperationsTool{client: r.client})
+	// Register template tools
+	r.registerTool(&ListTemplatesTool{library: r.library})
+	r.registerTool(&CreateFromTemplateTool{client: r.client, library: r.library, policy: r.policy})
+
+	// Register bulk change tools
+	r.registerTool(&BulkPlanTool{client: r.client, plans: r.bulkPlans, moveSupported: r.capabilities.MoveItems})
+	r.registerTool(&BulkExecuteTool{plans: r.bulkPlans, executor: r.bulkExecutor})
+	r.registerTool(&BulkStatusTool{plans: r.bulkPlans})
+	r.registerTool(&FindReplaceTool{client: r.client, plans: r.bulkPlans, executor: r.bulkExecutor})
+
+	// Register collection tools when the API exposes collections
+	if r.capabilities.Collections {
+		r.registerTool(&ListCollectionsTool{client: r.client})
+		r.registerTool(&GetCollectionTool{client: r.client})
+		r.registerTool(&CreateCollectionTool{client: r.client})
+		r.registerTool(&UpdateCollectionTool{client: r.client})
+		r.registerTool(&DeleteCollectionTool{client: r.client})
+		r.registerTool(&ListCollectionItemsTool{client: r.client})
+		r.registerTool(&GetCollectionOverviewTool{client: r.client})
+		r.registerTool(&OrganizeCollectionTool{client: r.client})
+		if r.capabilities.MoveItems {
+			r.registerTool(&BulkOperationsTool{client: r.client, executor: r.bulkExecutor})
+		}
+	}
 
 	// Register team and user tools
 	r.registerTool(&GetUserTool{client: r.client})
@@ -81,102 +224,244 @@ func (r *Registry) registerBasicTools() {
 	// Register file tools
 	r.registerTool(&ListFilesTool{client: r.client})
 	r.registerTool(&GetFileTool{client: r.client})
+	r.registerTool(&UploadFileTool{client: r.client, root: r.uploadRoot})
+	r.registerTool(&DownloadFileTool{client: r.client})
+	r.registerTool(&ReadFileTextTool{extractor: r.extractor})
+
+	// Register the audit log query tool when auditing is enabled
+	if r.audit != nil {
+		r.registerTool(&AuditQueryTool{log: r.audit})
+	}
 }
 
+// registerTool adds a tool unless its toolset is disabled
 func (r *Registry) registerTool(tool Tool) {
-	r.tools[tool.Name()] = tool
+	name := tool.Name()
+	if r.toolsets != nil && !r.toolsets[ToolsetOf(name)] {
+		return
+	}
+	r.tools[name] = tool
 }
 
-// ListTools returns all available tools for MCP tools/list
-func (r *Registry) ListTools() []mcp.Tool {
-	var tools []mcp.Tool
+// Definitions returns the tools/list entries of the available tools, with
+// their full input and output schemas. A multi-account registry lists the
+// tools of every account, each with an account argument.
+func (r *Registry) Definitions() []ToolDefinition {
+	if len(r.accounts) == 0 {
+		return r.definitions()
+	}
+
+	seen := make(map[string]bool)
+	var definitions []ToolDefinition
+	for _, name := range r.accountNames {
+		for _, definition := range r.accounts[name].definitions() {
+			if seen[definition.Name] {
+				continue
+			}
+			seen[definition.Name] = true
+			if spanning, ok := r.spanning[definition.Name]; ok {
+				definition.Description = spanning.Description()
+				definition.OutputSchema = outputSchema(spanning)
+			}
+			definition.InputSchema["properties"].(map[string]interface{})[accountArg] = r.accountProperty()
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions
+}
+
+// definitions returns the tools the policy permits
+func (r *Registry) definitions() []ToolDefinition {
+	var definitions []ToolDefinition
 	for _, tool := range r.tools {
-		// Convert interface{} to ToolInputSchema
-		schema := mcp.ToolInputSchema{
-			Type: "object", // Default type
+		if r.policy.permits(tool.Name()) != nil {
+			continue
+		}
+
+		schema := inputSchema(tool)
+		withReferenceHint(schema)
+		if _, ok := confirmedTools[tool.Name()]; ok {
+			schema["properties"].(map[string]interface{})[confirmArg] = StringProperty("Token from the preview returned by the first call; pass it to confirm the change")
 		}
 
-		if inputSchema := tool.InputSchema(); inputSchema != nil {
-			if schemaMap, ok := inputSchema.(map[string]interface{}); ok {
-				// Convert properties if they exist
-				if props, exists := schemaMap["properties"]; exists {
-					if propsMap, ok := props.(map[string]interface{}); ok {
-						schema.Properties = make(mcp.ToolInputSchemaProperties)
-						for key, value := range propsMap {
-							if valueMap, ok := value.(map[string]interface{}); ok {
-								schema.Properties[key] = valueMap
-							}
-						}
-					}
-				}
-
-				// Set schema type if provided
-				if schemaType, exists := schemaMap["type"]; exists {
-					if typeStr, ok := schemaType.(string); ok {
-						schema.Type = typeStr
-					}
-				}
+		definitions = append(definitions, ToolDefinition{
+			Name:         tool.Name(),
+			Description:  tool.Description(),
+			InputSchema:  schema,
+			OutputSchema: outputSchema(tool),
+		})
+	}
+	return definitions
+}
+
+// inputSchema returns a copy of the tool's input schema that arguments can
+// be added to
+func inputSchema(tool Tool) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object"}
+	if schemaMap, ok := tool.InputSchema().(map[string]interface{}); ok {
+		for key, value := range schemaMap {
+			schema[key] = value
+		}
+	}
+	properties := make(map[string]interface{})
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for key, value := range props {
+			properties[key] = value
+		}
+	}
+	schema["properties"] = properties
+	return schema
+}
+
+// outputSchema returns the tool's output schema, or nil for tools without
+// structured content
+func outputSchema(tool Tool) map[string]interface{} {
+	if schemer, ok := tool.(OutputSchemer); ok {
+		if schema, ok := schemer.OutputSchema().(map[string]interface{}); ok {
+			return schema
+		}
+	}
+	return nil
+}
+
+// ListTools returns the available tools as mcp.Tool, whose input schema
+// only has the type and properties; the server lists Definitions
+func (r *Registry) ListTools() []mcp.Tool {
+	definitions := r.Definitions()
+	tools := make([]mcp.Tool, 0, len(definitions))
+	for _, definition := range definitions {
+		schema := mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: make(mcp.ToolInputSchemaProperties),
+		}
+		if schemaType, ok := definition.InputSchema["type"].(string); ok {
+			schema.Type = schemaType
+		}
+		for key, value := range definition.InputSchema["properties"].(map[string]interface{}) {
+			if valueMap, ok := value.(map[string]interface{}); ok {
+				schema.Properties[key] = valueMap
 			}
 		}
 
 		tools = append(tools, mcp.Tool{
-			Name:        tool.Name(),
-			Description: tool.Description(),
+			Name:        definition.Name,
+			Description: definition.Description,
 			InputSchema: schema,
 		})
 	}
 	return tools
 }
 
-// CallTool executes a tool by name
+// CallTool executes a tool by name, in a multi-account registry for the
+// account named by the account argument
 func (r *Registry) CallTool(name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
-	tool, exists := r.tools[name]
-	if !exists {
-		return nil, fmt.Errorf("tool not found: %s", name)
+	return r.CallToolContext(context.Background(), name, args)
+}
+
+// CallToolContext is CallTool for a request context. Destructive tools run
+// in two phases: the first call previews what would be deleted and returns
+// a short-lived confirm_token, and a second call with the token deletes.
+// When the context has an Elicitor, the user is asked directly instead.
+// Every call gets a trace span and is recorded in the audit log and the
+// metrics.
+func (r *Registry) CallToolContext(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "tool "+name, attribute.String("mcp.tool.name", name))
+	account, _ := args[accountArg].(string)
+	if account != "" {
+		span.SetAttributes(attribute.String("nuclino.account", account))
 	}
+	result, resolved, err := r.recoverCall(ctx, name, args)
+	if err == nil && result != nil && result.IsError {
+		tracing.Fail(span, "tool returned an error result")
+	}
+	tracing.End(span, err)
+	r.recordCall(ctx, name, account, resolved, start, result, err)
+	if r.metrics != nil {
+		r.metrics.RecordToolCall(name, err == nil && result != nil && !result.IsError, time.Since(start))
+	}
+	return result, err
+}
 
-	return tool.Execute(args)
+// recoverCall runs callTool, turning a panic into an error result so one
+// bad call cannot take the server down
+func (r *Registry) recoverCall(ctx context.Context, name string, args map[string]interface{}) (result *mcp.CallToolResult, resolved map[string]interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Error().Str("tool", name).Interface("panic", p).Bytes("stack", debug.Stack()).Msg("Tool call panicked")
+			resolved = args
+			result, err = FormatError(fmt.Errorf("internal error in %s: %v", name, p))
+		}
+	}()
+	return r.callTool(ctx, name, args)
 }
 
-// JSONSchema helper for creating input schemas
-func JSONSchema(properties map[string]interface{}, required []string) interface{} {
-	schema := map[string]interface{}{
-		"type":       "object",
-		"properties": properties,
+// callTool runs a call and returns its arguments with references resolved
+// to IDs, for the audit log
+func (r *Registry) callTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, map[string]interface{}, error) {
+	target, spanning, routed, err := r.route(name, args)
+	if err != nil {
+		return nil, args, err
 	}
-	if len(required) > 0 {
-		schema["required"] = required
+	args = routed
+	if spanning != nil {
+		result, err := spanning.Execute(ctx, args)
+		return result, args, err
 	}
-	return schema
-}
 
-// StringProperty creates a string property for JSON schema
-func StringProperty(description string) map[string]interface{} {
-	return map[string]interface{}{
-		"type":        "string",
-		"description": description,
+	tool, exists := target.tools[name]
+	if !exists {
+		return nil, args, fmt.Errorf("tool not found: %s", name)
+	}
+	// A denied tool must not look up references, whose candidates would
+	// reveal content
+	if denied := target.policy.permits(name); denied != nil {
+		result, err := FormatPermissionError(denied)
+		return result, args, err
+	}
+	resolved, err := target.resolveArgs(ctx, name, args)
+	if err != nil {
+		var resolveErr *resolve.Error
+		if errors.As(err, &resolveErr) {
+			result, err := FormatResolveError(resolveErr)
+			return result, args, err
+		}
+		result, err := FormatError(err)
+		return result, args, err
+	}
+	args = resolved
+	if denied := target.policy.authorize(ctx, target.client, target.bulkPlans, name, args); denied != nil {
+		result, err := FormatPermissionError(denied)
+		return result, args, err
+	}
+	if preview, ok := confirmedTools[name]; ok {
+		if result := target.confirm(ctx, name, preview, args); result != nil {
+			return result, args, nil
+		}
+		args = withoutArg(args, confirmArg)
 	}
-}
 
-// IntProperty creates an integer property for JSON schema
-func IntProperty(description string) map[string]interface{} {
-	return map[string]interface{}{
-		"type":        "integer",
-		"description": description,
+	result, err := tool.Execute(ctx, args)
+	if CategoryOf(name) != CategoryRead {
+		// Created, renamed and moved content changes what references mean
+		target.resolver.Invalidate()
 	}
+	return result, args, err
 }
 
-// BoolProperty creates a boolean property for JSON schema
-func BoolProperty(description string) map[string]interface{} {
+// StringProperty creates a string property for JSON schema
+func StringProperty(description string) map[string]interface{} {
 	return map[string]interface{}{
-		"type":        "boolean",
+		"type":        "string",
 		"description": description,
 	}
 }
 
-// FormatResult formats a result as JSON string for MCP response
+// FormatResult formats a result for an MCP response: a compact text
+// rendering for the model, plus the result itself as structured content
+// when it is a JSON object
 func FormatResult(result interface{}) (*mcp.CallToolResult, error) {
-	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	data, err := json.Marshal(result)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []interface{}{
@@ -189,14 +474,18 @@ func FormatResult(result interface{}) (*mcp.CallToolResult, error) {
 		}, nil
 	}
 
-	return &mcp.CallToolResult{
+	toolResult := &mcp.CallToolResult{
 		Content: []interface{}{
 			mcp.TextContent{
 				Type: "text",
-				Text: string(jsonBytes),
+				Text: renderText(result, data),
 			},
 		},
-	}, nil
+	}
+	if isObject(data) {
+		withStructuredContent(toolResult, data)
+	}
+	return toolResult, nil
 }
 
 // FormatError formats an error for MCP response
@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+// renderText returns the text content of a tool result: a compact,
+// readable summary of the API types, and compact JSON for anything else.
+// data is the result's JSON.
+func renderText(result interface{}, data []byte) string {
+	var b strings.Builder
+	switch v := result.(type) {
+	case *nuclino.Item:
+		renderItem(&b, v, 0)
+	case *ItemResult:
+		renderItem(&b, &v.Item, v.ContentOffset)
+		if v.Continuation != nil {
+			renderContinuation(&b, v.Continuation, "characters")
+		}
+	case *ItemContent:
+		fmt.Fprintf(&b, "Item %q [%s]", v.Title, v.ItemID)
+		if v.Section != "" {
+			fmt.Fprintf(&b, " section %q", v.Section)
+		}
+		end := v.Offset + len([]rune(v.Content))
+		fmt.Fprintf(&b, ", characters %d-%d of %d\n", v.Offset, end, v.TotalChars)
+		if v.Content != "" {
+			fmt.Fprintf(&b, "\n%s\n", v.Content)
+		}
+		if v.Continuation != nil {
+			renderContinuation(&b, v.Continuation, "characters")
+		}
+	case *ItemOutline:
+		fmt.Fprintf(&b, "Outline of %q [%s], %s\n", v.Title, v.ItemID, wordCount(v.Words))
+		if v.PreambleWords > 0 {
+			fmt.Fprintf(&b, "(%s before the first heading)\n", wordCount(v.PreambleWords))
+		}
+		for _, section := range v.Sections {
+			fmt.Fprintf(&b, "%s- %s [#%s] %s", strings.Repeat("  ", section.Level-1), section.Title, section.Anchor, wordCount(section.Words))
+			if section.TotalWords > section.Words {
+				fmt.Fprintf(&b, ", %d with subsections", section.TotalWords)
+			}
+			b.WriteString("\n")
+		}
+	case *ItemSections:
+		fmt.Fprintf(&b, "Item %q [%s], %d sections\n", v.Title, v.ItemID, len(v.Sections))
+		for _, section := range v.Sections {
+			fmt.Fprintf(&b, "\n[#%s", section.Anchor)
+			if section.Offset > 0 {
+				fmt.Fprintf(&b, " from character %d", section.Offset)
+			}
+			fmt.Fprintf(&b, "]\n%s\n", section.Content)
+		}
+		if v.Continuation != nil {
+			renderContinuation(&b, v.Continuation, "characters")
+		}
+	case *nuclino.ItemsResponse:
+		renderItems(&b, v)
+	case *ItemsResult:
+		renderItems(&b, &v.ItemsResponse)
+		if v.CutItem != nil {
+			renderCutItem(&b, v.CutItem)
+		}
+		if v.Continuation != nil {
+			renderContinuation(&b, v.Continuation, "results of this page")
+		}
+	case *nuclino.Collection:
+		fmt.Fprintf(&b, "Collection %q [%s]\n", v.Title, v.ID)
+		renderFacts(&b, "workspace", v.WorkspaceID, "children", countOf(v.ChildIDs), "updated", formatTime(v.UpdatedAt), "url", v.URL)
+	case *nuclino.CollectionsResponse:
+		renderList(&b, "collections", len(v.Results), v.Total, v.Offset)
+		for _, collection := range v.Results {
+			fmt.Fprintf(&b, "- %s [%s]\n", collection.Title, collection.ID)
+		}
+	case *nuclino.Workspace:
+		fmt.Fprintf(&b, "Workspace %q [%s]\n", v.Name, v.ID)
+		renderFacts(&b, "team", v.TeamID, "children", countOf(v.ChildIDs), "updated", formatTime(v.UpdatedAt))
+	case *nuclino.WorkspacesResponse:
+		renderList(&b, "workspaces", len(v.Results), v.Total, v.Offset)
+		for _, workspace := range v.Results {
+			fmt.Fprintf(&b, "- %s [%s] team %s\n", workspace.Name, workspace.ID, workspace.TeamID)
+		}
+	case *nuclino.Team:
+		fmt.Fprintf(&b, "Team %q [%s]\n", v.Name, v.ID)
+	case *nuclino.TeamsResponse:
+		renderList(&b, "teams", len(v.Results), v.Total, v.Offset)
+		for _, team := range v.Results {
+			fmt.Fprintf(&b, "- %s [%s]\n", team.Name, team.ID)
+		}
+	case *nuclino.User:
+		fmt.Fprintf(&b, "User %s [%s]\n", strings.TrimSpace(v.FirstName+" "+v.LastName), v.ID)
+		renderFacts(&b, "email", v.Email)
+	case *nuclino.File:
+		fmt.Fprintf(&b, "File %q [%s]\n", v.DisplayName(), v.ID)
+		renderFacts(&b, "item", v.ItemID, "type", v.MimeType, "size", sizeOf(v.Size))
+	case *nuclino.FilesResponse:
+		renderList(&b, "files", len(v.Results), v.Total, v.Offset)
+		for _, file := range v.Results {
+			fmt.Fprintf(&b, "- %s [%s]\n", file.DisplayName(), file.ID)
+		}
+	case *UnresolvedReference:
+		argument := "reference"
+		if v.Argument != "" {
+			argument = v.Argument
+		}
+		fmt.Fprintf(&b, "Cannot resolve %s %q (%s): %s\n", argument, v.Reference, v.Reason, v.Message)
+		for _, candidate := range v.Candidates {
+			fmt.Fprintf(&b, "- %s %s [%s]\n", candidate.Object, candidate.Path, candidate.ID)
+		}
+	default:
+		return string(data)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderItem writes an item; offset is the character offset of its content
+// when reading on from a cursor
+func renderItem(b *strings.Builder, item *nuclino.Item, offset int) {
+	kind := "Item"
+	if item.IsCollection() {
+		kind = "Collection"
+	}
+	fmt.Fprintf(b, "%s %q [%s]\n", kind, item.Title, item.ID)
+	updated := formatTime(item.ModifiedAt())
+	if updated != "" && item.UpdatedBy != "" {
+		updated += " by " + item.UpdatedBy
+	}
+	renderFacts(b, "workspace", item.WorkspaceID, "collection", item.CollectionID,
+		"updated", updated, "children", countOf(item.ChildIDs), "url", item.URL)
+	if len(item.Fields) > 0 {
+		names := make([]string, 0, len(item.Fields))
+		for name := range item.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fields := make([]string, len(names))
+		for i, name := range names {
+			fields[i] = fmt.Sprintf("%s=%v", name, item.Fields[name])
+		}
+		fmt.Fprintf(b, "fields: %s\n", strings.Join(fields, ", "))
+	}
+	if offset > 0 {
+		fmt.Fprintf(b, "content from character %d:\n", offset)
+	}
+	if item.Content != "" {
+		fmt.Fprintf(b, "\n%s\n", item.Content)
+	}
+}
+
+func renderItems(b *strings.Builder, page *nuclino.ItemsResponse) {
+	renderList(b, "items", len(page.Results), page.Total, page.Offset)
+	for i := range page.Results {
+		renderItemLine(b, &page.Results[i])
+	}
+}
+
+func renderItemLine(b *strings.Builder, item *nuclino.Item) {
+	title := item.Title
+	if item.IsCollection() {
+		title += "/"
+	}
+	fmt.Fprintf(b, "- %s [%s]", title, item.ID)
+	if updated := formatTime(item.ModifiedAt()); updated != "" {
+		fmt.Fprintf(b, " updated %s", updated)
+	}
+	b.WriteString("\n")
+	if item.Highlight != "" {
+		fmt.Fprintf(b, "  %s\n", strings.Join(strings.Fields(item.Highlight), " "))
+	}
+}
+
+// renderList writes the header of a page of results
+func renderList(b *strings.Builder, noun string, count, total, offset int) {
+	if total < count {
+		total = count
+	}
+	fmt.Fprintf(b, "%d of %d %s", count, total, noun)
+	if offset > 0 {
+		fmt.Fprintf(b, " from offset %d", offset)
+	}
+	b.WriteString("\n")
+}
+
+// renderFacts writes the non-empty name/value pairs on one line
+func renderFacts(b *strings.Builder, pairs ...string) {
+	var facts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			facts = append(facts, pairs[i]+": "+pairs[i+1])
+		}
+	}
+	if len(facts) > 0 {
+		fmt.Fprintf(b, "%s\n", strings.Join(facts, " | "))
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04")
+}
+
+func countOf(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	return fmt.Sprint(len(ids))
+}
+
+func wordCount(n int) string {
+	if n == 1 {
+		return "1 word"
+	}
+	return fmt.Sprintf("%d words", n)
+}
+
+func sizeOf(size int64) string {
+	if size <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/resolve"
+)
+
+// workspaceArgs are the arguments naming workspaces. They and itemArgs
+// accept references (URLs, names and paths) as well as IDs.
+var workspaceArgs = []string{"workspace_id"}
+
+// referenceHint is added to the description of every argument that
+// accepts references
+const referenceHint = ` Accepts an ID, a https://app.nuclino.com/... URL or a name; items and collections also take a path such as "Engineering / Onboarding".`
+
+// resolveArgs replaces the references in the workspace and item arguments
+// of a call with the IDs they name, copying rather than changing the
+// caller's map. Only read tools take fuzzy matches; the others need
+// references that match exactly.
+func (r *Registry) resolveArgs(ctx context.Context, name string, args map[string]interface{}) (map[string]interface{}, error) {
+	resolver := r.resolver
+	if CategoryOf(name) != CategoryRead {
+		resolver = resolver.Exact()
+	}
+
+	var resolved map[string]interface{}
+	set := func(key string, value interface{}) {
+		if resolved == nil {
+			resolved = make(map[string]interface{}, len(args))
+			for k, v := range args {
+				resolved[k] = v
+			}
+		}
+		resolved[key] = value
+	}
+
+	for _, key := range workspaceArgs {
+		ref, _ := args[key].(string)
+		if ref == "" || resolve.IsID(ref) {
+			continue
+		}
+		id, err := resolver.Workspace(ctx, ref)
+		if err != nil {
+			return nil, withArgument(err, key)
+		}
+		set(key, id)
+	}
+
+	for _, key := range itemArgs {
+		switch value := args[key].(type) {
+		case string:
+			// A comma-separated list of IDs needs no resolving; anything
+			// else is one reference, commas included
+			if value == "" || allIDs(splitIDs(value)) {
+				continue
+			}
+			id, err := resolver.Item(ctx, value)
+			if err != nil {
+				return nil, withArgument(err, key)
+			}
+			set(key, id)
+		case []interface{}:
+			var ids []interface{}
+			for i, entry := range value {
+				ref, ok := entry.(string)
+				if !ok || ref == "" || resolve.IsID(ref) {
+					continue
+				}
+				id, err := resolver.Item(ctx, ref)
+				if err != nil {
+					return nil, withArgument(err, key)
+				}
+				if ids == nil {
+					ids = append([]interface{}(nil), value...)
+				}
+				ids[i] = id
+			}
+			if ids != nil {
+				set(key, ids)
+			}
+		}
+	}
+
+	if resolved == nil {
+		return args, nil
+	}
+	return resolved, nil
+}
+
+func allIDs(ids []string) bool {
+	for _, id := range ids {
+		if !resolve.IsID(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// withArgument names the argument of a resolve.Error
+func withArgument(err error, key string) error {
+	var resolveErr *resolve.Error
+	if errors.As(err, &resolveErr) {
+		resolveErr.Argument = key
+	}
+	return err
+}
+
+// UnresolvedReference is the structured content of a reference that did
+// not resolve
+type UnresolvedReference struct {
+	Error      string              `json:"error"`
+	Argument   string              `json:"argument,omitempty"`
+	Reference  string              `json:"reference"`
+	Reason     string              `json:"reason"`
+	Message    string              `json:"message"`
+	Candidates []resolve.Candidate `json:"candidates,omitempty"`
+}
+
+// FormatResolveError formats a reference that did not resolve, listing
+// the candidates it may have meant
+func FormatResolveError(err *resolve.Error) (*mcp.CallToolResult, error) {
+	result, formatErr := FormatResult(&UnresolvedReference{
+		Error:      "unresolved_reference",
+		Argument:   err.Argument,
+		Reference:  err.Reference,
+		Reason:     err.Reason,
+		Message:    err.Message,
+		Candidates: err.Candidates,
+	})
+	result.IsError = true
+	return result, formatErr
+}
+
+// withReferenceHint documents references on the arguments of schema that
+// accept them
+func withReferenceHint(schema map[string]interface{}) {
+	properties := schema["properties"].(map[string]interface{})
+	for _, key := range append(append([]string(nil), workspaceArgs...), itemArgs...) {
+		property, ok := properties[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hinted := make(map[string]interface{}, len(property))
+		for k, v := range property {
+			hinted[k] = v
+		}
+		description, _ := hinted["description"].(string)
+		hinted["description"] = strings.TrimSpace(description + referenceHint)
+		properties[key] = hinted
+	}
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino/nuclinotest"
+)
+
+func TestRegistry_ResolvesReferences(t *testing.T) {
+	fake := nuclinotest.NewServer()
+	defer fake.Close()
+	fake.SeedDemo()
+	registry := NewRegistry(nuclino.NewClientFromConfig(nuclino.ClientConfig{APIKey: nuclinotest.DemoAPIKey, BaseURL: fake.URL}))
+
+	result := mustCall(t, registry, "nuclino_get_item", map[string]interface{}{"item_id": "Engineering / Handbook / Onboarding"})
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	var item nuclino.Item
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &item))
+	assert.Equal(t, "Onboarding", item.Title)
+
+	result = mustCall(t, registry, "nuclino_get_item", map[string]interface{}{"item_id": item.URL, "fields": []interface{}{"id"}})
+	assert.JSONEq(t, `{"id":"`+item.ID+`"}`, string(StructuredContent(result)))
+
+	result = mustCall(t, registry, "nuclino_list_items", map[string]interface{}{"workspace_id": "product"})
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Q1 goals")
+
+	// Changes need an exact reference; a fuzzy one is offered back instead
+	result = mustCall(t, registry, "nuclino_update_item", map[string]interface{}{"item_id": "Engineering / Handbook / Onboard", "title": "Renamed"})
+	require.True(t, result.IsError)
+	assert.Contains(t, string(StructuredContent(result)), `"reason":"ambiguous"`)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, item.ID)
+
+	// Content created through the registry is found right away
+	mustCall(t, registry, "nuclino_create_item", map[string]interface{}{"workspace_id": "Product", "title": "Onboarding"})
+	result = mustCall(t, registry, "nuclino_read_item", map[string]interface{}{"item_id": "Onboarding"})
+	require.True(t, result.IsError)
+
+	var body struct {
+		Error      string `json:"error"`
+		Argument   string `json:"argument"`
+		Reason     string `json:"reason"`
+		Candidates []struct {
+			ID   string `json:"id"`
+			Path string `json:"path"`
+		} `json:"candidates"`
+	}
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &body))
+	assert.Equal(t, "unresolved_reference", body.Error)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Product / Onboarding")
+	assert.Equal(t, "item_id", body.Argument)
+	assert.Equal(t, "ambiguous", body.Reason)
+	require.Len(t, body.Candidates, 2)
+	assert.Equal(t, "Engineering / Handbook / Onboarding", body.Candidates[0].Path)
+	assert.Equal(t, "Product / Onboarding", body.Candidates[1].Path)
+
+	var getItem ToolDefinition
+	for _, definition := range registry.Definitions() {
+		if definition.Name == "nuclino_get_item" {
+			getItem = definition
+		}
+	}
+	property := getItem.InputSchema["properties"].(map[string]interface{})["item_id"].(map[string]interface{})
+	assert.Contains(t, property["description"], "Engineering / Onboarding")
+}
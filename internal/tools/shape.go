@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+)
+
+// continuationReserve is budget kept for the continuation of a cut result
+const continuationReserve = 200
+
+// budgetArgs bound the size of a tool's output
+type budgetArgs struct {
+	MaxChars int    `json:"max_chars" desc:"Output budget in characters, about 4 per token (default: 20000). Longer output is cut at a paragraph boundary and returns a continuation cursor" validate:"min=500,max=1000000" default:"20000"`
+	Cursor   string `json:"cursor" desc:"continuation.next_cursor of a cut result, to read on; repeat the other arguments unchanged"`
+}
+
+// outputArgs are the output options of tools returning items
+type outputArgs struct {
+	Fields []string `json:"fields" desc:"Only return these fields of each item, e.g. [\"id\",\"title\"]" validate:"oneof=id object title content collectionId workspaceId createdAt updatedAt lastUpdatedAt createdBy updatedBy url contentMeta childIds fields highlight"`
+	budgetArgs
+}
+
+// Continuation says how to get the rest of a result cut to its budget
+type Continuation struct {
+	NextCursor string `json:"next_cursor"`
+	// Remaining counts the results, or characters of content, left out
+	Remaining int `json:"remaining"`
+}
+
+// ItemResult is an item shaped by the output options
+type ItemResult struct {
+	nuclino.Item
+	// ContentOffset is the character offset of content in the item when
+	// reading on from a cursor
+	ContentOffset int           `json:"content_offset,omitempty"`
+	Continuation  *Continuation `json:"continuation,omitempty"`
+}
+
+// ItemsResult is a page of items shaped by the output options
+type ItemsResult struct {
+	nuclino.ItemsResponse
+	Continuation *Continuation `json:"continuation,omitempty"`
+	// CutItem is set when the only result did not fit the budget and its
+	// content was cut
+	CutItem *CutItem `json:"cut_item,omitempty"`
+}
+
+// CutItem says where the content of a result cut to the budget ends, so
+// that nuclino_read_item can read on from there
+type CutItem struct {
+	ItemID string `json:"item_id"`
+	// Offset is the character offset the returned content ends at
+	Offset    int `json:"offset"`
+	Remaining int `json:"remaining"`
+}
+
+// cursor is the decoded form of a continuation cursor. Content cursors
+// hold a character offset in an item; list cursors hold the page request
+// and how many of its results were already returned.
+type cursor struct {
+	Tool    string `json:"t"`
+	ItemID  string `json:"i,omitempty"`
+	Section string `json:"h,omitempty"`
+	Offset  int    `json:"o,omitempty"`
+	Limit   int    `json:"l,omitempty"`
+	Skip    int    `json:"s,omitempty"`
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses the cursor argument of tool; nil without one
+func (b *budgetArgs) decodeCursor(tool string) (*cursor, error) {
+	if b.Cursor == "" {
+		return nil, nil
+	}
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(b.Cursor)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.Tool != tool {
+		return nil, toolargs.Errors{{Field: "cursor", Message: "is not a continuation cursor of " + tool}}
+	}
+	if c.Offset < 0 || c.Skip < 0 || c.Limit < 0 {
+		return nil, toolargs.Errors{{Field: "cursor", Message: "holds a negative position"}}
+	}
+	return &c, nil
+}
+
+// selects reports whether the output includes field
+func (o *outputArgs) selects(field string) bool {
+	if len(o.Fields) == 0 {
+		return true
+	}
+	for _, selected := range o.Fields {
+		if selected == field {
+			return true
+		}
+	}
+	return false
+}
+
+// formatItem formats an item, cutting its content to the budget. from is
+// the cursor the call continues from.
+func (o *outputArgs) formatItem(tool string, item *nuclino.Item, from *cursor) (*mcp.CallToolResult, error) {
+	result := &ItemResult{Item: *item}
+	if o.selects("content") {
+		content := []rune(item.Content)
+		offset := 0
+		if from != nil {
+			offset = min(from.Offset, len(content))
+		}
+		content = content[offset:]
+
+		result.Content = ""
+		room := o.MaxChars - len(o.project(result)) - continuationReserve
+		keep := cutPage(content, room)
+		result.Content = string(content[:keep])
+		result.ContentOffset = offset
+		if keep < len(content) {
+			result.Continuation = &Continuation{
+				NextCursor: cursor{Tool: tool, ItemID: item.ID, Offset: offset + keep}.encode(),
+				Remaining:  len(content) - keep,
+			}
+		}
+	}
+	return o.format(result)
+}
+
+// formatItems formats a page of items, dropping the results that do not
+// fit the budget. A lone result over budget keeps what fits of its content
+// and is reported as the cut item. The page was requested at offset with
+// limit; from is the cursor the call continues from.
+func (o *outputArgs) formatItems(tool string, page *nuclino.ItemsResponse, offset, limit int, from *cursor) (*mcp.CallToolResult, error) {
+	results := page.Results
+	skip := 0
+	if from != nil {
+		skip = min(from.Skip, len(results))
+		results = results[skip:]
+	}
+
+	result := &ItemsResult{ItemsResponse: nuclino.ItemsResponse{
+		Results: []nuclino.Item{},
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+	}}
+	used := len(o.project(result)) + continuationReserve
+	for _, item := range results {
+		size := len(o.project(&item)) + 1
+		if used+size > o.MaxChars {
+			if len(result.Results) > 0 {
+				break
+			}
+			content := []rune(item.Content)
+			keep := cutText(content, o.MaxChars-used-continuationReserve-(size-len(content)))
+			if keep < len(content) {
+				item.Content = string(content[:keep])
+				result.CutItem = &CutItem{ItemID: item.ID, Offset: keep, Remaining: len(content) - keep}
+			}
+			size = len(o.project(&item)) + 1
+		}
+		result.Results = append(result.Results, item)
+		used += size
+	}
+
+	if kept := len(result.Results); kept < len(results) {
+		result.Continuation = &Continuation{
+			NextCursor: cursor{Tool: tool, Offset: offset, Limit: limit, Skip: skip + kept}.encode(),
+			Remaining:  len(results) - kept,
+		}
+	}
+	return o.format(result)
+}
+
+// format formats a shaped result, projected to the selected fields
+func (o *outputArgs) format(result interface{}) (*mcp.CallToolResult, error) {
+	if len(o.Fields) == 0 {
+		return FormatResult(result)
+	}
+	data := o.project(result)
+	toolResult := &mcp.CallToolResult{
+		Content: []interface{}{
+			mcp.TextContent{
+				Type: "text",
+				Text: renderProjected(data, o.Fields),
+			},
+		},
+	}
+	return withStructuredContent(toolResult, data), nil
+}
+
+// project marshals an item, ItemResult or ItemsResult keeping only the
+// selected fields of each item
+func (o *outputArgs) project(result interface{}) json.RawMessage {
+	data, err := json.Marshal(result)
+	if err != nil || len(o.Fields) == 0 {
+		return data
+	}
+	var object map[string]interface{}
+	if json.Unmarshal(data, &object) != nil {
+		return data
+	}
+	if results, ok := object["results"].([]interface{}); ok {
+		for _, entry := range results {
+			if item, ok := entry.(map[string]interface{}); ok {
+				o.keepSelected(item)
+			}
+		}
+	} else {
+		o.keepSelected(object)
+	}
+	data, _ = json.Marshal(object)
+	return data
+}
+
+// keepSelected removes the unselected item fields of object
+func (o *outputArgs) keepSelected(object map[string]interface{}) {
+	for key := range object {
+		if key != "continuation" && key != "content_offset" && !o.selects(key) {
+			delete(object, key)
+		}
+	}
+}
+
+// renderProjected renders projected items with their fields in the
+// requested order: one line per result of a page, one line per field of
+// a single item
+func renderProjected(data json.RawMessage, fields []string) string {
+	var object map[string]interface{}
+	_ = json.Unmarshal(data, &object)
+
+	var b strings.Builder
+	unit := "characters"
+	if results, ok := object["results"].([]interface{}); ok {
+		unit = "results of this page"
+		renderList(&b, "items", len(results), intOf(object["total"]), intOf(object["offset"]))
+		for _, entry := range results {
+			item, _ := entry.(map[string]interface{})
+			values := make([]string, 0, len(fields))
+			for _, field := range fields {
+				if value, ok := item[field]; ok {
+					values = append(values, field+": "+valueText(value))
+				}
+			}
+			fmt.Fprintf(&b, "- %s\n", strings.Join(values, " | "))
+		}
+	} else {
+		for _, field := range fields {
+			if value, ok := object[field]; ok {
+				fmt.Fprintf(&b, "%s: %s\n", field, valueText(value))
+			}
+		}
+	}
+	if cut, ok := object["cut_item"].(map[string]interface{}); ok {
+		id, _ := cut["item_id"].(string)
+		renderCutItem(&b, &CutItem{ItemID: id, Offset: intOf(cut["offset"]), Remaining: intOf(cut["remaining"])})
+	}
+	if continuation, ok := object["continuation"].(map[string]interface{}); ok {
+		next, _ := continuation["next_cursor"].(string)
+		renderContinuation(&b, &Continuation{NextCursor: next, Remaining: intOf(continuation["remaining"])}, unit)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderContinuation writes how to read on from a cut result; unit names
+// what was left out
+func renderContinuation(b *strings.Builder, continuation *Continuation, unit string) {
+	fmt.Fprintf(b, "\n[cut to the output budget: %d %s left; call again with cursor %q to continue]\n", continuation.Remaining, unit, continuation.NextCursor)
+}
+
+// renderCutItem writes how to read the rest of a result cut to the budget
+func renderCutItem(b *strings.Builder, cut *CutItem) {
+	fmt.Fprintf(b, "\n[content of %s cut to the output budget: %d characters left; call nuclino_read_item with item_id %q and offset %d to read on]\n", cut.ItemID, cut.Remaining, cut.ItemID, cut.Offset)
+}
+
+func valueText(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func intOf(value interface{}) int {
+	n, _ := value.(float64)
+	return int(n)
+}
+
+// cutText returns how many characters of text fit in max, ending at a
+// paragraph break when one falls in the second half of the budget, else at
+// a line break, then a space
+func cutText(text []rune, max int) int {
+	if len(text) <= max {
+		return len(text)
+	}
+	if max <= 0 {
+		return 0
+	}
+	for _, boundary := range []string{"\n\n", "\n", " "} {
+		if n := lastBoundary(text[:max], []rune(boundary), max/2); n > 0 {
+			return n
+		}
+	}
+	return max
+}
+
+// cutPage is cutText for a page read through a cursor, which keeps at least
+// one character so that the next cursor moves on
+func cutPage(text []rune, budget int) int {
+	return max(cutText(text, budget), min(len(text), 1))
+}
+
+// lastBoundary returns the end of the last boundary in text after floor,
+// or 0
+func lastBoundary(text, boundary []rune, floor int) int {
+	for end := len(text); end-len(boundary) >= floor; end-- {
+		if string(text[end-len(boundary):end]) == string(boundary) {
+			return end
+		}
+	}
+	return 0
+}
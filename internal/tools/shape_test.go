@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+)
+
+func TestCutText(t *testing.T) {
+	text := []rune("First paragraph here.\n\nSecond paragraph, a bit longer.\nWith a second line.")
+
+	assert.Equal(t, len(text), cutText(text, 500), "text within budget is kept whole")
+	assert.Equal(t, len("First paragraph here.\n\n"), cutText(text, 40), "cut at the paragraph break")
+	assert.Equal(t, len("First paragraph here.\n\nSecond paragraph, a bit longer.\n"), cutText(text, 70), "cut at the line break")
+	assert.Equal(t, len("First "), cutText(text, 10), "cut at a space")
+	assert.Equal(t, 4, cutText([]rune("unbroken"), 4), "cut mid-word without a boundary")
+	assert.Equal(t, 0, cutText(text, -5))
+}
+
+func longContent(paragraphs int) string {
+	parts := make([]string, paragraphs)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("Paragraph %d. %s", i, strings.Repeat("lorem ipsum ", 20))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func TestGetItemTool_ContentBudget(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &GetItemTool{client: mockClient}
+	content := longContent(20)
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(&nuclino.Item{ID: "item-1", Title: "Long", Content: content}, nil)
+
+	var read strings.Builder
+	args := map[string]interface{}{"item_id": "item-1", "max_chars": float64(1000)}
+	for calls := 0; ; calls++ {
+		require.Less(t, calls, 50, "the cursor makes progress")
+		result, err := tool.Execute(context.Background(), args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+		var shaped ItemResult
+		require.NoError(t, json.Unmarshal(StructuredContent(result), &shaped))
+		assert.LessOrEqual(t, len(StructuredContent(result)), 1000)
+		assert.Equal(t, len([]rune(read.String())), shaped.ContentOffset)
+		read.WriteString(shaped.Content)
+		if shaped.Continuation == nil {
+			break
+		}
+		assert.True(t, strings.HasSuffix(shaped.Content, "\n\n"), "cut at a paragraph boundary")
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, shaped.Continuation.NextCursor)
+		args["cursor"] = shaped.Continuation.NextCursor
+	}
+	assert.Equal(t, content, read.String())
+
+	// Another item's cursor is rejected
+	other := &GetItemTool{client: mockClient}
+	result, err := other.Execute(context.Background(), map[string]interface{}{"item_id": "item-2", "cursor": args["cursor"]})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "belongs to item item-1")
+}
+
+func TestSearchItemsTool_FieldsAndBudget(t *testing.T) {
+	mockClient := new(MockClient)
+	tool := &SearchItemsTool{client: mockClient}
+	page := &nuclino.ItemsResponse{Total: 30}
+	for i := 0; i < 30; i++ {
+		page.Results = append(page.Results, nuclino.Item{
+			ID: fmt.Sprintf("item-%d", i), Title: fmt.Sprintf("Note %d", i), WorkspaceID: "ws-1",
+			Content: strings.Repeat("x", 100),
+		})
+	}
+	mockClient.On("SearchItems", mock.Anything, mock.Anything).Return(page, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"query": "note", "fields": []interface{}{"title", "id"}, "max_chars": float64(600),
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	var shaped struct {
+		Results      []map[string]interface{} `json:"results"`
+		Continuation *Continuation            `json:"continuation"`
+	}
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &shaped))
+	require.NotEmpty(t, shaped.Results)
+	assert.Equal(t, map[string]interface{}{"id": "item-0", "title": "Note 0"}, shaped.Results[0])
+	require.NotNil(t, shaped.Continuation)
+	assert.Equal(t, 30-len(shaped.Results), shaped.Continuation.Remaining)
+
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "- title: Note 0 | id: item-0\n")
+	assert.NotContains(t, text, "xxxx")
+
+	// The cursor returns the rest of the page
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"query": "note", "fields": []interface{}{"id"}, "max_chars": float64(100000), "cursor": shaped.Continuation.NextCursor,
+	})
+	require.NoError(t, err)
+	var rest ItemsResult
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &rest))
+	assert.Len(t, rest.Results, shaped.Continuation.Remaining)
+	assert.Equal(t, fmt.Sprintf("item-%d", len(shaped.Results)), rest.Results[0].ID)
+	assert.Nil(t, rest.Continuation)
+
+	// Unknown fields are argument errors
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"query": "note", "fields": []interface{}{"body"}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "fields")
+}
+
+func TestSearchItemsTool_LoneResultOverBudget(t *testing.T) {
+	mockClient := new(MockClient)
+	content := longContent(20)
+	item := nuclino.Item{ID: "item-1", Title: "Long", Content: content}
+	mockClient.On("SearchItems", mock.Anything, mock.Anything).Return(&nuclino.ItemsResponse{Results: []nuclino.Item{item}, Total: 1}, nil)
+	mockClient.On("GetItem", mock.Anything, "item-1").Return(&item, nil)
+
+	result, err := (&SearchItemsTool{client: mockClient}).Execute(context.Background(), map[string]interface{}{"query": "long", "max_chars": float64(1000)})
+	require.NoError(t, err)
+	var shaped ItemsResult
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &shaped))
+	require.Len(t, shaped.Results, 1)
+	require.NotNil(t, shaped.CutItem, "the cut is reported")
+	assert.Nil(t, shaped.Continuation, "no results are left on the page")
+	kept := len([]rune(shaped.Results[0].Content))
+	assert.Equal(t, CutItem{ItemID: "item-1", Offset: kept, Remaining: len([]rune(content)) - kept}, *shaped.CutItem)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, fmt.Sprintf("call nuclino_read_item with item_id \"item-1\" and offset %d", kept))
+
+	// nuclino_read_item reads on from the cut
+	result, err = (&ReadItemTool{client: mockClient}).Execute(context.Background(), map[string]interface{}{"item_id": "item-1", "offset": float64(shaped.CutItem.Offset), "max_chars": float64(1000000)})
+	require.NoError(t, err)
+	var rest ItemContent
+	require.NoError(t, json.Unmarshal(StructuredContent(result), &rest))
+	assert.Equal(t, content, shaped.Results[0].Content+rest.Content)
+}
+
+// assertCursorRejected checks that tool refuses cursors holding negative
+// positions as an argument error, before calling the API
+func assertCursorRejected(t *testing.T, tool Tool, args map[string]interface{}) {
+	t.Helper()
+	for _, c := range []cursor{
+		{Tool: tool.Name(), ItemID: item1ID, Offset: -3},
+		{Tool: tool.Name(), ItemID: item1ID, Limit: 10, Skip: -1},
+		{Tool: tool.Name(), Limit: -10},
+	} {
+		withCursor := map[string]interface{}{"cursor": c.encode()}
+		for key, value := range args {
+			withCursor[key] = value
+		}
+		result, err := tool.Execute(context.Background(), withCursor)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		var body struct {
+			Error  string `json:"error"`
+			Fields []struct {
+				Field string `json:"field"`
+			} `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &body))
+		assert.Equal(t, "invalid_arguments", body.Error, tool.Name())
+		require.Len(t, body.Fields, 1)
+		assert.Equal(t, "cursor", body.Fields[0].Field)
+	}
+}
+
+func TestCursors_RejectNegativePositions(t *testing.T) {
+	// The mock has no expectations, so any API call fails the test
+	mockClient := new(MockClient)
+	assertCursorRejected(t, &GetItemTool{client: mockClient}, map[string]interface{}{"item_id": item1ID})
+	assertCursorRejected(t, &SearchItemsTool{client: mockClient}, map[string]interface{}{"query": "roadmap"})
+	assertCursorRejected(t, &ListItemsTool{client: mockClient}, map[string]interface{}{"workspace_id": workspace123ID})
+	assertCursorRejected(t, &ListCollectionItemsTool{client: mockClient}, map[string]interface{}{"collection_id": collection123ID})
+}
+
+func TestRegistry_RecoversToolPanics(t *testing.T) {
+	// An unexpected call on the mock panics inside the tool
+	registry := NewRegistry(new(MockClient))
+
+	result, err := registry.CallTool("nuclino_get_item", map[string]interface{}{"item_id": item1ID})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "internal error in nuclino_get_item")
+}
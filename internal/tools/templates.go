@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/templates"
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ListTemplatesTool lists the item templates available to a workspace
+type ListTemplatesTool struct {
+	library *templates.Library
+}
+
+func (t *ListTemplatesTool) Name() string {
+	return "nuclino_list_templates"
+}
+
+func (t *ListTemplatesTool) Description() string {
+	return "List item templates from the workspace's \"Templates\" collection and the local template directory, with the variables each template needs"
+}
+
+// ListTemplatesArgs are the arguments of nuclino_list_templates
+type ListTemplatesArgs struct {
+	WorkspaceID string `json:"workspace_id" desc:"Workspace whose Templates collection to read (optional; without it only local templates are listed)"`
+	Refresh     bool   `json:"refresh" desc:"Reload templates stored in Nuclino (default: false)"`
+}
+
+func (t *ListTemplatesTool) InputSchema() interface{} {
+	return SchemaOf[ListTemplatesArgs]()
+}
+
+func (t *ListTemplatesTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *ListTemplatesTool) run(ctx context.Context, args *ListTemplatesArgs) (*mcp.CallToolResult, error) {
+	if args.Refresh {
+		t.library.Invalidate(args.WorkspaceID)
+	}
+
+	list, err := t.library.List(ctx, args.WorkspaceID)
+	if err != nil {
+		return FormatError(err)
+	}
+	return FormatResult(map[string]interface{}{
+		"templates": list,
+		"defaults":  []string{"Date", "Time", "DateTime", "Year", "Month", "Weekday", "User", "UserEmail", "UserID"},
+	})
+}
+
+// CreateFromTemplateTool renders a template and creates the item
+type CreateFromTemplateTool struct {
+	client  nuclino.Client
+	library *templates.Library
+	policy  Policy
+}
+
+func (t *CreateFromTemplateTool) Name() string {
+	return "nuclino_create_from_template"
+}
+
+func (t *CreateFromTemplateTool) Description() string {
+	return "Create a Nuclino item from a template (ADR, incident report, meeting notes, ...). Templates use Go text/template syntax such as {{.Subject}}; Date, Time, Year, User and similar values are filled in automatically. The item is created under the given parent, the template's default parent, or the workspace root."
+}
+
+// CreateFromTemplateArgs are the arguments of nuclino_create_from_template
+type CreateFromTemplateArgs struct {
+	Template string `json:"template" desc:"Template name (see nuclino_list_templates)" validate:"required"`
+	// Values is an object or a JSON string holding one
+	Values      interface{} `json:"values" desc:"Template variables, e.g. {\"Subject\": \"Use Postgres\"}"`
+	WorkspaceID string      `json:"workspace_id" desc:"Workspace to create the item in; optional when a parent is known"`
+	ParentID    string      `json:"parent_id" desc:"Collection to create the item under (overrides the template's parent)"`
+	Title       string      `json:"title" desc:"Item title, may use template variables (default: the template's title or first heading)"`
+	UserID      string      `json:"user_id" desc:"User filled in as User/UserEmail (default: NUCLINO_USER_ID)"`
+	Preview     bool        `json:"preview" desc:"Only render the item without creating it (default: false)"`
+}
+
+func (t *CreateFromTemplateTool) InputSchema() interface{} {
+	return SchemaOf[CreateFromTemplateArgs]()
+}
+
+func (t *CreateFromTemplateTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
+
+func (t *CreateFromTemplateTool) run(ctx context.Context, args *CreateFromTemplateArgs) (*mcp.CallToolResult, error) {
+	values, err := templateValues(args.Values)
+	if err != nil {
+		return FormatArgsError(toolargs.Errors{{Field: "values", Message: err.Error()}})
+	}
+
+	workspaceID := args.WorkspaceID
+	tmpl, err := t.library.Find(ctx, workspaceID, args.Template)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	parentID := args.ParentID
+	if parentID == "" {
+		parentID = tmpl.ParentID
+	}
+	// The template's parent is not among the arguments the policy checks,
+	// so the parent is checked here against the allowlist and the workspace
+	if parentID != "" {
+		parent, err := t.client.GetItem(ctx, parentID)
+		if err != nil {
+			return FormatError(fmt.Errorf("failed to get parent %s: %w", parentID, err))
+		}
+		if !t.policy.allowsWorkspace(parent.WorkspaceID) {
+			return FormatError(fmt.Errorf("parent %s is in workspace %s, which is not in the workspace allowlist", parentID, parent.WorkspaceID))
+		}
+		if workspaceID == "" {
+			workspaceID = parent.WorkspaceID
+		} else if parent.WorkspaceID != workspaceID {
+			return FormatError(fmt.Errorf("parent %s is in workspace %s, not %s", parentID, parent.WorkspaceID, workspaceID))
+		}
+	}
+	if workspaceID == "" {
+		return FormatError(fmt.Errorf("workspace_id is required when neither parent_id nor the template sets a parent"))
+	}
+
+	rendered, err := tmpl.Render(args.Title, values, t.library.Defaults(ctx, args.UserID))
+	if err != nil {
+		return FormatError(err)
+	}
+
+	if args.Preview {
+		return FormatResult(map[string]interface{}{
+			"template":     tmpl.Name,
+			"workspace_id": workspaceID,
+			"parent_id":    parentID,
+			"title":        rendered.Title,
+			"content":      rendered.Content,
+		})
+	}
+
+	item, err := t.client.CreateItem(ctx, &nuclino.CreateItemRequest{
+		Title:       rendered.Title,
+		Content:     rendered.Content,
+		WorkspaceID: workspaceID,
+		ParentID:    parentID,
+	})
+	if err != nil {
+		return FormatError(err)
+	}
+	return FormatResult(item)
+}
+
+// templateValues accepts the values argument as an object or a JSON string
+func templateValues(raw interface{}) (map[string]interface{}, error) {
+	switch v := raw.(type) {
+	case nil:
+		return map[string]interface{}{}, nil
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		values := map[string]interface{}{}
+		if v == "" {
+			return values, nil
+		}
+		if err := json.Unmarshal([]byte(v), &values); err != nil {
+			return nil, fmt.Errorf("must be a JSON object: %w", err)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("must be an object")
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
+	"github.com/lukasz/nuclino-mcp-server/internal/templates"
+)
+
+func TestCreateFromTemplateTool_Execute(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "incident.md"), []byte(
+		"---\nparent: incidents\n---\n# Incident: {{.Summary}}\n\nReported by {{.User}} on {{.Date}}.\n"), 0o600))
+
+	mockClient := new(MockClient)
+	mockClient.On("GetItem", mock.Anything, "incidents").Return(&nuclino.Item{ID: "incidents", WorkspaceID: "workspace-1"}, nil)
+	mockClient.On("GetUser", mock.Anything, "user-1").Return(&nuclino.User{FirstName: "Ada", LastName: "Lovelace"}, nil)
+	tool := &CreateFromTemplateTool{
+		client:  mockClient,
+		library: templates.NewLibrary(mockClient, templates.Config{Dir: dir}),
+	}
+
+	args := map[string]interface{}{
+		"template": "incident",
+		"values":   map[string]interface{}{"Summary": "API outage"},
+		"user_id":  "user-1",
+		"preview":  true,
+	}
+	result, err := tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"title":"Incident: API outage"`)
+	assert.Contains(t, text, "Reported by Ada Lovelace on")
+	mockClient.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+
+	mockClient.On("CreateItem", mock.Anything, mock.MatchedBy(func(req *nuclino.CreateItemRequest) bool {
+		return req.Title == "Incident: API outage" && req.WorkspaceID == "workspace-1" &&
+			req.ParentID == "incidents" && strings.HasPrefix(req.Content, "Reported by Ada Lovelace")
+	})).Return(&nuclino.Item{ID: "new-item", Title: "Incident: API outage"}, nil)
+
+	args["preview"] = false
+	result, err = tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "new-item")
+
+	// Missing variables are reported by name
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"template": "incident", "values": `{}`})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Summary")
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCreateFromTemplateTool_ChecksTheTemplateParent(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "incident.md"), []byte(
+		"---\nparent: incidents\n---\n# Incident\n"), 0o600))
+
+	mockClient := new(MockClient)
+	mockClient.On("GetItem", mock.Anything, "incidents").Return(&nuclino.Item{ID: "incidents", WorkspaceID: "workspace-2"}, nil)
+	mockClient.On("GetWorkspace", mock.Anything, "workspace-1").Return(&nuclino.Workspace{ID: "workspace-1"}, nil)
+	tool := &CreateFromTemplateTool{
+		client:  mockClient,
+		library: templates.NewLibrary(mockClient, templates.Config{Dir: dir}),
+		policy:  Policy{Workspaces: []string{"workspace-1"}},
+	}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"template": "incident", "workspace_id": "workspace-1"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "parent incidents is in workspace workspace-2, which is not in the workspace allowlist")
+
+	tool.policy = Policy{}
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"template": "incident", "workspace_id": "workspace-1"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "parent incidents is in workspace workspace-2, not workspace-1")
+	mockClient.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Toolsets group tools so a deployment can enable only what it needs
+const (
+	ToolsetItems       = "items"
+	ToolsetWorkspaces  = "workspaces"
+	ToolsetLinks       = "links"
+	ToolsetTemplates   = "templates"
+	ToolsetBulk        = "bulk"
+	ToolsetCollections = "collections"
+	ToolsetUsers       = "users"
+	ToolsetFiles       = "files"
+	ToolsetAudit       = "audit"
+)
+
+// Tool categories describe what a tool does to Nuclino content
+const (
+	CategoryRead        = "read"
+	CategoryWrite       = "write"
+	CategoryDestructive = "destructive"
+)
+
+// toolsets maps each tool to its toolset
+var toolsets = map[string]string{
+	"nuclino_get_item":      ToolsetItems,
+	"nuclino_read_item":     ToolsetItems,
+	"nuclino_get_outline":   ToolsetItems,
+	"nuclino_read_sections": ToolsetItems,
+	"nuclino_search_items":  ToolsetItems,
+	"nuclino_create_item":   ToolsetItems,
+	"nuclino_update_item":   ToolsetItems,
+	"nuclino_delete_item":   ToolsetItems,
+	"nuclino_move_item":     ToolsetItems,
+	"nuclino_list_items":    ToolsetItems,
+
+	"nuclino_list_workspaces":          ToolsetWorkspaces,
+	"nuclino_get_workspace":            ToolsetWorkspaces,
+	"nuclino_create_workspace":         ToolsetWorkspaces,
+	"nuclino_update_workspace":         ToolsetWorkspaces,
+	"nuclino_delete_workspace":         ToolsetWorkspaces,
+	"nuclino_get_workspace_overview":   ToolsetWorkspaces,
+	"nuclino_search_workspace_content": ToolsetWorkspaces,
+
+	"nuclino_get_backlinks":      ToolsetLinks,
+	"nuclino_link_health_report": ToolsetLinks,
+	"nuclino_lint_workspace":     ToolsetLinks,
+	"nuclino_find_duplicates":    ToolsetLinks,
+	"nuclino_merge_duplicates":   ToolsetLinks,
+
+	"nuclino_list_templates":       ToolsetTemplates,
+	"nuclino_create_from_template": ToolsetTemplates,
+
+	"nuclino_bulk_plan":    ToolsetBulk,
+	"nuclino_bulk_execute": ToolsetBulk,
+	"nuclino_bulk_status":  ToolsetBulk,
+	"nuclino_find_replace": ToolsetBulk,
+
+	"nuclino_list_collections":           ToolsetCollections,
+	"nuclino_get_collection":             ToolsetCollections,
+	"nuclino_create_collection":          ToolsetCollections,
+	"nuclino_update_collection":          ToolsetCollections,
+	"nuclino_delete_collection":          ToolsetCollections,
+	"nuclino_list_collection_items":      ToolsetCollections,
+	"nuclino_get_collection_overview":    ToolsetCollections,
+	"nuclino_organize_collection":        ToolsetCollections,
+	"nuclino_bulk_collection_operations": ToolsetCollections,
+
+	"nuclino_get_user":   ToolsetUsers,
+	"nuclino_list_teams": ToolsetUsers,
+	"nuclino_get_team":   ToolsetUsers,
+
+	"nuclino_list_files":     ToolsetFiles,
+	"nuclino_get_file":       ToolsetFiles,
+	"nuclino_upload_file":    ToolsetFiles,
+	"nuclino_download_file":  ToolsetFiles,
+	"nuclino_read_file_text": ToolsetFiles,
+
+	"nuclino_audit_query": ToolsetAudit,
+}
+
+// categories lists the tools that change content; every other tool is
+// CategoryRead
+var categories = map[string]string{
+	"nuclino_create_item":                CategoryWrite,
+	"nuclino_update_item":                CategoryWrite,
+	"nuclino_move_item":                  CategoryWrite,
+	"nuclino_create_workspace":           CategoryWrite,
+	"nuclino_update_workspace":           CategoryWrite,
+	"nuclino_create_from_template":       CategoryWrite,
+	"nuclino_find_replace":               CategoryWrite,
+	"nuclino_create_collection":          CategoryWrite,
+	"nuclino_update_collection":          CategoryWrite,
+	"nuclino_organize_collection":        CategoryWrite,
+	"nuclino_bulk_collection_operations": CategoryWrite,
+	"nuclino_upload_file":                CategoryWrite,
+
+	"nuclino_delete_item":       CategoryDestructive,
+	"nuclino_delete_workspace":  CategoryDestructive,
+	"nuclino_delete_collection": CategoryDestructive,
+	"nuclino_merge_duplicates":  CategoryDestructive,
+	"nuclino_bulk_execute":      CategoryDestructive,
+}
+
+// ToolsetOf returns the toolset a tool belongs to
+func ToolsetOf(name string) string {
+	return toolsets[name]
+}
+
+// CategoryOf returns whether a tool reads, writes or destroys content
+func CategoryOf(name string) string {
+	if category, ok := categories[name]; ok {
+		return category
+	}
+	return CategoryRead
+}
+
+// Toolsets returns the names of every toolset, sorted
+func Toolsets() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, toolset := range toolsets {
+		if !seen[toolset] {
+			seen[toolset] = true
+			names = append(names, toolset)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateToolsets rejects unknown toolset names
+func ValidateToolsets(names []string) error {
+	known := Toolsets()
+	for _, name := range names {
+		found := false
+		for _, toolset := range known {
+			found = found || toolset == name
+		}
+		if !found {
+			return fmt.Errorf("unknown toolset %q; available: %s", name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
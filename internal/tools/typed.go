@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/lukasz/nuclino-mcp-server/internal/toolargs"
+)
+
+// SchemaOf returns the input schema generated from the arguments struct A;
+// see package toolargs for the tags
+func SchemaOf[A any]() interface{} {
+	var args A
+	return toolargs.Schema(&args)
+}
+
+// runTyped decodes and validates args into A and calls run with them.
+// Argument problems are answered with an invalid_arguments error result
+// before run is called.
+func runTyped[A any](ctx context.Context, args map[string]interface{}, run func(context.Context, *A) (*mcp.CallToolResult, error)) (*mcp.CallToolResult, error) {
+	var typed A
+	if err := toolargs.Decode(args, &typed); err != nil {
+		return FormatArgsError(err)
+	}
+	return run(ctx, &typed)
+}
+
+// FormatArgsError formats argument errors as a structured MCP error result
+// listing each field, so the model can fix its call
+func FormatArgsError(err error) (*mcp.CallToolResult, error) {
+	var fieldErrs toolargs.Errors
+	if !errors.As(err, &fieldErrs) {
+		return FormatError(err)
+	}
+	body, _ := json.MarshalIndent(struct {
+		Error   string          `json:"error"`
+		Message string          `json:"message"`
+		Fields  toolargs.Errors `json:"fields"`
+	}{"invalid_arguments", fieldErrs.Error(), fieldErrs}, "", "  ")
+	return &mcp.CallToolResult{
+		Content: []interface{}{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(body),
+			},
+		},
+		IsError: true,
+	}, nil
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -21,19 +20,25 @@ func (t *GetUserTool) Description() string {
 	return "Get information about a specific Nuclino user by their ID"
 }
 
+// GetUserArgs are the arguments of nuclino_get_user
+type GetUserArgs struct {
+	UserID string `json:"user_id" desc:"The ID of the user to retrieve" validate:"required"`
+}
+
 func (t *GetUserTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"user_id": StringProperty("The ID of the user to retrieve"),
-	}, []string{"user_id"})
+	return SchemaOf[GetUserArgs]()
 }
 
-func (t *GetUserTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	userID, ok := args["user_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("user_id must be a string"))
-	}
+func (t *GetUserTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.User]()
+}
+
+func (t *GetUserTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	user, err := t.client.GetUser(context.Background(), userID)
+func (t *GetUserTool) run(ctx context.Context, args *GetUserArgs) (*mcp.CallToolResult, error) {
+	user, err := t.client.GetUser(ctx, args.UserID)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -54,26 +59,25 @@ func (t *ListTeamsTool) Description() string {
 	return "List all accessible Nuclino teams with pagination support"
 }
 
-func (t *ListTeamsTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"limit":  IntProperty("Maximum number of teams to return (default: 50)"),
-		"offset": IntProperty("Number of teams to skip for pagination (default: 0)"),
-	}, []string{})
+// ListTeamsArgs are the arguments of nuclino_list_teams
+type ListTeamsArgs struct {
+	pageArgs
 }
 
-func (t *ListTeamsTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	limit := 50
-	offset := 0
+func (t *ListTeamsTool) InputSchema() interface{} {
+	return SchemaOf[ListTeamsArgs]()
+}
 
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
-	}
+func (t *ListTeamsTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.TeamsResponse]()
+}
 
-	if o, ok := args["offset"].(float64); ok {
-		offset = int(o)
-	}
+func (t *ListTeamsTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	teams, err := t.client.ListTeams(context.Background(), limit, offset)
+func (t *ListTeamsTool) run(ctx context.Context, args *ListTeamsArgs) (*mcp.CallToolResult, error) {
+	teams, err := t.client.ListTeams(ctx, args.Limit, args.Offset)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -94,19 +98,25 @@ func (t *GetTeamTool) Description() string {
 	return "Get detailed information about a specific Nuclino team"
 }
 
+// GetTeamArgs are the arguments of nuclino_get_team
+type GetTeamArgs struct {
+	TeamID string `json:"team_id" desc:"The ID of the team to retrieve" validate:"required"`
+}
+
 func (t *GetTeamTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"team_id": StringProperty("The ID of the team to retrieve"),
-	}, []string{"team_id"})
+	return SchemaOf[GetTeamArgs]()
 }
 
-func (t *GetTeamTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	teamID, ok := args["team_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("team_id must be a string"))
-	}
+func (t *GetTeamTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.Team]()
+}
+
+func (t *GetTeamTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	team, err := t.client.GetTeam(context.Background(), teamID)
+func (t *GetTeamTool) run(ctx context.Context, args *GetTeamArgs) (*mcp.CallToolResult, error) {
+	team, err := t.client.GetTeam(ctx, args.TeamID)
 	if err != nil {
 		return FormatError(err)
 	}
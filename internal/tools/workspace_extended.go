@@ -3,7 +3,12 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
+	"github.com/lukasz/nuclino-mcp-server/internal/extract"
 	"github.com/lukasz/nuclino-mcp-server/internal/nuclino"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -21,44 +26,33 @@ func (t *GetWorkspaceOverviewTool) Description() string {
 	return "Get comprehensive overview of a Nuclino workspace including collections, item counts, and recent activity"
 }
 
-func (t *GetWorkspaceOverviewTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id":   StringProperty("The ID of the workspace to analyze"),
-		"include_items":  BoolProperty("Whether to include summary of items (default: false)"),
-		"include_recent": BoolProperty("Whether to include recent items (default: false)"),
-		"recent_limit":   IntProperty("Number of recent items to include (default: 10)"),
-	}, []string{"workspace_id"})
+// GetWorkspaceOverviewArgs are the arguments of nuclino_get_workspace_overview
+type GetWorkspaceOverviewArgs struct {
+	WorkspaceID   string `json:"workspace_id" desc:"The ID of the workspace to analyze" validate:"required"`
+	IncludeItems  bool   `json:"include_items" desc:"Whether to include summary of items (default: false)"`
+	IncludeRecent bool   `json:"include_recent" desc:"Whether to include recent items (default: false)"`
+	RecentLimit   int    `json:"recent_limit" desc:"Number of recent items to include (default: 10)" validate:"min=0" default:"10"`
 }
 
-func (t *GetWorkspaceOverviewTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
-
-	includeItems := false
-	if include, ok := args["include_items"].(bool); ok {
-		includeItems = include
-	}
+func (t *GetWorkspaceOverviewTool) InputSchema() interface{} {
+	return SchemaOf[GetWorkspaceOverviewArgs]()
+}
 
-	includeRecent := false
-	if include, ok := args["include_recent"].(bool); ok {
-		includeRecent = include
-	}
+func (t *GetWorkspaceOverviewTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	recentLimit := 10
-	if limit, ok := args["recent_limit"].(float64); ok {
-		recentLimit = int(limit)
-	}
+func (t *GetWorkspaceOverviewTool) run(ctx context.Context, args *GetWorkspaceOverviewArgs) (*mcp.CallToolResult, error) {
+	workspaceID := args.WorkspaceID
 
 	// Get workspace info
-	workspace, err := t.client.GetWorkspace(context.Background(), workspaceID)
+	workspace, err := t.client.GetWorkspace(ctx, workspaceID)
 	if err != nil {
 		return FormatError(err)
 	}
 
 	// Get collections in workspace
-	collections, err := t.client.ListCollections(context.Background(), workspaceID, 100, 0)
+	collections, err := t.client.ListCollections(ctx, workspaceID, 100, 0)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -71,32 +65,32 @@ func (t *GetWorkspaceOverviewTool) Execute(args map[string]interface{}) (*mcp.Ca
 		},
 	}
 
-	if includeItems {
+	if args.IncludeItems {
 		// Get items summary
-		items, err := t.client.ListItems(context.Background(), workspaceID, 1000, 0) // Get many for counting
+		items, err := nuclino.ListAllItems(ctx, t.client, workspaceID, listPageSize)
 		if err != nil {
 			return FormatError(err)
 		}
 
 		// Count items per collection
 		itemCounts := make(map[string]int)
-		for _, item := range items.Results {
+		for _, item := range items {
 			itemCounts[item.CollectionID]++
 		}
 
 		overview["items_summary"] = map[string]interface{}{
-			"total_items":          items.Total,
+			"total_items":          len(items),
 			"items_per_collection": itemCounts,
 		}
 
-		if includeRecent {
+		if args.IncludeRecent {
 			// Get recent items (first N items, assuming they're ordered by update time)
-			limit := recentLimit
-			if limit > len(items.Results) {
-				limit = len(items.Results)
+			limit := args.RecentLimit
+			if limit > len(items) {
+				limit = len(items)
 			}
 
-			recentItems := items.Results[:limit]
+			recentItems := items[:limit]
 			overview["recent_items"] = recentItems
 		}
 	}
@@ -106,7 +100,8 @@ func (t *GetWorkspaceOverviewTool) Execute(args map[string]interface{}) (*mcp.Ca
 
 // SearchWorkspaceContentTool provides advanced search across workspace
 type SearchWorkspaceContentTool struct {
-	client nuclino.Client
+	client    nuclino.Client
+	extractor *extract.Service
 }
 
 func (t *SearchWorkspaceContentTool) Name() string {
@@ -114,50 +109,30 @@ func (t *SearchWorkspaceContentTool) Name() string {
 }
 
 func (t *SearchWorkspaceContentTool) Description() string {
-	return "Advanced search within a workspace with content type filtering and aggregated results"
+	return "Advanced search within a workspace with content type filtering and aggregated results. Optionally searches the text of attached files."
 }
 
-func (t *SearchWorkspaceContentTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id":        StringProperty("The ID of the workspace to search in"),
-		"query":               StringProperty("Search query text"),
-		"search_titles":       BoolProperty("Whether to search in titles (default: true)"),
-		"search_content":      BoolProperty("Whether to search in content (default: true)"),
-		"group_by_collection": BoolProperty("Whether to group results by collection (default: false)"),
-		"limit":               IntProperty("Maximum number of items to return (default: 50)"),
-	}, []string{"workspace_id", "query"})
+// SearchWorkspaceContentArgs are the arguments of nuclino_search_workspace_content
+type SearchWorkspaceContentArgs struct {
+	WorkspaceID       string `json:"workspace_id" desc:"The ID of the workspace to search in" validate:"required"`
+	Query             string `json:"query" desc:"Search query text" validate:"required"`
+	SearchTitles      bool   `json:"search_titles" desc:"Whether to search in titles (default: true)" default:"true"`
+	SearchContent     bool   `json:"search_content" desc:"Whether to search in content (default: true)" default:"true"`
+	GroupByCollection bool   `json:"group_by_collection" desc:"Whether to group results by collection (default: false)"`
+	SearchAttachments bool   `json:"search_attachments" desc:"Whether to search extracted text of attached files (default: false)"`
+	Limit             int    `json:"limit" desc:"Maximum number of items to return (default: 50)" validate:"min=1" default:"50"`
 }
 
-func (t *SearchWorkspaceContentTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
-
-	query, ok := args["query"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("query must be a string"))
-	}
-
-	searchTitles := true
-	if search, ok := args["search_titles"].(bool); ok {
-		searchTitles = search
-	}
-
-	searchContent := true
-	if search, ok := args["search_content"].(bool); ok {
-		searchContent = search
-	}
+func (t *SearchWorkspaceContentTool) InputSchema() interface{} {
+	return SchemaOf[SearchWorkspaceContentArgs]()
+}
 
-	groupByCollection := false
-	if group, ok := args["group_by_collection"].(bool); ok {
-		groupByCollection = group
-	}
+func (t *SearchWorkspaceContentTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	limit := 50
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
-	}
+func (t *SearchWorkspaceContentTool) run(ctx context.Context, args *SearchWorkspaceContentArgs) (*mcp.CallToolResult, error) {
+	workspaceID, query, limit := args.WorkspaceID, args.Query, args.Limit
 
 	// Search items in workspace
 	searchReq := &nuclino.SearchItemsRequest{
@@ -167,7 +142,7 @@ func (t *SearchWorkspaceContentTool) Execute(args map[string]interface{}) (*mcp.
 		Offset:      0,
 	}
 
-	items, err := t.client.SearchItems(context.Background(), searchReq)
+	items, err := t.client.SearchItems(ctx, searchReq)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -177,11 +152,11 @@ func (t *SearchWorkspaceContentTool) Execute(args map[string]interface{}) (*mcp.
 	for _, item := range items.Results {
 		matches := false
 
-		if searchTitles && containsIgnoreCase(item.Title, query) {
+		if args.SearchTitles && containsIgnoreCase(item.Title, query) {
 			matches = true
 		}
 
-		if searchContent && containsIgnoreCase(item.Content, query) {
+		if args.SearchContent && containsIgnoreCase(item.Content, query) {
 			matches = true
 		}
 
@@ -194,6 +169,27 @@ func (t *SearchWorkspaceContentTool) Execute(args map[string]interface{}) (*mcp.
 		}
 	}
 
+	var attachmentMatches []attachmentMatch
+	var attachmentErrors []string
+	if args.SearchAttachments && t.extractor != nil {
+		attachmentMatches, attachmentErrors, err = t.searchAttachments(ctx, workspaceID, query)
+		if err != nil {
+			return FormatError(err)
+		}
+
+		// Items whose attachments match are results in their own right
+		seen := make(map[string]bool)
+		for _, item := range filteredItems {
+			seen[item.ID] = true
+		}
+		for _, match := range attachmentMatches {
+			if !seen[match.item.ID] && len(filteredItems) < limit {
+				filteredItems = append(filteredItems, match.item)
+				seen[match.item.ID] = true
+			}
+		}
+	}
+
 	result := map[string]interface{}{
 		"query":        query,
 		"workspace_id": workspaceID,
@@ -201,7 +197,24 @@ func (t *SearchWorkspaceContentTool) Execute(args map[string]interface{}) (*mcp.
 		"items":        filteredItems,
 	}
 
-	if groupByCollection {
+	if args.SearchAttachments {
+		matches := make([]map[string]interface{}, 0, len(attachmentMatches))
+		for _, match := range attachmentMatches {
+			matches = append(matches, map[string]interface{}{
+				"item_id":    match.item.ID,
+				"item_title": match.item.Title,
+				"file_id":    match.fileID,
+				"file_name":  match.fileName,
+				"snippet":    match.snippet,
+			})
+		}
+		result["attachment_matches"] = matches
+		if len(attachmentErrors) > 0 {
+			result["attachment_errors"] = attachmentErrors
+		}
+	}
+
+	if args.GroupByCollection {
 		// Group results by collection
 		groupedResults := make(map[string][]nuclino.Item)
 		for _, item := range filteredItems {
@@ -214,6 +227,141 @@ func (t *SearchWorkspaceContentTool) Execute(args map[string]interface{}) (*mcp.
 	return FormatResult(result)
 }
 
+// listPageSize is the page size used to list every item of a workspace
+const listPageSize = 100
+
+// attachmentMatch is a search hit inside the extracted text of an attached file
+type attachmentMatch struct {
+	item     nuclino.Item
+	fileID   string
+	fileName string
+	snippet  string
+}
+
+// Limits on the attached files a single search reads. Extraction downloads
+// every file, so large workspaces would otherwise keep a call busy for minutes.
+const (
+	attachmentSearchMaxFiles = 50
+	attachmentSearchMaxBytes = 50 * 1024 * 1024
+	attachmentSearchTimeout  = 30 * time.Second
+	attachmentSearchWorkers  = 4
+)
+
+// searchAttachments scans the files attached to workspace items with a
+// bounded number of concurrent extractions. Files that cannot be extracted,
+// or are skipped once the file, byte or time budget is spent, are reported
+// but do not fail the search.
+func (t *SearchWorkspaceContentTool) searchAttachments(ctx context.Context, workspaceID, query string) ([]attachmentMatch, []string, error) {
+	items, err := nuclino.ListAllItems(ctx, t.client, workspaceID, listPageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type attachment struct {
+		item   nuclino.Item
+		fileID string
+	}
+	var attachments []attachment
+	for _, item := range items {
+		for _, fileID := range item.ContentMeta.FileIDs {
+			attachments = append(attachments, attachment{item: item, fileID: fileID})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, attachmentSearchTimeout)
+	defer cancel()
+	// Every file writes only its own slot, so results keep the item order
+	found := make([]*attachmentMatch, len(attachments))
+	failed := make([]string, len(attachments))
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		read int64
+	)
+	sem := make(chan struct{}, attachmentSearchWorkers)
+
+	for i := range attachments {
+		file := attachments[i]
+		sem <- struct{}{}
+		mu.Lock()
+		spent := read >= attachmentSearchMaxBytes
+		mu.Unlock()
+		switch {
+		case i >= attachmentSearchMaxFiles:
+			failed[i] = fmt.Sprintf("%s: skipped, a search reads at most %d files", file.fileID, attachmentSearchMaxFiles)
+		case spent:
+			failed[i] = fmt.Sprintf("%s: skipped, a search reads at most %d bytes", file.fileID, attachmentSearchMaxBytes)
+		case ctx.Err() != nil:
+			failed[i] = fmt.Sprintf("%s: skipped, the search ran out of time after %s", file.fileID, attachmentSearchTimeout)
+		}
+		if failed[i] != "" {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			extracted, err := t.extractor.ExtractFile(ctx, file.fileID)
+			if err != nil {
+				failed[i] = fmt.Sprintf("%s: %v", file.fileID, err)
+				return
+			}
+			mu.Lock()
+			read += int64(extracted.Size)
+			mu.Unlock()
+			if snippet, ok := matchSnippet(extracted.Text, query, 80); ok {
+				found[i] = &attachmentMatch{
+					item:     file.item,
+					fileID:   file.fileID,
+					fileName: extracted.FileName,
+					snippet:  snippet,
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var matches []attachmentMatch
+	var failures []string
+	for i := range attachments {
+		if found[i] != nil {
+			matches = append(matches, *found[i])
+		}
+		if failed[i] != "" {
+			failures = append(failures, failed[i])
+		}
+	}
+	return matches, failures, nil
+}
+
+// matchSnippet returns the text surrounding the first case-insensitive match
+func matchSnippet(text, query string, radius int) (string, bool) {
+	idx := indexOfSubstring(toLower(text), toLower(query))
+	if idx < 0 {
+		return "", false
+	}
+
+	start := idx - radius
+	if start < 0 {
+		start = 0
+	}
+	end := idx + len(query) + radius
+	if end > len(text) {
+		end = len(text)
+	}
+	// Avoid cutting multi-byte characters in half
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	return strings.Join(strings.Fields(text[start:end]), " "), true
+}
+
 // Helper function for case-insensitive string contains
 func containsIgnoreCase(str, substr string) bool {
 	str = toLower(str)
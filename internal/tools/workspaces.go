@@ -21,26 +21,25 @@ func (t *ListWorkspacesTool) Description() string {
 	return "List all accessible Nuclino workspaces with pagination support"
 }
 
-func (t *ListWorkspacesTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"limit":  IntProperty("Maximum number of workspaces to return (default: 50)"),
-		"offset": IntProperty("Number of workspaces to skip for pagination (default: 0)"),
-	}, []string{})
+// ListWorkspacesArgs are the arguments of nuclino_list_workspaces
+type ListWorkspacesArgs struct {
+	pageArgs
 }
 
-func (t *ListWorkspacesTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	limit := 50
-	offset := 0
+func (t *ListWorkspacesTool) InputSchema() interface{} {
+	return SchemaOf[ListWorkspacesArgs]()
+}
 
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
-	}
+func (t *ListWorkspacesTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.WorkspacesResponse]()
+}
 
-	if o, ok := args["offset"].(float64); ok {
-		offset = int(o)
-	}
+func (t *ListWorkspacesTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	workspaces, err := t.client.ListWorkspaces(context.Background(), limit, offset)
+func (t *ListWorkspacesTool) run(ctx context.Context, args *ListWorkspacesArgs) (*mcp.CallToolResult, error) {
+	workspaces, err := t.client.ListWorkspaces(ctx, args.Limit, args.Offset)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -61,19 +60,25 @@ func (t *GetWorkspaceTool) Description() string {
 	return "Get detailed information about a specific Nuclino workspace"
 }
 
+// GetWorkspaceArgs are the arguments of nuclino_get_workspace
+type GetWorkspaceArgs struct {
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to retrieve" validate:"required"`
+}
+
 func (t *GetWorkspaceTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id": StringProperty("The ID of the workspace to retrieve"),
-	}, []string{"workspace_id"})
+	return SchemaOf[GetWorkspaceArgs]()
 }
 
-func (t *GetWorkspaceTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
+func (t *GetWorkspaceTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.Workspace]()
+}
+
+func (t *GetWorkspaceTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	workspace, err := t.client.GetWorkspace(context.Background(), workspaceID)
+func (t *GetWorkspaceTool) run(ctx context.Context, args *GetWorkspaceArgs) (*mcp.CallToolResult, error) {
+	workspace, err := t.client.GetWorkspace(ctx, args.WorkspaceID)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -94,30 +99,31 @@ func (t *CreateWorkspaceTool) Description() string {
 	return "Create a new Nuclino workspace within a team"
 }
 
+// CreateWorkspaceArgs are the arguments of nuclino_create_workspace
+type CreateWorkspaceArgs struct {
+	Name   string `json:"name" desc:"The name of the new workspace" validate:"required"`
+	TeamID string `json:"team_id" desc:"The ID of the team to create the workspace in" validate:"required"`
+}
+
 func (t *CreateWorkspaceTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"name":    StringProperty("The name of the new workspace"),
-		"team_id": StringProperty("The ID of the team to create the workspace in"),
-	}, []string{"name", "team_id"})
+	return SchemaOf[CreateWorkspaceArgs]()
 }
 
-func (t *CreateWorkspaceTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	name, ok := args["name"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("name must be a string"))
-	}
+func (t *CreateWorkspaceTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.Workspace]()
+}
 
-	teamID, ok := args["team_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("team_id must be a string"))
-	}
+func (t *CreateWorkspaceTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
+func (t *CreateWorkspaceTool) run(ctx context.Context, args *CreateWorkspaceArgs) (*mcp.CallToolResult, error) {
 	req := &nuclino.CreateWorkspaceRequest{
-		Name:   name,
-		TeamID: teamID,
+		Name:   args.Name,
+		TeamID: args.TeamID,
 	}
 
-	workspace, err := t.client.CreateWorkspace(context.Background(), req)
+	workspace, err := t.client.CreateWorkspace(ctx, req)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -138,29 +144,30 @@ func (t *UpdateWorkspaceTool) Description() string {
 	return "Update an existing Nuclino workspace (currently supports name changes)"
 }
 
+// UpdateWorkspaceArgs are the arguments of nuclino_update_workspace
+type UpdateWorkspaceArgs struct {
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to update" validate:"required"`
+	Name        string `json:"name" desc:"The new name for the workspace" validate:"required"`
+}
+
 func (t *UpdateWorkspaceTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id": StringProperty("The ID of the workspace to update"),
-		"name":         StringProperty("The new name for the workspace"),
-	}, []string{"workspace_id", "name"})
+	return SchemaOf[UpdateWorkspaceArgs]()
 }
 
-func (t *UpdateWorkspaceTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
+func (t *UpdateWorkspaceTool) OutputSchema() interface{} {
+	return OutputSchemaOf[nuclino.Workspace]()
+}
 
-	name, ok := args["name"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("name must be a string"))
-	}
+func (t *UpdateWorkspaceTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
+func (t *UpdateWorkspaceTool) run(ctx context.Context, args *UpdateWorkspaceArgs) (*mcp.CallToolResult, error) {
 	req := &nuclino.UpdateWorkspaceRequest{
-		Name: &name,
+		Name: &args.Name,
 	}
 
-	workspace, err := t.client.UpdateWorkspace(context.Background(), workspaceID, req)
+	workspace, err := t.client.UpdateWorkspace(ctx, args.WorkspaceID, req)
 	if err != nil {
 		return FormatError(err)
 	}
@@ -178,34 +185,30 @@ func (t *DeleteWorkspaceTool) Name() string {
 }
 
 func (t *DeleteWorkspaceTool) Description() string {
-	return "Delete a Nuclino workspace. WARNING: This action cannot be undone and will delete all content."
+	return "Delete a Nuclino workspace. WARNING: This action cannot be undone and will delete all content. The first call returns what would be deleted and a confirm_token; call again with the token to delete."
 }
 
-func (t *DeleteWorkspaceTool) InputSchema() interface{} {
-	return JSONSchema(map[string]interface{}{
-		"workspace_id": StringProperty("The ID of the workspace to delete"),
-		"confirm":      BoolProperty("Confirmation that you want to delete the workspace (must be true)"),
-	}, []string{"workspace_id", "confirm"})
+// DeleteWorkspaceArgs are the arguments of nuclino_delete_workspace
+type DeleteWorkspaceArgs struct {
+	WorkspaceID string `json:"workspace_id" desc:"The ID of the workspace to delete" validate:"required"`
 }
 
-func (t *DeleteWorkspaceTool) Execute(args map[string]interface{}) (*mcp.CallToolResult, error) {
-	workspaceID, ok := args["workspace_id"].(string)
-	if !ok {
-		return FormatError(fmt.Errorf("workspace_id must be a string"))
-	}
+func (t *DeleteWorkspaceTool) InputSchema() interface{} {
+	return SchemaOf[DeleteWorkspaceArgs]()
+}
 
-	confirm, ok := args["confirm"].(bool)
-	if !ok || !confirm {
-		return FormatError(fmt.Errorf("you must set confirm=true to delete a workspace"))
-	}
+func (t *DeleteWorkspaceTool) Execute(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return runTyped(ctx, args, t.run)
+}
 
-	err := t.client.DeleteWorkspace(context.Background(), workspaceID)
+func (t *DeleteWorkspaceTool) run(ctx context.Context, args *DeleteWorkspaceArgs) (*mcp.CallToolResult, error) {
+	err := t.client.DeleteWorkspace(ctx, args.WorkspaceID)
 	if err != nil {
 		return FormatError(err)
 	}
 
 	return FormatResult(map[string]string{
 		"status":  "success",
-		"message": fmt.Sprintf("Workspace %s has been deleted successfully", workspaceID),
+		"message": fmt.Sprintf("Workspace %s has been deleted successfully", args.WorkspaceID),
 	})
 }
@@ -0,0 +1,189 @@
+// Package tracing sets up OpenTelemetry tracing and holds the helpers the
+// server uses to start spans. Until Setup installs a provider every span is
+// a no-op.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of every span the server starts
+const instrumentationName = "github.com/lukasz/nuclino-mcp-server"
+
+// Exporters
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// Config selects where spans go
+type Config struct {
+	// Exporter is ExporterNone, ExporterOTLP or ExporterFile
+	Exporter string
+	// Endpoint is the OTLP/HTTP endpoint URL, e.g. http://localhost:4318;
+	// empty uses OTEL_EXPORTER_OTLP_ENDPOINT or the SDK default
+	Endpoint string
+	// File receives one JSON span per line with ExporterFile
+	File string
+	// SampleRatio is the share of traces kept, from 0 to 1 (default: 1)
+	SampleRatio float64
+	// ServiceName and ServiceVersion describe the process in every span
+	ServiceName    string
+	ServiceVersion string
+}
+
+// DefaultFilePath returns the default file of the JSON exporter
+func DefaultFilePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "nuclino-mcp", "traces.jsonl")
+}
+
+// Setup installs the global tracer provider for config and returns a
+// function that flushes and stops it. ExporterNone leaves tracing off.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	var (
+		exporter sdktrace.SpanExporter
+		file     *os.File
+		err      error
+	)
+	switch config.Exporter {
+	case "", ExporterNone:
+		return noop, nil
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if config.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(config.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	case ExporterFile:
+		path := config.File
+		if path == "" {
+			path = DefaultFilePath()
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return noop, fmt.Errorf("failed to create trace directory: %w", err)
+		}
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return noop, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return noop, fmt.Errorf("unknown trace exporter %q (use %q, %q or %q)", config.Exporter, ExporterNone, ExporterOTLP, ExporterFile)
+	}
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return noop, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	ratio := config.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = "nuclino-mcp-server"
+	}
+	attrs := []attribute.KeyValue{attribute.String("service.name", serviceName)}
+	if config.ServiceVersion != "" {
+		attrs = append(attrs, attribute.String("service.version", config.ServiceVersion))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns the server's tracer from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span as a child of the span in ctx, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if not nil, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Fail marks span as failed with a message, for errors that are results
+// rather than Go errors
+func Fail(span trace.Span, message string) {
+	span.SetStatus(codes.Error, message)
+}
+
+// transport starts a span for each HTTP round trip
+type transport struct {
+	base http.RoundTripper
+}
+
+// Transport wraps base, or http.DefaultTransport when nil, so each HTTP
+// attempt, including every retry, gets its own span with the method, path
+// and status code
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Tracer().Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		))
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		Fail(span, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a provider that keeps ended spans in memory
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestTransport(t *testing.T) {
+	recorder := recordSpans(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, parent := Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v0/items", http.NoBody)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	attempt := spans[0]
+	assert.Equal(t, "HTTP GET", attempt.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), attempt.Parent().SpanID())
+	assert.Equal(t, codes.Error, attempt.Status().Code)
+	attrs := make(map[string]interface{})
+	for _, attr := range attempt.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	assert.Equal(t, "/v0/items", attrs["url.path"])
+	assert.Equal(t, int64(429), attrs["http.response.status_code"])
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	path := filepath.Join(t.TempDir(), "traces", "spans.jsonl")
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterFile, File: path})
+	require.NoError(t, err)
+
+	ctx, parent := Start(context.Background(), "tool nuclino_get_item")
+	_, child := Start(ctx, "nuclino GET")
+	child.End()
+	parent.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var span struct {
+			Name string
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span), "one JSON span per line")
+		names = append(names, span.Name)
+	}
+	assert.Equal(t, []string{"nuclino GET", "tool nuclino_get_item"}, names)
+
+	_, err = Setup(context.Background(), Config{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+// Package validate checks struct fields against their validate tags. It
+// backs both the nuclino request types and the decoding of tool arguments,
+// so the API client does not depend on the tool layer.
+//
+// The validate tag takes a comma-separated list of required, min=N, max=N
+// and oneof=a b c, the subset of go-playground/validator syntax the nuclino
+// request types use. Fields are named by their json tag; fields without
+// one, or tagged "-", are skipped, and anonymous struct fields are
+// flattened.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a problem with one field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// Errors lists every field problem found
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "invalid arguments: " + strings.Join(messages, "; ")
+}
+
+// Rules are a field's parsed validate tag
+type Rules struct {
+	Required bool
+	Min      *float64
+	Max      *float64
+	OneOf    []string
+}
+
+// ParseRules reads a validate tag
+func ParseRules(tag string) (Rules, error) {
+	var r Rules
+	if tag == "" {
+		return r, nil
+	}
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required":
+			r.Required = true
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return r, fmt.Errorf("invalid %s rule %q", key, value)
+			}
+			if key == "min" {
+				r.Min = &n
+			} else {
+				r.Max = &n
+			}
+		case "oneof":
+			r.OneOf = strings.Fields(value)
+		case "", "omitempty":
+		default:
+			return r, fmt.Errorf("unknown validate rule %q", key)
+		}
+	}
+	return r, nil
+}
+
+// Field is a json-named field of a struct
+type Field struct {
+	Name  string
+	Index []int
+	Type  reflect.Type
+	Tag   reflect.StructTag
+	Rules Rules
+}
+
+// Fields returns the json-named fields of struct type t, in declaration
+// order
+func Fields(t reflect.Type) ([]Field, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validate: %s is not a struct", t)
+	}
+
+	var result []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Tag.Get("json") == "" {
+			embedded, err := Fields(sf.Type)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range embedded {
+				f.Index = append([]int{i}, f.Index...)
+				result = append(result, f)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		parsed, err := ParseRules(sf.Tag.Get("validate"))
+		if err != nil {
+			return nil, fmt.Errorf("validate: field %s: %w", sf.Name, err)
+		}
+		result = append(result, Field{
+			Name:  name,
+			Index: []int{i},
+			Type:  sf.Type,
+			Tag:   sf.Tag,
+			Rules: parsed,
+		})
+	}
+	return result, nil
+}
+
+// Struct checks the validate tags of struct v. Constraints other than
+// required apply to non-zero values only.
+func Struct(v interface{}) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	fs, err := Fields(value.Type())
+	if err != nil {
+		return err
+	}
+	var errs Errors
+	for _, f := range fs {
+		fv := value.FieldByIndex(f.Index)
+		if fv.IsZero() {
+			if f.Rules.Required {
+				errs = append(errs, &FieldError{Field: f.Name, Message: "is required"})
+			}
+			continue
+		}
+		if err := f.Check(fv); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Check applies the min, max and oneof rules to v
+func (f *Field) Check(v reflect.Value) *FieldError {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var size float64
+	var unit string
+	switch v.Kind() {
+	case reflect.String:
+		size, unit = float64(len([]rune(v.String()))), " characters"
+	case reflect.Slice, reflect.Map:
+		size, unit = float64(v.Len()), " entries"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = v.Float()
+	default:
+		return nil
+	}
+
+	if f.Rules.Min != nil && size < *f.Rules.Min {
+		if unit != "" {
+			return &FieldError{Field: f.Name, Message: fmt.Sprintf("must have at least %s%s", formatNumber(*f.Rules.Min), unit)}
+		}
+		return &FieldError{Field: f.Name, Message: "must be at least " + formatNumber(*f.Rules.Min)}
+	}
+	if f.Rules.Max != nil && size > *f.Rules.Max {
+		if unit != "" {
+			return &FieldError{Field: f.Name, Message: fmt.Sprintf("must have at most %s%s", formatNumber(*f.Rules.Max), unit)}
+		}
+		return &FieldError{Field: f.Name, Message: "must be at most " + formatNumber(*f.Rules.Max)}
+	}
+	if len(f.Rules.OneOf) == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Slice {
+		// oneof on a list constrains its elements
+		for i := 0; i < v.Len(); i++ {
+			if !f.Rules.allows(v.Index(i)) {
+				return &FieldError{Field: fmt.Sprintf("%s[%d]", f.Name, i), Message: "must be one of " + strings.Join(f.Rules.OneOf, ", ")}
+			}
+		}
+		return nil
+	}
+	if !f.Rules.allows(v) {
+		return &FieldError{Field: f.Name, Message: "must be one of " + strings.Join(f.Rules.OneOf, ", ")}
+	}
+	return nil
+}
+
+func (r Rules) allows(v reflect.Value) bool {
+	text := fmt.Sprint(v.Interface())
+	for _, allowed := range r.OneOf {
+		if text == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,32 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStruct(t *testing.T) {
+	type request struct {
+		Title       string   `json:"title" validate:"required"`
+		WorkspaceID string   `json:"workspaceId" validate:"required"`
+		Limit       int      `json:"limit" validate:"max=10"`
+		Fields      []string `json:"fields" validate:"oneof=id title"`
+	}
+	assert.NoError(t, Struct(&request{Title: "a", WorkspaceID: "b"}))
+	assert.NoError(t, Struct((*request)(nil)))
+	assert.EqualError(t, Struct(request{Title: "a", Limit: 11}), "invalid arguments: workspaceId is required; limit must be at most 10")
+	assert.EqualError(t, Struct(request{Title: "a", WorkspaceID: "b", Fields: []string{"id", "url"}}), "invalid arguments: fields[1] must be one of id, title")
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("required,min=1,max=100,oneof=a b")
+	assert.NoError(t, err)
+	assert.True(t, rules.Required)
+	assert.Equal(t, 1.0, *rules.Min)
+	assert.Equal(t, 100.0, *rules.Max)
+	assert.Equal(t, []string{"a", "b"}, rules.OneOf)
+
+	_, err = ParseRules("email")
+	assert.EqualError(t, err, `unknown validate rule "email"`)
+}